@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+// summaryTitlesMax caps the item titles listed per section of a job summary.
+const summaryTitlesMax = 50
+
+// inActions reports whether kube-board is running as a GitHub Actions step.
+func inActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// applyActionInputs maps the step's inputs (INPUT_* variables) onto the
+// environment variables kube-board reads, so the flag defaults pick them
+// up: input `dest-board-owner` sets GITHUB_DEST_BOARD_OWNER and `token` sets
+// GITHUB_TOKEN. Empty inputs are skipped. It returns the subcommand and
+// arguments from the `command` and `args` inputs, the latter split the way a
+// shell would.
+func applyActionInputs() (command string, args []string) {
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		input, ok := strings.CutPrefix(key, "INPUT_")
+		if !ok || value == "" {
+			continue
+		}
+		name := strings.ToUpper(strings.ReplaceAll(input, "-", "_"))
+		switch name {
+		case "COMMAND":
+			command = value
+			continue
+		case "ARGS":
+			var err error
+			if args, err = splitArgs(value); err != nil {
+				failf(statusUsage, "args input: %v", err)
+			}
+			continue
+		}
+		if ev, ok := lookupEnv(name); ok && ev.envOnly {
+			slog.Warn("Ignoring input; set the variable in the step's env instead", "input", strings.ToLower(input), "env", ev.name)
+		} else if ok {
+			os.Setenv(ev.name, value)
+		} else {
+			slog.Warn("Ignoring unknown input", "input", strings.ToLower(input))
+		}
+	}
+	if command == "" {
+		command = "sync-boards"
+	}
+	return command, args
+}
+
+// splitArgs splits s into words the way a POSIX shell does, without
+// expanding anything: whitespace separates words, single quotes keep
+// everything up to the next one, a backslash escapes the next character
+// (inside double quotes, only " \\ $ and `), and a backslash-newline joins
+// lines. So `--filter 'item.state == "OPEN"'` is two words.
+func splitArgs(s string) ([]string, error) {
+	var (
+		args  []string
+		word  strings.Builder
+		inArg bool // a word has started, possibly an empty "" or ''
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, word.String())
+				word.Reset()
+				inArg = false
+			}
+		case c == '\\':
+			if i++; i == len(s) {
+				return nil, fmt.Errorf("trailing backslash in %q", s)
+			}
+			if s[i] != '\n' { // a backslash-newline continues the line
+				inArg = true
+				word.WriteByte(s[i])
+			}
+		case c == '\'':
+			inArg = true
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in %q", s)
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += 1 + end
+		case c == '"':
+			inArg = true
+			for i++; ; i++ {
+				if i == len(s) {
+					return nil, fmt.Errorf("unterminated double quote in %q", s)
+				}
+				if s[i] == '"' {
+					break
+				}
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`\n", s[i+1]) >= 0 {
+					if i++; s[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+		default:
+			inArg = true
+			word.WriteByte(c)
+		}
+	}
+	if inArg {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
+
+// reportToActions appends a sync's outcome to the job summary and sets the
+// step's outputs. Item titles are only listed with GITHUB_SUMMARY_TITLES=true:
+// anyone who can see the workflow run can read its summary. With several
+// syncs per step (sync-sigs, run-all) the outputs describe the last one.
+func reportToActions(s *runSummary, c *board.Changes) {
+	if !inActions() {
+		return
+	}
+	titles := os.Getenv("GITHUB_SUMMARY_TITLES") == "true"
+
+	var md strings.Builder
+	heading := s.Board
+	if c != nil && c.Project != nil && c.Project.URL != "" {
+		heading = fmt.Sprintf("[%s](%s)", s.Board, c.Project.URL)
+	}
+	fmt.Fprintf(&md, "### %s\n\n", heading)
+	if s.DryRun {
+		md.WriteString("Dry run: nothing was written.\n\n")
+	}
+	m := s.Mutations
+	md.WriteString("| Items | Added | Fields updated | Removed | Deferred | Failed |\n|---|---|---|---|---|---|\n")
+	fmt.Fprintf(&md, "| %d | %d | %d | %d | %d | %d |\n\n", s.Items, m.Added, m.FieldsUpdated, m.Removed, m.Deferred, m.Failed)
+	if titles && c != nil {
+		added := make([]string, 0, len(c.Added))
+		for _, it := range c.Added {
+			added = append(added, it.Title)
+		}
+		writeSummaryList(&md, "Added", added)
+		writeSummaryList(&md, "Removed", c.Removed)
+	}
+	for _, e := range s.Errors {
+		fmt.Fprintf(&md, "> **Error:** %s\n\n", e)
+	}
+	if err := appendFile(os.Getenv("GITHUB_STEP_SUMMARY"), md.String()); err != nil {
+		slog.Warn("Could not write the job summary", "err", err)
+	}
+
+	outputs := []string{
+		fmt.Sprintf("items=%d", s.Items),
+		fmt.Sprintf("added=%d", m.Added),
+		fmt.Sprintf("updated=%d", m.FieldsUpdated),
+		fmt.Sprintf("removed=%d", m.Removed),
+		fmt.Sprintf("deferred=%d", m.Deferred),
+		fmt.Sprintf("failed=%d", m.Failed),
+	}
+	if c != nil && c.Project != nil {
+		outputs = append(outputs, "board-url="+c.Project.URL, fmt.Sprintf("board-number=%d", c.Project.Number))
+	}
+	if err := appendFile(os.Getenv("GITHUB_OUTPUT"), strings.Join(outputs, "\n")+"\n"); err != nil {
+		slog.Warn("Could not set step outputs", "err", err)
+	}
+}
+
+func writeSummaryList(md *strings.Builder, heading string, titles []string) {
+	if len(titles) == 0 {
+		return
+	}
+	fmt.Fprintf(md, "<details><summary>%s (%d)</summary>\n\n", heading, len(titles))
+	for i, t := range titles {
+		if i == summaryTitlesMax {
+			fmt.Fprintf(md, "- … and %d more\n", len(titles)-i)
+			break
+		}
+		fmt.Fprintf(md, "- %s\n", t)
+	}
+	md.WriteString("\n</details>\n\n")
+}
+
+// appendFile appends text to the Actions file command at path; a no-op when
+// the runner didn't provide one.
+func appendFile(path, text string) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr string
+	}{
+		{in: "", want: nil},
+		{in: "  --sync   --age-field Age ", want: []string{"--sync", "--age-field", "Age"}},
+		{in: `--filter 'item.state == "OPEN"'`, want: []string{"--filter", `item.state == "OPEN"`}},
+		{in: `--name "SIG Auth \"1.33\""`, want: []string{"--name", `SIG Auth "1.33"`}},
+		{in: `"a\b" 'c\d'`, want: []string{`a\b`, `c\d`}},
+		{in: `--source-field ""`, want: []string{"--source-field", ""}},
+		{in: `a\ b c`, want: []string{"a b", "c"}},
+		{in: "a''b\"c\"d", want: []string{"abcd"}},
+		{in: "--sync \\\n  --dry-run", want: []string{"--sync", "--dry-run"}},
+		{in: "\"a\\\nb\"", want: []string{"ab"}},
+		{in: "a\tb\nc", want: []string{"a", "b", "c"}},
+		{in: "'open", wantErr: "unterminated single quote"},
+		{in: `"open`, wantErr: "unterminated double quote"},
+		{in: `a\`, wantErr: "trailing backslash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := splitArgs(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("splitArgs(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitArgs(%q): %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyActionInputs(t *testing.T) {
+	t.Setenv("INPUT_COMMAND", "items")
+	t.Setenv("INPUT_ARGS", `--filter '"sig/auth" in item.labels' --dry-run`)
+	t.Setenv("INPUT_DEST-BOARD-OWNER", "my-org")
+	t.Setenv("GITHUB_DEST_BOARD_OWNER", "")
+
+	command, args := applyActionInputs()
+	if command != "items" {
+		t.Errorf("command = %q, want items", command)
+	}
+	if want := []string{"--filter", `"sig/auth" in item.labels`, "--dry-run"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+	if got := os.Getenv("GITHUB_DEST_BOARD_OWNER"); got != "my-org" {
+		t.Errorf("GITHUB_DEST_BOARD_OWNER = %q, want my-org", got)
+	}
+}
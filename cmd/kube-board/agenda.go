@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/cache"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// agendaSection is one heading of the meeting agenda.
+type agendaSection struct {
+	heading string
+	items   []board.ProjectItemWithFields
+}
+
+// agendaOptions holds the `kube-board report agenda` flags.
+type agendaOptions struct {
+	owner       *string
+	number      *int
+	cacheDir    *string
+	statusField *string
+	blocked     *string
+	staleDays   *int
+	firstDays   *int
+	record      *bool
+	keep        *int
+	out         *string
+	filters     *listFlags
+}
+
+// registerAgendaFlags adds the report agenda flags to fs.
+func registerAgendaFlags(fs *flag.FlagSet) *agendaOptions {
+	return &agendaOptions{
+		owner:       fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number:      fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		cacheDir:    fs.String("cache-dir", defaultCacheDir, "Directory holding agenda snapshots"),
+		statusField: fs.String("status-field", "Status", "Board field holding each item's status"),
+		blocked:     fs.String("blocked", "Blocked", "Status of items needing a decision (case-insensitive substring)"),
+		staleDays:   fs.Int("stale-days", 30, "List open items with no activity for more than N days"),
+		firstDays:   fs.Int("first-days", 14, "With no previous agenda, treat items created in the last N days as new"),
+		record:      fs.Bool("record", true, "Save a snapshot so the next agenda lists only newer items (--record=false for a preview)"),
+		keep:        fs.Int("snapshot-keep", 52, "Number of agenda snapshots to keep per board"),
+		out:         fs.String("out", "", "Write the Markdown agenda to this file (default stdout)"),
+		filters:     registerListFlags(fs),
+	}
+}
+
+// runAgenda implements `kube-board report agenda`: the standard SIG meeting
+// structure — new items since the last agenda, items needing a decision,
+// stale items, and PRs awaiting review. Each run records a snapshot of the
+// board so the next agenda knows what is new.
+func runAgenda(args []string) {
+	fs := flag.NewFlagSet("agenda", flag.ExitOnError)
+	opts := registerAgendaFlags(fs)
+	parseFlags(fs, args)
+	opts.filters.validate()
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+	all := fetchBoardItems(gql, project)
+	list, err := opts.filters.apply(all)
+	if err != nil {
+		fatal(err)
+	}
+
+	now := time.Now()
+	prefix := cache.SafeString(fmt.Sprintf("agenda_%s_%d_", *opts.owner, *opts.number))
+	history, err := cache.ReadSnapshots[items.StatusEntry](*opts.cacheDir, prefix)
+	if err != nil {
+		slog.Warn("Could not read agenda history", "err", err)
+	}
+	migrateSnapshotIDs(gql, history)
+
+	var since time.Time
+	var seen map[string]bool
+	if len(history) > 0 {
+		prev := history[len(history)-1]
+		since = prev.At
+		seen = make(map[string]bool, len(prev.Items))
+		for _, e := range prev.Items {
+			seen[e.ItemID] = true
+		}
+	} else {
+		since = now.AddDate(0, 0, -*opts.firstDays)
+	}
+
+	var fresh, decide, stale, review []board.ProjectItemWithFields
+	for _, it := range list {
+		if (seen != nil && !seen[it.ItemID]) || (seen == nil && it.CreatedAt.After(since)) {
+			fresh = append(fresh, it)
+		}
+		if items.StatusMatches(it.Fields[*opts.statusField], *opts.blocked) {
+			decide = append(decide, it)
+		}
+		if it.State == "OPEN" && *opts.staleDays > 0 && items.IdleDays(it, now) > *opts.staleDays {
+			stale = append(stale, it)
+		}
+		if it.Type == "PullRequest" && it.State == "OPEN" && !it.IsDraft && it.ReviewDecision != "APPROVED" {
+			review = append(review, it)
+		}
+	}
+
+	sections := []agendaSection{
+		{"New since last meeting", fresh},
+		{fmt.Sprintf("Needs decision (%s)", *opts.blocked), decide},
+		{fmt.Sprintf("Stale (no activity in %d+ days)", *opts.staleDays), stale},
+		{"PRs awaiting review", review},
+	}
+	w, closeOut := reportOutput(*opts.out)
+	writeAgenda(w, project.Title, *opts.statusField, now, since, len(history) > 0, sections)
+	closeOut()
+
+	if *opts.record {
+		cache.Write(*opts.cacheDir, prefix+cache.Timestamp()+".json", items.StatusEntries(all, *opts.statusField))
+		if _, err := cache.Clean(*opts.cacheDir, prefix, *opts.keep); err != nil {
+			slog.Warn("Could not prune agenda snapshots", "err", err)
+		}
+	}
+}
+
+// writeAgenda renders the agenda as Markdown, one bullet per item with its
+// link, status, and assignees.
+func writeAgenda(w io.Writer, title, statusField string, now, since time.Time, hadPrevious bool, sections []agendaSection) {
+	fmt.Fprintf(w, "# %s — meeting agenda %s\n\n", title, now.Format("2006-01-02"))
+	if hadPrevious {
+		fmt.Fprintf(w, "_Changes since the previous agenda (%s)._\n\n", since.Local().Format("2006-01-02 15:04"))
+	} else {
+		fmt.Fprintf(w, "_No previous agenda — items created since %s count as new._\n\n", since.Local().Format("2006-01-02"))
+	}
+
+	for _, s := range sections {
+		fmt.Fprintf(w, "## %s (%d)\n\n", s.heading, len(s.items))
+		if len(s.items) == 0 {
+			fmt.Fprintln(w, "_None._")
+			fmt.Fprintln(w)
+			continue
+		}
+		for _, it := range s.items {
+			link := it.Title
+			if it.URL != "" {
+				link = fmt.Sprintf("[%s#%d](%s) %s", it.Repo, it.Number, it.URL, it.Title)
+			}
+			who := "_unassigned_"
+			if len(it.Assignees) > 0 {
+				who = "@" + strings.Join(it.Assignees, ", @")
+			}
+			status := it.Fields[statusField]
+			if status == "" {
+				status = "No Status"
+			}
+			fmt.Fprintf(w, "- %s — %s · %s\n", link, status, who)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+// applyTemplateOptions holds the `kube-board board apply-template` flags.
+type applyTemplateOptions struct {
+	file   *string
+	owner  *string
+	name   *string
+	dryRun *bool
+}
+
+// registerApplyTemplateFlags adds the board apply-template flags to fs.
+func registerApplyTemplateFlags(fs *flag.FlagSet) *applyTemplateOptions {
+	return &applyTemplateOptions{
+		file:   fs.String("file", "", "Board template file (YAML; see board.Spec)"),
+		owner:  fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		name:   fs.String("name", os.Getenv("GITHUB_DEST_BOARD_NAME"), "Board title (created if missing; default: the template's title); may use {{.Milestone}}, {{.Org}}, and {{.Date}}"),
+		dryRun: fs.Bool("dry-run", false, "Check the template and print what it declares without touching the board"),
+	}
+}
+
+// runApplyTemplate implements `kube-board board apply-template`: reconcile
+// a board, new or existing, with a declarative template file of fields,
+// views, and visibility (see board.Spec).
+func runApplyTemplate(args []string) {
+	fs := flag.NewFlagSet("apply-template", flag.ExitOnError)
+	opts := registerApplyTemplateFlags(fs)
+	parseFlags(fs, args)
+
+	if *opts.file == "" {
+		fatal("--file is required")
+	}
+	spec, err := board.LoadSpec(*opts.file)
+	if err != nil {
+		fatalf("Error loading template: %v", err)
+	}
+	title := *opts.name
+	if title == "" {
+		title = spec.Title
+	}
+	if *opts.owner == "" || title == "" {
+		fatal("board owner and title are required (--owner/--name, GITHUB_DEST_BOARD_OWNER/GITHUB_DEST_BOARD_NAME, or the template's title)")
+	}
+	if title, err = expandBoardName(title, *opts.owner); err != nil {
+		fatalf("--name: %v", err)
+	}
+
+	if *opts.dryRun {
+		visibility := spec.Visibility
+		if visibility == "" {
+			visibility = board.Private
+		}
+		fmt.Printf("Board %s/%q (%s)\n", *opts.owner, title, visibility)
+		for _, f := range spec.FieldSpecs() {
+			fmt.Printf("  field %-30s %s", f.Name, f.Type)
+			if len(f.Options) > 0 {
+				fmt.Printf("  [%s]", strings.Join(f.Options, ", "))
+			}
+			fmt.Println()
+		}
+		for _, v := range spec.ViewConfigs() {
+			fmt.Printf("  view  %-30s %s\n", v.Name, describeView(v))
+		}
+		return
+	}
+
+	project, err := board.ApplySpec(newClient(), *opts.owner, title, *spec)
+	if err != nil {
+		fatal(err)
+	}
+	fmt.Printf("\nProject board: %s\n", project.URL)
+}
+
+// describeView summarizes a view's settings for --dry-run.
+func describeView(v board.ViewConfig) string {
+	layout := v.Layout
+	if layout == "" {
+		layout = "TABLE_LAYOUT"
+	}
+	parts := []string{layout}
+	if v.Filter != "" {
+		parts = append(parts, "filter="+v.Filter)
+	}
+	if len(v.FieldNames) > 0 {
+		parts = append(parts, "columns="+strings.Join(v.FieldNames, ","))
+	}
+	for _, s := range v.SortBy {
+		parts = append(parts, "sort="+s.Field+" "+strings.ToLower(s.Direction))
+	}
+	if len(v.GroupBy) > 0 {
+		parts = append(parts, "group="+strings.Join(v.GroupBy, ","))
+	}
+	if len(v.VerticalGroupBy) > 0 {
+		parts = append(parts, "columns-by="+strings.Join(v.VerticalGroupBy, ","))
+	}
+	return strings.Join(parts, " ")
+}
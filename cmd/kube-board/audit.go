@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/search"
+)
+
+// auditOptions holds the `kube-board audit` flags.
+type auditOptions struct {
+	owner  *string
+	number *int
+	org    *string
+	label  *string
+	extra  *string
+}
+
+// registerAuditFlags adds the audit flags to fs.
+func registerAuditFlags(fs *flag.FlagSet) *auditOptions {
+	return &auditOptions{
+		owner:  fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number: fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		org:    fs.String("org", "kubernetes", "Org to search"),
+		label:  fs.String("label", "sig/auth", "Label that marks items the board should track"),
+		extra:  fs.String("query", "", "Extra search qualifiers, e.g. \"-label:lifecycle/rotten\""),
+	}
+}
+
+// runAudit implements `kube-board audit`: compare every open item in an org
+// carrying a label against a board, and report items that should be tracked
+// but aren't, and tracked items that no longer match. Nothing is modified.
+func runAudit(args []string) {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	opts := registerAuditFlags(fs)
+	parseFlags(fs, args)
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+	onBoard := fetchBoardItems(gql, project)
+
+	// Issues and PRs are searched separately so each gets its own 1000-result cap.
+	var expected []board.ProjectItemWithFields
+	for _, kind := range []string{"is:issue", "is:pr"} {
+		q := strings.TrimSpace(fmt.Sprintf("org:%s label:%q is:open %s %s", *opts.org, *opts.label, kind, *opts.extra))
+		slog.Info("Searching", "query", q)
+		res, err := search.Issues(gql, q)
+		if err != nil {
+			fatalf("Error searching: %v", err)
+		}
+		slog.Debug("Searched", "query", q, "count", len(res.Items))
+		expected = append(expected, res.Items...)
+	}
+
+	boardIDs := make(map[string]bool, len(onBoard))
+	for _, it := range onBoard {
+		boardIDs[it.ContentID] = true
+	}
+	expectedIDs := make(map[string]bool, len(expected))
+	for _, it := range expected {
+		expectedIDs[it.ContentID] = true
+	}
+
+	var missing []board.ProjectItemWithFields
+	for _, it := range expected {
+		if !boardIDs[it.ContentID] {
+			missing = append(missing, it)
+		}
+	}
+	var extraneous []board.ProjectItemWithFields
+	var reasons []string
+	for _, it := range onBoard {
+		if it.ContentID == "" || it.Type == "DraftIssue" || expectedIDs[it.ContentID] {
+			continue
+		}
+		extraneous = append(extraneous, it)
+		reasons = append(reasons, auditReason(it, *opts.org, *opts.label))
+	}
+
+	fmt.Printf("\n=== Audit: %s vs. open %q items in %s ===\n", project.Title, *opts.label, *opts.org)
+	fmt.Printf("%d item(s) on the board, %d open labeled item(s) in the org\n", len(onBoard), len(expected))
+
+	fmt.Printf("\n--- Missing from the board (%d) ---\n", len(missing))
+	for _, it := range missing {
+		fmt.Printf("  [%s] %s#%-6d %s\n", it.Type, it.Repo, it.Number, items.Truncate(it.Title, 60))
+		fmt.Printf("           %s\n", it.URL)
+	}
+
+	fmt.Printf("\n--- On the board but not matching (%d) ---\n", len(extraneous))
+	for i, it := range extraneous {
+		fmt.Printf("  [%s] %s#%-6d %s\n", it.Type, it.Repo, it.Number, items.Truncate(it.Title, 60))
+		fmt.Printf("           %s (%s)\n", it.URL, reasons[i])
+	}
+
+	if len(missing) > 0 || len(extraneous) > 0 {
+		exit(1)
+	}
+}
+
+// auditReason explains why a board item is outside the search set.
+func auditReason(it board.ProjectItemWithFields, org, label string) string {
+	switch {
+	case it.State != "OPEN":
+		return strings.ToLower(it.State)
+	case !strings.EqualFold(strings.SplitN(it.Repo, "/", 2)[0], org):
+		return "outside " + org
+	case !items.HasLabel(it, label):
+		return "no " + label + " label"
+	default:
+		return "excluded by search qualifiers"
+	}
+}
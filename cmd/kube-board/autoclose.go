@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/releasenotes"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/upstream"
+)
+
+// autocloseListMax caps how many still-open items the final summary names.
+const autocloseListMax = 15
+
+// autocloseOptions holds the `kube-board board autoclose` flags.
+type autocloseOptions struct {
+	owner       *string
+	number      *int
+	milestone   *string
+	allDone     *bool
+	statusField *string
+	doneStatus  *string
+	dryRun      *bool
+}
+
+// registerAutocloseFlags adds the board autoclose flags to fs.
+func registerAutocloseFlags(fs *flag.FlagSet) *autocloseOptions {
+	return &autocloseOptions{
+		owner:       fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number:      fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		milestone:   fs.String("milestone", "", "Close the board once this milestone (e.g. v1.36) is closed in every repo its items come from"),
+		allDone:     fs.Bool("all-done", false, "Close the board once every item is done"),
+		statusField: fs.String("status-field", "Status", "Single-select field holding each item's column"),
+		doneStatus:  fs.String("done", "Done", "Status that marks an item done (substring match); merged PRs and closed issues are always done"),
+		dryRun:      fs.Bool("dry-run", false, "Print the summary without posting it or closing the board"),
+	}
+}
+
+// runAutoclose implements `kube-board board autoclose`: once the board's
+// milestone is closed, or every item on it is done, post a final summary as
+// a project status update and close the board. It is meant to run on a
+// schedule and does nothing until the condition holds.
+func runAutoclose(args []string) {
+	fs := flag.NewFlagSet("autoclose", flag.ExitOnError)
+	opts := registerAutocloseFlags(fs)
+	parseFlags(fs, args)
+
+	if *opts.milestone == "" && !*opts.allDone {
+		fatal("pass --milestone and/or --all-done to say when the board is finished")
+	}
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+	if project.Closed {
+		slog.Info("Board is already closed", "board", project.Title)
+		return
+	}
+	list := fetchBoardItems(gql, project)
+	isDone := func(it board.ProjectItemWithFields) bool {
+		return releasenotes.Completed(it) || items.StatusMatches(it.Fields[*opts.statusField], *opts.doneStatus)
+	}
+
+	var reason string
+	if *opts.milestone != "" {
+		closed, err := milestoneClosed(gql, list, *opts.milestone)
+		if err != nil {
+			fatal(err)
+		}
+		if closed {
+			reason = fmt.Sprintf("milestone %s is closed", *opts.milestone)
+		}
+	}
+	if reason == "" && *opts.allDone {
+		done := 0
+		for _, it := range list {
+			if isDone(it) {
+				done++
+			}
+		}
+		slog.Info("Counted done items", "done", done, "count", len(list))
+		if len(list) > 0 && done == len(list) {
+			reason = fmt.Sprintf("all %d items are done", len(list))
+		}
+	}
+	if reason == "" {
+		slog.Info("Board is not finished yet; leaving it open", "board", project.Title)
+		return
+	}
+
+	summary := autocloseSummary(list, reason, *opts.statusField, isDone)
+	fmt.Printf("\n%s\n\n", summary)
+	if *opts.dryRun {
+		return
+	}
+	if err := board.CreateStatusUpdate(gql, project.ID, summary, board.StatusComplete); err != nil {
+		fatalf("Error posting the final status update: %v", err)
+	}
+	if err := board.CloseProject(gql, project.ID); err != nil {
+		fatalf("Error closing the board: %v", err)
+	}
+	slog.Info("Closed board", "board", project.Title, "reason", reason)
+}
+
+// milestoneClosed reports whether the milestone is closed in every repository
+// whose items on the board carry it. A milestone no item carries is never
+// considered closed.
+func milestoneClosed(gql *ghgql.Client, list []board.ProjectItemWithFields, milestone string) (bool, error) {
+	repos := make(map[string]bool)
+	for _, it := range list {
+		if it.Milestone == milestone && it.Repo != "" {
+			repos[it.Repo] = true
+		}
+	}
+	if len(repos) == 0 {
+		slog.Info("No item on the board is in the milestone", "milestone", milestone)
+		return false, nil
+	}
+	for repo := range repos {
+		state, err := upstream.MilestoneState(gql, repo, milestone)
+		if err != nil {
+			return false, fmt.Errorf("reading milestone %s in %s: %w", milestone, repo, err)
+		}
+		if state != "CLOSED" {
+			slog.Info("Milestone is still open", "milestone", milestone, "repo", repo)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// autocloseSummary renders the final status update: why the board closed,
+// how its items ended up, and which ones are still open.
+func autocloseSummary(list []board.ProjectItemWithFields, reason, statusField string, isDone func(board.ProjectItemWithFields) bool) string {
+	byStatus := make(map[string]int)
+	var open []string
+	for _, it := range list {
+		status := it.Fields[statusField]
+		if status == "" {
+			status = "No Status"
+		}
+		byStatus[status]++
+		if !isDone(it) {
+			if it.Repo != "" {
+				open = append(open, fmt.Sprintf("%s#%d %s", it.Repo, it.Number, it.Title))
+			} else {
+				open = append(open, it.Title)
+			}
+		}
+	}
+	statuses := make([]string, 0, len(byStatus))
+	for s := range byStatus {
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return byStatus[statuses[i]] > byStatus[statuses[j]] })
+	counts := make([]string, len(statuses))
+	for i, s := range statuses {
+		counts[i] = fmt.Sprintf("%s %d", s, byStatus[s])
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Final summary** — closing this board: %s.\n\n", reason)
+	fmt.Fprintf(&b, "- Items: %d (%d done, %d not done)\n", len(list), len(list)-len(open), len(open))
+	fmt.Fprintf(&b, "- By %s: %s", statusField, strings.Join(counts, " · "))
+	if len(open) > 0 {
+		b.WriteString("\n\nNot done:\n")
+		for i, o := range open {
+			if i == autocloseListMax {
+				fmt.Fprintf(&b, "- …and %d more\n", len(open)-autocloseListMax)
+				break
+			}
+			fmt.Fprintf(&b, "- %s\n", o)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
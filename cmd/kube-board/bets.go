@@ -0,0 +1,221 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	boarditems "github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// betsConfig is the YAML structure for the bets config file.
+type betsConfig struct {
+	// FieldName is the single-select field to set (default: "Bet").
+	FieldName string `yaml:"fieldName"`
+	// Categories maps each Bet value to a list of Epic names.
+	Categories map[string][]string `yaml:"categories"`
+}
+
+// loadBetsConfig reads and parses the YAML config file.
+func loadBetsConfig(path string) (*betsConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	var cfg betsConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	if cfg.FieldName == "" {
+		cfg.FieldName = "Bet"
+	}
+	return &cfg, nil
+}
+
+// buildEpicToBet builds a case-insensitive lookup from epic name → bet category.
+func buildEpicToBet(cfg *betsConfig) map[string]string {
+	m := make(map[string]string)
+	for bet, epics := range cfg.Categories {
+		for _, epic := range epics {
+			m[strings.ToLower(epic)] = bet
+		}
+	}
+	return m
+}
+
+// assignBetsOptions holds the `kube-board assign-bets` flags.
+type assignBetsOptions struct {
+	owner  *string
+	number *int
+	config *string
+	dryRun *bool
+}
+
+// registerAssignBetsFlags adds the assign-bets flags to fs.
+func registerAssignBetsFlags(fs *flag.FlagSet) *assignBetsOptions {
+	return &assignBetsOptions{
+		owner:  fs.String("owner", cmp.Or(os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Azure"), "Board owner (user or org)"),
+		number: fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 940), "Board number"),
+		config: fs.String("config", "cmd/kube-board/bets.yaml", "Path to the bets YAML config file"),
+		dryRun: fs.Bool("dry-run", false, "Preview assignments without writing to the board"),
+	}
+}
+
+// runAssignBets implements `kube-board assign-bets`: populate a "Bet"
+// single-select field from --config, which maps Bet categories (e.g. "Top
+// Bets", "Tough Cuts") to lists of Epic names. Every item whose Epic is
+// listed gets the corresponding category.
+func runAssignBets(args []string) {
+	fs := flag.NewFlagSet("assign-bets", flag.ExitOnError)
+	opts := registerAssignBetsFlags(fs)
+	parseFlags(fs, args)
+
+	// 1. Load config.
+	cfg, err := loadBetsConfig(*opts.config)
+	if err != nil {
+		fatalf("Error loading config: %v", err)
+	}
+	epicToBet := buildEpicToBet(cfg)
+
+	slog.Info("Loaded bet categories", "path", *opts.config, "count", len(cfg.Categories))
+	for bet, epics := range cfg.Categories {
+		slog.Debug("Bet category", "bet", bet, "epics", strings.Join(epics, ", "))
+	}
+
+	// 2. Connect and find the project.
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+
+	// 3. Locate the Bet field.
+	betField, ok := project.Fields[cfg.FieldName]
+	if !ok {
+		fatalf("%q field not found on the board", cfg.FieldName)
+	}
+	slog.Info("Found bet field", "field", cfg.FieldName, "count", len(betField.Options))
+	for _, opt := range betField.Options {
+		slog.Debug("Bet option", "option", opt.Name, "id", opt.ID)
+	}
+
+	// 4. Ensure all bet categories exist as options on the field.
+	for bet := range cfg.Categories {
+		betField, err = board.EnsureOption(gql, betField, bet)
+		if err != nil {
+			fatalf("Could not ensure %s option %q: %v", cfg.FieldName, bet, err)
+		}
+	}
+
+	// 5. Check for the Epic field the bets are read from.
+	if _, hasEpic := project.Fields["Epic"]; !hasEpic {
+		fatal("\"Epic\" field not found on the board — cannot map epics to bets")
+	}
+
+	// 6. Fetch all items.
+	items := fetchBoardItems(gql, project)
+
+	// 7. Process: for each item, read Epic → look up Bet → set if changed.
+	var (
+		setCount    int
+		skipSame    int
+		skipNoEpic  int
+		skipNoMatch int
+		errorCount  int
+	)
+
+	// Track counts per bet for summary
+	betCounts := make(map[string]int)
+	// Track epics that aren't in our config (for review)
+	unmappedEpics := make(map[string]int)
+
+	for i, item := range items {
+		epic := item.Fields["Epic"]
+		if epic == "" {
+			skipNoEpic++
+			continue
+		}
+
+		bet, found := epicToBet[strings.ToLower(epic)]
+		if !found {
+			skipNoMatch++
+			unmappedEpics[epic]++
+			continue
+		}
+
+		// Already set to the right value?
+		current := item.Fields[cfg.FieldName]
+		if strings.EqualFold(current, bet) {
+			skipSame++
+			continue
+		}
+
+		betCounts[bet]++
+
+		optID, resolved := board.ResolveOptionID(betField, bet)
+		if !resolved {
+			slog.Warn("Bet is not a valid option, skipping item", "bet", bet, "item", itemRef(item))
+			errorCount++
+			continue
+		}
+
+		if *opts.dryRun {
+			slog.Info("[DRY-RUN] Would set Bet", "item", itemRef(item), "title", boarditems.Truncate(item.Title, 50),
+				"epic", epic, "from", current, "to", bet)
+		} else {
+			err := board.UpdateItemField(gql, project.ID, item.ItemID, betField.ID, board.FieldValue{
+				SingleSelectOptionID: optID,
+			})
+			if err != nil {
+				slog.Error("Could not update item", "item", itemRef(item), "err", err)
+				errorCount++
+				continue
+			}
+			setCount++
+			if setCount%50 == 0 {
+				slog.Debug("Updating items", "updated", setCount)
+			}
+		}
+
+		if (i+1)%500 == 0 {
+			slog.Debug("Processing items", "processed", i+1, "count", len(items))
+		}
+	}
+
+	// 8. Summary
+	fmt.Println()
+	fmt.Println("=== Bet Assignment Summary ===")
+	fmt.Printf("  Total items on board:     %d\n", len(items))
+	fmt.Printf("  No Epic set (skipped):    %d\n", skipNoEpic)
+	fmt.Printf("  Epic not in config:       %d\n", skipNoMatch)
+	fmt.Printf("  Already correct (skip):   %d\n", skipSame)
+	if *opts.dryRun {
+		total := 0
+		for _, c := range betCounts {
+			total += c
+		}
+		fmt.Printf("  Would update:             %d\n", total)
+	} else {
+		fmt.Printf("  Successfully updated:     %d\n", setCount)
+		fmt.Printf("  Errors:                   %d\n", errorCount)
+	}
+
+	if len(betCounts) > 0 {
+		fmt.Println()
+		fmt.Println("  Assignments by bet:")
+		for bet, count := range betCounts {
+			fmt.Printf("    %-30s %d\n", bet, count)
+		}
+	}
+
+	if len(unmappedEpics) > 0 {
+		fmt.Println()
+		fmt.Printf("  Unmapped epics (%d unique) — add to config or ignore:\n", len(unmappedEpics))
+		for epic, count := range unmappedEpics {
+			fmt.Printf("    %-40s %d item(s)\n", epic, count)
+		}
+	}
+}
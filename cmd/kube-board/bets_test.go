@@ -0,0 +1,68 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadBetsConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		wantFieldName string
+		wantErr       string
+	}{
+		{name: "field name defaults to Bet", content: "categories:\n  Top Bets: [Certs]\n", wantFieldName: "Bet"},
+		{name: "field name", content: "fieldName: Priority\ncategories:\n  Top Bets: [Certs]\n", wantFieldName: "Priority"},
+		{name: "invalid YAML", content: "categories: [\n", wantErr: "parse config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadBetsConfig(writeConfig(t, "bets.yaml", tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadBetsConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.FieldName != tt.wantFieldName {
+				t.Errorf("FieldName = %q, want %q", cfg.FieldName, tt.wantFieldName)
+			}
+		})
+	}
+
+	if _, err := loadBetsConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "read config") {
+		t.Errorf("loadBetsConfig(missing) error = %v, want read config", err)
+	}
+}
+
+// TestBetsYAML keeps the shipped bets.yaml loadable.
+func TestBetsYAML(t *testing.T) {
+	cfg, err := loadBetsConfig("bets.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Categories) == 0 {
+		t.Error("bets.yaml has no categories")
+	}
+}
+
+func TestBuildEpicToBet(t *testing.T) {
+	cfg := &betsConfig{Categories: map[string][]string{
+		"Top Bets":   {"Certs", "AKS Azure RBAC++"},
+		"Tough Cuts": {"DRA"},
+	}}
+	want := map[string]string{
+		"certs":            "Top Bets",
+		"aks azure rbac++": "Top Bets",
+		"dra":              "Tough Cuts",
+	}
+	if got := buildEpicToBet(cfg); !reflect.DeepEqual(got, want) {
+		t.Errorf("buildEpicToBet() = %v, want %v", got, want)
+	}
+}
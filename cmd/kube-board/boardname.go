@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/upstream"
+)
+
+// milestoneRepo is where the current release milestone is looked up when
+// GITHUB_KUBERNETES_MILESTONE is unset.
+const milestoneRepo = "kubernetes/kubernetes"
+
+// boardNameData is what a templated destination board name (or
+// description or README) can refer to, e.g. "SIG Auth {{.Milestone}}".
+type boardNameData struct {
+	Milestone string    // GITHUB_KUBERNETES_MILESTONE, or the release in progress
+	Org       string    // the destination board owner
+	Date      boardDate // today; {{.Date.Format "Jan 2006"}} for other layouts
+	Query     string    // the sync's source boards and searches
+}
+
+// boardDate prints as YYYY-MM-DD but keeps time.Time's methods.
+type boardDate struct{ time.Time }
+
+func (d boardDate) String() string { return d.Format(time.DateOnly) }
+
+// expandBoardName executes name as a Go template, so that one setting
+// names a new board each release cycle. Names without "{{" are returned
+// as is. The milestone is only looked up when the name refers to it.
+func expandBoardName(name, owner string) (string, error) {
+	if !strings.Contains(name, "{{") {
+		return name, nil
+	}
+	expanded, err := expandBoardText(name, owner, "")
+	if err != nil {
+		return "", err
+	}
+	if expanded = strings.TrimSpace(expanded); expanded == "" {
+		return "", fmt.Errorf("%q expands to an empty name", name)
+	}
+	return expanded, nil
+}
+
+// expandBoardText executes text as a Go template with boardNameData, query
+// being the sync's sources and searches. Text without "{{" is returned as
+// is.
+func expandBoardText(text, owner, query string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("board").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	data := boardNameData{Org: owner, Date: boardDate{time.Now()}, Query: query}
+	if strings.Contains(text, ".Milestone") {
+		if data.Milestone, err = currentMilestone(); err != nil {
+			return "", err
+		}
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// currentMilestone returns GITHUB_KUBERNETES_MILESTONE, or else the release
+// milestone in progress in milestoneRepo.
+func currentMilestone() (string, error) {
+	if m := os.Getenv("GITHUB_KUBERNETES_MILESTONE"); m != "" {
+		return m, nil
+	}
+	m, err := upstream.CurrentMilestone(newClient(), milestoneRepo)
+	if err != nil {
+		return "", fmt.Errorf("looking up the current milestone in %s: %w", milestoneRepo, err)
+	}
+	if m == "" {
+		return "", fmt.Errorf("%s has no open release milestone; set GITHUB_KUBERNETES_MILESTONE", milestoneRepo)
+	}
+	return m, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandBoardName(t *testing.T) {
+	t.Setenv("GITHUB_KUBERNETES_MILESTONE", "v1.36")
+	today := time.Now()
+	tests := []struct {
+		name, text string
+		want       string
+		wantErr    string
+	}{
+		{name: "no template", text: "SIG Auth {not a template}", want: "SIG Auth {not a template}"},
+		{name: "milestone override", text: "SIG Auth {{.Milestone}}", want: "SIG Auth v1.36"},
+		{name: "org", text: "{{.Org}} triage", want: "my-org triage"},
+		{name: "date", text: "Triage {{.Date}}", want: "Triage " + today.Format(time.DateOnly)},
+		{name: "date layout", text: `Triage {{.Date.Format "Jan 2006"}}`, want: "Triage " + today.Format("Jan 2006")},
+		{name: "trimmed", text: "  {{.Milestone}}  ", want: "v1.36"},
+		{name: "empty", text: `{{if false}}x{{end}}  `, wantErr: "expands to an empty name"},
+		{name: "bad template", text: "{{.Milestone", wantErr: "unclosed action"},
+		{name: "unknown field", text: "{{.Release}}", wantErr: "can't evaluate field Release"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandBoardName(tt.text, "my-org")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expandBoardName(%q) error = %v, want %q", tt.text, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandBoardName(%q): %v", tt.text, err)
+			}
+			if got != tt.want {
+				t.Errorf("expandBoardName(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandBoardText(t *testing.T) {
+	t.Setenv("GITHUB_KUBERNETES_MILESTONE", "v1.36")
+	tests := []struct {
+		name, text, want string
+	}{
+		{name: "no template", text: "Synced by kube-board.\n", want: "Synced by kube-board.\n"},
+		{name: "query", text: "Synced from {{.Query}} for {{.Milestone}}.", want: "Synced from kubernetes/42 for v1.36."},
+		{name: "may be empty", text: "{{if false}}x{{end}}", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandBoardText(tt.text, "my-org", "kubernetes/42")
+			if err != nil {
+				t.Fatalf("expandBoardText(%q): %v", tt.text, err)
+			}
+			if got != tt.want {
+				t.Errorf("expandBoardText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
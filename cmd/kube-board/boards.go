@@ -0,0 +1,16 @@
+package main
+
+// boardCommands are the board lifecycle commands under `kube-board board`.
+var boardCommands = []subcommand{
+	{"copy", "Create a board as a copy of an existing one (fields, views, optionally drafts)", runCopy, flagsOf(registerCopyFlags), nil},
+	{"rollover", "Start the next release cycle's board, carrying over open items", runRollover, flagsOf(registerRolloverFlags), nil},
+	{"autoclose", "Post a final summary and close a board once its milestone or items are done", runAutoclose, flagsOf(registerAutocloseFlags), nil},
+	{"inspect", "Print a board's views, fields, recent status updates, and sample field values", runInspect, flagsOf(registerInspectFlags), nil},
+	{"apply-template", "Create or update a board to match a template file of fields, views, and visibility", runApplyTemplate, flagsOf(registerApplyTemplateFlags), nil},
+}
+
+// runBoard implements `kube-board board <name>`: manage whole boards rather
+// than their items.
+func runBoard(args []string) {
+	dispatch("board", "Commands", boardCommands, args)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/version"
+)
+
+// Changelog destinations for --changelog.
+const (
+	changelogDraft  = "draft"  // a draft item pinned to the top of the board
+	changelogStatus = "status" // a project status update
+)
+
+// changelogMarker heads the changelog draft body, ahead of the run entries.
+const changelogMarker = "<!-- kube-board:changelog -->"
+
+// changelogSeparator divides run entries in the changelog draft.
+const changelogSeparator = "\n\n---\n\n"
+
+// changelogNoChanges starts the entry of a run that changed nothing.
+const changelogNoChanges = "No changes"
+
+// changelogListMax caps how many items are named per line of an entry.
+const changelogListMax = 15
+
+// changelogEntry renders one run's changes as Markdown.
+func changelogEntry(c *board.Changes, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**Sync %s** (kube-board %s)\n", now.UTC().Format("2006-01-02 15:04 UTC"), version.Get().Version)
+	if len(c.Added) == 0 && len(c.Updated) == 0 && len(c.Removed) == 0 && len(c.Unlinked) == 0 {
+		fmt.Fprintf(&b, "\n%s; %d item(s) already current.", changelogNoChanges, c.Unchanged+c.Skipped)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\n%s\n\n", changesSummary(c))
+	if len(c.Added) > 0 {
+		fmt.Fprintf(&b, "- **Added (%d):** %s\n", len(c.Added), changelogItems(c.Added))
+	}
+	if len(c.Updated) > 0 {
+		fmt.Fprintf(&b, "- **Fields updated (%d):** %s\n", len(c.Updated), changelogItems(c.Updated))
+	}
+	if len(c.Removed) > 0 {
+		fmt.Fprintf(&b, "- **Removed (%d):** %s\n", len(c.Removed), changelogNames(c.Removed))
+	}
+	if len(c.Unlinked) > 0 {
+		fmt.Fprintf(&b, "- **Repos unlinked (%d):** %s\n", len(c.Unlinked), changelogNames(c.Unlinked))
+	}
+	fmt.Fprintf(&b, "- Unchanged: %d", c.Unchanged)
+	return b.String()
+}
+
+// changesSummary sums up a run's changes in one line, e.g. "+12 items,
+// 3 removed, 5 now Done".
+func changesSummary(c *board.Changes) string {
+	var parts []string
+	if len(c.Added) > 0 {
+		parts = append(parts, fmt.Sprintf("+%d items", len(c.Added)))
+	}
+	if len(c.Removed) > 0 {
+		parts = append(parts, fmt.Sprintf("%d removed", len(c.Removed)))
+	}
+	for _, status := range slices.Sorted(maps.Keys(c.Moved)) {
+		parts = append(parts, fmt.Sprintf("%d now %s", c.Moved[status], status))
+	}
+	if moved := sumValues(c.Moved); len(c.Updated) > moved {
+		parts = append(parts, fmt.Sprintf("%d updated", len(c.Updated)-moved))
+	}
+	if len(c.Unlinked) > 0 {
+		parts = append(parts, fmt.Sprintf("%d repo(s) unlinked", len(c.Unlinked)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sumValues(m map[string]int) int {
+	n := 0
+	for _, v := range m {
+		n += v
+	}
+	return n
+}
+
+func changelogItems(list []board.Item) string {
+	names := make([]string, len(list))
+	for i, it := range list {
+		if it.Type == "DraftIssue" {
+			names[i] = it.Title
+			continue
+		}
+		names[i] = fmt.Sprintf("#%d %s", it.Number, it.Title)
+	}
+	return changelogNames(names)
+}
+
+func changelogNames(names []string) string {
+	if len(names) <= changelogListMax {
+		return strings.Join(names, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(names[:changelogListMax], "; "), len(names)-changelogListMax)
+}
+
+// postChangelog records this run's changes on the destination board as
+// --changelog directs: a new status update, or a new entry at the top of a
+// pinned draft item that keeps the --changelog-keep most recent runs.
+func (p *syncPlan) postChangelog(gql *ghgql.Client, c *board.Changes) error {
+	entry := changelogEntry(c, time.Now())
+	if *p.changelog == changelogStatus {
+		// An hourly sync would otherwise bury the Updates tab in
+		// "No changes" posts; one in a row is enough.
+		if strings.Contains(entry, "\n"+changelogNoChanges) {
+			latest, err := board.ListStatusUpdates(gql, c.Project.ID, 1)
+			if err != nil {
+				return fmt.Errorf("listing status updates: %w", err)
+			}
+			if len(latest) > 0 && strings.Contains(latest[0].Body, "\n"+changelogNoChanges) {
+				slog.Info("No changes since the last status update; not posting another")
+				return nil
+			}
+		}
+		return board.CreateStatusUpdate(gql, c.Project.ID, entry, "")
+	}
+
+	title := *p.changelogTitle
+	itemID, draftID, err := board.FindDraftItem(gql, c.Project.ID, title)
+	if err != nil {
+		return fmt.Errorf("finding changelog draft: %w", err)
+	}
+	entries := []string{entry}
+	if draftID != "" {
+		body, err := board.DraftIssueBody(gql, draftID)
+		if err != nil {
+			return fmt.Errorf("reading changelog draft: %w", err)
+		}
+		old := strings.TrimSpace(strings.TrimPrefix(body, changelogMarker))
+		if old != "" {
+			entries = append(entries, strings.Split(old, changelogSeparator)...)
+		}
+	}
+	if len(entries) > *p.changelogKeep {
+		entries = entries[:*p.changelogKeep]
+	}
+	body := changelogMarker + "\n" + strings.Join(entries, changelogSeparator)
+
+	if draftID == "" {
+		if itemID, err = board.AddDraftIssue(gql, c.Project.ID, title, body); err != nil {
+			return fmt.Errorf("creating changelog draft: %w", err)
+		}
+	} else if err := board.UpdateDraftIssue(gql, draftID, title, body); err != nil {
+		return fmt.Errorf("updating changelog draft: %w", err)
+	}
+	return board.SetItemPosition(gql, c.Project.ID, itemID, "") // keep it pinned at the top
+}
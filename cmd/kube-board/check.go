@@ -0,0 +1,360 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/search"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/upstream"
+)
+
+// checkResult is one line of the check-config report.
+type checkResult struct {
+	check  string
+	status string // "ok", "warn", or "fail"
+	detail string
+}
+
+// checker collects check results.
+type checker struct {
+	results []checkResult
+}
+
+func (c *checker) add(check, status, format string, v ...any) {
+	c.results = append(c.results, checkResult{check, status, fmt.Sprintf(format, v...)})
+}
+
+func (c *checker) failed() int {
+	n := 0
+	for _, r := range c.results {
+		if r.status == "fail" {
+			n++
+		}
+	}
+	return n
+}
+
+// scopeImplies lists the classic OAuth scopes that include another.
+var scopeImplies = map[string][]string{
+	"repo":      {"public_repo"},
+	"project":   {"read:project"},
+	"admin:org": {"write:org", "read:org"},
+	"write:org": {"read:org"},
+}
+
+// hasScope reports whether the granted scopes include want.
+func hasScope(granted []string, want string) bool {
+	for _, g := range granted {
+		if g == want || slices.Contains(scopeImplies[g], want) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConfigOptions holds the `kube-board check-config` flags.
+type checkConfigOptions struct {
+	*syncOptions
+	milestones *string
+	labels     *string
+	readOnly   *bool
+}
+
+// registerCheckConfigFlags adds the check-config flags to fs.
+func registerCheckConfigFlags(fs *flag.FlagSet) *checkConfigOptions {
+	return &checkConfigOptions{
+		syncOptions: registerSyncFlags(fs),
+		milestones:  fs.String("milestone", "", "Comma-separated milestone titles that must exist in the repos (in addition to the searches' milestone: qualifiers)"),
+		labels:      fs.String("labels", "", "Comma-separated labels that must exist in the repos (in addition to the searches' label: qualifiers)"),
+		readOnly:    fs.Bool("read-only", false, "Only require the scopes a --dry-run needs"),
+	}
+}
+
+// runCheckConfig implements `kube-board check-config`: check a sync-boards
+// configuration against GitHub before running it — the token and its
+// scopes, the destination owner, the source boards, searches, repos,
+// milestones, and labels — and report every problem at once rather than
+// the first one, partway through a sync. Nothing is written.
+func runCheckConfig(args []string) {
+	fs := flag.NewFlagSet("check-config", flag.ExitOnError)
+	opts := registerCheckConfigFlags(fs)
+	parseFlags(fs, args)
+
+	c := &checker{}
+	invalid := 0
+	for _, ev := range knownEnv {
+		value, source := ev.effective()
+		if value == "" || ev.validate == nil {
+			continue
+		}
+		if err := ev.validate(value); err != nil {
+			c.add(ev.name, "fail", "%s (from %s)", err, source)
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		exitCheck(c, "fix the settings above, then re-run to check them against GitHub")
+	}
+	p := opts.plan(!*opts.readOnly)
+
+	token, source, err := resolveToken()
+	switch {
+	case err != nil:
+		c.add("token", "fail", "%v", err)
+		exitCheck(c, "fix the token source, then re-run to check the rest against GitHub")
+	case token == "":
+		c.add("token", "fail", "none of GITHUB_TOKEN, GITHUB_TOKEN_FILE, GITHUB_TOKEN_CMD, or GITHUB_TOKEN_KEYCHAIN is set, and gh isn't logged in")
+		exitCheck(c, "set one, then re-run to check the rest against GitHub")
+	}
+	write := strings.TrimSpace(os.Getenv("GITHUB_WRITE_TOKEN"))
+	tokens := ghgql.SplitTokens(token)
+	for i, t := range tokens {
+		check := "token"
+		if len(tokens) > 1 {
+			check = fmt.Sprintf("token %d of %d", i+1, len(tokens))
+		}
+		if !checkToken(c, ghgql.NewClient(t, clientOptions...), check, source, *opts.readOnly || write != "") {
+			exitCheck(c, "fix the token, then re-run to check the rest against GitHub")
+		}
+	}
+	if write != "" && !*opts.readOnly {
+		if !checkToken(c, ghgql.NewClient(write, clientOptions...), "write token", "GITHUB_WRITE_TOKEN", false) {
+			exitCheck(c, "fix the write token, then re-run to check the rest against GitHub")
+		}
+	}
+	gql := ghgql.NewClient(token, clientOptions...)
+
+	if gql.Enterprise() {
+		switch features, err := gql.Features(); {
+		case err != nil:
+			c.add("server", "fail", "%s: %v", ghgql.Host(), err)
+		case !features.ProjectsV2:
+			c.add("server", "fail", "%s has no Projects (ProjectV2) support", ghgql.Host())
+			exitCheck(c, "the server can't host boards")
+		default:
+			for _, gap := range p.unsupported(features) {
+				c.add("server", "fail", "%s: %s", ghgql.Host(), gap)
+			}
+			if missing := features.Missing(); len(missing) > 0 {
+				c.add("server", "warn", "%s lacks %s", ghgql.Host(), strings.Join(missing, ", "))
+			} else {
+				c.add("server", "ok", "%s supports every Projects API feature used", ghgql.Host())
+			}
+		}
+	}
+
+	writeGQL := gql
+	if write != "" {
+		writeGQL = ghgql.NewClient(write, clientOptions...)
+	}
+	if ghgql.IsFineGrained(writeGQL.Token) && *p.owner != "" && !*opts.readOnly {
+		if problem := fineGrainedProblem(writeGQL, *p.owner, *p.name); problem != "" {
+			c.add("token access", "fail", "%s", problem)
+		} else {
+			c.add("token access", "ok", "the fine-grained token can write %s's boards", *p.owner)
+		}
+	}
+	if *p.owner != "" {
+		if _, err := board.ResolveOwnerNodeID(gql, *p.owner); err != nil {
+			c.add("destination owner", "fail", "%s: %v", *p.owner, err)
+		} else if *p.name != "" {
+			switch project, err := board.FindProject(gql, *p.owner, *p.name); {
+			case err != nil:
+				c.add("destination board", "fail", "%v", err)
+			case project == nil:
+				c.add("destination board", "ok", "%q will be created under %s", *p.name, *p.owner)
+			default:
+				c.add("destination board", "ok", "%s", project.URL)
+			}
+		}
+	}
+	if t := p.template; t != nil {
+		if _, err := board.FindProjectByOwnerNumber(gql, t.Owner, t.Number); err != nil {
+			c.add("template", "fail", "%s/projects/%d: %v", t.Owner, t.Number, err)
+		} else {
+			c.add("template", "ok", "%s/projects/%d", t.Owner, t.Number)
+		}
+	}
+	for _, ref := range p.refs {
+		if project, err := board.FindProjectByOwnerNumber(gql, ref.Owner, ref.Number); err != nil {
+			c.add("source board", "fail", "%s: %v", ref, err)
+		} else {
+			c.add("source board", "ok", "%s: %s", ref, project.Title)
+		}
+	}
+
+	repos := splitList(*p.linkRepos)
+	wantMilestones, wantLabels := splitList(*opts.milestones), splitList(*opts.labels)
+	for _, q := range p.queries {
+		switch n, err := search.Count(gql, q); {
+		case err != nil:
+			c.add("search", "fail", "%v", err)
+		case n == 0:
+			c.add("search", "warn", "%q matches nothing", q)
+		default:
+			c.add("search", "ok", "%q matches %d item(s)", q, n)
+		}
+		for _, org := range append(search.Qualifiers(q, "org"), search.Qualifiers(q, "user")...) {
+			if _, err := board.ResolveOwnerNodeID(gql, org); err != nil {
+				c.add("search owner", "fail", "%s: %v", org, err)
+			}
+		}
+		repos = appendNew(repos, search.Qualifiers(q, "repo")...)
+		wantMilestones = appendNew(wantMilestones, search.Qualifiers(q, "milestone")...)
+		wantLabels = appendNew(wantLabels, search.Qualifiers(q, "label")...)
+	}
+
+	var usable []string
+	for _, repo := range repos {
+		if errs := board.CheckRepositories(gql, []string{repo}); len(errs) > 0 {
+			c.add("repo", "fail", "%v", errs[0])
+		} else {
+			usable = append(usable, repo)
+			c.add("repo", "ok", "%s", repo)
+		}
+	}
+	if (len(wantMilestones) > 0 || len(wantLabels) > 0) && len(usable) == 0 {
+		c.add("milestones/labels", "warn", "no repos to check them in; give --link-repos or repo: qualifiers")
+	} else {
+		checkMilestones(c, gql, usable, wantMilestones)
+		checkLabels(c, gql, usable, wantLabels)
+	}
+
+	exitCheck(c, "")
+}
+
+// checkToken checks that the client's token works and has the scopes a
+// sync needs, reporting whether GitHub accepted it.
+func checkToken(c *checker, gql *ghgql.Client, check, source string, readOnly bool) bool {
+	login, scopes, classic, err := gql.TokenScopes()
+	switch {
+	case err != nil:
+		c.add(check, "fail", "from %s rejected: %v", source, err)
+		return false
+	case ghgql.IsFineGrained(gql.Token):
+		c.add(check, "ok", "from %s is a fine-grained token for %s; its board access is checked below", source, login)
+	case !classic:
+		c.add(check, "warn", "from %s authenticates as %s; an App token's permissions can't be listed, so they aren't checked", source, login)
+	default:
+		want := []string{"repo", "read:org", "project"}
+		if readOnly {
+			want[2] = "read:project"
+		}
+		var missing []string
+		for _, s := range want {
+			if !hasScope(scopes, s) {
+				missing = append(missing, s)
+			}
+		}
+		if len(missing) > 0 {
+			c.add(check, "fail", "from %s (%s) lacks scope(s) %s (has %s)", source, login, strings.Join(missing, ", "), strings.Join(scopes, ", "))
+		} else {
+			c.add(check, "ok", "from %s authenticates as %s with %s", source, login, strings.Join(scopes, ", "))
+		}
+	}
+	return true
+}
+
+// checkMilestones checks that each milestone title exists in at least one
+// repo, warning about the repos missing it.
+func checkMilestones(c *checker, gql *ghgql.Client, repos, titles []string) {
+	for _, title := range titles {
+		var missing []string
+		for _, repo := range repos {
+			state, err := upstream.MilestoneState(gql, repo, title)
+			if err != nil {
+				c.add("milestone", "fail", "%s in %s: %v", title, repo, err)
+				continue
+			}
+			if state == "" {
+				missing = append(missing, repo)
+			}
+		}
+		reportMissing(c, "milestone", title, repos, missing)
+	}
+}
+
+// checkLabels checks that each label exists in at least one repo, warning
+// about the repos missing it.
+func checkLabels(c *checker, gql *ghgql.Client, repos, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	defined := make(map[string]map[string]bool) // repo → lowercased label names
+	for _, repo := range repos {
+		list, err := upstream.Labels(gql, repo)
+		if err != nil {
+			c.add("label", "fail", "listing labels in %s: %v", repo, err)
+			continue
+		}
+		defined[repo] = make(map[string]bool, len(list))
+		for _, l := range list {
+			defined[repo][strings.ToLower(l.Name)] = true
+		}
+	}
+	for _, name := range names {
+		var checked, missing []string
+		for _, repo := range repos {
+			if defined[repo] == nil {
+				continue
+			}
+			checked = append(checked, repo)
+			if !defined[repo][strings.ToLower(name)] { // GitHub label names are case-insensitive
+				missing = append(missing, repo)
+			}
+		}
+		reportMissing(c, "label", name, checked, missing)
+	}
+}
+
+// reportMissing adds the result for something that should exist in repos:
+// a failure when no repo has it, a warning when only some do.
+func reportMissing(c *checker, check, name string, repos, missing []string) {
+	switch {
+	case len(repos) == 0:
+	case len(missing) == len(repos):
+		c.add(check, "fail", "%q exists in none of %s", name, strings.Join(repos, ", "))
+	case len(missing) > 0:
+		c.add(check, "warn", "%q is missing from %s", name, strings.Join(missing, ", "))
+	default:
+		c.add(check, "ok", "%q", name)
+	}
+}
+
+// appendNew appends the values not already in list.
+func appendNew(list []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// exitCheck prints the results and exits, 1 if any check failed. hint, if
+// set, explains why the checks stopped early.
+func exitCheck(c *checker, hint string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tSTATUS\tDETAIL")
+	for _, r := range c.results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.check, r.status, items.Truncate(r.detail, 120))
+	}
+	w.Flush()
+	fmt.Println()
+	if hint != "" {
+		fmt.Println("Stopped early: " + hint + ".")
+	}
+	if n := c.failed(); n > 0 {
+		fmt.Printf("%d problem(s) found\n", n)
+		exit(statusError)
+	}
+	fmt.Println("No problems found")
+	exit(statusOK)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/cache"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// collaboratorsKeep is how many collaborator records are kept per board.
+const collaboratorsKeep = 5
+
+// collaboratorPolicy builds the destination board's collaborator list from
+// --admin-team, --read-team, and --collaborators. Later entries override
+// earlier ones for the same login, so --collaborators can make exceptions to
+// the team defaults.
+func collaboratorPolicy(adminTeams, readTeams, collaborators string) ([]board.Collaborator, error) {
+	var list []board.Collaborator
+	for _, team := range splitList(adminTeams) {
+		list = append(list, board.Collaborator{Login: team, Role: "ADMIN"})
+	}
+	for _, team := range splitList(readTeams) {
+		list = append(list, board.Collaborator{Login: team, Role: "READER"})
+	}
+	for _, c := range list {
+		if !c.IsTeam() {
+			return nil, fmt.Errorf("team %q must be org/team-slug", c.Login)
+		}
+	}
+	extra, err := board.ParseCollaborators(collaborators)
+	if err != nil {
+		return nil, err
+	}
+	list = append(list, extra...)
+
+	index := make(map[string]int)
+	var out []board.Collaborator
+	for _, c := range list {
+		key := strings.ToLower(c.Login)
+		if i, ok := index[key]; ok {
+			out[i] = c
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// reconcileCollaborators finds the collaborators an earlier run granted that
+// the policy no longer covers, and reports them or, with
+// --prune-collaborators, revokes their access. It does not see the board's
+// actual collaborators: GitHub's API can't list them, so only grants recorded
+// in the cache directory are checked, and anyone added by hand has to be
+// reviewed in the board's settings.
+func (p *syncPlan) reconcileCollaborators(gql *ghgql.Client, projectID string) error {
+	prefix := cache.SafeString(fmt.Sprintf("collaborators_%s_%s_", *p.owner, *p.name))
+	previous, err := cache.ReadLatest[board.Collaborator](defaultCacheDir, prefix)
+	if err != nil {
+		slog.Warn("Could not read earlier collaborator grants", "err", err)
+	}
+
+	covered := make(map[string]bool)
+	var granted []board.Collaborator
+	for _, c := range p.collabs {
+		covered[strings.ToLower(c.Login)] = true
+		if c.Role != "NONE" {
+			granted = append(granted, c)
+		}
+	}
+	var stale []board.Collaborator
+	for _, c := range previous {
+		if !covered[strings.ToLower(c.Login)] && c.Role != "NONE" {
+			stale = append(stale, c)
+		}
+	}
+
+	var revokeErr error
+	if len(stale) > 0 {
+		names := make([]string, len(stale))
+		for i, c := range stale {
+			names[i] = fmt.Sprintf("%s (%s)", c.Login, strings.ToLower(c.Role))
+		}
+		if *p.pruneCollabs {
+			revoke := make([]board.Collaborator, len(stale))
+			for i, c := range stale {
+				revoke[i] = board.Collaborator{Login: c.Login, Role: "NONE"}
+			}
+			if _, err := board.SetCollaborators(gql, projectID, revoke); err != nil {
+				granted = append(granted, stale...) // still granted; try again next run
+				revokeErr = fmt.Errorf("revoking %s: %w", strings.Join(names, ", "), err)
+			} else {
+				slog.Info("Revoked board access no longer covered by policy", "collaborators", strings.Join(names, ", "))
+			}
+		} else {
+			granted = append(granted, stale...)
+			slog.Warn("Collaborators granted by earlier runs are not covered by policy — add them to the policy, or pass --prune-collaborators to revoke", "collaborators", strings.Join(names, ", "))
+		}
+	}
+
+	slog.Info("Checked only the collaborators earlier runs granted; review the board's Manage access settings for anyone added by hand", "board", *p.name)
+	cache.Write(defaultCacheDir, prefix+cache.Timestamp()+".json", granted)
+	if _, err := cache.Clean(defaultCacheDir, prefix, collaboratorsKeep); err != nil {
+		slog.Warn("Could not prune collaborator records", "err", err)
+	}
+	return revokeErr
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/cache"
+)
+
+func TestCollaboratorPolicy(t *testing.T) {
+	got, err := collaboratorPolicy("my-org/sig-auth-leads", "my-org/sig-auth", "My-Org/SIG-Auth=write, alice=read")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []board.Collaborator{
+		{Login: "my-org/sig-auth-leads", Role: "ADMIN"},
+		{Login: "My-Org/SIG-Auth", Role: "WRITER"},
+		{Login: "alice", Role: "READER"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collaboratorPolicy = %v, want %v", got, want)
+	}
+
+	if _, err := collaboratorPolicy("sig-auth-leads", "", ""); err == nil || !strings.Contains(err.Error(), "must be org/team-slug") {
+		t.Errorf("collaboratorPolicy(bare team) error = %v, want org/team-slug", err)
+	}
+}
+
+func TestReconcileCollaborators(t *testing.T) {
+	tests := []struct {
+		name        string
+		prune       bool
+		wantRevoked bool
+		wantRecord  []string // logins recorded as granted
+	}{
+		{name: "report only", wantRecord: []string{"alice", "bob"}},
+		{name: "prune", prune: true, wantRevoked: true, wantRecord: []string{"alice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			saved := defaultCacheDir
+			defaultCacheDir = dir
+			t.Cleanup(func() { defaultCacheDir = saved })
+
+			owner, name := "my-org", "SIG Auth"
+			prefix := cache.SafeString("collaborators_" + owner + "_" + name + "_")
+			earlier := []board.Collaborator{{Login: "alice", Role: "WRITER"}, {Login: "bob", Role: "READER"}, {Login: "carol", Role: "NONE"}}
+			cache.Write(dir, prefix+"2020-01-01T00-00-00.json", earlier)
+
+			var revoked []any
+			gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query     string         `json:"query"`
+					Variables map[string]any `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				switch {
+				case strings.Contains(req.Query, "user(login"):
+					io.WriteString(w, `{"data": {"user": {"id": "U_`+req.Variables["login"].(string)+`"}}}`)
+				case strings.Contains(req.Query, "updateProjectV2Collaborators"):
+					revoked = req.Variables["collaborators"].([]any)
+					io.WriteString(w, `{"data": {"updateProjectV2Collaborators": {"collaborators": {"totalCount": 1}}}}`)
+				default:
+					t.Errorf("unexpected query %s", req.Query)
+				}
+			})
+
+			prune := tt.prune
+			p := &syncPlan{
+				syncOptions: &syncOptions{owner: &owner, name: &name, pruneCollabs: &prune},
+				collabs:     []board.Collaborator{{Login: "alice", Role: "WRITER"}},
+			}
+			if err := p.reconcileCollaborators(gql, "PVT_1"); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantRevoked {
+				want := []any{map[string]any{"userId": "U_bob", "role": "NONE"}}
+				if !reflect.DeepEqual(revoked, want) {
+					t.Errorf("revoked %v, want %v", revoked, want)
+				}
+			} else if revoked != nil {
+				t.Errorf("revoked %v, want nothing", revoked)
+			}
+
+			matches, _ := filepath.Glob(filepath.Join(dir, prefix+"*.json"))
+			if len(matches) != 2 {
+				t.Fatalf("records = %v, want the earlier one and a new one", matches)
+			}
+			data, err := os.ReadFile(matches[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var record []board.Collaborator
+			if err := json.Unmarshal(data, &record); err != nil {
+				t.Fatal(err)
+			}
+			var logins []string
+			for _, c := range record {
+				logins = append(logins, c.Login)
+			}
+			if !reflect.DeepEqual(logins, tt.wantRecord) {
+				t.Errorf("recorded %v, want %v", logins, tt.wantRecord)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is a settings file passed with --config (or KUBE_BOARD_CONFIG),
+// so a board's setup can be version-controlled and shared instead of living
+// in someone's shell. Top-level keys are the settings kube-board reads from
+// environment variables, named as in `kube-board env` or as Action inputs
+// (dest-board-owner for GITHUB_DEST_BOARD_OWNER); a variable that is set
+// overrides the file. flags holds defaults for each subcommand's own flags,
+// which the command line overrides, and destinations fans sync-boards out
+// to several boards (see runDestinations), e.g.
+//
+//	dest-board-owner: my-org
+//	dest-board-name: SIG Auth
+//	source-boards: [kubernetes/projects/241]
+//	flags:
+//	  sync-boards:
+//	    sync: true
+//	    max-size: M
+//	  board:
+//	    rollover:
+//	      close-old: false
+//	destinations:
+//	  - name: SIG Auth Review
+//	    filter: item.type == "PullRequest"
+type configFile struct {
+	Settings     map[string]string
+	Flags        map[string]map[string]string // subcommand ("board rollover") → flag → value
+	Destinations []sigEntry                   // sync-boards flags per destination board
+}
+
+// configDestinations are the config file's destinations, if any.
+var configDestinations []sigEntry
+
+// envSource records the variables kube-board set itself and where their
+// values came from, "config", "flag", or "file", for `kube-board env`.
+var envSource = make(map[string]string)
+
+// configFlag removes the global --config flag from args and returns the
+// path it names, defaulting to KUBE_BOARD_CONFIG. Some subcommands have a
+// --config of their own (sync-sigs' and run-all's jobs file, slack-bot's
+// queries), so for them only a --config before the subcommand is global.
+func configFlag(args []string) (rest []string, path string) {
+	path = os.Getenv("KUBE_BOARD_CONFIG")
+	cmds := subcommands
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			if sc, ok := lookupSubcommand(cmds, arg); ok {
+				cmds = sc.commands
+				if fs := flagSetOf(sc); fs != nil && fs.Lookup("config") != nil {
+					rest = append(rest, args[i:]...)
+					break
+				}
+			}
+			rest = append(rest, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "config" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		path = value
+		os.Setenv("KUBE_BOARD_CONFIG", path) // for --show-config and kube-board env
+		envSource["KUBE_BOARD_CONFIG"] = "flag"
+	}
+	return rest, path
+}
+
+// applyConfig loads the config file at path, exports its settings to the
+// environment where the variable isn't already set, and returns args with
+// the file's flags for the subcommand args[0] (args[1] too for a group
+// such as board) inserted before the ones given, so the command line wins.
+// check-config, which checks a sync-boards configuration, gets
+// sync-boards' flags too.
+func applyConfig(path string, args []string) ([]string, error) {
+	cfg, err := loadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	configDestinations = cfg.Destinations
+	for name, value := range cfg.Settings {
+		if os.Getenv(name) == "" {
+			os.Setenv(name, value)
+			envSource[name] = "config"
+		}
+	}
+	if len(args) == 0 {
+		return args, nil
+	}
+	command, n := args[0], 1
+	if sc, ok := lookupSubcommand(subcommands, args[0]); ok && sc.commands != nil && len(args) > 1 {
+		command, n = args[0]+" "+args[1], 2
+	}
+	flags := make(map[string]string)
+	sources := []string{command}
+	if command == "check-config" {
+		sources = []string{"sync-boards", "check-config"}
+	}
+	for _, command := range sources {
+		for name, value := range cfg.Flags[command] {
+			flags[name] = value
+		}
+	}
+	names := make([]string, 0, len(flags))
+	configFileFlag = make(map[string]bool, len(flags))
+	for name := range flags {
+		names = append(names, name)
+		configFileFlag[name] = true
+	}
+	sort.Strings(names)
+	out := slices.Clone(args[:n])
+	for _, name := range names {
+		out = append(out, "--"+name+"="+flags[name])
+	}
+	return append(out, args[n:]...), nil
+}
+
+// loadConfigFile reads a YAML config file.
+func loadConfigFile(path string) (*configFile, error) {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return nil, fmt.Errorf("%s: TOML isn't supported; write the config file in YAML", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	cfg := &configFile{Settings: make(map[string]string), Flags: make(map[string]map[string]string)}
+	for key, value := range raw {
+		if key == "flags" {
+			commands, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: flags must map subcommands to their flags", path)
+			}
+			for command, v := range commands {
+				sc, ok := lookupSubcommand(subcommands, command)
+				if !ok {
+					return nil, fmt.Errorf("%s: flags: unknown subcommand %q", path, command)
+				}
+				if err := configFlags(cfg, sc, command, v); err != nil {
+					return nil, fmt.Errorf("%s: %w", path, err)
+				}
+			}
+			continue
+		}
+		if key == "destinations" {
+			if cfg.Destinations, err = parseDestinations(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			continue
+		}
+		ev, ok := lookupEnv(key)
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown setting %q (see kube-board env)", path, key)
+		}
+		if ev.secret {
+			return nil, fmt.Errorf("%s: %s is a secret; set it in the environment, not a file that may be committed", path, ev.name)
+		}
+		if ev.envOnly {
+			return nil, fmt.Errorf("%s: %s decides where the token comes from; set it in the environment", path, ev.name)
+		}
+		cfg.Settings[ev.name] = configValue(value, listSeparator(ev.name))
+	}
+	return cfg, nil
+}
+
+// parseDestinations converts the destinations list: each entry maps
+// sync-boards flag names to values and must name its board. The query is
+// shared, so entries can't set sources or searches.
+func parseDestinations(value any) ([]sigEntry, error) {
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("destinations must be a list")
+	}
+	var dests []sigEntry
+	seen := make(map[string]bool)
+	for i, v := range list {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("destination %d must map sync-boards flags to values", i+1)
+		}
+		d := sigEntry{Flags: make(map[string]string, len(m))}
+		for flagName, fv := range m {
+			if flagName == "source" || flagName == "search" {
+				return nil, fmt.Errorf("destination %d: --%s is shared by every destination; set it outside destinations", i+1, flagName)
+			}
+			d.Flags[flagName] = configValue(fv, listSeparator(flagName))
+		}
+		d.Name = d.Flags["name"]
+		if d.Name == "" {
+			return nil, fmt.Errorf("destination %d has no name", i+1)
+		}
+		key := d.Flags["owner"] + "/" + d.Name
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate destination %q", d.Name)
+		}
+		seen[key] = true
+		dests = append(dests, d)
+	}
+	return dests, nil
+}
+
+// lookupEnv finds the known variable key names: its own name or, in
+// lowercase with dashes, the name with or without the GITHUB_ prefix.
+func lookupEnv(key string) (envVar, bool) {
+	name := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+	for _, ev := range knownEnv {
+		if ev.name == name || ev.name == "GITHUB_"+name {
+			return ev, true
+		}
+	}
+	return envVar{}, false
+}
+
+// configFlags adds the flags value sets for sc, named command in the
+// file, to cfg. A group such as board maps its commands to their flags,
+// which are keyed "board rollover".
+func configFlags(cfg *configFile, sc subcommand, command string, value any) error {
+	fv, ok := value.(map[string]any)
+	if !ok && sc.commands != nil {
+		return fmt.Errorf("flags.%s must map %s commands to their flags", command, command)
+	}
+	if !ok {
+		return fmt.Errorf("flags.%s must map flag names to values", command)
+	}
+	if sc.commands != nil {
+		for name, v := range fv {
+			c, ok := lookupSubcommand(sc.commands, name)
+			if !ok {
+				return fmt.Errorf("flags.%s: unknown %s command %q", command, command, name)
+			}
+			if err := configFlags(cfg, c, command+" "+name, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	cfg.Flags[command] = make(map[string]string, len(fv))
+	for name, v := range fv {
+		cfg.Flags[command][name] = configValue(v, listSeparator(name))
+	}
+	return nil
+}
+
+// listSeparator is how a list value is joined for the variable or flag
+// name: searches and filter chains are semicolon-separated, since a search
+// or filter can contain commas.
+func listSeparator(name string) string {
+	switch name {
+	case "GITHUB_SOURCE_SEARCHES", "search", "filters", "repo-filter":
+		return ";"
+	}
+	return ","
+}
+
+// configValue renders a YAML value as the string a flag or variable
+// takes.
+func configValue(v any, sep string) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []any:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = configValue(e, sep)
+		}
+		return strings.Join(parts, sep)
+	case map[string]any: // key=value entries, e.g. repo-filter
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = k + "=" + configValue(v[k], sep)
+		}
+		return strings.Join(parts, sep)
+	}
+	return fmt.Sprint(v)
+}
+
+// Where the resolved configuration came from, for --show-config.
+var (
+	showConfig      bool            // --show-config was given
+	commandLineFlag map[string]bool // subcommand flags given on the command line
+	configFileFlag  map[string]bool // subcommand flags set by the config file
+)
+
+// showConfigFlag removes the global --show-config flag from args and
+// reports whether it was there.
+func showConfigFlag(args []string) (rest []string, show bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			return append(rest, args[i:]...), show
+		}
+		if arg == "--show-config" || arg == "-show-config" {
+			show = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, show
+}
+
+// flagNames returns the names of the flags in args.
+func flagNames(args []string) map[string]bool {
+	names := make(map[string]bool)
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") {
+			name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// parseFlags parses a subcommand's flags. With --show-config it prints the
+// resolved configuration instead of running the subcommand.
+func parseFlags(fs *flag.FlagSet, args []string) {
+	fs.Parse(args)
+	if showConfig {
+		printConfig(os.Stdout, fs)
+		exit(statusOK)
+	}
+}
+
+// printConfig prints every setting and fs's flags with their effective
+// values and where each came from, so it's clear which layer won: a flag on
+// the command line beats a variable, which beats the config file, which
+// beats the default. Secrets are redacted.
+func printConfig(out io.Writer, fs *flag.FlagSet) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+	envFlag := make(map[string]envVar)
+	for _, ev := range knownEnv {
+		value, source := ev.effective()
+		if ev.secret && value != "" {
+			value = "(redacted)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", ev.name, orDash(value), source)
+		if ev.flag != "" {
+			envFlag[strings.TrimPrefix(ev.flag, "--")] = ev
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "FLAG (%s)\tVALUE\tSOURCE\n", fs.Name())
+	fs.VisitAll(func(f *flag.Flag) {
+		value, source := f.Value.String(), "default"
+		switch ev, ok := envFlag[f.Name]; {
+		case commandLineFlag[f.Name]:
+			source = "flag"
+		case configFileFlag[f.Name]:
+			source = "config"
+		case ok && value != "":
+			_, source = ev.effective()
+			if source == "flag" {
+				source = "flag (" + ev.globalFlag() + ")"
+			}
+			if ev.secret {
+				value = "(redacted)"
+			}
+		}
+		fmt.Fprintf(w, "--%s\t%s\t%s\n", f.Name, orDash(value), source)
+	})
+	w.Flush()
+}
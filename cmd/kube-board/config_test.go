@@ -0,0 +1,236 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes content to a file named name in a temporary directory
+// and returns its path.
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := writeConfig(t, "sync.yaml", `
+dest-board-owner: my-org
+GITHUB_DEST_BOARD_NAME: SIG Auth
+source-boards: [kubernetes/projects/241, kubernetes/projects/242]
+source-searches:
+  - is:open label:sig/auth
+  - is:pr label:"sig/auth,kind/bug"
+max-retries: 3
+flags:
+  sync-boards:
+    sync: true
+    max-size: M
+    repo-filter:
+      kubernetes/kubernetes: item.state == "OPEN"
+      kubernetes/website: "true"
+destinations:
+  - name: SIG Auth Review
+    filter: item.type == "PullRequest"
+  - name: SIG Auth Triage
+    owner: other-org
+`)
+	cfg, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSettings := map[string]string{
+		"GITHUB_DEST_BOARD_OWNER": "my-org",
+		"GITHUB_DEST_BOARD_NAME":  "SIG Auth",
+		"GITHUB_SOURCE_BOARDS":    "kubernetes/projects/241,kubernetes/projects/242",
+		"GITHUB_SOURCE_SEARCHES":  `is:open label:sig/auth;is:pr label:"sig/auth,kind/bug"`,
+		"GITHUB_MAX_RETRIES":      "3",
+	}
+	if !reflect.DeepEqual(cfg.Settings, wantSettings) {
+		t.Errorf("settings = %v, want %v", cfg.Settings, wantSettings)
+	}
+	wantFlags := map[string]map[string]string{
+		"sync-boards": {
+			"sync":        "true",
+			"max-size":    "M",
+			"repo-filter": `kubernetes/kubernetes=item.state == "OPEN";kubernetes/website=true`,
+		},
+	}
+	if !reflect.DeepEqual(cfg.Flags, wantFlags) {
+		t.Errorf("flags = %v, want %v", cfg.Flags, wantFlags)
+	}
+	wantDests := []sigEntry{
+		{Name: "SIG Auth Review", Flags: map[string]string{"name": "SIG Auth Review", "filter": `item.type == "PullRequest"`}},
+		{Name: "SIG Auth Triage", Flags: map[string]string{"name": "SIG Auth Triage", "owner": "other-org"}},
+	}
+	if !reflect.DeepEqual(cfg.Destinations, wantDests) {
+		t.Errorf("destinations = %+v, want %+v", cfg.Destinations, wantDests)
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content, wantErr string
+	}{
+		{name: "toml", file: "sync.toml", content: `dest-board-owner = "my-org"`, wantErr: "TOML isn't supported"},
+		{name: "bad yaml", file: "sync.yaml", content: "dest-board-owner: [", wantErr: "parse "},
+		{name: "unknown setting", file: "sync.yaml", content: "dest-board-colour: red", wantErr: `unknown setting "dest-board-colour"`},
+		{name: "secret", file: "sync.yaml", content: "token: ghp_x", wantErr: "GITHUB_TOKEN is a secret"},
+		{name: "slack secret", file: "sync.yaml", content: "SLACK_SIGNING_SECRET: x", wantErr: "SLACK_SIGNING_SECRET is a secret"},
+		{name: "flags not a map", file: "sync.yaml", content: "flags: [sync-boards]", wantErr: "flags must map subcommands"},
+		{name: "unknown subcommand", file: "sync.yaml", content: "flags:\n  sync-everything: {}", wantErr: `unknown subcommand "sync-everything"`},
+		{name: "subcommand flags not a map", file: "sync.yaml", content: "flags:\n  items: true", wantErr: "flags.items must map flag names"},
+		{name: "group flags not a map", file: "sync.yaml", content: "flags:\n  board: true", wantErr: "flags.board must map board commands"},
+		{name: "unknown group command", file: "sync.yaml", content: "flags:\n  board:\n    teleport: {}", wantErr: `unknown board command "teleport"`},
+		{name: "group command flags not a map", file: "sync.yaml", content: "flags:\n  board:\n    rollover: true", wantErr: "flags.board rollover must map flag names"},
+		{name: "destinations not a list", file: "sync.yaml", content: "destinations: {name: x}", wantErr: "destinations must be a list"},
+		{name: "destination without name", file: "sync.yaml", content: "destinations:\n  - filter: 'true'", wantErr: "destination 1 has no name"},
+		{name: "destination with a source", file: "sync.yaml", content: "destinations:\n  - name: x\n    source: a/projects/1", wantErr: "--source is shared by every destination"},
+		{name: "duplicate destination", file: "sync.yaml", content: "destinations:\n  - name: x\n  - name: x", wantErr: `duplicate destination "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfigFile(writeConfig(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadConfigFile error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := loadConfigFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "read config") {
+		t.Errorf("missing file error = %v, want a read error", err)
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	t.Setenv("GITHUB_DEST_BOARD_OWNER", "")
+	t.Setenv("GITHUB_DEST_BOARD_NAME", "From the environment")
+	path := writeConfig(t, "sync.yaml", `
+dest-board-owner: my-org
+dest-board-name: From the file
+flags:
+  sync-boards:
+    sync: true
+    max-size: M
+  check-config:
+    strict: true
+`)
+	defer func() {
+		delete(envSource, "GITHUB_DEST_BOARD_OWNER")
+		configFileFlag = nil
+		configDestinations = nil
+	}()
+
+	args, err := applyConfig(path, []string{"sync-boards", "--max-size", "L"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"sync-boards", "--max-size=M", "--sync=true", "--max-size", "L"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+	if got := os.Getenv("GITHUB_DEST_BOARD_OWNER"); got != "my-org" || envSource["GITHUB_DEST_BOARD_OWNER"] != "config" {
+		t.Errorf("GITHUB_DEST_BOARD_OWNER = %q from %q, want my-org from config", got, envSource["GITHUB_DEST_BOARD_OWNER"])
+	}
+	if got := os.Getenv("GITHUB_DEST_BOARD_NAME"); got != "From the environment" {
+		t.Errorf("GITHUB_DEST_BOARD_NAME = %q, want the environment to win", got)
+	}
+
+	args, err = applyConfig(path, []string{"check-config"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"check-config", "--max-size=M", "--strict=true", "--sync=true"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("check-config args = %q, want %q", args, want)
+	}
+}
+
+func TestApplyConfigGroup(t *testing.T) {
+	path := writeConfig(t, "board.yaml", `
+flags:
+  board:
+    rollover:
+      close-old: false
+    inspect:
+      limit: 5
+`)
+	defer func() {
+		configFileFlag = nil
+		configDestinations = nil
+	}()
+
+	args, err := applyConfig(path, []string{"board", "rollover", "--to", "v1.37"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"board", "rollover", "--close-old=false", "--to", "v1.37"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+
+	// Without a command, the group prints its usage; nothing is inserted.
+	args, err = applyConfig(path, []string{"board"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"board"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %q, want %q", args, want)
+	}
+}
+
+func TestConfigValue(t *testing.T) {
+	tests := []struct {
+		v    any
+		sep  string
+		want string
+	}{
+		{v: nil, sep: ",", want: ""},
+		{v: "x", sep: ",", want: "x"},
+		{v: 3, sep: ",", want: "3"},
+		{v: true, sep: ",", want: "true"},
+		{v: []any{"a", "b"}, sep: ",", want: "a,b"},
+		{v: []any{"a", "b"}, sep: ";", want: "a;b"},
+		{v: map[string]any{"b/b": "2", "a/a": true}, sep: ";", want: "a/a=true;b/b=2"},
+	}
+	for _, tt := range tests {
+		if got := configValue(tt.v, tt.sep); got != tt.want {
+			t.Errorf("configValue(%v, %q) = %q, want %q", tt.v, tt.sep, got, tt.want)
+		}
+	}
+}
+
+func TestConfigFlag(t *testing.T) {
+	t.Setenv("KUBE_BOARD_CONFIG", "default.yaml")
+	defer delete(envSource, "KUBE_BOARD_CONFIG")
+	tests := []struct {
+		args     []string
+		wantRest []string
+		wantPath string
+	}{
+		{args: []string{"items"}, wantRest: []string{"items"}, wantPath: "default.yaml"},
+		{args: []string{"--config", "a.yaml", "items"}, wantRest: []string{"items"}, wantPath: "a.yaml"},
+		{args: []string{"items", "--config=b.yaml", "-n", "5"}, wantRest: []string{"items", "-n", "5"}, wantPath: "b.yaml"},
+		{args: []string{"sync-sigs", "--config", "jobs.yaml"}, wantRest: []string{"sync-sigs", "--config", "jobs.yaml"}, wantPath: "default.yaml"},
+		{args: []string{"-config", "c.yaml", "run-all", "--config", "jobs.yaml"}, wantRest: []string{"run-all", "--config", "jobs.yaml"}, wantPath: "c.yaml"},
+		{args: []string{"slack-bot", "--config", "queries.yaml"}, wantRest: []string{"slack-bot", "--config", "queries.yaml"}, wantPath: "default.yaml"},
+		{args: []string{"board", "inspect", "--config", "d.yaml"}, wantRest: []string{"board", "inspect"}, wantPath: "d.yaml"},
+		{args: []string{"items", "--", "--config", "x"}, wantRest: []string{"items", "--", "--config", "x"}, wantPath: "default.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			os.Setenv("KUBE_BOARD_CONFIG", "default.yaml")
+			rest, path := configFlag(tt.args)
+			if !reflect.DeepEqual(rest, tt.wantRest) || path != tt.wantPath {
+				t.Errorf("configFlag = %q, %q; want %q, %q", rest, path, tt.wantRest, tt.wantPath)
+			}
+		})
+	}
+}
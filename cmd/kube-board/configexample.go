@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// configCommands are the `kube-board config` commands.
+var configCommands = []subcommand{
+	{"example", "Print a commented sample configuration file covering every setting and flag", runConfigExample, nil, nil},
+}
+
+// runConfig implements `kube-board config <name>`.
+func runConfig(args []string) {
+	dispatch("config", "Commands", configCommands, args)
+}
+
+// runConfigExample implements `kube-board config example`: print a sample
+// configuration file with every setting and every subcommand's flags,
+// commented out at their defaults. It is generated from knownEnv and the
+// subcommands' own flag sets, so it can't drift from the code.
+func runConfigExample(args []string) {
+	fs := flag.NewFlagSet("config example", flag.ExitOnError)
+	parseFlags(fs, args)
+	writeConfigExample(os.Stdout)
+}
+
+// writeConfigExample writes the sample configuration to w.
+func writeConfigExample(w io.Writer) {
+	fmt.Fprintln(w, "# kube-board configuration, from `kube-board config example`.")
+	fmt.Fprintln(w, "#")
+	fmt.Fprintln(w, "# Use it with `kube-board --config kube-board.yaml <subcommand>` or")
+	fmt.Fprintln(w, "# KUBE_BOARD_CONFIG=kube-board.yaml. Everything is commented out at its")
+	fmt.Fprintln(w, "# default; uncomment what you need. Environment variables override the file,")
+	fmt.Fprintln(w, "# and flags on the command line override both.")
+	fmt.Fprintln(w)
+
+	envFlag := make(map[string]string) // flag name → setting key
+	var secrets []string
+	for _, ev := range knownEnv {
+		key := strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(ev.name, "GITHUB_"), "_", "-"))
+		if ev.flag != "" {
+			envFlag[strings.TrimPrefix(ev.flag, "--")] = key
+		}
+		if ev.secret || ev.envOnly {
+			secrets = append(secrets, ev.name)
+			continue
+		}
+		if ev.name == "KUBE_BOARD_CONFIG" {
+			continue
+		}
+		fmt.Fprintf(w, "# %s (%s)\n", ev.usage, ev.name)
+		value := strconv.Quote(ev.def)
+		if ev.boolean {
+			value = "false"
+		}
+		fmt.Fprintf(w, "# %s: %s\n", key, value)
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "# Secrets and token sources are refused here; keep them in the environment: %s.\n", strings.Join(secrets, ", "))
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "# Defaults for each subcommand's flags. Flags set by a setting above")
+	fmt.Fprintln(w, "# (e.g. --owner by dest-board-owner) are left out, as are check-config's")
+	fmt.Fprintln(w, "# sync-boards flags, which it reads from the sync-boards section.")
+	fmt.Fprintln(w, "# flags:")
+	sync, _ := lookupSubcommand(subcommands, "sync-boards")
+	syncFlags := flagSetOf(sync)
+	for _, sc := range subcommands {
+		skip := envFlag
+		if sc.name == "check-config" {
+			skip = maps.Clone(envFlag)
+			syncFlags.VisitAll(func(f *flag.Flag) { skip[f.Name] = "" })
+		}
+		writeExampleFlags(w, sc, "#   ", skip)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "# Feed several boards from one sync-boards query. Each entry takes sync-boards")
+	fmt.Fprintln(w, "# flags; name is required, and filter is combined with the shared --filter.")
+	fmt.Fprintln(w, "# destinations:")
+	fmt.Fprintln(w, "#   - name: SIG Auth Review")
+	fmt.Fprintln(w, "#     filter: item.type == \"PullRequest\"")
+	fmt.Fprintln(w, "#   - name: SIG Auth Triage")
+	fmt.Fprintln(w, "#     filter: item.type == \"Issue\"")
+}
+
+// writeExampleFlags writes the flags of sc, or of each command in its group,
+// other than those in skip, as an entry of the sample's flags section
+// indented by indent. It writes nothing for a command with no such flags.
+func writeExampleFlags(w io.Writer, sc subcommand, indent string, skip map[string]string) {
+	if sc.commands != nil {
+		var buf strings.Builder
+		for _, c := range sc.commands {
+			writeExampleFlags(&buf, c, indent+"  ", skip)
+		}
+		if buf.Len() > 0 {
+			fmt.Fprintf(w, "%s%s:\n%s", indent, sc.name, buf.String())
+		}
+		return
+	}
+	fs := flagSetOf(sc)
+	if fs == nil {
+		return
+	}
+	var lines []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, ok := skip[f.Name]; ok {
+			return
+		}
+		lines = append(lines, fmt.Sprintf("%s  # %s", indent, f.Usage), fmt.Sprintf("%s  %s: %s", indent, f.Name, exampleValue(f)))
+	})
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s%s:\n", indent, sc.name)
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+// exampleValue renders f's default as a YAML value.
+func exampleValue(f *flag.Flag) string {
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return f.DefValue
+	}
+	if _, err := strconv.ParseFloat(f.DefValue, 64); err == nil {
+		return f.DefValue
+	}
+	return strconv.Quote(f.DefValue)
+}
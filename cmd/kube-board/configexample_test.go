@@ -0,0 +1,76 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFlagSetOf(t *testing.T) {
+	var check func(prefix string, cmds []subcommand)
+	check = func(prefix string, cmds []subcommand) {
+		for _, sc := range cmds {
+			name := strings.TrimSpace(prefix + " " + sc.name)
+			if sc.commands != nil {
+				if sc.flags != nil {
+					t.Errorf("%s: a group has no flags of its own", name)
+				}
+				check(name, sc.commands)
+				continue
+			}
+			// Registering a flag twice panics, so this also catches a
+			// command whose flags collide with a shared set's.
+			fs := flagSetOf(sc)
+			if fs == nil {
+				if name != "config example" {
+					t.Errorf("%s: no flags registered", name)
+				}
+				continue
+			}
+			if fs.Name() != sc.name {
+				t.Errorf("%s: flag set named %q", name, fs.Name())
+			}
+		}
+	}
+	check("", subcommands)
+}
+
+func TestWriteConfigExample(t *testing.T) {
+	var b strings.Builder
+	writeConfigExample(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		"#   sync-boards:\n",
+		"#   board:\n#     copy:\n",
+		"#     rollover:\n",
+		"#       close-old: true\n",
+		"#   report:\n#     release-notes:\n",
+		"#   labels:\n#     audit:\n",
+		"#   version:\n#     # Print only the version\n#     short: false\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("example is missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{
+		"#     owner:",  // set by dest-board-owner
+		"#   config:",   // config example has no flags
+		"#       name:", // set by dest-board-name
+	} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("example has %q", unwanted)
+		}
+	}
+
+	// check-config reads sync-boards' flags from its section, so only its
+	// own are listed under it.
+	section := out[strings.Index(out, "#   check-config:\n"):]
+	section = section[:regexp.MustCompile(`\n#   \S`).FindStringIndex(section)[0]]
+	if strings.Contains(section, "conflict-policy:") {
+		t.Errorf("check-config section repeats sync-boards flags:\n%s", section)
+	}
+	if !strings.Contains(section, "read-only: false") {
+		t.Errorf("check-config section is missing --read-only:\n%s", section)
+	}
+}
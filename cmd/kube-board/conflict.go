@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// Conflict policies for an item found on more than one source board.
+const (
+	policyFirstWins    = "first-wins"           // first board in --source order
+	policyMostAdvanced = "most-advanced-status" // furthest along --status-order
+	policyPreferSource = "project-priority"     // first board in --prefer-source order
+)
+
+// defaultStatusOrder ranks the usual Projects V2 columns, least advanced first.
+const defaultStatusOrder = "Backlog,Todo,Ready,In progress,In review,Done"
+
+// conflictPolicy picks which source board's copy of a duplicated item is
+// mirrored.
+type conflictPolicy struct {
+	policy      string
+	statusField string             // field ranked by most-advanced-status
+	order       []string           // statuses, least advanced first
+	prefer      []boardsync.Source // boards in preference order for project-priority
+}
+
+func newConflictPolicy(policy, statusField, order, prefer string) (conflictPolicy, error) {
+	c := conflictPolicy{policy: policy, statusField: statusField, order: splitList(order)}
+	switch policy {
+	case policyFirstWins:
+	case policyMostAdvanced:
+		if len(c.order) == 0 {
+			return c, fmt.Errorf("%s needs --status-order", policy)
+		}
+	case policyPreferSource:
+		for _, s := range splitList(prefer) {
+			ref, err := boardsync.ParseSource(s)
+			if err != nil {
+				return c, fmt.Errorf("--prefer-source: %w", err)
+			}
+			c.prefer = append(c.prefer, ref)
+		}
+		if len(c.prefer) == 0 {
+			return c, fmt.Errorf("%s needs --prefer-source", policy)
+		}
+	default:
+		return c, fmt.Errorf("unknown policy %q (want %s, %s, or %s)", policy, policyFirstWins, policyMostAdvanced, policyPreferSource)
+	}
+	return c, nil
+}
+
+// resolve returns the copy to keep. copies are in --source order, and ties
+// under every policy go to the earliest.
+func (c conflictPolicy) resolve(copies []boardsync.Copy) boardsync.Copy {
+	var rank func(boardsync.Copy) int // higher wins
+	switch c.policy {
+	case policyMostAdvanced:
+		rank = func(sc boardsync.Copy) int { return c.statusRank(sc.Item.Fields[c.statusField]) }
+	case policyPreferSource:
+		rank = func(sc boardsync.Copy) int {
+			for i, ref := range c.prefer {
+				if ref == sc.Source {
+					return len(c.prefer) - i
+				}
+			}
+			return 0
+		}
+	default:
+		return copies[0]
+	}
+	sorted := append([]boardsync.Copy(nil), copies...)
+	sort.SliceStable(sorted, func(i, j int) bool { return rank(sorted[i]) > rank(sorted[j]) })
+	return sorted[0]
+}
+
+// statusRank is status's position in the order, preferring an exact
+// (case-insensitive) match over a substring one; -1 when unranked.
+func (c conflictPolicy) statusRank(status string) int {
+	if status == "" {
+		return -1
+	}
+	for i, s := range c.order {
+		if strings.EqualFold(status, s) {
+			return i
+		}
+	}
+	for i := len(c.order) - 1; i >= 0; i-- {
+		if items.StatusMatches(status, c.order[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// fieldConflicts lists the fields set on both a and b with different values,
+// formatted for a log line.
+func fieldConflicts(a, b board.ProjectItemWithFields) []string {
+	var out []string
+	for name, av := range a.Fields {
+		if bv, ok := b.Fields[name]; ok && av != "" && bv != "" && av != bv {
+			out = append(out, fmt.Sprintf("%s=%q vs %q", name, av, bv))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// logConflict reports a duplicated item whose copies disagree, and which copy
+// the policy kept.
+func (c conflictPolicy) logConflict(kept boardsync.Copy, copies []boardsync.Copy) bool {
+	conflicted := false
+	for _, other := range copies {
+		if other.Source == kept.Source {
+			continue
+		}
+		diffs := fieldConflicts(kept.Item, other.Item)
+		if len(diffs) == 0 {
+			continue
+		}
+		conflicted = true
+		slog.Warn("Conflict: item differs between source boards",
+			"item", itemRef(kept.Item), "kept", kept.Source, "other", other.Source, "fields", strings.Join(diffs, ", "), "policy", c.policy)
+	}
+	return conflicted
+}
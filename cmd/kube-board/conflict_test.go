@@ -0,0 +1,119 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+)
+
+func TestNewConflictPolicy(t *testing.T) {
+	tests := []struct {
+		name, policy, order, prefer string
+		wantErr                     string
+	}{
+		{name: "first-wins", policy: policyFirstWins},
+		{name: "most-advanced", policy: policyMostAdvanced, order: defaultStatusOrder},
+		{name: "most-advanced without order", policy: policyMostAdvanced, wantErr: "needs --status-order"},
+		{name: "project-priority", policy: policyPreferSource, prefer: "a/projects/1, b/projects/2"},
+		{name: "project-priority without boards", policy: policyPreferSource, wantErr: "needs --prefer-source"},
+		{name: "project-priority bad board", policy: policyPreferSource, prefer: "a", wantErr: "--prefer-source: invalid board"},
+		{name: "unknown", policy: "newest", wantErr: `unknown policy "newest"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newConflictPolicy(tt.policy, "Status", tt.order, tt.prefer)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("newConflictPolicy: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("newConflictPolicy error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConflictPolicyResolve(t *testing.T) {
+	a := boardsync.Source{Owner: "a", Number: 1}
+	b := boardsync.Source{Owner: "b", Number: 2}
+	c := boardsync.Source{Owner: "c", Number: 3}
+	copyOn := func(src boardsync.Source, status string) boardsync.Copy {
+		return boardsync.Copy{Source: src, Item: board.ProjectItemWithFields{Fields: map[string]string{"Status": status}}}
+	}
+
+	tests := []struct {
+		name, policy, prefer string
+		copies               []boardsync.Copy
+		want                 boardsync.Source
+	}{
+		{
+			name:   "first-wins keeps the first board",
+			policy: policyFirstWins,
+			copies: []boardsync.Copy{copyOn(a, "Todo"), copyOn(b, "Done")},
+			want:   a,
+		},
+		{
+			name:   "most-advanced keeps the furthest status",
+			policy: policyMostAdvanced,
+			copies: []boardsync.Copy{copyOn(a, "Todo"), copyOn(b, "Done"), copyOn(c, "In review")},
+			want:   b,
+		},
+		{
+			name:   "most-advanced matches decorated statuses",
+			policy: policyMostAdvanced,
+			copies: []boardsync.Copy{copyOn(a, "Todo"), copyOn(b, "👀 In review")},
+			want:   b,
+		},
+		{
+			name:   "most-advanced ranks unset and unknown last",
+			policy: policyMostAdvanced,
+			copies: []boardsync.Copy{copyOn(a, ""), copyOn(b, "Someday"), copyOn(c, "Backlog")},
+			want:   c,
+		},
+		{
+			name:   "most-advanced ties go to the earliest",
+			policy: policyMostAdvanced,
+			copies: []boardsync.Copy{copyOn(a, "Done"), copyOn(b, "done")},
+			want:   a,
+		},
+		{
+			name:   "project-priority follows --prefer-source",
+			policy: policyPreferSource,
+			prefer: "c/projects/3,b/projects/2",
+			copies: []boardsync.Copy{copyOn(a, "Done"), copyOn(b, "Todo"), copyOn(c, "Todo")},
+			want:   c,
+		},
+		{
+			name:   "project-priority falls back to --source order",
+			policy: policyPreferSource,
+			prefer: "c/projects/3",
+			copies: []boardsync.Copy{copyOn(a, "Done"), copyOn(b, "Todo")},
+			want:   a,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newConflictPolicy(tt.policy, "Status", defaultStatusOrder, tt.prefer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.resolve(tt.copies).Source; got != tt.want {
+				t.Errorf("resolve kept %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldConflicts(t *testing.T) {
+	x := board.ProjectItemWithFields{Fields: map[string]string{"Status": "Todo", "Size": "M", "Owner": "", "Only": "x"}}
+	y := board.ProjectItemWithFields{Fields: map[string]string{"Status": "Done", "Size": "M", "Owner": "ann"}}
+	want := []string{`Status="Todo" vs "Done"`}
+	if got := fieldConflicts(x, y); !reflect.DeepEqual(got, want) {
+		t.Errorf("fieldConflicts = %q, want %q", got, want)
+	}
+}
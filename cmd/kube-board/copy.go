@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+// copyOptions holds the `kube-board board copy` flags.
+type copyOptions struct {
+	from      *int
+	fromOwner *string
+	owner     *string
+	name      *string
+	drafts    *bool
+	dryRun    *bool
+}
+
+// registerCopyFlags adds the board copy flags to fs.
+func registerCopyFlags(fs *flag.FlagSet) *copyOptions {
+	return &copyOptions{
+		from:      fs.Int("from", 0, "Number of the board to copy"),
+		fromOwner: fs.String("from-owner", "", "Owner of the board to copy (default: --owner)"),
+		owner:     fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Owner of the new board (user or org)"),
+		name:      fs.String("name", os.Getenv("GITHUB_DEST_BOARD_NAME"), "Title of the new board; may use {{.Milestone}}, {{.Org}}, and {{.Date}}, e.g. \"SIG Auth {{.Milestone}}\""),
+		drafts:    fs.Bool("drafts", false, "Also copy the board's draft items"),
+		dryRun:    fs.Bool("dry-run", false, "Print what would be copied without creating the board"),
+	}
+}
+
+// runCopy implements `kube-board board copy`: start a new board as a copy of
+// an existing one — last cycle's board, say — with its fields, views, and
+// workflows, and optionally its draft items, for the sync to populate.
+func runCopy(args []string) {
+	fs := flag.NewFlagSet("copy", flag.ExitOnError)
+	opts := registerCopyFlags(fs)
+	parseFlags(fs, args)
+
+	if *opts.from <= 0 {
+		fatal("--from is required: the number of the board to copy")
+	}
+	if *opts.owner == "" || *opts.name == "" {
+		fatal("board owner and title are required (--owner/--name or GITHUB_DEST_BOARD_OWNER/GITHUB_DEST_BOARD_NAME)")
+	}
+	if *opts.fromOwner == "" {
+		*opts.fromOwner = *opts.owner
+	}
+	title, err := expandBoardName(*opts.name, *opts.owner)
+	if err != nil {
+		fatalf("--name: %v", err)
+	}
+
+	gql := newClient()
+	src := openBoard(gql, *opts.fromOwner, *opts.from)
+	existing, err := board.FindProject(gql, *opts.owner, title)
+	if err != nil {
+		fatalf("Error looking for %q: %v", title, err)
+	}
+	if existing != nil {
+		fatalf("%s/%q already exists: %s", *opts.owner, title, existing.URL)
+	}
+
+	what := "fields, views, and workflows"
+	if *opts.drafts {
+		what = "fields, views, workflows, and draft items"
+	}
+	fmt.Printf("Copy %s (%s/projects/%d) to %s/%q: %s, %d field(s)\n", src.Title, *opts.fromOwner, *opts.from, *opts.owner, title, what, len(src.Fields))
+	if *opts.dryRun {
+		return
+	}
+
+	project, err := board.CopyTemplate(gql, board.Template{Owner: *opts.fromOwner, Number: *opts.from, IncludeDrafts: *opts.drafts}, *opts.owner, title)
+	if err != nil {
+		fatalf("Error copying project: %v", err)
+	}
+	slog.Info("Created project", "url", project.URL)
+	fmt.Printf("\nProject board: %s\n", project.URL)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ratelimit"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/version"
+)
+
+// daemonStatus is the state reported by /status. All fields are guarded by mu.
+type daemonStatus struct {
+	mu sync.Mutex
+
+	Version      string     `json:"version"`
+	StartedAt    time.Time  `json:"startedAt"`
+	Interval     string     `json:"interval"`
+	Syncs        int        `json:"syncs"`
+	Failures     int        `json:"failures"`
+	Running      bool       `json:"running"`
+	LastSyncAt   *time.Time `json:"lastSyncAt,omitempty"`    // when the last sync finished
+	LastSuccess  *time.Time `json:"lastSuccessAt,omitempty"` // when the last successful sync finished
+	LastError    string     `json:"-"`                       // served only to local clients; see handleStatus
+	ItemsManaged int        `json:"itemsManaged"`
+	RateLimit    *rateInfo  `json:"rateLimit,omitempty"`
+
+	interval time.Duration
+}
+
+type rateInfo struct {
+	Remaining int       `json:"remaining"`
+	Limit     int       `json:"limit"`
+	ResetAt   time.Time `json:"resetAt"`
+}
+
+// healthyIntervals is how many intervals may pass without a successful sync
+// before /healthz fails.
+const healthyIntervals = 3
+
+// healthy reports whether the daemon is keeping up: a sync has succeeded
+// within the last healthyIntervals intervals, or, before the first success,
+// the daemon started that recently. A first sync that hangs or keeps failing
+// turns it unhealthy just the same.
+func (s *daemonStatus) healthy(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	since := s.StartedAt
+	if s.LastSuccess != nil {
+		since = *s.LastSuccess
+	}
+	return now.Sub(since) < healthyIntervals*s.interval
+}
+
+func (s *daemonStatus) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if !s.healthy(time.Now()) {
+		http.Error(w, "no successful sync in the last three intervals", http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
+
+// handleStatus serves the status as JSON. The last error can name private
+// boards, repos, and items, so it is only included for requests from the
+// loopback interface, such as through `kubectl port-forward`.
+func (s *daemonStatus) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	var lastError string
+	if isLoopback(r.RemoteAddr) {
+		lastError = s.LastError
+	}
+	body, err := json.MarshalIndent(struct {
+		*daemonStatus
+		LastError string `json:"lastError,omitempty"`
+	}{s, lastError}, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
+
+// isLoopback reports whether addr, a request's "host:port" remote address,
+// is on the loopback interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// daemonOptions holds the `kube-board daemon` flags.
+type daemonOptions struct {
+	*syncOptions
+	interval *time.Duration
+	listen   *string
+}
+
+// registerDaemonFlags adds the daemon flags to fs.
+func registerDaemonFlags(fs *flag.FlagSet) *daemonOptions {
+	return &daemonOptions{
+		syncOptions: registerSyncFlags(fs),
+		interval:    fs.Duration("interval", 6*time.Hour, "Time between syncs"),
+		listen:      fs.String("listen", ":8080", "Address for the /healthz and /status endpoints"),
+	}
+}
+
+// runDaemon implements `kube-board daemon`: run sync-boards on an interval
+// and serve /healthz and /status so Kubernetes or systemd can supervise the
+// process.
+func runDaemon(args []string) {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	opts := registerDaemonFlags(fs)
+	parseFlags(fs, args)
+	p := opts.plan(true)
+	if *opts.interval < time.Minute {
+		fatalf("--interval must be at least 1m, got %s", *opts.interval)
+	}
+
+	s, token := newSyncer()
+	requireFeatures(s.WriteClient(), p)
+	requireProjectAccess(s.WriteClient(), p)
+	requireRepos(s.Client(), "--link-repos", splitList(*p.linkRepos))
+
+	status := &daemonStatus{
+		Version:   version.Get().Version,
+		StartedAt: time.Now(),
+		Interval:  opts.interval.String(),
+		interval:  *opts.interval,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", status.handleHealthz)
+	mux.HandleFunc("/status", status.handleStatus)
+	srv := &http.Server{Addr: *opts.listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		slog.Info("Serving /healthz and /status", "listen", *opts.listen)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatalf("Status server: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(*opts.interval)
+	defer ticker.Stop()
+	for {
+		daemonSync(ctx, p, s, token, status)
+		select {
+		case <-ctx.Done():
+			slog.Info("Shutting down")
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			srv.Shutdown(shutdownCtx)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// daemonSync runs one sync and records the outcome in status. Errors are
+// logged and reported rather than fatal so the daemon keeps running.
+func daemonSync(ctx context.Context, p *syncPlan, s *boardsync.Syncer, token string, status *daemonStatus) {
+	status.mu.Lock()
+	status.Running = true
+	status.mu.Unlock()
+
+	slog.Info("Starting sync")
+	list, _, err := p.run(ctx, s, token, "daemon", false)
+	rl, rlErr := ratelimit.FetchREST(token, clientOptions...)
+
+	now := time.Now()
+	status.mu.Lock()
+	defer status.mu.Unlock()
+	status.Running = false
+	status.Syncs++
+	status.LastSyncAt = &now
+	if err != nil {
+		status.Failures++
+		status.LastError = err.Error()
+		slog.Error("Sync failed", "err", err)
+	} else {
+		status.LastSuccess = &now
+		status.LastError = ""
+		status.ItemsManaged = len(list)
+		slog.Info("Sync complete", "count", len(list))
+	}
+	if rlErr == nil {
+		status.RateLimit = &rateInfo{Remaining: rl.GraphQL.Remaining, Limit: rl.GraphQL.Limit, ResetAt: rl.GraphQL.ResetAt}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDaemonHealthy(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { t := start.Add(d); return &t }
+	tests := []struct {
+		name        string
+		lastSync    *time.Time
+		lastSuccess *time.Time
+		now         time.Duration // since start
+		want        bool
+	}{
+		{name: "starting up", now: time.Hour, want: true},
+		{name: "first sync hangs", now: 3 * time.Hour, want: false},
+		{name: "first syncs fail", lastSync: at(150 * time.Minute), now: 3 * time.Hour, want: false},
+		{name: "recent success", lastSync: at(5 * time.Hour), lastSuccess: at(5 * time.Hour), now: 7 * time.Hour, want: true},
+		{name: "failing since the last success", lastSync: at(9 * time.Hour), lastSuccess: at(time.Hour), now: 10 * time.Hour, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &daemonStatus{StartedAt: start, interval: time.Hour, LastSyncAt: tt.lastSync, LastSuccess: tt.lastSuccess}
+			if got := s.healthy(start.Add(tt.now)); got != tt.want {
+				t.Errorf("healthy = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDaemonStatusLastError(t *testing.T) {
+	s := &daemonStatus{Version: "v1", Syncs: 2, Failures: 1, LastError: "adding my-org/secret#1: forbidden", interval: time.Hour}
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{remote: "127.0.0.1:50000", want: s.LastError},
+		{remote: "[::1]:50000", want: s.LastError},
+		{remote: "10.0.0.7:50000", want: ""},
+		{remote: "bogus", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/status", nil)
+			req.RemoteAddr = tt.remote
+			rec := httptest.NewRecorder()
+			s.handleStatus(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got["version"] != "v1" || got["syncs"] != 2.0 || got["failures"] != 1.0 {
+				t.Errorf("status = %s, want version, syncs, and failures", rec.Body)
+			}
+			lastError, ok := got["lastError"]
+			if tt.want == "" {
+				if ok {
+					t.Errorf("served lastError %q to %s, want none", lastError, tt.remote)
+				}
+			} else if lastError != tt.want {
+				t.Errorf("lastError = %v, want %q", lastError, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/upstream"
+)
+
+// doneCommentMarker is appended (as an HTML comment) to every completion
+// comment so later runs can tell the item was already handled.
+const doneCommentMarker = "<!-- kube-board:done -->"
+
+// doneOptions holds the `kube-board done` flags.
+type doneOptions struct {
+	owner       *string
+	number      *int
+	statusField *string
+	doneStatus  *string
+	closeIssues *bool
+	label       *string
+	comment     *string
+	dryRun      *bool
+	filters     *listFlags
+}
+
+// registerDoneFlags adds the done flags to fs.
+func registerDoneFlags(fs *flag.FlagSet) *doneOptions {
+	return &doneOptions{
+		owner:       fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number:      fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		statusField: fs.String("status-field", "Status", "Single-select field holding each item's column"),
+		doneStatus:  fs.String("done", "Done", "Status that marks an item complete (substring match, so \"Done\" matches \"✅ Done\")"),
+		closeIssues: fs.Bool("close", false, "Close open issues as completed (pull requests are left to merge)"),
+		label:       fs.String("label", "", "Add this existing label, e.g. \"release-note-needed\""),
+		comment:     fs.String("comment", "", "Post this comment, once per item"),
+		dryRun:      fs.Bool("dry-run", false, "List the actions without taking them"),
+		filters:     registerListFlags(fs),
+	}
+}
+
+// runDone implements `kube-board done`: for every board item in the Done
+// column, close, label, or comment on the issue/PR upstream. Nothing happens
+// unless at least one action is requested.
+func runDone(args []string) {
+	fs := flag.NewFlagSet("done", flag.ExitOnError)
+	opts := registerDoneFlags(fs)
+	parseFlags(fs, args)
+	opts.filters.validate()
+
+	if !*opts.closeIssues && *opts.label == "" && *opts.comment == "" {
+		fatal("no action requested: pass --close, --label, and/or --comment")
+	}
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+	var done []board.ProjectItemWithFields
+	for _, it := range fetchBoardItems(gql, project) {
+		if it.Type != "DraftIssue" && it.ContentID != "" && items.StatusMatches(it.Fields[*opts.statusField], *opts.doneStatus) {
+			done = append(done, it)
+		}
+	}
+	done, err := opts.filters.apply(done)
+	if err != nil {
+		fatal(err)
+	}
+	slog.Info("Found done items", "status", *opts.doneStatus, "count", len(done))
+
+	labeler := upstream.NewLabeler(gql)
+	acted, failed := 0, 0
+	for _, it := range done {
+		ref := itemRef(it)
+		var actions []func() error
+		var names []string
+		if *opts.comment != "" {
+			seen, err := upstream.HasComment(gql, it.ContentID, doneCommentMarker)
+			if err != nil {
+				slog.Error("Could not read comments", "item", ref, "err", err)
+				failed++
+				continue
+			}
+			if !seen {
+				names = append(names, "comment")
+				actions = append(actions, func() error {
+					return upstream.AddComment(gql, it.ContentID, *opts.comment+"\n\n"+doneCommentMarker)
+				})
+			}
+		}
+		if *opts.label != "" && !items.HasLabel(it, *opts.label) {
+			names = append(names, "label "+*opts.label)
+			actions = append(actions, func() error { return labeler.AddLabel(it.Repo, it.ContentID, *opts.label) })
+		}
+		if *opts.closeIssues && it.Type == "Issue" && it.State == "OPEN" {
+			names = append(names, "close")
+			actions = append(actions, func() error { return upstream.CloseIssue(gql, it.ContentID) })
+		}
+		if len(actions) == 0 {
+			continue
+		}
+
+		fmt.Printf("  %-40s %s\n", ref, strings.Join(names, ", "))
+		if *opts.dryRun {
+			continue
+		}
+		ok := true
+		for i, action := range actions {
+			if err := action(); err != nil {
+				slog.Error("Could not act on item", "item", ref, "action", names[i], "err", err)
+				ok = false
+				break
+			}
+		}
+		if ok {
+			acted++
+		} else {
+			failed++
+		}
+	}
+
+	if *opts.dryRun {
+		return
+	}
+	slog.Info("Acted on items", "count", acted, "failed", failed)
+	if failed > 0 {
+		exit(1)
+	}
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/logging"
+)
+
+// envVar describes one environment variable kube-board reads.
+type envVar struct {
+	name     string
+	flag     string // flag that overrides it, e.g. "--owner" ("" if none)
+	global   string // its global flag, when not derived from the name (see globalFlag)
+	def      string // default when unset ("" if none)
+	secret   bool   // mask the value when printing
+	envOnly  bool   // read only from the environment (see globalFlag)
+	boolean  bool   // its global flag may be given without a value
+	usage    string
+	validate func(v string) error // nil accepts any value
+}
+
+// knownEnv lists every environment variable kube-board reads, in the order
+// `kube-board env` prints them. Keep it in sync with the Environment
+// Variables table in the README.
+var knownEnv = []envVar{
+	{name: "GITHUB_TOKEN", secret: true, usage: "Classic PAT with read:org, read:project, repo, project; several, comma-separated, are used in turn as each one's GraphQL budget runs out", validate: validateToken},
+	{name: "GITHUB_WRITE_TOKEN", secret: true, usage: "Token for writing the destination board; the token above then only reads (sync-boards, sync-sigs, run-all, daemon)", validate: validateToken},
+	{name: "GITHUB_TOKEN_FILE", envOnly: true, usage: "Read the token from this file instead (e.g. a mounted secret)", validate: validateFile},
+	{name: "GITHUB_TOKEN_CMD", envOnly: true, usage: "Use the output of this shell command as the token, e.g. \"pass show github\""},
+	{name: "GITHUB_TOKEN_KEYCHAIN", envOnly: true, usage: "Read the token from the OS keychain item with this service name (macOS security, Linux secret-tool)"},
+	{name: "GH_TOKEN", secret: true, usage: "gh CLI token, used when none of the above is set (before gh's own login)", validate: validateToken},
+	{name: "GITHUB_DEST_BOARD_OWNER", flag: "--owner", usage: "User or org owning the destination board"},
+	{name: "GITHUB_DEST_BOARD_NAME", flag: "--name", usage: "Title of the destination board (sync-boards); may use {{.Milestone}}, {{.Org}}, and {{.Date}}"},
+	{name: "GITHUB_DEST_BOARD_VISIBILITY", flag: "--visibility", usage: "Visibility enforced on the destination board every run: private (default) or public (sync-boards)", validate: validateVisibility},
+	{name: "GITHUB_DEST_BOARD_DESCRIPTION", flag: "--description", usage: "Short description set on the destination board (sync-boards); may use {{.Milestone}}, {{.Org}}, {{.Date}}, and {{.Query}}"},
+	{name: "GITHUB_DEST_BOARD_README", flag: "--readme", usage: "README set on the destination board, or @file to read it from a file (sync-boards); may use the same as --description"},
+	{name: "GITHUB_DEST_BOARD_NUMBER", flag: "--number", usage: "Number of the board to read (items, rescue)", validate: validatePositiveInt},
+	{name: "GITHUB_DEST_TEMPLATE_PROJECT", flag: "--template", usage: "Template project copied to create a missing destination board, owner/N (sync-boards)", validate: validateBoard},
+	{name: "GITHUB_DEST_BOARD_COLLABORATORS", flag: "--collaborators", usage: "Users/teams to share the destination board with, login=role (sync-boards)", validate: validateCollaborators},
+	{name: "GITHUB_SOURCE_BOARDS", flag: "--source", usage: "Source boards to mirror, owner/projects/N (sync-boards)", validate: validateBoardList},
+	{name: "GITHUB_SOURCE_SEARCHES", flag: "--search", usage: "Issue/PR searches to mirror, semicolon-separated (sync-boards)"},
+	{name: "GITHUB_KUBERNETES_MILESTONE", usage: "Release milestone for {{.Milestone}} in board names, e.g. v1.36 (default: the earliest open vX.Y milestone in kubernetes/kubernetes)"},
+	{name: "GITHUB_LINK_REPOS", flag: "--link-repos", usage: "Repos to link to the destination board, owner/name (sync-boards)", validate: validateRepoList},
+	{name: "SLACK_WEBHOOK_URL", secret: true, usage: "Slack incoming webhook for --slack", validate: validateURL},
+	{name: "SLACK_SIGNING_SECRET", secret: true, usage: "Slack app signing secret for slack-bot"},
+	{name: "GITHUB_WEBHOOK_SECRET", secret: true, usage: "Webhook secret for webhook deliveries"},
+	{name: "GITHUB_SUMMARY_TITLES", boolean: true, usage: "true to list added/removed item titles in the GitHub Actions job summary (default counts only)"},
+	{name: "KUBE_BOARD_CONFIG", flag: "--config", usage: "YAML file of settings and per-subcommand flag defaults; the environment and command line override it", validate: validateFile},
+	{name: "GITHUB_API_URL", usage: "REST API base URL of a GitHub Enterprise Server instance, e.g. https://ghe.example.com/api/v3 (set by Actions)", validate: validateAnyURL},
+	{name: "GITHUB_GRAPHQL_URL", usage: "GraphQL API URL of a GitHub Enterprise Server instance (default derived from GITHUB_API_URL)", validate: validateAnyURL},
+	{name: "HTTPS_PROXY", secret: true, usage: "Proxy for GitHub requests, e.g. http://proxy.example.com:3128 (may hold credentials)", validate: validateAnyURL},
+	{name: "NO_PROXY", usage: "Comma-separated hosts to reach without the proxy"},
+	{name: "GITHUB_REQUEST_TIMEOUT", def: "2m", usage: "Give up on a GitHub API request with no complete response after this long, e.g. 30s (0 for no limit)", validate: validateDuration},
+	{name: "GITHUB_KEEP_ALIVE", def: "30s", usage: "TCP keep-alive interval of connections to GitHub (0 to turn keep-alives off)", validate: validateDuration},
+	{name: "GITHUB_IDLE_CONN_TIMEOUT", def: "90s", usage: "How long an idle connection to GitHub is kept for reuse (0 for no limit)", validate: validateDuration},
+	{name: "GITHUB_MAX_IDLE_CONNS", def: "4", usage: "Idle connections to GitHub kept for reuse; at least GITHUB_MAX_CONCURRENT avoids reconnecting", validate: validatePositiveInt},
+	{name: "GITHUB_MAX_RETRIES", def: "5", usage: "Retries of a GitHub API request after a rate limit or a transient failure (network error, 5xx), with jittered exponential backoff (0 to fail at once)", validate: validateNonNegativeInt},
+	{name: "GITHUB_MAX_CONCURRENT", def: "4", usage: "GitHub API requests in flight at once; callers beyond it wait, still paced and throttled as one", validate: validatePositiveInt},
+	{name: "GITHUB_WAIT_ON_RATE_LIMIT", boolean: true, usage: "true to wait out rate limits (Retry-After, or until the budget resets) for as long as it takes instead of failing after the retries above"},
+	{name: "GITHUB_SELECT_RATE_LIMIT", boolean: true, usage: "true to add a rateLimit selection to every GraphQL query, so each response reports its exact cost and the budget left, and points spent by anything else sharing the token are noticed as the run goes"},
+	{name: "GITHUB_DEBUG_GRAPHQL", usage: "Append every GitHub API request (query, variables, status, response, rate-limit cost) to this file, or - for stderr; tokens are masked, but board contents are not"},
+	{name: "GITHUB_RECORD", global: "--record-cassette", usage: "Record every GitHub API request and response to this cassette file, for --replay; it holds board contents, but no tokens"},
+	{name: "GITHUB_REPLAY", usage: "Answer GitHub API requests from this cassette file instead of GitHub, offline and without a token"},
+	{name: "LOG_LEVEL", flag: "--log-level", def: "info", usage: "Minimum log level: debug, info, warn, or error", validate: validateLogLevel},
+	{name: "LOG_FORMAT", flag: "--log-format", def: "plain", usage: "Log format: plain, text (slog key=value), or json"},
+	{name: "OTEL_EXPORTER_OTLP_ENDPOINT", usage: "OTLP/HTTP collector for tracing (e.g. http://localhost:4318); tracing is off when unset", validate: validateAnyURL},
+}
+
+// envOptions holds the `kube-board env` flags.
+type envOptions struct {
+	showUsage *bool
+	envFile   *string
+}
+
+// registerEnvFlags adds the env flags to fs.
+func registerEnvFlags(fs *flag.FlagSet) *envOptions {
+	opts := &envOptions{
+		showUsage: fs.Bool("usage", false, "Also print what each variable is for"),
+		envFile:   fs.String("env-file", "", "Also read KEY=VALUE lines (as in .env/kube-board.env) for variables the environment leaves unset"),
+	}
+	for _, ev := range knownEnv {
+		// Each variable's flag is accepted too, to show what giving it to
+		// another subcommand would do. Secrets stay off the command line.
+		if ev.flag == "" || ev.secret || fs.Lookup(strings.TrimPrefix(ev.flag, "--")) != nil {
+			continue
+		}
+		fs.Func(strings.TrimPrefix(ev.flag, "--"), "Override "+ev.name, func(v string) error {
+			envSource[ev.name] = "flag"
+			return os.Setenv(ev.name, v)
+		})
+	}
+	return opts
+}
+
+// runEnv implements `kube-board env`: print every recognized environment
+// variable with its effective value, where that value came from, and
+// whether it is valid.
+func runEnv(args []string) {
+	fs := flag.NewFlagSet("env", flag.ExitOnError)
+	opts := registerEnvFlags(fs)
+	parseFlags(fs, args)
+	if *opts.envFile != "" {
+		if err := loadEnvFile(*opts.envFile); err != nil {
+			fatalf("--env-file: %v", err)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "NAME\tVALUE\tSOURCE\tFLAG\tSTATUS"
+	if *opts.showUsage {
+		header += "\tUSAGE"
+	}
+	fmt.Fprintln(w, header)
+
+	invalid := 0
+	for _, ev := range knownEnv {
+		value, source := ev.effective()
+		status := "ok"
+		switch {
+		case value == "":
+			status = "unset"
+		case ev.validate != nil:
+			if err := ev.validate(value); err != nil {
+				status = "invalid: " + err.Error()
+				invalid++
+			}
+		}
+		shown := value
+		if ev.secret {
+			shown = maskSecret(value)
+		}
+		if shown == "" {
+			shown = "-"
+		}
+		flagName := ev.flag
+		if global := ev.globalFlag(); global != "" {
+			flagName = strings.TrimPrefix(flagName+", "+global, ", ")
+		}
+		if flagName == "" {
+			flagName = "-"
+		}
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s", ev.name, items.Truncate(shown, 60), source, flagName, status)
+		if *opts.showUsage {
+			line += "\t" + ev.usage
+		}
+		fmt.Fprintln(w, line)
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Println("A flag given on the command line overrides the variable it is listed against;")
+	fmt.Println("the long-named flags work with every subcommand.")
+	if invalid > 0 {
+		fmt.Printf("%d variable(s) have invalid values\n", invalid)
+		exit(1)
+	}
+}
+
+// globalFlag is the flag that sets the variable for any subcommand: its
+// name in lowercase with dashes, without the GITHUB_ prefix, as for config
+// file keys and Action inputs. Secrets have none, to keep them out of shell
+// history and process listings; nor do the token sources (envOnly), since
+// GITHUB_TOKEN_CMD runs its value as a shell command and must not be
+// settable by anything but whoever controls the environment; nor do
+// variables whose own flag already has that name. A variable whose derived
+// name a subcommand already uses for something else names its own, as
+// GITHUB_RECORD does since report agenda has a --record.
+func (ev envVar) globalFlag() string {
+	if ev.secret || ev.envOnly {
+		return ""
+	}
+	if ev.global != "" {
+		return ev.global
+	}
+	f := "--" + strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(ev.name, "GITHUB_"), "_", "-"))
+	if f == ev.flag {
+		return ""
+	}
+	return f
+}
+
+// envFlags removes global variable flags (see globalFlag) from args and
+// sets the variables they name, overriding the environment. Once args name
+// the subcommand, a flag it defines itself is left to it, even if a
+// variable's global flag has the same name.
+func envFlags(args []string) []string {
+	var rest []string
+	cmds := subcommands
+	var own *flag.FlagSet // the subcommand's flags, once it is named
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(rest, args[i:]...)
+		}
+		if !strings.HasPrefix(arg, "-") {
+			if sc, ok := lookupSubcommand(cmds, arg); ok {
+				cmds, own = sc.commands, flagSetOf(sc)
+			}
+			rest = append(rest, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		ev, ok := globalFlagVar(name)
+		if !ok || (own != nil && own.Lookup(strings.TrimLeft(name, "-")) != nil) {
+			rest = append(rest, arg)
+			continue
+		}
+		switch {
+		case hasValue:
+		case ev.boolean:
+			value = "true"
+		case i+1 < len(args):
+			i++
+			value = args[i]
+		}
+		os.Setenv(ev.name, value)
+		envSource[ev.name] = "flag"
+	}
+	return rest
+}
+
+// globalFlagVar finds the variable whose global flag is arg, accepting one
+// or two dashes and the GITHUB_ prefix spelled out.
+func globalFlagVar(arg string) (envVar, bool) {
+	if !strings.HasPrefix(arg, "-") {
+		return envVar{}, false
+	}
+	name := "--" + strings.TrimPrefix(strings.TrimLeft(arg, "-"), "github-")
+	for _, ev := range knownEnv {
+		if f := ev.globalFlag(); f != "" && f == name {
+			return ev, true
+		}
+	}
+	return envVar{}, false
+}
+
+// loadEnvFile sets the variables in a file of KEY=VALUE lines, with an
+// optional "export " and quotes as a shell would take them, that the
+// environment leaves unset. Blank lines and # comments are skipped.
+func loadEnvFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE", path, i+1)
+		}
+		name = strings.TrimSpace(name)
+		if v, err := strconv.Unquote(value); err == nil {
+			value = v
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		if v, set := os.LookupEnv(name); set && v != "" {
+			continue
+		}
+		os.Setenv(name, value)
+		envSource[name] = "file"
+	}
+	return nil
+}
+
+// effective returns the variable's value and where it came from: "env",
+// "flag", "file", "config", "default", or "unset".
+func (ev envVar) effective() (value, source string) {
+	if v, ok := os.LookupEnv(ev.name); ok && v != "" {
+		if s := envSource[ev.name]; s != "" {
+			return v, s
+		}
+		return v, "env"
+	}
+	if ev.def != "" {
+		return ev.def, "default"
+	}
+	return "", "unset"
+}
+
+// maskSecret hides all but the first and last four characters of long
+// values, and all of short ones.
+func maskSecret(v string) string {
+	if v == "" {
+		return ""
+	}
+	if len(v) <= 12 {
+		return "****"
+	}
+	return v[:4] + "****" + v[len(v)-4:]
+}
+
+func validateToken(v string) error {
+	tokens := ghgql.SplitTokens(v)
+	if len(tokens) == 0 {
+		return fmt.Errorf("empty")
+	}
+	for i, t := range tokens {
+		if strings.ContainsAny(t, " \t\n") {
+			if len(tokens) > 1 {
+				return fmt.Errorf("token %d contains whitespace", i+1)
+			}
+			return fmt.Errorf("contains whitespace")
+		}
+	}
+	return nil
+}
+
+func validateLogLevel(v string) error {
+	_, err := logging.ParseLevel(v)
+	return err
+}
+
+func validatePositiveInt(v string) error {
+	if n, err := strconv.Atoi(v); err != nil || n <= 0 {
+		return fmt.Errorf("not a positive number")
+	}
+	return nil
+}
+
+func validateNonNegativeInt(v string) error {
+	if n, err := strconv.Atoi(v); err != nil || n < 0 {
+		return fmt.Errorf("not a non-negative number")
+	}
+	return nil
+}
+
+func validateBoard(v string) error {
+	_, err := boardsync.ParseSource(v)
+	return err
+}
+
+func validateBoardList(v string) error {
+	for _, s := range splitList(v) {
+		if _, err := boardsync.ParseSource(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateRepoList(v string) error {
+	for _, s := range splitList(v) {
+		if parts := strings.Split(s, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid repo %q (expected owner/name)", s)
+		}
+	}
+	return nil
+}
+
+func validateCollaborators(v string) error {
+	_, err := board.ParseCollaborators(v)
+	return err
+}
+
+func validateURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("not an https URL")
+	}
+	return nil
+}
+
+func validateAnyURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("not a URL")
+	}
+	return nil
+}
+
+func validateVisibility(v string) error {
+	if v != board.Private && v != board.Public {
+		return fmt.Errorf("not private or public")
+	}
+	return nil
+}
+
+func validateDuration(v string) error {
+	if d, err := time.ParseDuration(v); err != nil || d < 0 {
+		return fmt.Errorf("not a duration such as 30s or 2m")
+	}
+	return nil
+}
+
+func validateFile(v string) error {
+	if _, err := os.Stat(v); err != nil {
+		return fmt.Errorf("no such file")
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGlobalFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "GITHUB_DEST_BOARD_OWNER", want: "--dest-board-owner"},
+		{name: "GITHUB_SUMMARY_TITLES", want: "--summary-titles"},
+		{name: "GITHUB_RECORD", want: "--record-cassette"},
+		{name: "LOG_LEVEL", want: ""}, // its own flag is --log-level
+		{name: "GITHUB_TOKEN", want: ""},
+		{name: "SLACK_WEBHOOK_URL", want: ""},
+		{name: "GITHUB_TOKEN_FILE", want: ""},
+		{name: "GITHUB_TOKEN_CMD", want: ""},
+		{name: "GITHUB_TOKEN_KEYCHAIN", want: ""},
+	}
+	for _, tt := range tests {
+		ev, ok := lookupEnv(tt.name)
+		if !ok {
+			t.Fatalf("%s is not a known variable", tt.name)
+		}
+		if got := ev.globalFlag(); got != tt.want {
+			t.Errorf("%s.globalFlag() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTokenSourcesAreEnvOnly(t *testing.T) {
+	for _, name := range []string{"GITHUB_TOKEN_FILE", "GITHUB_TOKEN_CMD", "GITHUB_TOKEN_KEYCHAIN"} {
+		t.Run(name, func(t *testing.T) {
+			key := strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(name, "GITHUB_"), "_", "-"))
+
+			if _, ok := globalFlagVar("--" + key); ok {
+				t.Errorf("--%s is accepted as a global flag", key)
+			}
+
+			path := writeConfig(t, "kube-board.yaml", key+": echo ghp_x\n")
+			if _, err := loadConfigFile(path); err == nil || !strings.Contains(err.Error(), name+" decides where the token comes from") {
+				t.Errorf("loadConfigFile error = %v, want %s refused", err, name)
+			}
+
+			t.Setenv(name, "")
+			t.Setenv("INPUT_"+strings.ToUpper(key), "echo ghp_x")
+			applyActionInputs()
+			if got := os.Getenv(name); got != "" {
+				t.Errorf("the %s input set %s=%q", key, name, got)
+			}
+		})
+	}
+}
+
+func TestEnvFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantEnv map[string]string // "" for unset
+	}{
+		{
+			name:    "before the subcommand",
+			args:    []string{"--dest-board-owner", "my-org", "sync-boards", "--dry-run"},
+			want:    []string{"sync-boards", "--dry-run"},
+			wantEnv: map[string]string{"GITHUB_DEST_BOARD_OWNER": "my-org"},
+		},
+		{
+			name:    "after the subcommand",
+			args:    []string{"items", "--github-dest-board-number=5", "--max-size", "M"},
+			want:    []string{"items", "--max-size", "M"},
+			wantEnv: map[string]string{"GITHUB_DEST_BOARD_NUMBER": "5"},
+		},
+		{
+			name:    "board visibility",
+			args:    []string{"--dest-board-visibility", "public", "sync-boards"},
+			want:    []string{"sync-boards"},
+			wantEnv: map[string]string{"GITHUB_DEST_BOARD_VISIBILITY": "public"},
+		},
+		{
+			name:    "boolean without a value",
+			args:    []string{"sync-boards", "--summary-titles", "--dry-run"},
+			want:    []string{"sync-boards", "--dry-run"},
+			wantEnv: map[string]string{"GITHUB_SUMMARY_TITLES": "true"},
+		},
+		{
+			name:    "cassette",
+			args:    []string{"--record-cassette", "/tmp/c.jsonl", "report", "agenda"},
+			want:    []string{"report", "agenda"},
+			wantEnv: map[string]string{"GITHUB_RECORD": "/tmp/c.jsonl"},
+		},
+		{
+			name:    "the subcommand's own flag",
+			args:    []string{"report", "agenda", "--record=false"},
+			want:    []string{"report", "agenda", "--record=false"},
+			wantEnv: map[string]string{"GITHUB_RECORD": ""},
+		},
+		{
+			name:    "after --",
+			args:    []string{"items", "--", "--dest-board-owner", "x"},
+			want:    []string{"items", "--", "--dest-board-owner", "x"},
+			wantEnv: map[string]string{"GITHUB_DEST_BOARD_OWNER": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name := range tt.wantEnv {
+				t.Setenv(name, "")
+				defer delete(envSource, name)
+			}
+			if got := envFlags(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("envFlags(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+			for name, want := range tt.wantEnv {
+				if got := os.Getenv(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+				if want != "" && envSource[name] != "flag" {
+					t.Errorf("%s source = %q, want flag", name, envSource[name])
+				}
+			}
+		})
+	}
+}
+
+func TestGlobalFlagsDontShadowSubcommandFlags(t *testing.T) {
+	// A flag a subcommand defines is its own, so a global flag of the same
+	// name couldn't be given after that subcommand's name. Name the global
+	// flag differently instead, as for GITHUB_RECORD.
+	var check func(prefix string, cmds []subcommand)
+	check = func(prefix string, cmds []subcommand) {
+		for _, sc := range cmds {
+			name := strings.TrimSpace(prefix + " " + sc.name)
+			check(name, sc.commands)
+			fs := flagSetOf(sc)
+			if fs == nil {
+				continue
+			}
+			for _, ev := range knownEnv {
+				f := strings.TrimPrefix(ev.globalFlag(), "--")
+				if f != "" && fs.Lookup(f) != nil {
+					t.Errorf("%s defines --%s, the global flag for %s", name, f, ev.name)
+				}
+			}
+		}
+	}
+	check("", subcommands)
+}
+
+func TestSecretsAreEnvOnly(t *testing.T) {
+	for _, ev := range knownEnv {
+		if ev.secret && ev.flag != "" {
+			t.Errorf("%s is a secret but has the flag %s", ev.name, ev.flag)
+		}
+	}
+
+	// The flags these secrets once had must not come back.
+	for _, tt := range []struct{ command, flag string }{
+		{"items", "slack-webhook"},
+		{"webhook", "secret"},
+		{"slack-bot", "signing-secret"},
+	} {
+		sc, ok := lookupSubcommand(subcommands, tt.command)
+		if !ok {
+			t.Fatalf("no %s subcommand", tt.command)
+		}
+		if flagSetOf(sc).Lookup(tt.flag) != nil {
+			t.Errorf("%s has a --%s flag", tt.command, tt.flag)
+		}
+	}
+}
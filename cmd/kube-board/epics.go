@@ -1,177 +1,26 @@
-// Command assign-epics is a one-off tool to populate the "Epic" field on
-// items in an Azure org project board.  It fetches all items, filters to
-// those with an empty Epic field, then heuristically assigns an Epic based
-// on the item's repository and title.
-//
-// Usage:
-//   source .env/sig-auth-search.azure.env
-//   go run ./cmd/assign-epics --dry-run          # preview assignments
-//   go run ./cmd/assign-epics                     # apply assignments
 package main
 
 import (
 	"flag"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
-	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	boarditems "github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
 )
 
-// ---------------------------------------------------------------------------
-// Item fetched from the board (project-level ID, content info, field values)
-// ---------------------------------------------------------------------------
-
-type boardItem struct {
-	ItemID string // project item ID — needed for mutations
-	Number int
-	Title  string
-	Repo   string            // "owner/name"
-	State  string            // OPEN, CLOSED, MERGED
-	Fields map[string]string // field name → value
-}
-
-// fetchAllItems fetches every item on the project, including the repository
-// nameWithOwner so we can use it for epic matching.
-func fetchAllItems(gql *ghgql.Client, projectID string) ([]boardItem, error) {
-	query := `query($projectId: ID!, $cursor: String) {
-		node(id: $projectId) {
-			... on ProjectV2 {
-				items(first: 100, after: $cursor) {
-					nodes {
-						id
-						fieldValues(first: 20) {
-							nodes {
-								... on ProjectV2ItemFieldSingleSelectValue {
-									name
-									field { ... on ProjectV2FieldCommon { name } }
-								}
-								... on ProjectV2ItemFieldTextValue {
-									text
-									field { ... on ProjectV2FieldCommon { name } }
-								}
-								... on ProjectV2ItemFieldDateValue {
-									date
-									field { ... on ProjectV2FieldCommon { name } }
-								}
-							}
-						}
-						content {
-							... on Issue {
-								number title state
-								repository { nameWithOwner }
-							}
-							... on PullRequest {
-								number title state
-								repository { nameWithOwner }
-							}
-						}
-					}
-					pageInfo { hasNextPage endCursor }
-				}
-			}
-		}
-	}`
-
-	var items []boardItem
-	var cursor *string
-
-	for {
-		vars := map[string]any{"projectId": projectID}
-		if cursor != nil {
-			vars["cursor"] = *cursor
-		}
-
-		var result struct {
-			Node struct {
-				Items struct {
-					Nodes []struct {
-						ID          string `json:"id"`
-						FieldValues struct {
-							Nodes []struct {
-								Name  string `json:"name,omitempty"`
-								Text  string `json:"text,omitempty"`
-								Date  string `json:"date,omitempty"`
-								Field struct {
-									Name string `json:"name"`
-								} `json:"field"`
-							} `json:"nodes"`
-						} `json:"fieldValues"`
-						Content struct {
-							Number     int    `json:"number"`
-							Title      string `json:"title"`
-							State      string `json:"state"`
-							Repository struct {
-								NameWithOwner string `json:"nameWithOwner"`
-							} `json:"repository"`
-						} `json:"content"`
-					} `json:"nodes"`
-					PageInfo struct {
-						HasNextPage bool   `json:"hasNextPage"`
-						EndCursor   string `json:"endCursor"`
-					} `json:"pageInfo"`
-				} `json:"items"`
-			} `json:"node"`
-		}
-
-		err := gql.Do(ghgql.Request{Query: query, Variables: vars}, &result)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, n := range result.Node.Items.Nodes {
-			fields := make(map[string]string)
-			for _, fv := range n.FieldValues.Nodes {
-				fn := fv.Field.Name
-				if fn == "" {
-					continue
-				}
-				switch {
-				case fv.Name != "":
-					fields[fn] = fv.Name
-				case fv.Text != "":
-					fields[fn] = fv.Text
-				case fv.Date != "":
-					fields[fn] = fv.Date
-				}
-			}
-			items = append(items, boardItem{
-				ItemID: n.ID,
-				Number: n.Content.Number,
-				Title:  n.Content.Title,
-				Repo:   n.Content.Repository.NameWithOwner,
-				State:  n.Content.State,
-				Fields: fields,
-			})
-		}
-
-		if !result.Node.Items.PageInfo.HasNextPage {
-			break
-		}
-		c := result.Node.Items.PageInfo.EndCursor
-		cursor = &c
-	}
-
-	return items, nil
-}
-
-// ---------------------------------------------------------------------------
-// Epic matching rules
-// ---------------------------------------------------------------------------
-
-// rule maps a condition (repo substring or title keyword) to an epic name.
-type rule struct {
+// epicRule maps a condition (repo substring or title keyword) to an epic name.
+type epicRule struct {
 	repoContains  string // match against repo (case-insensitive)
 	titleContains string // match against title (case-insensitive)
 	epic          string
 }
 
-// Rules are evaluated in order; first match wins.
+// epicRules are evaluated in order; first match wins.
 // More specific rules should come first.
-var rules = []rule{
+var epicRules = []epicRule{
 	// ---- Repo-based rules (most specific first) ----
 
 	// AKS Azure RBAC++
@@ -328,85 +177,13 @@ var rules = []rule{
 	{titleContains: "key rotation", epic: "KMS"},
 }
 
-// ensureEpicOption adds a single-select option to the Epic field if it doesn't
-// already exist. Returns the updated FieldDef.
-func ensureEpicOption(gql *ghgql.Client, fieldID string, epicField board.FieldDef, optionName string) (board.FieldDef, error) {
-	// Already exists?
-	if _, found := board.ResolveOptionID(epicField, optionName); found {
-		return epicField, nil
-	}
-
-	log.Printf("Adding missing Epic option %q to the board...", optionName)
-
-	// Build the full options list (existing + new)
-	colors := []string{"GRAY", "BLUE", "GREEN", "YELLOW", "ORANGE", "RED", "PINK", "PURPLE"}
-	var opts []map[string]any
-	for _, existing := range epicField.Options {
-		opts = append(opts, map[string]any{
-			"id":   existing.ID,
-			"name": existing.Name,
-		})
-	}
-	opts = append(opts, map[string]any{
-		"name":  optionName,
-		"color": colors[len(epicField.Options)%len(colors)],
-	})
-
-	mutation := `mutation($fieldId: ID!, $opts: [ProjectV2SingleSelectFieldOptionInput!]!) {
-		updateProjectV2Field(input: {
-			fieldId: $fieldId
-			singleSelectOptions: $opts
-		}) {
-			projectV2Field {
-				... on ProjectV2SingleSelectField {
-					id name
-					options { id name }
-				}
-			}
-		}
-	}`
-
-	var result struct {
-		UpdateProjectV2Field struct {
-			ProjectV2Field struct {
-				ID      string `json:"id"`
-				Name    string `json:"name"`
-				Options []struct {
-					ID   string `json:"id"`
-					Name string `json:"name"`
-				} `json:"options"`
-			} `json:"projectV2Field"`
-		} `json:"updateProjectV2Field"`
-	}
-
-	err := gql.Do(ghgql.Request{
-		Query:     mutation,
-		Variables: map[string]any{"fieldId": fieldID, "opts": opts},
-	}, &result)
-	if err != nil {
-		return epicField, fmt.Errorf("failed to add option %q: %w", optionName, err)
-	}
-
-	// Rebuild FieldDef from response
-	updated := board.FieldDef{
-		ID:   epicField.ID,
-		Name: epicField.Name,
-		Type: "SINGLE_SELECT",
-	}
-	for _, opt := range result.UpdateProjectV2Field.ProjectV2Field.Options {
-		updated.Options = append(updated.Options, board.FieldOption{ID: opt.ID, Name: opt.Name})
-	}
-	log.Printf("  Added option %q — field now has %d options", optionName, len(updated.Options))
-	return updated, nil
-}
-
 // matchEpic returns the best-guess epic for an item, or "-" if no rule matches.
 // The "-" value represents a catchall "no epic" bucket.
 func matchEpic(repo, title string) string {
 	lowerRepo := strings.ToLower(repo)
 	lowerTitle := strings.ToLower(title)
 
-	for _, r := range rules {
+	for _, r := range epicRules {
 		if r.repoContains != "" && strings.Contains(lowerRepo, r.repoContains) {
 			return r.epic
 		}
@@ -417,67 +194,67 @@ func matchEpic(repo, title string) string {
 	return "-"
 }
 
-// ---------------------------------------------------------------------------
-// main
-// ---------------------------------------------------------------------------
-
-func main() {
-	dryRun := flag.Bool("dry-run", false, "Preview assignments without writing to the board")
-	flag.Parse()
+// assignEpicsOptions holds the `kube-board assign-epics` flags.
+type assignEpicsOptions struct {
+	owner  *string
+	number *int
+	dryRun *bool
+}
 
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		log.Fatal("GITHUB_TOKEN is required — source your .env file first")
+// registerAssignEpicsFlags adds the assign-epics flags to fs. The rules
+// below are written for the Azure board, so it is the default.
+func registerAssignEpicsFlags(fs *flag.FlagSet) *assignEpicsOptions {
+	return &assignEpicsOptions{
+		owner:  fs.String("owner", "Azure", "Board owner (user or org)"),
+		number: fs.Int("number", 940, "Board number"),
+		dryRun: fs.Bool("dry-run", false, "Preview assignments without writing to the board"),
 	}
+}
 
-	org := "Azure"
-	projectNum := 940
+// runAssignEpics implements `kube-board assign-epics`: populate the "Epic"
+// field on board items that have none. It fetches all items, filters to
+// open, recently updated ones with an empty Epic field, then heuristically
+// assigns an Epic based on the item's repository and title.
+func runAssignEpics(args []string) {
+	fs := flag.NewFlagSet("assign-epics", flag.ExitOnError)
+	opts := registerAssignEpicsFlags(fs)
+	parseFlags(fs, args)
 
-	gql := ghgql.NewClient(token)
+	gql := newClient()
 
 	// 1. Find the project and get field definitions (including Epic option IDs).
-	log.Printf("Finding project %s/projects/%d ...", org, projectNum)
-	project, err := board.FindProjectByNumber(gql, org, projectNum)
-	if err != nil {
-		log.Fatalf("Could not find project: %v", err)
-	}
-	log.Printf("Found: %s (ID: %s)", project.Title, project.ID)
+	project := openBoard(gql, *opts.owner, *opts.number)
 
 	epicField, ok := project.Fields["Epic"]
 	if !ok {
-		log.Fatal("\"Epic\" field not found on the board")
+		fatal("\"Epic\" field not found on the board")
 	}
-	log.Printf("Epic field has %d options", len(epicField.Options))
+	slog.Info("Found Epic field", "count", len(epicField.Options))
 	for _, opt := range epicField.Options {
-		log.Printf("  %s  (ID: %s)", opt.Name, opt.ID)
+		slog.Debug("Epic option", "option", opt.Name, "id", opt.ID)
 	}
 
 	// 1b. Ensure any epics referenced by rules actually exist on the board.
 	// Collect unique epic names from rules.
 	epicNames := make(map[string]bool)
-	for _, r := range rules {
+	for _, r := range epicRules {
 		epicNames[r.epic] = true
 	}
 	for name := range epicNames {
 		if _, found := board.ResolveOptionID(epicField, name); !found {
-			epicField, err = ensureEpicOption(gql, epicField.ID, epicField, name)
-			if err != nil {
-				log.Fatalf("Could not create Epic option %q: %v", name, err)
+			var err error
+			if epicField, err = board.EnsureOption(gql, epicField, name); err != nil {
+				fatalf("Could not create Epic option %q: %v", name, err)
 			}
 		}
 	}
 
 	// 2. Fetch all items with their field values and repo info.
-	log.Println("Fetching all board items (this may take several pages)...")
-	items, err := fetchAllItems(gql, project.ID)
-	if err != nil {
-		log.Fatalf("Error fetching items: %v", err)
-	}
-	log.Printf("Fetched %d total items", len(items))
+	items := fetchBoardItems(gql, project)
 
 	// 3. Filter to items with empty Epic, excluding done/closed/merged/stale.
 	oneYearAgo := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
-	var needsEpic []boardItem
+	var needsEpic []board.ProjectItemWithFields
 	skippedDone, skippedState, skippedStale := 0, 0, 0
 	for _, item := range items {
 		if item.Fields["Epic"] != "" {
@@ -507,8 +284,7 @@ func main() {
 
 		needsEpic = append(needsEpic, item)
 	}
-	log.Printf("%d items need Epic (after filtering)", len(needsEpic))
-	log.Printf("  Skipped: %d done, %d closed/merged, %d stale (>1yr)", skippedDone, skippedState, skippedStale)
+	slog.Info("Found items needing an Epic", "count", len(needsEpic), "done", skippedDone, "closed", skippedState, "stale", skippedStale)
 
 	// 4. Match and (optionally) apply.
 	matched, unmatched := 0, 0
@@ -542,31 +318,31 @@ func main() {
 
 		optID, found := board.ResolveOptionID(epicField, epic)
 		if !found {
-			log.Printf("  WARNING: Epic %q is not a valid option on the board — skipping #%d", epic, item.Number)
+			slog.Warn("Epic is not a valid option on the board, skipping item", "epic", epic, "item", itemRef(item))
 			errors++
 			continue
 		}
 
-		if *dryRun {
-			log.Printf("  [DRY-RUN] #%-5d %-60s repo=%-40s → %s", item.Number, truncate(item.Title, 60), item.Repo, epic)
+		if *opts.dryRun {
+			slog.Info("[DRY-RUN] Would set Epic", "item", itemRef(item), "title", boarditems.Truncate(item.Title, 60), "epic", epic)
 		} else {
 			err := board.UpdateItemField(gql, project.ID, item.ItemID, epicField.ID, board.FieldValue{
 				SingleSelectOptionID: optID,
 			})
 			if err != nil {
-				log.Printf("  ERROR updating #%d: %v", item.Number, err)
+				slog.Error("Could not update item", "item", itemRef(item), "err", err)
 				errors++
 				continue
 			}
 			updated++
 			if updated%50 == 0 {
-				log.Printf("  ... updated %d/%d", updated, matched)
+				slog.Debug("Updating items", "updated", updated, "matched", matched)
 			}
 		}
 
 		// Progress
 		if (i+1)%100 == 0 {
-			log.Printf("  Processed %d/%d items needing epic...", i+1, len(needsEpic))
+			slog.Debug("Processing items needing an Epic", "processed", i+1, "count", len(needsEpic))
 		}
 	}
 
@@ -577,7 +353,7 @@ func main() {
 	fmt.Printf("  Items needing epic:       %d\n", len(needsEpic))
 	fmt.Printf("  Matched by rules:         %d\n", matched)
 	fmt.Printf("  Unmatched (no rule):      %d\n", unmatched)
-	if !*dryRun {
+	if !*opts.dryRun {
 		fmt.Printf("  Successfully updated:     %d\n", updated)
 		fmt.Printf("  Errors:                   %d\n", errors)
 	}
@@ -594,14 +370,7 @@ func main() {
 		fmt.Println()
 		fmt.Printf("  Unmatched items (%d) — add rules or assign manually:\n", len(unmatchedItems))
 		for _, u := range unmatchedItems {
-			fmt.Printf("    #%-5d %-55s  repo=%s\n", u.Number, truncate(u.Title, 55), u.Repo)
+			fmt.Printf("    #%-5d %-55s  repo=%s\n", u.Number, boarditems.Truncate(u.Title, 55), u.Repo)
 		}
 	}
 }
-
-func truncate(s string, n int) string {
-	if len(s) <= n {
-		return s
-	}
-	return s[:n-3] + "..."
-}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestMatchEpic(t *testing.T) {
+	tests := []struct {
+		name  string
+		repo  string
+		title string
+		want  string
+	}{
+		{name: "repo rule", repo: "Azure/secrets-store-csi-driver-provider-azure", title: "Bump deps", want: "SS CSI Driver"},
+		{name: "more specific repo rule first", repo: "Azure/secrets-store-sync-controller", title: "Bump deps", want: "SS Controller"},
+		{name: "repo rule is case-insensitive", repo: "Azure/KAITO", title: "Bump deps", want: "AI"},
+		{name: "repo rule beats title rule", repo: "Azure/guard", title: "Rotate certificate", want: "AKS Azure RBAC++"},
+		{name: "title rule", repo: "kubernetes/kubernetes", title: "Fix Impersonation of service accounts", want: "Constrained Impersonation"},
+		{name: "earlier title rule wins", repo: "kubernetes/kubernetes", title: "Webhook certificate rotation", want: "Certs"},
+		{name: "no match", repo: "kubernetes/kubernetes", title: "Update README", want: "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchEpic(tt.repo, tt.title); got != tt.want {
+				t.Errorf("matchEpic(%q, %q) = %q, want %q", tt.repo, tt.title, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// Exit statuses, so CI can tell "nothing to do" from "half the adds failed".
+const (
+	statusOK          = 0
+	statusError       = 1 // anything not covered below
+	statusUsage       = 2 // bad flags or arguments
+	statusPartial     = 3 // the sync finished, but some mutations failed
+	statusAuth        = 4 // the token was rejected or lacks a permission
+	statusNotFound    = 5 // a board, repo, or item doesn't exist (or is hidden from the token)
+	statusRateLimit   = 6 // the GraphQL budget ran out
+	statusInterrupted = 130
+)
+
+// Error categories, as recorded in run summaries.
+const (
+	categoryAuth           = "auth"
+	categoryNotFound       = "not-found"
+	categoryRateLimit      = "rate-limit"
+	categoryMutationFailed = "mutation-failed"
+)
+
+// failureRecord is one rejected mutation in a run summary.
+type failureRecord struct {
+	Category string `json:"category"`
+	Kind     string `json:"kind"` // add, set-field, or remove
+	Item     string `json:"item"`
+	Error    string `json:"error"`
+}
+
+// category names the kind of err; errors GitHub doesn't classify count as
+// mutation failures.
+func category(err error) string {
+	if c := ghgql.Category(err); c != "" {
+		return c
+	}
+	return categoryMutationFailed
+}
+
+// categoryStatus is the exit status for a category.
+func categoryStatus(c string) int {
+	switch c {
+	case categoryAuth:
+		return statusAuth
+	case categoryNotFound:
+		return statusNotFound
+	case categoryRateLimit:
+		return statusRateLimit
+	}
+	return statusPartial
+}
+
+// exitStatus is the exit status for an error that stopped a command.
+func exitStatus(err error) int {
+	if c := ghgql.Category(err); c != "" {
+		return categoryStatus(c)
+	}
+	return statusError
+}
+
+// statusRank orders exit statuses by how much they need a human: a bad
+// token beats a spent budget beats a missing board beats everything else,
+// and a partial failure is the least of them.
+var statusRank = map[int]int{
+	statusPartial:   1,
+	statusError:     2,
+	statusNotFound:  3,
+	statusRateLimit: 4,
+	statusAuth:      5,
+}
+
+// worseStatus returns whichever of a and b ranks higher.
+func worseStatus(a, b int) int {
+	if statusRank[b] > statusRank[a] {
+		return b
+	}
+	return a
+}
+
+// failureRecords converts the mutations a sync had rejected for its summary.
+func failureRecords(failed []board.Failure) []failureRecord {
+	records := make([]failureRecord, 0, len(failed))
+	for _, f := range failed {
+		records = append(records, failureRecord{Category: category(f.Err), Kind: f.Kind, Item: f.Label, Error: f.Err.Error()})
+	}
+	return records
+}
+
+// status is the exit status for a sync that returned err.
+func (s *runSummary) status(err error) int {
+	code := statusOK
+	if err != nil {
+		code = exitStatus(err)
+	}
+	for _, f := range s.Failures {
+		code = worseStatus(code, categoryStatus(f.Category))
+	}
+	return code
+}
+
+// errorSummaryMax caps the failed mutations listed by logErrorSummary.
+const errorSummaryMax = 10
+
+// logErrorSummary logs how many of a sync's mutations failed, by category,
+// and the first few of them.
+func logErrorSummary(s *runSummary) {
+	if len(s.Failures) == 0 {
+		return
+	}
+	counts := make(map[string]int)
+	for _, f := range s.Failures {
+		counts[f.Category]++
+	}
+	var parts []string
+	for c, n := range counts {
+		parts = append(parts, fmt.Sprintf("%s=%d", c, n))
+	}
+	sort.Strings(parts)
+	slog.Error("Mutations failed", "count", len(s.Failures), "categories", strings.Join(parts, ", "))
+	for i, f := range s.Failures {
+		if i == errorSummaryMax {
+			slog.Error("More mutations failed; see the run summary", "count", len(s.Failures)-i, "dir", defaultCacheDir)
+			break
+		}
+		slog.Error("Mutation failed", "op", f.Kind, "item", f.Item, "category", f.Category, "err", f.Error)
+	}
+}
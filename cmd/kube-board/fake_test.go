@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// roundTripFunc lets a plain function serve a client's requests.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// newFakeClient returns a client whose requests are answered by handler,
+// without pacing or retries.
+func newFakeClient(t *testing.T, handler http.HandlerFunc) *ghgql.Client {
+	t.Helper()
+	c := ghgql.NewClientWithTransport("test-token", roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	}))
+	c.MinDelay = 0
+	c.MaxRetries = 0
+	c.RetryBackoff = 0
+	return c
+}
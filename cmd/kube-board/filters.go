@@ -0,0 +1,200 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/expr"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// listFlags holds the client-side item filters and ordering shared by every
+// subcommand that works on a list of board items.
+type listFlags struct {
+	maxSize string
+	minAge  int
+	maxAge  int
+	sortKey string
+	reverse bool
+	filter  string
+	sortBy  string
+	desc    bool
+	filters string
+
+	// repoFilter overrides filter for some repositories: semicolon-separated
+	// pattern=expression entries.
+	repoFilter string
+
+	chain      []items.Filter // built by validate, in the order applied
+	sortByProg *expr.Program  // compiled by validate
+
+	stages []filterStage // item counts recorded by the last apply
+}
+
+// filterStage is how many items were left after one filter.
+type filterStage struct {
+	Stage string `json:"stage"`
+	Items int    `json:"items"`
+}
+
+// registerListFlags adds the shared filter and sort flags to fs.
+func registerListFlags(fs *flag.FlagSet) *listFlags {
+	f := &listFlags{}
+	fs.StringVar(&f.maxSize, "max-size", "", "Only keep pull requests up to this size bucket (XS, S, M, L, XL)")
+	fs.IntVar(&f.minAge, "min-age", 0, "Only keep items created at least N days ago")
+	fs.IntVar(&f.maxAge, "max-age", 0, "Only keep items created at most N days ago")
+	fs.StringVar(&f.sortKey, "sort", "", "Sort items by key: "+items.SortKeys())
+	fs.BoolVar(&f.reverse, "reverse", false, "Reverse the --sort order (e.g. least recently updated first)")
+	fs.StringVar(&f.filter, "filter", "", "Only keep items matching an expression, e.g. 'item.state == \"OPEN\" && \"sig/auth\" in item.labels'. Fields: "+items.ExprFields)
+	fs.StringVar(&f.sortBy, "sort-by", "", "Sort items by a numeric expression, e.g. 'item.reactions*2 + item.priorityWeight' (lowest first; see --desc)")
+	fs.BoolVar(&f.desc, "desc", false, "Sort --sort-by results highest first")
+	fs.StringVar(&f.repoFilter, "repo-filter", "", "Use a different --filter for items from some repos: semicolon-separated repo=expression entries, where repo may be a glob, e.g. 'kubernetes/enhancements=true; kubernetes/*=\"sig/auth\" in item.labels' (first match wins; true keeps every item)")
+	fs.StringVar(&f.filters, "filters", "", "Extra filters, applied after the ones above: semicolon-separated name or name=arg entries, e.g. 'label=sig/auth; max-age=90'. Filters: "+items.FilterNames())
+	return f
+}
+
+// validate canonicalizes flag values, exiting on invalid input.
+func (f *listFlags) validate() {
+	f.chain = nil
+	if f.maxSize != "" {
+		canonical, err := items.ParseSize(f.maxSize)
+		if err != nil {
+			fatalf("--max-size: %v", err)
+		}
+		f.maxSize = canonical
+		f.chain = append(f.chain, items.NewFilter("max-size", func(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+			return items.FilterMaxSize(list, canonical), nil
+		}))
+	}
+	if f.minAge > 0 && f.maxAge > 0 && f.minAge > f.maxAge {
+		fatalf("--min-age (%d) is greater than --max-age (%d)", f.minAge, f.maxAge)
+	}
+	if minAge, maxAge := f.minAge, f.maxAge; minAge > 0 || maxAge > 0 {
+		f.chain = append(f.chain, items.NewFilter("age", func(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+			return items.FilterAge(list, minAge, maxAge, time.Now()), nil
+		}))
+	}
+	if f.sortKey != "" {
+		if err := items.Sort(nil, f.sortKey, false); err != nil {
+			fatalf("--sort: %v", err)
+		}
+	}
+	if f.filter != "" || f.repoFilter != "" {
+		var prog *expr.Program
+		if f.filter != "" {
+			var err error
+			if prog, err = expr.Compile(f.filter); err != nil {
+				fatalf("--filter: %v", err)
+			}
+		}
+		overrides, err := parseRepoFilters(f.repoFilter)
+		if err != nil {
+			fatalf("--repo-filter: %v", err)
+		}
+		f.chain = append(f.chain, items.NewFilter("filter", func(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+			return filterByRepo(list, prog, overrides, time.Now())
+		}))
+	}
+	extra, err := items.ParseFilters(f.filters)
+	if err != nil {
+		fatalf("--filters: %v", err)
+	}
+	f.chain = append(f.chain, extra...)
+	if f.sortBy != "" {
+		if f.sortKey != "" {
+			fatal("--sort and --sort-by are mutually exclusive")
+		}
+		prog, err := expr.Compile(f.sortBy)
+		if err != nil {
+			fatalf("--sort-by: %v", err)
+		}
+		f.sortByProg = prog
+	}
+}
+
+// repoFilter is one --repo-filter entry.
+type repoFilter struct {
+	pattern string // owner/name, or a path.Match glob such as kubernetes/*
+	prog    *expr.Program
+}
+
+// parseRepoFilters parses --repo-filter's pattern=expression entries.
+func parseRepoFilters(spec string) ([]repoFilter, error) {
+	var out []repoFilter
+	for _, entry := range strings.Split(spec, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		pattern, src, ok := strings.Cut(entry, "=")
+		pattern, src = strings.TrimSpace(pattern), strings.TrimSpace(src)
+		if !ok || !strings.Contains(pattern, "/") || src == "" {
+			return nil, fmt.Errorf("invalid entry %q (expected owner/name=expression)", entry)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid repo pattern %q", pattern)
+		}
+		prog, err := expr.Compile(src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pattern, err)
+		}
+		out = append(out, repoFilter{pattern: pattern, prog: prog})
+	}
+	return out, nil
+}
+
+// filterByRepo keeps the items matching their repository's override from
+// overrides, or prog when none matches. A nil prog keeps items from repos
+// without an override.
+func filterByRepo(list []board.ProjectItemWithFields, prog *expr.Program, overrides []repoFilter, now time.Time) ([]board.ProjectItemWithFields, error) {
+	if len(overrides) == 0 {
+		return items.FilterExpr(list, prog, now)
+	}
+	var kept []board.ProjectItemWithFields
+	for _, it := range list {
+		p := prog
+		for _, o := range overrides {
+			if ok, _ := path.Match(o.pattern, it.Repo); ok {
+				p = o.prog
+				break
+			}
+		}
+		if p == nil {
+			kept = append(kept, it)
+			continue
+		}
+		match, err := items.FilterExpr([]board.ProjectItemWithFields{it}, p, now)
+		if err != nil {
+			return nil, err
+		}
+		kept = append(kept, match...)
+	}
+	return kept, nil
+}
+
+// apply runs the filter chain over list, logging the count after each
+// filter, then sorts the survivors if --sort or --sort-by was given.
+func (f *listFlags) apply(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+	f.stages = []filterStage{{Stage: "input", Items: len(list)}}
+	for _, filter := range f.chain {
+		var err error
+		if list, err = filter.Apply(list); err != nil {
+			return nil, fmt.Errorf("%s filter: %w", filter.Name(), err)
+		}
+		slog.Info("Filtered items", "filter", filter.Name(), "count", len(list))
+		f.stages = append(f.stages, filterStage{filter.Name(), len(list)})
+	}
+	if f.sortKey != "" {
+		items.Sort(list, f.sortKey, f.reverse) // key validated up front
+	}
+	if f.sortByProg != nil {
+		if err := items.SortExpr(list, f.sortByProg, f.desc, time.Now()); err != nil {
+			return nil, fmt.Errorf("--sort-by: %w", err)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/expr"
+)
+
+func TestParseRepoFilters(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []string // pattern: expression
+		wantErr string
+	}{
+		{spec: "", want: nil},
+		{
+			spec: `kubernetes/kubernetes=item.state == "OPEN"; kubernetes-sigs/* = "sig/auth" in item.labels ;`,
+			want: []string{`kubernetes/kubernetes: item.state == "OPEN"`, `kubernetes-sigs/*: "sig/auth" in item.labels`},
+		},
+		{spec: "kubernetes=true", wantErr: `invalid entry "kubernetes=true" (expected owner/name=expression)`},
+		{spec: "k/k", wantErr: "invalid entry"},
+		{spec: "k/k=", wantErr: "invalid entry"},
+		{spec: "k/[=true", wantErr: `invalid repo pattern "k/["`},
+		{spec: "k/k=item.state ==", wantErr: "k/k: expr:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseRepoFilters(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseRepoFilters(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRepoFilters(%q): %v", tt.spec, err)
+			}
+			var entries []string
+			for _, f := range got {
+				entries = append(entries, f.pattern+": "+f.prog.String())
+			}
+			if !reflect.DeepEqual(entries, tt.want) {
+				t.Errorf("parseRepoFilters(%q) = %q, want %q", tt.spec, entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterByRepo(t *testing.T) {
+	list := []board.ProjectItemWithFields{
+		{Number: 1, Repo: "kubernetes/kubernetes", State: "OPEN"},
+		{Number: 2, Repo: "kubernetes/kubernetes", State: "CLOSED"},
+		{Number: 3, Repo: "kubernetes-sigs/kind", State: "CLOSED"},
+		{Number: 4, Repo: "kubernetes-sigs/kind", State: "OPEN"},
+		{Number: 5, Repo: "other/repo", State: "OPEN"},
+		{Number: 6, Repo: "other/repo", State: "CLOSED"},
+	}
+	overrides, err := parseRepoFilters(`kubernetes-sigs/*=item.state == "CLOSED"; other/repo=true`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	open, err := expr.Compile(`item.state == "OPEN"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numbers := func(list []board.ProjectItemWithFields) []int {
+		var out []int
+		for _, it := range list {
+			out = append(out, it.Number)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		prog      *expr.Program
+		overrides []repoFilter
+		want      []int
+	}{
+		{name: "no overrides", prog: open, want: []int{1, 4, 5}},
+		{name: "overrides win over --filter", prog: open, overrides: overrides, want: []int{1, 3, 5, 6}},
+		{name: "no --filter keeps other repos", overrides: overrides[:1], want: []int{1, 2, 3, 5, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterByRepo(list, tt.prog, tt.overrides, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(numbers(got), tt.want) {
+				t.Errorf("filterByRepo kept %v, want %v", numbers(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// inspectOptions holds the `kube-board board inspect` flags.
+type inspectOptions struct {
+	owner  *string
+	number *int
+	fields *string
+	limit  *int
+}
+
+// registerInspectFlags adds the board inspect flags to fs.
+func registerInspectFlags(fs *flag.FlagSet) *inspectOptions {
+	return &inspectOptions{
+		owner:  fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number: fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		fields: fs.String("fields", "Status", "Comma-separated fields to show for each sampled item"),
+		limit:  fs.Int("limit", 50, "Number of items to sample (0 = none)"),
+	}
+}
+
+// runInspect implements `kube-board board inspect`: print a board's views,
+// fields, recent status updates, and a sample of items with their values for chosen fields, for
+// debugging view filters and field setup.
+func runInspect(args []string) {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	opts := registerInspectFlags(fs)
+	parseFlags(fs, args)
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+
+	views, err := board.ListViews(gql, project.ID)
+	if err != nil {
+		fatalf("Error listing views: %v", err)
+	}
+	fmt.Printf("\n=== Views (%d) ===\n", len(views))
+	for _, v := range views {
+		fmt.Printf("  %-40s %-14s filter=%q\n", v.Name, v.Layout, v.Filter)
+	}
+
+	names := make([]string, 0, len(project.Fields))
+	for name := range project.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("\n=== Fields (%d) ===\n", len(names))
+	for _, name := range names {
+		f := project.Fields[name]
+		fmt.Printf("  %-30s type=%-15s id=%s", name, f.Type, f.ID)
+		if len(f.Options) > 0 {
+			fmt.Printf("  (%d options)", len(f.Options))
+		}
+		fmt.Println()
+	}
+
+	updates, err := board.ListStatusUpdates(gql, project.ID, 5)
+	if err != nil {
+		fatalf("Error listing status updates: %v", err)
+	}
+	fmt.Printf("\n=== Recent status updates (%d) ===\n", len(updates))
+	for _, u := range updates {
+		status := u.Status
+		if status == "" {
+			status = "-"
+		}
+		body, _, _ := strings.Cut(strings.TrimSpace(u.Body), "\n")
+		fmt.Printf("  %s  %-10s %-15s %s\n", u.CreatedAt.Format(time.DateOnly), status, u.Creator, items.Truncate(body, 60))
+	}
+
+	if *opts.limit <= 0 {
+		return
+	}
+	show := splitList(*opts.fields)
+	list := fetchBoardItems(gql, project)
+	sample := list[:min(*opts.limit, len(list))]
+	fmt.Printf("\n=== Sample items (%d of %d) ===\n", len(sample), len(list))
+	empty := make(map[string]int)
+	for _, it := range sample {
+		fmt.Printf("  #%-6d", it.Number)
+		for _, name := range show {
+			v := it.Fields[name]
+			if v == "" {
+				empty[name]++
+				v = "-"
+			}
+			fmt.Printf(" %s=%-15s", name, v)
+		}
+		fmt.Printf(" %s\n", items.Truncate(it.Title, 50))
+	}
+	fmt.Println()
+	for _, name := range show {
+		fmt.Printf("  %s: %d of %d sampled item(s) empty\n", name, empty[name], len(sample))
+	}
+}
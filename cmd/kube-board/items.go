@@ -0,0 +1,195 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/slack"
+)
+
+// itemsOptions holds the `kube-board items` flags.
+type itemsOptions struct {
+	owner     *string
+	number    *int
+	cacheDir  *string
+	slaPath   *string
+	slaSlack  *bool
+	filters   *listFlags
+	status    *statusFlags
+	setSize   *bool
+	sizeField *string
+	reorder   *bool
+	dryRun    *bool
+}
+
+// registerItemsFlags adds the items flags to fs.
+func registerItemsFlags(fs *flag.FlagSet) *itemsOptions {
+	return &itemsOptions{
+		owner:     fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number:    fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		cacheDir:  fs.String("cache-dir", defaultCacheDir, "Directory for status snapshots (time-in-status history)"),
+		slaPath:   fs.String("sla", "", "Path to an SLA rules YAML file (see cmd/kube-board/sla.yaml)"),
+		slaSlack:  fs.Bool("slack", false, "Post the SLA breach summary to the Slack webhook"),
+		filters:   registerListFlags(fs),
+		status:    registerStatusFlags(fs),
+		setSize:   fs.Bool("set-size", false, "Write each pull request's size bucket to a single-select field on the board"),
+		sizeField: fs.String("size-field", "Size", "Field name used by --set-size"),
+		reorder:   fs.Bool("reorder", false, "Move items on the board into the printed order (use with --sort or --sort-by)"),
+		dryRun:    fs.Bool("dry-run", false, "Preview --set-size/--reorder changes, skip --set-stuck writes, and don't save a status snapshot"),
+	}
+}
+
+// runItems implements `kube-board items`: fetch every item on a board,
+// compute derived columns (including time-in-status from earlier runs'
+// snapshots), apply filters, print, and optionally write the computed values
+// back to the board as fields.
+func runItems(args []string) {
+	fs := flag.NewFlagSet("items", flag.ExitOnError)
+	opts := registerItemsFlags(fs)
+	parseFlags(fs, args)
+	opts.filters.validate()
+
+	var sla *items.SLAConfig
+	if *opts.slaPath != "" {
+		var err error
+		if sla, err = items.LoadSLAConfig(*opts.slaPath); err != nil {
+			fatalf("Error loading SLA rules: %v", err)
+		}
+		slog.Info("Loaded SLA rules", "path", *opts.slaPath, "count", len(sla.Rules))
+	}
+	// The webhook URL is a credential, so it is environment-only.
+	webhook := os.Getenv("SLACK_WEBHOOK_URL")
+	if *opts.slaSlack && webhook == "" {
+		fatal("--slack requires SLACK_WEBHOOK_URL")
+	}
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+	list := fetchBoardItems(gql, project)
+	if n := items.CountPrivate(list); n > 0 && project.Public {
+		slog.Warn("Board is PUBLIC but items come from private repositories — their titles are visible to anyone", "board", project.Title, "count", n)
+	}
+
+	if *opts.setSize {
+		writeSizeField(gql, project, list, *opts.sizeField, *opts.dryRun)
+	}
+
+	tracker := trackStatus(gql, *opts.cacheDir, boardsync.Source{Owner: *opts.owner, Number: *opts.number}, list, opts.status, !*opts.dryRun)
+	if opts.status.setStuck && !*opts.dryRun {
+		tracker.writeStuckField(gql, project, list)
+	}
+
+	list, err := opts.filters.apply(list)
+	if err != nil {
+		fatal(err)
+	}
+
+	annotators := []items.Annotator{tracker.annotate}
+	var breaches []items.Breach
+	if sla != nil {
+		breaches = items.EvaluateSLA(sla, list, time.Now())
+		annotators = append(annotators, items.BreachAnnotator(breaches))
+	}
+
+	items.PrintItems(project.Title, list, annotators...)
+	tracker.report(list)
+
+	if *opts.reorder {
+		reorderBoard(gql, project, list, *opts.dryRun)
+	}
+
+	if sla != nil {
+		summary := items.FormatBreaches(sla, breaches)
+		fmt.Println()
+		fmt.Print(summary)
+		if *opts.slaSlack && len(breaches) > 0 {
+			if err := slack.Post(webhook, fmt.Sprintf("*%s* (%s)\n```%s```", project.Title, project.URL, summary)); err != nil {
+				slog.Warn("Could not post SLA summary to Slack", "err", err)
+			} else {
+				slog.Info("Posted SLA summary to Slack")
+			}
+		}
+	}
+}
+
+// writeSizeField sets the size bucket of every pull request on the board,
+// creating the single-select field first if needed. Items already holding
+// the correct value are left alone.
+func writeSizeField(gql *ghgql.Client, project *board.ProjectWithFields, list []board.ProjectItemWithFields, fieldName string, dryRun bool) {
+	fields := project.Fields
+	if !dryRun {
+		fields = board.EnsureFields(gql, project.ID, []board.FieldSpec{
+			{Name: fieldName, Type: "SINGLE_SELECT", Options: items.SizeBuckets},
+		}, fields)
+	}
+	sizeDef, ok := fields[fieldName]
+	if !ok && !dryRun {
+		slog.Warn("Field not available on the board, skipping --set-size", "field", fieldName)
+		return
+	}
+
+	updated, unchanged, errors := 0, 0, 0
+	for _, item := range list {
+		if item.Type != "PullRequest" {
+			continue
+		}
+		bucket := items.SizeBucket(item.Additions, item.Deletions)
+		if item.Fields[fieldName] == bucket {
+			unchanged++
+			continue
+		}
+		if dryRun {
+			slog.Info("[DRY-RUN] Would set field", "item", itemRef(item), "title", items.Truncate(item.Title, 50), "field", fieldName, "value", bucket)
+			updated++
+			continue
+		}
+		optID, found := board.ResolveOptionID(sizeDef, bucket)
+		if !found {
+			slog.Warn("Option not found, skipping item", "field", fieldName, "option", bucket, "item", itemRef(item))
+			errors++
+			continue
+		}
+		err := board.UpdateItemField(gql, project.ID, item.ItemID, sizeDef.ID, board.FieldValue{SingleSelectOptionID: optID})
+		if err != nil {
+			slog.Error("Could not update item", "item", itemRef(item), "err", err)
+			errors++
+			continue
+		}
+		item.Fields[fieldName] = bucket
+		updated++
+	}
+
+	verb := "Updated"
+	if dryRun {
+		verb = "Would update"
+	}
+	fmt.Printf("%s %s on %d pull request(s) (%d already correct, %d error(s))\n", verb, fieldName, updated, unchanged, errors)
+}
+
+// reorderBoard moves each item on the board to sit after the one before it
+// in list, so the board's default ordering matches list. The first item goes
+// to the top; items filtered out of list keep their relative order below.
+func reorderBoard(gql *ghgql.Client, project *board.ProjectWithFields, list []board.ProjectItemWithFields, dryRun bool) {
+	if dryRun {
+		slog.Info("[DRY-RUN] Would reorder items on the board", "count", len(list))
+		return
+	}
+	slog.Info("Reordering items on the board", "count", len(list))
+	errors := 0
+	afterID := ""
+	for _, item := range list {
+		if err := board.SetItemPosition(gql, project.ID, item.ItemID, afterID); err != nil {
+			slog.Error("Could not position item", "item", itemRef(item), "err", err)
+			errors++
+		}
+		afterID = item.ItemID
+	}
+	fmt.Printf("Reordered %d item(s) (%d error(s))\n", len(list), errors)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/upstream"
+)
+
+// labelCommands are the label commands under `kube-board labels`.
+var labelCommands = []subcommand{
+	{"audit", "Check repos for the labels board queries depend on, and for inconsistent colors/descriptions", runLabelsAudit, flagsOf(registerLabelsAuditFlags), nil},
+}
+
+// runLabels implements `kube-board labels <name>`.
+func runLabels(args []string) {
+	dispatch("labels", "Commands", labelCommands, args)
+}
+
+// labelsAuditOptions holds the `kube-board labels audit` flags.
+type labelsAuditOptions struct {
+	repos     *string
+	patterns  *string
+	require   *string
+	reference *string
+}
+
+// registerLabelsAuditFlags adds the labels audit flags to fs.
+func registerLabelsAuditFlags(fs *flag.FlagSet) *labelsAuditOptions {
+	return &labelsAuditOptions{
+		repos:     fs.String("repos", os.Getenv("GITHUB_LINK_REPOS"), "Comma-separated repos (owner/name) to check"),
+		patterns:  fs.String("labels", "sig/*,lifecycle/*,triage/*", "Label patterns the queries depend on; every reference-repo label matching one must exist everywhere"),
+		require:   fs.String("require", "", "Labels that must exist in every repo, even if the reference repo lacks them (e.g. sig/auth)"),
+		reference: fs.String("reference", "", "Repo whose labels, colors, and descriptions are canonical (default: the first --repos entry)"),
+	}
+}
+
+// runLabelsAudit implements `kube-board labels audit`: label-based searches
+// and filters silently miss items in repos where a label is missing or
+// spelled differently, so check every repo against a reference repo.
+// Nothing is modified.
+func runLabelsAudit(args []string) {
+	fs := flag.NewFlagSet("labels audit", flag.ExitOnError)
+	opts := registerLabelsAuditFlags(fs)
+	parseFlags(fs, args)
+
+	repoList := splitList(*opts.repos)
+	if len(repoList) == 0 {
+		fatal("--repos (or GITHUB_LINK_REPOS) is required")
+	}
+	ref := *opts.reference
+	if ref == "" {
+		ref = repoList[0]
+	}
+	for _, p := range splitList(*opts.patterns) {
+		if _, err := path.Match(p, ""); err != nil {
+			fatalf("--labels: invalid pattern %q", p)
+		}
+	}
+
+	gql := newClient()
+	byRepo := make(map[string]map[string]upstream.Label)
+	for _, repo := range append([]string{ref}, repoList...) {
+		if byRepo[repo] != nil {
+			continue
+		}
+		list, err := upstream.Labels(gql, repo)
+		if err != nil {
+			fatalf("Error listing labels in %s: %v", repo, err)
+		}
+		labels := make(map[string]upstream.Label, len(list))
+		for _, l := range list {
+			labels[strings.ToLower(l.Name)] = l
+		}
+		byRepo[repo] = labels
+		slog.Debug("Listed labels", "repo", repo, "count", len(list))
+	}
+
+	expected := make(map[string]bool)
+	for key, l := range byRepo[ref] {
+		for _, p := range splitList(*opts.patterns) {
+			if ok, _ := path.Match(strings.ToLower(p), key); ok {
+				expected[l.Name] = true
+			}
+		}
+	}
+	for _, name := range splitList(*opts.require) {
+		expected[name] = true
+	}
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var missing, inconsistent []string
+	for _, repo := range repoList {
+		for _, name := range names {
+			want, inRef := byRepo[ref][strings.ToLower(name)]
+			got, ok := byRepo[repo][strings.ToLower(name)]
+			switch {
+			case !ok:
+				missing = append(missing, fmt.Sprintf("%s: %s", repo, name))
+			case repo == ref || !inRef:
+			case !strings.EqualFold(got.Color, want.Color):
+				inconsistent = append(inconsistent, fmt.Sprintf("%s: %s color #%s (%s has #%s)", repo, got.Name, got.Color, ref, want.Color))
+			case got.Description != want.Description:
+				inconsistent = append(inconsistent, fmt.Sprintf("%s: %s description %q (%s has %q)", repo, got.Name, got.Description, ref, want.Description))
+			}
+		}
+	}
+
+	fmt.Printf("\n=== Label audit: %d label(s) across %d repo(s), reference %s ===\n", len(names), len(repoList), ref)
+	fmt.Printf("\n--- Missing (%d) ---\n", len(missing))
+	for _, m := range missing {
+		fmt.Printf("  %s\n", m)
+	}
+	fmt.Printf("\n--- Inconsistent with %s (%d) ---\n", ref, len(inconsistent))
+	for _, m := range inconsistent {
+		fmt.Printf("  %s\n", m)
+	}
+
+	if len(missing) > 0 {
+		exit(1)
+	}
+}
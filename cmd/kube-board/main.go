@@ -0,0 +1,508 @@
+// Command kube-board is the single CLI for working with GitHub Projects V2
+// boards.  Each piece of functionality is a subcommand with its own flags;
+// shared configuration (token, board owner/number) comes from the
+// environment, so a sourced .env file works for every subcommand.
+//
+// Usage:
+//
+//	source .env/kube-board.env
+//	kube-board <subcommand> [flags]
+//	kube-board <subcommand> --help
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/logging"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/tracing"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/version"
+)
+
+// subcommand is a named entry point dispatched from main.
+type subcommand struct {
+	name    string
+	summary string
+	run     func(args []string)
+
+	// flags registers the flags run parses, for `config example` and for
+	// telling global flags from the subcommand's own; nil if it has none.
+	flags func(fs *flag.FlagSet)
+
+	// commands are the commands run dispatches to, for subcommands that
+	// group several (`kube-board board`); nil for the rest.
+	commands []subcommand
+}
+
+// flagsOf adapts a registerXFlags function to subcommand.flags.
+func flagsOf[T any](register func(fs *flag.FlagSet) T) func(fs *flag.FlagSet) {
+	return func(fs *flag.FlagSet) { register(fs) }
+}
+
+// lookupSubcommand returns the entry of cmds called name.
+func lookupSubcommand(cmds []subcommand, name string) (subcommand, bool) {
+	for _, sc := range cmds {
+		if sc.name == name {
+			return sc, true
+		}
+	}
+	return subcommand{}, false
+}
+
+// flagSetOf returns the flags sc registers, or nil if it has none.
+func flagSetOf(sc subcommand) *flag.FlagSet {
+	if sc.flags == nil {
+		return nil
+	}
+	fs := flag.NewFlagSet(sc.name, flag.ContinueOnError)
+	sc.flags(fs)
+	return fs
+}
+
+// subcommands is filled in by init, since `config example` reads it.
+var subcommands []subcommand
+
+func init() {
+	subcommands = []subcommand{
+		{"items", "List board items with computed columns (PR size, age, ...)", runItems, flagsOf(registerItemsFlags), nil},
+		{"sync-boards", "Mirror items from source boards onto a destination board", runSync, flagsOf(registerSyncBoardsFlags), nil},
+		{"sync-sigs", "Run sync-boards for every SIG in a config file, sharing fetches and budget", runSIGs, flagsOf(registerSIGsFlags), nil},
+		{"run-all", "Run every job (board sync) in a jobs file in sequence, with a combined summary", runAll, flagsOf(registerRunAllFlags), nil},
+		{"resume", "Perform the board mutations a sync deferred until the rate limit reset", runResume, flagsOf(registerResumeFlags), nil},
+		{"daemon", "Run sync-boards on an interval, serving /healthz and /status", runDaemon, flagsOf(registerDaemonFlags), nil},
+		{"webhook", "Enforce required fields on a board as projects_v2_item webhooks arrive", runWebhook, flagsOf(registerWebhookFlags), nil},
+		{"slack-bot", "Answer a Slack slash command with named board queries", runSlackBot, flagsOf(registerSlackBotFlags), nil},
+		{"audit", "Report labeled org items missing from a board, and board items that no longer match", runAudit, flagsOf(registerAuditFlags), nil},
+		{"labels", "Check repos for the labels board queries depend on (audit)", runLabels, nil, labelCommands},
+		{"where", "List every board an issue or PR is on, with its status", runWhere, flagsOf(registerWhereFlags), nil},
+		{"set-field", "Set a field to one value on every board item matching a filter", runSetField, flagsOf(registerSetFieldFlags), nil},
+		{"assign-epics", "Fill in empty Epic fields from rules matching each item's repo and title", runAssignEpics, flagsOf(registerAssignEpicsFlags), nil},
+		{"assign-bets", "Set each item's Bet field from its Epic, per a YAML mapping", runAssignBets, flagsOf(registerAssignBetsFlags), nil},
+		{"done", "Close, label, or comment on the issues/PRs in a board's Done column", runDone, flagsOf(registerDoneFlags), nil},
+		{"security", "Add open Dependabot alerts and security advisories to a private board as drafts", runSecurity, flagsOf(registerSecurityFlags), nil},
+		{"report", "Render a Markdown report (release-notes, agenda) from a board", runReport, nil, reports},
+		{"board", "Manage whole boards (rollover, autoclose, inspect)", runBoard, nil, boardCommands},
+		{"split", "Move items matching a filter from a board onto another (new) board", runSplit, flagsOf(registerSplitFlags), nil},
+		{"rescue", "List items the lifecycle bot will mark stale/rotten/closed soon", runRescue, flagsOf(registerRescueFlags), nil},
+		{"check-config", "Check a sync-boards configuration against GitHub (token scopes, boards, repos, milestones, labels)", runCheckConfig, flagsOf(registerCheckConfigFlags), nil},
+		{"config", "Print a commented sample configuration file (example)", runConfig, nil, configCommands},
+		{"env", "Show recognized environment variables, their values, and validity", runEnv, flagsOf(registerEnvFlags), nil},
+		{"version", "Print version, commit, build date, and API versions", runVersion, flagsOf(registerVersionFlags), nil},
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: kube-board <subcommand> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Subcommands:")
+	for _, sc := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", sc.name, sc.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'kube-board <subcommand> --help' for subcommand flags.")
+	fmt.Fprintln(os.Stderr, "Every subcommand also takes --log-level (debug, info, warn, error) and --log-format (plain, text, json),")
+	fmt.Fprintln(os.Stderr, "--config FILE, a YAML file of settings and flag defaults (see the README), and a flag")
+	fmt.Fprintln(os.Stderr, "for each environment variable, e.g. --dest-board-owner for GITHUB_DEST_BOARD_OWNER (see 'kube-board env').")
+	fmt.Fprintln(os.Stderr, "--show-config prints the resolved settings and flags, and where each came from, instead of running.")
+}
+
+func main() {
+	if inActions() {
+		command, args := applyActionInputs()
+		if len(os.Args) < 2 {
+			os.Args = append([]string{os.Args[0], command}, args...)
+		}
+	}
+	args, configPath := configFlag(envFlags(os.Args[1:]))
+	args, showConfig = showConfigFlag(args)
+	if len(args) > 0 {
+		commandLineFlag = flagNames(args[1:])
+	}
+	if configPath != "" {
+		var err error
+		if args, err = applyConfig(configPath, args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(statusUsage)
+		}
+	}
+	args, level, format := logFlags(args)
+	if err := setupLogging(level, format); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(statusUsage)
+	}
+	if len(args) < 1 {
+		usage()
+		os.Exit(statusUsage)
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
+	}
+	for _, sc := range subcommands {
+		if sc.name == name {
+			shutdown, err := tracing.Setup("kube-board", version.Get().Version, "kube-board "+name)
+			if err != nil {
+				slog.Warn("Tracing disabled", "err", err)
+			}
+			closeDebugLog := setupDebugLog()
+			closeCassette := setupCassette()
+			cleanup = sync.OnceFunc(func() {
+				closeCassette()
+				closeDebugLog()
+				shutdown()
+			})
+			sc.run(args[1:])
+			cleanup()
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown subcommand %q\n\n", name)
+	usage()
+	os.Exit(statusUsage)
+}
+
+// ---------------------------------------------------------------------------
+// Shared helpers
+// ---------------------------------------------------------------------------
+
+// logFlags removes the global --log-level and --log-format flags, which may
+// appear anywhere before a "--", from args and returns their values,
+// defaulting to LOG_LEVEL and LOG_FORMAT.
+func logFlags(args []string) (rest []string, level, format string) {
+	level, format = os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT")
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || (name != "log-level" && name != "log-format") {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		if name == "log-level" {
+			level = value
+		} else {
+			format = value
+		}
+	}
+	return rest, level, format
+}
+
+// setupLogging configures log levels and output format; see pkg/logging.
+func setupLogging(level, format string) error {
+	if level == "" {
+		level = "info"
+	}
+	if format == "" {
+		format = logging.FormatPlain
+	}
+	l, err := logging.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("--log-level: %w", err)
+	}
+	if err := logging.Setup(os.Stderr, format, l); err != nil {
+		return fmt.Errorf("--log-format: %w", err)
+	}
+	return nil
+}
+
+// setupDebugLog starts the --debug-graphql log (GITHUB_DEBUG_GRAPHQL), if
+// asked for, and returns a func that closes it. The file is readable only
+// by its owner: it holds whatever the requests read from private boards.
+func setupDebugLog() func() {
+	path := os.Getenv("GITHUB_DEBUG_GRAPHQL")
+	switch path {
+	case "":
+		return func() {}
+	case "-":
+		ghgql.SetDebugLog(os.Stderr)
+		return func() {}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		slog.Warn("--debug-graphql disabled", "err", err)
+		return func() {}
+	}
+	ghgql.SetDebugLog(f)
+	slog.Info("Logging GitHub API requests", "path", path)
+	return func() {
+		ghgql.SetDebugLog(nil)
+		f.Close()
+	}
+}
+
+// clientOptions are given to every GitHub client the run makes, go-github's
+// included; setupCassette adds the cassette's transport.
+var clientOptions []ghgql.Option
+
+// setupCassette starts recording GitHub API requests to the
+// --record-cassette cassette (GITHUB_RECORD), or answers them from the --replay one
+// (GITHUB_REPLAY), and returns a func that finishes the recording.
+func setupCassette() func() {
+	record, replay := os.Getenv("GITHUB_RECORD"), os.Getenv("GITHUB_REPLAY")
+	switch {
+	case record != "" && replay != "":
+		fatal("--record-cassette and --replay can't be used together")
+	case replay != "":
+		r, err := ghgql.LoadCassette(replay)
+		if err != nil {
+			fatalf("--replay: %v", err)
+		}
+		clientOptions = append(clientOptions, ghgql.WithTransport(r))
+		slog.Info("Replaying GitHub API responses", "path", replay)
+	case record != "":
+		r, err := ghgql.NewRecorder(record, nil)
+		if err != nil {
+			fatalf("--record-cassette: %v", err)
+		}
+		clientOptions = append(clientOptions, ghgql.WithTransport(r))
+		slog.Info("Recording GitHub API requests", "path", record)
+		return func() { r.Close() }
+	}
+	return func() {}
+}
+
+// cleanup closes the cassette and debug log and flushes the run's trace
+// spans, once. main sets it once the subcommand is known.
+var cleanup = func() {}
+
+// exit runs cleanup and exits with status, so a command that stops early
+// still records and exports what it did.
+func exit(status int) {
+	cleanup()
+	os.Exit(status)
+}
+
+// fatal logs v at error level, which no --log-level hides, and exits 1.
+func fatal(v ...any) {
+	slog.Error(fmt.Sprint(v...))
+	exit(statusError)
+}
+
+// fatalf is fatal with a format string.
+func fatalf(format string, v ...any) {
+	failf(statusError, format, v...)
+}
+
+// failf is fatalf exiting with status instead of 1.
+func failf(status int, format string, v ...any) {
+	slog.Error(fmt.Sprintf(format, v...))
+	exit(status)
+}
+
+// interruptContext returns a context cancelled by the first SIGINT or
+// SIGTERM, so a long-running command can stop after the mutation in flight
+// and report what it did. A second signal exits immediately.
+func interruptContext() context.Context {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+		slog.Warn("Interrupted — stopping after the request in flight (interrupt again to exit now)")
+	}()
+	return ctx
+}
+
+// dispatch runs the entry of cmds named by args[0], for subcommands that
+// group several commands (`kube-board report <name>`), or prints the group's
+// usage and exits.
+func dispatch(group, heading string, cmds []subcommand, args []string) {
+	if len(args) > 0 {
+		for _, c := range cmds {
+			if c.name == args[0] {
+				c.run(args[1:])
+				return
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Unknown %s command %q\n\n", group, args[0])
+	}
+	fmt.Fprintf(os.Stderr, "Usage: kube-board %s <name> [flags]\n", group)
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintf(os.Stderr, "%s:\n", heading)
+	for _, c := range cmds {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", c.name, c.summary)
+	}
+	os.Exit(statusUsage)
+}
+
+// requireToken returns the token (see resolveToken) or exits with a
+// helpful message.
+func requireToken() string {
+	token, _, err := resolveToken()
+	if err != nil {
+		fatalf("Error reading the GitHub token: %v", err)
+	}
+	if token == "" {
+		fatal("GITHUB_TOKEN is required — source your .env file first, set GITHUB_TOKEN_FILE, GITHUB_TOKEN_CMD, or GITHUB_TOKEN_KEYCHAIN, or log in with gh auth login")
+	}
+	return token
+}
+
+// newClient returns a GraphQL client authenticated with the token.
+func newClient() *ghgql.Client {
+	return ghgql.NewClient(requireToken(), clientOptions...)
+}
+
+// envInt returns the integer value of an environment variable, or def when
+// the variable is unset or not a number.
+func envInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// splitList splits a comma- or newline-separated value, trimming blanks.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if p := strings.TrimSpace(part); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// openBoard resolves the board identified by owner + number and logs it.
+func openBoard(gql *ghgql.Client, owner string, number int) *board.ProjectWithFields {
+	if owner == "" || number <= 0 {
+		fatal("board owner and number are required (--owner/--number or GITHUB_DEST_BOARD_OWNER/GITHUB_DEST_BOARD_NUMBER)")
+	}
+	slog.Info("Finding project", "board", fmt.Sprintf("%s/projects/%d", owner, number))
+	project, err := board.FindProjectByOwnerNumber(gql, owner, number)
+	if err != nil {
+		fatalf("Could not find project: %v", err)
+	}
+	slog.Info("Found project", "title", project.Title, "id", project.ID)
+	return project
+}
+
+// requireRepos checks that every repository in repos exists and is readable
+// with the token, exiting with the full list of problems if any aren't, so a
+// typo fails at startup instead of partway through a run. what names the
+// setting they came from, e.g. "--link-repos".
+func requireRepos(gql *ghgql.Client, what string, repos []string) {
+	if len(repos) == 0 {
+		return
+	}
+	errs := board.CheckRepositories(gql, repos)
+	if len(errs) == 0 {
+		return
+	}
+	for _, err := range errs {
+		slog.Error("Can't use repo", "setting", what, "err", err)
+	}
+	fatalf("%d of %d repo(s) in %s can't be used; fix the list and re-run", len(errs), len(repos), what)
+}
+
+// requireFeatures checks that a GitHub Enterprise Server instance supports
+// the parts of the Projects API the plans use, exiting with the gaps if
+// not. github.com supports them all, so nothing is checked there.
+func requireFeatures(gql *ghgql.Client, plans ...*syncPlan) {
+	if !gql.Enterprise() {
+		return
+	}
+	features, err := gql.Features()
+	if err != nil {
+		fatalf("Error checking %s's Projects API: %v", ghgql.Host(), err)
+	}
+	var gaps []string
+	for _, p := range plans {
+		gaps = appendNew(gaps, p.unsupported(features)...)
+	}
+	if len(gaps) == 0 {
+		return
+	}
+	for _, gap := range gaps {
+		slog.Error("Projects API feature missing", "host", ghgql.Host(), "gap", gap)
+	}
+	fatalf("%s lacks %d Projects API feature(s) this configuration needs; upgrade it or drop the settings above", ghgql.Host(), len(gaps))
+}
+
+// requireProjectAccess checks, for a fine-grained token, that it was granted
+// the destination owners' boards, exiting with what to grant if not. A
+// fine-grained token's permissions can't be listed, so without this a
+// missing grant shows up as a failure partway through the first write.
+// Classic tokens aren't checked.
+func requireProjectAccess(gql *ghgql.Client, plans ...*syncPlan) {
+	if !ghgql.IsFineGrained(gql.Token) {
+		return
+	}
+	var problems []string
+	checked := make(map[string]bool)
+	for _, p := range plans {
+		key := *p.owner + "/" + *p.name
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+		if problem := fineGrainedProblem(gql, *p.owner, *p.name); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if len(problems) == 0 {
+		return
+	}
+	for _, problem := range problems {
+		slog.Error("Fine-grained token lacks access", "problem", problem)
+	}
+	fatal("the fine-grained token can't write the destination board(s); grant the permissions above, or use a classic token")
+}
+
+// fineGrainedProblem describes what a fine-grained token lacks to write
+// owner's board titled name, or returns "" if nothing.
+func fineGrainedProblem(gql *ghgql.Client, owner, name string) string {
+	var projectID string
+	if project, err := board.FindProject(gql, owner, name); err == nil && project != nil {
+		projectID = project.ID
+	}
+	access, err := gql.CheckProjectAccess(owner, projectID)
+	switch {
+	case err != nil:
+		return fmt.Sprintf("%s: %v", owner, err)
+	case !access.IsOrg:
+		return fmt.Sprintf("%s is a user; fine-grained tokens can't access user-owned boards", owner)
+	case !access.Read:
+		return fmt.Sprintf("%s hasn't granted the token its projects: pick %s as the token's resource owner and give it Organization permissions → Projects: Read and write (an org owner may need to approve it)", owner, owner)
+	case projectID != "" && !access.Write:
+		return fmt.Sprintf("%s/%s is readable but not writable: give the token Projects: Read and write, not Read-only", owner, name)
+	}
+	return ""
+}
+
+// itemRef names a board item for logs, e.g. "kubernetes/kubernetes#123".
+func itemRef(it board.ProjectItemWithFields) string {
+	return fmt.Sprintf("%s#%d", it.Repo, it.Number)
+}
+
+// fetchBoardItems fetches every item on project, exiting on error.
+func fetchBoardItems(gql *ghgql.Client, project *board.ProjectWithFields) []board.ProjectItemWithFields {
+	slog.Info("Fetching all board items (this may take several pages)")
+	list, err := board.FetchProjectItems(gql, project.ID)
+	if err != nil {
+		fatalf("Error fetching items: %v", err)
+	}
+	slog.Info("Fetched board items", "count", len(list))
+	return list
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/cache"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// fieldsKeep is how many created-field records are kept per board.
+const fieldsKeep = 5
+
+// reconcileFields finds the fields earlier runs created that specs (this
+// run's fields) no longer include, and reports them or, with --prune-fields,
+// deletes them. Only fields this tool created, recorded in the cache
+// directory along with created (this run's), are candidates: a field that
+// was on the board before the sync first wrote it is never deleted.
+func (p *syncPlan) reconcileFields(gql *ghgql.Client, projectID string, specs []board.FieldSpec, created []string) error {
+	prefix := cache.SafeString(fmt.Sprintf("fields_%s_%s_", *p.owner, *p.name))
+	previous, err := cache.ReadLatest[string](defaultCacheDir, prefix)
+	if err != nil {
+		slog.Warn("Could not read earlier created fields", "err", err)
+	}
+
+	wanted := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		wanted[spec.Name] = true
+	}
+	managed := slices.Clone(created)
+	var stale []string
+	for _, name := range previous {
+		switch {
+		case slices.Contains(managed, name):
+		case wanted[name]:
+			managed = append(managed, name)
+		default:
+			stale = append(stale, name)
+		}
+	}
+
+	var pruneErr error
+	if len(stale) > 0 && !*p.pruneFields {
+		managed = append(managed, stale...)
+		slog.Warn("Fields created by earlier syncs are no longer synced — pass --prune-fields to delete them and their values", "fields", strings.Join(stale, ", "))
+	} else if len(stale) > 0 {
+		if fields, err := board.GetProjectFields(gql, projectID); err != nil {
+			managed = append(managed, stale...) // try again next run
+			pruneErr = fmt.Errorf("listing fields to prune: %w", err)
+		} else {
+			for _, name := range stale {
+				field, ok := fields[name]
+				if !ok {
+					continue // already deleted by hand
+				}
+				if err := board.DeleteField(gql, field.ID); err != nil {
+					managed = append(managed, name)
+					pruneErr = fmt.Errorf("deleting field %q: %w", name, err)
+					continue
+				}
+				slog.Info("Deleted field no longer synced", "field", name)
+			}
+		}
+	}
+
+	if len(managed) > 0 || len(previous) > 0 {
+		cache.Write(defaultCacheDir, prefix+cache.Timestamp()+".json", managed)
+		if _, err := cache.Clean(defaultCacheDir, prefix, fieldsKeep); err != nil {
+			slog.Warn("Could not prune created-field records", "err", err)
+		}
+	}
+	return pruneErr
+}
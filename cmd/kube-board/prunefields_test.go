@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/cache"
+)
+
+func TestReconcileFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		prune       bool
+		failDelete  string // field whose delete GitHub rejects
+		wantDeleted []string
+		wantRecord  []string
+		wantErr     string
+	}{
+		{
+			name:       "report only",
+			wantRecord: []string{"Team", "Priority", "Old", "Broken", "Gone"},
+		},
+		{
+			name:        "prune",
+			prune:       true,
+			wantDeleted: []string{"PVTF_old", "PVTF_broken"},
+			wantRecord:  []string{"Team", "Priority"},
+		},
+		{
+			name:        "failed delete stays recorded",
+			prune:       true,
+			failDelete:  "PVTF_broken",
+			wantDeleted: []string{"PVTF_old", "PVTF_broken"},
+			wantRecord:  []string{"Team", "Priority", "Broken"},
+			wantErr:     `deleting field "Broken"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			saved := defaultCacheDir
+			defaultCacheDir = dir
+			t.Cleanup(func() { defaultCacheDir = saved })
+
+			owner, name := "my-org", "SIG Auth"
+			prefix := cache.SafeString("fields_" + owner + "_" + name + "_")
+			// Gone was deleted by hand; Handmade was never the tool's.
+			cache.Write(dir, prefix+"2020-01-01T00-00-00.json", []string{"Priority", "Old", "Broken", "Gone"})
+
+			var deleted []string
+			gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query     string         `json:"query"`
+					Variables map[string]any `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				switch {
+				case strings.Contains(req.Query, "deleteProjectV2Field"):
+					id := req.Variables["fieldId"].(string)
+					deleted = append(deleted, id)
+					if id == tt.failDelete {
+						io.WriteString(w, `{"errors": [{"message": "something went wrong"}]}`)
+						return
+					}
+					io.WriteString(w, `{"data": {"deleteProjectV2Field": {"projectV2Field": {"id": "`+id+`"}}}}`)
+				case strings.Contains(req.Query, "fields(first: 50)"):
+					io.WriteString(w, `{"data": {"node": {"fields": {"nodes": [
+						{"id": "PVTF_priority", "name": "Priority", "dataType": "SINGLE_SELECT"},
+						{"id": "PVTF_team", "name": "Team", "dataType": "TEXT"},
+						{"id": "PVTF_old", "name": "Old", "dataType": "TEXT"},
+						{"id": "PVTF_broken", "name": "Broken", "dataType": "TEXT"},
+						{"id": "PVTF_handmade", "name": "Handmade", "dataType": "TEXT"}
+					]}}}}`)
+				default:
+					t.Errorf("unexpected query %s", req.Query)
+				}
+			})
+
+			prune := tt.prune
+			p := &syncPlan{syncOptions: &syncOptions{owner: &owner, name: &name, pruneFields: &prune}}
+			specs := []board.FieldSpec{{Name: "Priority"}, {Name: "Team"}}
+			err := p.reconcileFields(gql, "PVT_1", specs, []string{"Team"})
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("reconcileFields error = %v, want %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(deleted, tt.wantDeleted) {
+				t.Errorf("deleted %v, want %v", deleted, tt.wantDeleted)
+			}
+
+			matches, _ := filepath.Glob(filepath.Join(dir, prefix+"*.json"))
+			if len(matches) != 2 {
+				t.Fatalf("records = %v, want the earlier one and a new one", matches)
+			}
+			data, err := os.ReadFile(matches[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var record []string
+			if err := json.Unmarshal(data, &record); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(record, tt.wantRecord) {
+				t.Errorf("recorded %v, want %v", record, tt.wantRecord)
+			}
+		})
+	}
+}
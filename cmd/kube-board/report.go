@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/releasenotes"
+)
+
+// reports are the Markdown reports available under `kube-board report`.
+var reports = []subcommand{
+	{"release-notes", "Draft release notes from items completed in a milestone", runReleaseNotes, flagsOf(registerReleaseNotesFlags), nil},
+	{"agenda", "SIG meeting agenda: new, blocked, stale items, and PRs awaiting review", runAgenda, flagsOf(registerAgendaFlags), nil},
+}
+
+// runReport implements `kube-board report <name>`: render a Markdown report
+// from a board's items.
+func runReport(args []string) {
+	dispatch("report", "Reports", reports, args)
+}
+
+// reportOutput opens path for a report, creating parent directories, or
+// returns stdout when path is empty. The returned func closes the file.
+func reportOutput(path string) (io.Writer, func()) {
+	if path == "" {
+		return os.Stdout, func() {}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		fatalf("Error creating %s: %v", filepath.Dir(path), err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		fatalf("Error creating report: %v", err)
+	}
+	return f, func() {
+		if err := f.Close(); err != nil {
+			fatalf("Error writing report: %v", err)
+		}
+		slog.Info("Wrote report", "path", path)
+	}
+}
+
+// releaseNotesOptions holds the `kube-board report release-notes` flags.
+type releaseNotesOptions struct {
+	owner     *string
+	number    *int
+	milestone *string
+	out       *string
+	filters   *listFlags
+}
+
+// registerReleaseNotesFlags adds the report release-notes flags to fs.
+func registerReleaseNotesFlags(fs *flag.FlagSet) *releaseNotesOptions {
+	return &releaseNotesOptions{
+		owner:     fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number:    fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		milestone: fs.String("milestone", "", "Milestone to report on, e.g. v1.36"),
+		out:       fs.String("out", "", "Write the Markdown draft to this file (default stdout), e.g. _output/release-notes.md"),
+		filters:   registerListFlags(fs),
+	}
+}
+
+// runReleaseNotes implements `kube-board report release-notes`: collect the
+// merged PRs and closed issues on a board in --milestone, pull the
+// release-note block from each description, and render them grouped by kind.
+func runReleaseNotes(args []string) {
+	fs := flag.NewFlagSet("release-notes", flag.ExitOnError)
+	opts := registerReleaseNotesFlags(fs)
+	parseFlags(fs, args)
+	opts.filters.validate()
+
+	if *opts.milestone == "" {
+		fatal("--milestone is required")
+	}
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+	var done []board.ProjectItemWithFields
+	for _, it := range fetchBoardItems(gql, project) {
+		if it.Milestone == *opts.milestone && releasenotes.Completed(it) {
+			done = append(done, it)
+		}
+	}
+	done, err := opts.filters.apply(done)
+	if err != nil {
+		fatal(err)
+	}
+	slog.Info("Found completed items", "milestone", *opts.milestone, "count", len(done))
+
+	ids := make([]string, len(done))
+	for i, it := range done {
+		ids[i] = it.ContentID
+	}
+	bodies, err := releasenotes.FetchBodies(gql, ids)
+	if err != nil {
+		fatal(err)
+	}
+
+	var notes []releasenotes.Note
+	for _, it := range done {
+		if text, ok := releasenotes.Extract(bodies[it.ContentID]); ok {
+			notes = append(notes, releasenotes.Note{Item: it, Text: text, Kind: releasenotes.Kind(it)})
+		}
+	}
+	slog.Info("Found release notes", "count", len(notes), "without", len(done)-len(notes))
+
+	w, closeOut := reportOutput(*opts.out)
+	releasenotes.Render(w, fmt.Sprintf("%s Release Notes (draft) — %s", *opts.milestone, project.Title), notes)
+	closeOut()
+}
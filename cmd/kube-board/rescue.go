@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// rescueOptions holds the `kube-board rescue` flags.
+type rescueOptions struct {
+	owner   *string
+	number  *int
+	within  *int
+	filters *listFlags
+}
+
+// registerRescueFlags adds the rescue flags to fs.
+func registerRescueFlags(fs *flag.FlagSet) *rescueOptions {
+	return &rescueOptions{
+		owner:   fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number:  fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		within:  fs.Int("within", 14, "Report items reaching their next lifecycle stage within N days"),
+		filters: registerListFlags(fs),
+	}
+}
+
+// runRescue implements `kube-board rescue`: list open items the lifecycle bot
+// will mark stale, rotten, or close within the next N days, so someone can
+// step in before the work is lost.
+func runRescue(args []string) {
+	fs := flag.NewFlagSet("rescue", flag.ExitOnError)
+	opts := registerRescueFlags(fs)
+	parseFlags(fs, args)
+	opts.filters.validate()
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+	list, err := opts.filters.apply(fetchBoardItems(gql, project))
+	if err != nil {
+		fatal(err)
+	}
+
+	risks := items.PredictLifecycle(list, time.Now(), *opts.within)
+
+	fmt.Printf("\n=== Rescue list: %s ===\n", project.Title)
+	fmt.Printf("%d open item(s) reach their next lifecycle stage within %d day(s)\n", len(risks), *opts.within)
+	for _, stage := range []string{"closed", "rotten", "stale"} {
+		var group []items.LifecycleRisk
+		for _, r := range risks {
+			if r.Next == stage {
+				group = append(group, r)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		fmt.Printf("\n--- Will be %s (%d) ---\n", stage, len(group))
+		for _, r := range group {
+			fmt.Printf("  in %2dd  #%-6d %-55s  %s\n", r.DaysLeft, r.Item.Number, items.Truncate(r.Item.Title, 55), r.Item.Author)
+			fmt.Printf("           %s\n", r.Item.URL)
+			fmt.Printf("           → %s\n", r.Action)
+		}
+	}
+	if len(risks) == 0 {
+		slog.Info("Nothing at risk — all clear")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/cache"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ratelimit"
+)
+
+// deferredPrefix names the queue files sync-boards leaves for `resume`.
+const deferredPrefix = "deferred_"
+
+// deferredFile returns the queue file for the board owner/name.
+func deferredFile(owner, name string) string {
+	return cache.SafeString(fmt.Sprintf("%s%s_%s.json", deferredPrefix, owner, name))
+}
+
+// mutationBudget returns how many mutations can be sent while keeping
+// reserve GraphQL points back, and when the budget resets. It returns a nil
+// budget (unlimited) if the rate limit can't be read.
+func mutationBudget(token string, reserve int) (*board.Budget, time.Time) {
+	rl, err := ratelimit.FetchREST(token, clientOptions...)
+	if err != nil {
+		slog.Warn("Could not read the rate limit; mutations won't be deferred", "err", err)
+		return nil, time.Time{}
+	}
+	return &board.Budget{Limit: max(rl.GraphQL.Remaining-reserve, 0)}, rl.GraphQL.ResetAt
+}
+
+// saveDeferred writes the mutations a sync couldn't afford to the board's
+// queue file, or removes the file once a sync completes in full (the sync
+// has redone everything the queue held).
+func saveDeferred(owner, name string, ops []board.Op, resetAt time.Time) {
+	file := deferredFile(owner, name)
+	if len(ops) == 0 {
+		if err := os.Remove(filepath.Join(defaultCacheDir, file)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Could not remove deferred mutations file", "path", file, "err", err)
+		}
+		return
+	}
+	q := board.DeferredOps{Owner: owner, Name: name, ResetAt: resetAt, Ops: ops}
+	path := cache.Write(defaultCacheDir, file, q)
+	slog.Warn("Deferred mutations until the GraphQL budget resets; run `kube-board resume` then",
+		"count", len(ops), "reset", resetAt.Local().Format("15:04 MST"), "path", path)
+}
+
+// resumeOptions holds the `kube-board resume` flags.
+type resumeOptions struct {
+	owner   *string
+	name    *string
+	reserve *int
+	wait    *bool
+}
+
+// registerResumeFlags adds the resume flags to fs.
+func registerResumeFlags(fs *flag.FlagSet) *resumeOptions {
+	return &resumeOptions{
+		owner:   fs.String("owner", "", "Only resume this board owner's queue (default: every queue)"),
+		name:    fs.String("name", "", "Only resume the queue of the board with this title"),
+		reserve: fs.Int("budget-reserve", 100, "GraphQL points to leave unspent; ops beyond the budget stay queued"),
+		wait:    fs.Bool("wait", false, "If the budget hasn't reset yet, sleep until it has instead of exiting"),
+	}
+}
+
+// runResume implements `kube-board resume`: perform the mutations earlier
+// syncs deferred for lack of GraphQL budget, instead of re-running the whole
+// sync.
+func runResume(args []string) {
+	fs := flag.NewFlagSet("resume", flag.ExitOnError)
+	opts := registerResumeFlags(fs)
+	parseFlags(fs, args)
+
+	paths, err := filepath.Glob(filepath.Join(defaultCacheDir, deferredPrefix+"*.json"))
+	if err != nil {
+		fatal(err)
+	}
+	var queues []board.DeferredOps
+	for _, path := range paths {
+		q, err := readDeferred(path)
+		if err != nil {
+			fatalf("Error reading %s: %v", path, err)
+		}
+		if (*opts.owner != "" && !strings.EqualFold(q.Owner, *opts.owner)) || (*opts.name != "" && q.Name != *opts.name) {
+			continue
+		}
+		queues = append(queues, q)
+	}
+	if len(queues) == 0 {
+		slog.Info("Nothing to resume: no deferred mutations", "dir", defaultCacheDir)
+		return
+	}
+
+	token := writeBudget(requireToken()) // replaying writes the board
+	gql := ghgql.NewClient(token, clientOptions...).WithContext(interruptContext())
+	failed := false
+	for _, q := range queues {
+		if err := resumeQueue(gql, token, q, *opts.reserve, *opts.wait); err != nil {
+			slog.Error("Could not resume", "board", q.Name, "err", err)
+			failed = true
+		}
+	}
+	if failed {
+		exit(1)
+	}
+}
+
+// readDeferred loads one queue file.
+func readDeferred(path string) (board.DeferredOps, error) {
+	var q board.DeferredOps
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return q, err
+	}
+	if err := json.Unmarshal(data, &q); err != nil {
+		return q, fmt.Errorf("parse: %w", err)
+	}
+	return q, nil
+}
+
+// resumeQueue replays one board's queue as far as the budget allows, then
+// rewrites its file with what's left or removes it.
+func resumeQueue(gql *ghgql.Client, token string, q board.DeferredOps, reserve int, wait bool) error {
+	slog.Info("Resuming deferred mutations", "board", q.Name, "owner", q.Owner, "count", len(q.Ops))
+
+	budget, resetAt := mutationBudget(token, reserve)
+	if budget != nil && budget.Limit == 0 {
+		if !wait {
+			return fmt.Errorf("no GraphQL budget left; it resets at %s", resetAt.Local().Format("15:04 MST"))
+		}
+		d := time.Until(resetAt) + time.Minute
+		slog.Info("Waiting for the GraphQL budget to reset", "wait", d.Round(time.Second))
+		select {
+		case <-gql.Context().Done():
+			return gql.Context().Err()
+		case <-time.After(d):
+		}
+		budget, resetAt = mutationBudget(token, reserve)
+	}
+
+	done, remaining := board.ReplayOps(gql, q.Ops, budget)
+	slog.Info("Replayed deferred mutations", "count", done, "deferred", len(remaining))
+	saveDeferred(q.Owner, q.Name, remaining, resetAt)
+	return nil
+}
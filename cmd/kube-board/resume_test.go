@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+func TestSaveDeferred(t *testing.T) {
+	saved := defaultCacheDir
+	defaultCacheDir = t.TempDir()
+	t.Cleanup(func() { defaultCacheDir = saved })
+
+	ops := []board.Op{
+		{Kind: board.OpAdd, ProjectID: "PVT_1", ContentID: "I_1", Label: "o/r#1"},
+		{Kind: board.OpSetField, ProjectID: "PVT_1", ContentID: "I_1", Field: "Status", Value: "Todo", Label: "o/r#1"},
+	}
+	resetAt := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	path := filepath.Join(defaultCacheDir, deferredFile("o", "Board"))
+	other := filepath.Join(defaultCacheDir, deferredFile("o", "Other"))
+
+	saveDeferred("o", "Board", ops, resetAt)
+	saveDeferred("o", "Other", ops[:1], resetAt)
+	q, err := readDeferred(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := board.DeferredOps{Owner: "o", Name: "Board", ResetAt: resetAt, Ops: ops}
+	if !reflect.DeepEqual(q, want) {
+		t.Errorf("queue = %+v, want %+v", q, want)
+	}
+
+	saveDeferred("o", "Board", nil, resetAt)
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("queue file after an empty save: %v, want it removed", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("another board's queue: %v, want it kept", err)
+	}
+	saveDeferred("o", "Board", nil, resetAt) // nothing left to remove
+}
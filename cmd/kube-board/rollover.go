@@ -0,0 +1,223 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// rolloverOptions holds the `kube-board board rollover` flags.
+type rolloverOptions struct {
+	owner         *string
+	number        *int
+	from          *string
+	to            *string
+	title         *string
+	closeOld      *bool
+	archiveSuffix *string
+	dryRun        *bool
+	filters       *listFlags
+}
+
+// registerRolloverFlags adds the board rollover flags to fs.
+func registerRolloverFlags(fs *flag.FlagSet) *rolloverOptions {
+	return &rolloverOptions{
+		owner:         fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Owner of the current cycle's board (user or org)"),
+		number:        fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Number of the current cycle's board"),
+		from:          fs.String("from", "", "Current cycle as it appears in the board title, e.g. v1.36"),
+		to:            fs.String("to", "", "Next cycle, e.g. v1.37; replaces --from in the new board's title"),
+		title:         fs.String("title", "", "Title for the new board (default: the old title with --from replaced by --to)"),
+		closeOld:      fs.Bool("close-old", true, "Close the old board once every item is carried over"),
+		archiveSuffix: fs.String("archive-suffix", "", "Also rename the old board by appending this, e.g. \" (archived)\""),
+		dryRun:        fs.Bool("dry-run", false, "Print the plan without creating, changing, or closing any board"),
+		filters:       registerListFlags(fs),
+	}
+}
+
+// runRollover implements `kube-board board rollover`: create the board for
+// the next release cycle with the same fields and views as the current one,
+// carry over the items that are still open, then close (and optionally
+// rename) the old board. Re-running it after a partial failure picks up
+// where it left off.
+func runRollover(args []string) {
+	fs := flag.NewFlagSet("rollover", flag.ExitOnError)
+	opts := registerRolloverFlags(fs)
+	parseFlags(fs, args)
+	opts.filters.validate()
+
+	if *opts.from == "" || *opts.to == "" {
+		fatal("--from and --to are required (e.g. --from v1.36 --to v1.37)")
+	}
+
+	gql := newClient()
+	old := openBoard(gql, *opts.owner, *opts.number)
+	newTitle := *opts.title
+	if newTitle == "" {
+		if !strings.Contains(old.Title, *opts.from) {
+			fatalf("Board title %q doesn't contain %q; pass --title for the new board", old.Title, *opts.from)
+		}
+		newTitle = strings.ReplaceAll(old.Title, *opts.from, *opts.to)
+	}
+
+	var open []board.ProjectItemWithFields
+	for _, it := range fetchBoardItems(gql, old) {
+		if it.Type == "DraftIssue" || it.State == "OPEN" {
+			open = append(open, it)
+		}
+	}
+	carry, err := opts.filters.apply(open)
+	if err != nil {
+		fatal(err)
+	}
+	specs := rolloverFieldSpecs(old.Fields)
+	views, err := board.ListViews(gql, old.ID)
+	if err != nil {
+		fatalf("Error reading the board's views: %v", err)
+	}
+
+	fmt.Printf("\n=== %s → %s: carrying %d open item(s) ===\n", old.Title, newTitle, len(carry))
+	for _, it := range carry {
+		fmt.Printf("  [%s] %s#%-6d %s\n", it.Type, it.Repo, it.Number, items.Truncate(it.Title, 60))
+	}
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		names[i] = spec.Name
+	}
+	fmt.Printf("Fields: %s\n", strings.Join(names, ", "))
+	names = make([]string, len(views))
+	for i, v := range views {
+		names[i] = v.Name
+	}
+	fmt.Printf("Views: %s\n", strings.Join(names, ", "))
+	if *opts.archiveSuffix != "" {
+		fmt.Printf("Old board renamed to: %s%s\n", old.Title, *opts.archiveSuffix)
+	}
+	if *opts.closeOld {
+		fmt.Println("Old board closed afterwards")
+	}
+	if *opts.dryRun {
+		return
+	}
+
+	dest, err := board.FindProject(gql, *opts.owner, newTitle)
+	if err != nil {
+		fatalf("Error searching for the new board: %v", err)
+	}
+	if dest == nil {
+		slog.Info("Creating project", "board", newTitle)
+		if dest, err = board.CreateProject(gql, *opts.owner, newTitle); err != nil {
+			fatalf("Error creating the new board: %v", err)
+		}
+		slog.Info("Created project", "url", dest.URL)
+	}
+	if dest.ID == old.ID {
+		fatal("The new board's title matches the old board; pass a different --to or --title")
+	}
+
+	copied, err := ensureCopiedFields(gql, dest.ID, specs)
+	if err != nil {
+		fatal(err)
+	}
+	board.EnsureViews(gql, *opts.owner, dest, board.ViewConfigs(views))
+	present, err := board.FetchProjectItems(gql, dest.ID)
+	if err != nil {
+		fatalf("Error reading the new board: %v", err)
+	}
+	drafts := make(map[string]bool)
+	for _, it := range present {
+		if it.Type == "DraftIssue" {
+			drafts[it.Title] = true
+		}
+	}
+
+	carried, failed := 0, 0
+	for _, it := range carry {
+		if it.Type == "DraftIssue" {
+			if drafts[it.Title] {
+				continue // carried by an earlier run
+			}
+			err = carryDraft(gql, dest.ID, it, copied)
+		} else {
+			err = moveItem(gql, old.ID, dest.ID, it, copied, true)
+		}
+		if err != nil {
+			slog.Error("Could not carry item", "item", it.Title, "err", err)
+			failed++
+			continue
+		}
+		carried++
+	}
+	slog.Info("Carried items", "count", carried, "failed", failed)
+	if failed > 0 {
+		slog.Warn("Leaving the old board open; re-run once the errors are fixed", "board", old.Title)
+		fmt.Printf("\nProject board: %s\n", dest.URL)
+		exit(1)
+	}
+
+	if *opts.archiveSuffix != "" {
+		if err := board.RenameProject(gql, old.ID, old.Title+*opts.archiveSuffix); err != nil {
+			slog.Warn("Could not rename the old board", "board", old.Title, "err", err)
+		} else {
+			slog.Info("Renamed the old board", "board", old.Title, "title", old.Title+*opts.archiveSuffix)
+		}
+	}
+	if *opts.closeOld {
+		if err := board.CloseProject(gql, old.ID); err != nil {
+			slog.Warn("Could not close the old board", "board", old.Title, "err", err)
+		} else {
+			slog.Info("Closed the old board", "url", old.URL)
+		}
+	}
+	fmt.Printf("\nProject board: %s\n", dest.URL)
+}
+
+// rolloverFieldSpecs returns every copyable field on the old board with all
+// of its single-select options, sorted by name, so the new board starts with
+// the same spec whether or not the open items use each value.
+func rolloverFieldSpecs(fields board.FieldMap) []board.FieldSpec {
+	var specs []board.FieldSpec
+	for name, def := range fields {
+		if !copyableFieldTypes[def.Type] {
+			continue
+		}
+		spec := board.FieldSpec{Name: name, Type: def.Type}
+		for _, opt := range def.Options {
+			spec.Options = append(spec.Options, opt.Name)
+		}
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs
+}
+
+// carryDraft recreates a draft issue, with its body and copied field values,
+// on the destination board. Drafts can't be added to a second board.
+func carryDraft(gql *ghgql.Client, destID string, it board.ProjectItemWithFields, copied board.FieldMap) error {
+	body, err := board.DraftIssueBody(gql, it.ContentID)
+	if err != nil {
+		return fmt.Errorf("reading draft: %w", err)
+	}
+	itemID, err := board.AddDraftIssue(gql, destID, it.Title, body)
+	if err != nil {
+		return fmt.Errorf("creating draft: %w", err)
+	}
+	values := make(map[string]string)
+	for name, value := range it.Fields {
+		if _, ok := copied[name]; ok {
+			values[name] = value
+		}
+	}
+	if err := board.SetItemFields(gql, destID, itemID, values, copied); err != nil {
+		// A re-run skips drafts already on the new board, so say so.
+		return fmt.Errorf("copying fields to the new draft (created; set them by hand): %w", err)
+	}
+	slog.Debug("Carried draft", "item", it.Title)
+	return nil
+}
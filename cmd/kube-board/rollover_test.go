@@ -0,0 +1,89 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+func TestRolloverFieldSpecs(t *testing.T) {
+	fields := board.FieldMap{
+		"Status":    {Name: "Status", Type: "SINGLE_SELECT", Options: []board.FieldOption{{Name: "Todo"}, {Name: "Done"}}},
+		"Notes":     {Name: "Notes", Type: "TEXT"},
+		"Iteration": {Name: "Iteration", Type: "ITERATION"},
+		"Assignees": {Name: "Assignees", Type: "ASSIGNEES"},
+	}
+	want := []board.FieldSpec{
+		{Name: "Notes", Type: "TEXT"},
+		{Name: "Status", Type: "SINGLE_SELECT", Options: []string{"Todo", "Done"}},
+	}
+	if got := rolloverFieldSpecs(fields); !reflect.DeepEqual(got, want) {
+		t.Errorf("rolloverFieldSpecs = %v, want %v", got, want)
+	}
+}
+
+func TestCarryDraft(t *testing.T) {
+	it := board.ProjectItemWithFields{
+		ItemID:    "PVTI_source",
+		ContentID: "DI_1",
+		Title:     "Write the KEP",
+		Type:      "DraftIssue",
+		Fields:    map[string]string{"Notes": "keep me", "Status": "Todo"},
+	}
+	copied := board.FieldMap{"Notes": {ID: "PVTF_notes", Name: "Notes", Type: "TEXT"}}
+
+	tests := []struct {
+		name     string
+		setFails bool
+		wantErr  string
+	}{
+		{name: "carried"},
+		{name: "field copy fails", setFails: true, wantErr: "set them by hand"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created, set bool
+			gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				switch q := string(body); {
+				case strings.Contains(q, "DraftIssue { body }"):
+					w.Write([]byte(`{"data":{"node":{"body":"Draft body"}}}`))
+				case strings.Contains(q, "addProjectV2DraftIssue"):
+					created = true
+					if !strings.Contains(q, "Draft body") {
+						t.Errorf("draft create %s doesn't carry the body", q)
+					}
+					w.Write([]byte(`{"data":{"addProjectV2DraftIssue":{"projectItem":{"id":"PVTI_dest"}}}}`))
+				case strings.Contains(q, "updateProjectV2ItemFieldValue"):
+					set = true
+					if tt.setFails {
+						w.Write([]byte(`{"errors":[{"message":"field is read-only"}]}`))
+						return
+					}
+					w.Write([]byte(`{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"PVTI_dest"}}}}`))
+				default:
+					t.Errorf("unexpected request %s", q)
+				}
+			})
+
+			err := carryDraft(gql, "PVT_dest", it, copied)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("carryDraft error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("carryDraft: %v", err)
+			}
+			if !created || !set {
+				t.Errorf("created = %v, set = %v; want both", created, set)
+			}
+		})
+	}
+}
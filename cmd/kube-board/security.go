@@ -0,0 +1,201 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/security"
+)
+
+// securityOptions holds the `kube-board security` flags.
+type securityOptions struct {
+	owner         *string
+	number        *int
+	repos         *string
+	dependabot    *bool
+	advisories    *bool
+	minSeverity   *string
+	severityField *string
+	packageField  *string
+	dryRun        *bool
+}
+
+// registerSecurityFlags adds the security flags to fs.
+func registerSecurityFlags(fs *flag.FlagSet) *securityOptions {
+	return &securityOptions{
+		owner:         fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number:        fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		repos:         fs.String("repos", os.Getenv("GITHUB_LINK_REPOS"), "Comma-separated repos (owner/name) to pull alerts from"),
+		dependabot:    fs.Bool("dependabot", true, "Include open Dependabot alerts"),
+		advisories:    fs.Bool("advisories", true, "Include repository security advisories in triage or draft"),
+		minSeverity:   fs.String("min-severity", "", "Skip alerts below this severity (critical, high, medium, low)"),
+		severityField: fs.String("severity-field", "Severity", "Single-select field for the alert severity"),
+		packageField:  fs.String("package-field", "Package", "Text field for the affected package"),
+		dryRun:        fs.Bool("dry-run", false, "List the alerts that would be added without writing to the board"),
+	}
+}
+
+// runSecurity implements `kube-board security`: add every open Dependabot
+// alert and in-progress security advisory in --repos to a board as a draft
+// item with Severity and Package fields. Alerts already on the board (matched
+// by the key in the draft title) are skipped.
+func runSecurity(args []string) {
+	fs := flag.NewFlagSet("security", flag.ExitOnError)
+	opts := registerSecurityFlags(fs)
+	parseFlags(fs, args)
+
+	repoList := splitList(*opts.repos)
+	if len(repoList) == 0 {
+		fatal("--repos (or GITHUB_LINK_REPOS) is required")
+	}
+	if *opts.minSeverity != "" && !slices.Contains(security.Severities, strings.ToLower(*opts.minSeverity)) {
+		fatalf("--min-severity must be one of %s", strings.Join(security.Severities, ", "))
+	}
+
+	gql := newClient()
+	requireRepos(gql, "--repos", repoList)
+	project := openBoard(gql, *opts.owner, *opts.number)
+	if project.Public {
+		fatalf("%s is public — security alerts must only go on private boards", project.Title)
+	}
+
+	var alerts []security.Alert
+	var checked []string // repos whose alerts were all fetched
+	for _, repo := range repoList {
+		var found []security.Alert
+		ok := true
+		if *opts.dependabot {
+			list, err := security.DependabotAlerts(gql, repo)
+			if err != nil {
+				slog.Warn("Could not list Dependabot alerts", "repo", repo, "err", err)
+				ok = false
+			}
+			found = append(found, list...)
+		}
+		if *opts.advisories {
+			list, err := security.RepositoryAdvisories(gql, repo)
+			if err != nil {
+				slog.Warn("Could not list security advisories", "repo", repo, "err", err)
+				ok = false
+			}
+			found = append(found, list...)
+		}
+		if ok {
+			checked = append(checked, repo)
+		}
+		slog.Info("Found open alerts", "repo", repo, "count", len(found))
+		for _, a := range found {
+			if security.SeverityAtLeast(a.Severity, *opts.minSeverity) {
+				alerts = append(alerts, a)
+			}
+		}
+	}
+
+	onBoard := make(map[string]bool)
+	for _, it := range fetchBoardItems(gql, project) {
+		if key, ok := securityDraftKey(it); ok {
+			onBoard[key] = true
+		}
+	}
+	var adding []security.Alert
+	open := make(map[string]bool, len(alerts))
+	for _, a := range alerts {
+		open[a.Key()] = true
+		if !onBoard[a.Key()] {
+			adding = append(adding, a)
+		}
+	}
+
+	fmt.Printf("\n=== %d new alert(s) for %s (%d already on the board) ===\n", len(adding), project.Title, len(alerts)-len(adding))
+	for _, a := range adding {
+		fmt.Printf("  %-8s %s\n", strings.ToUpper(a.Severity), items.Truncate(a.Title(), 100))
+	}
+	if resolved := resolvedAlerts(onBoard, open, checked, *opts.dependabot, *opts.advisories); len(resolved) > 0 {
+		fmt.Printf("\n%d alert draft(s) on the board are no longer open: %s\n", len(resolved), strings.Join(resolved, ", "))
+	}
+	if *opts.dryRun || len(adding) == 0 {
+		return
+	}
+
+	specs := []board.FieldSpec{
+		{Name: *opts.severityField, Type: "SINGLE_SELECT", Options: security.Severities},
+		{Name: *opts.packageField, Type: "TEXT"},
+	}
+	fields := board.EnsureFields(gql, project.ID, specs, project.Fields)
+
+	added, failed := 0, 0
+	for _, a := range adding {
+		itemID, err := board.AddDraftIssue(gql, project.ID, a.Title(), a.Body())
+		if err != nil {
+			slog.Error("Could not add alert", "item", a.Key(), "err", err)
+			failed++
+			continue
+		}
+		if err := board.SetItemFields(gql, project.ID, itemID, map[string]string{
+			*opts.severityField: a.Severity,
+			*opts.packageField:  a.Package,
+		}, fields); err != nil {
+			// The draft is on the board now, so a re-run won't retry it.
+			slog.Error("Added alert without its fields; set them by hand", "item", a.Key(), "err", err)
+			failed++
+			continue
+		}
+		added++
+	}
+	slog.Info("Added alerts", "count", added, "failed", failed)
+	if failed > 0 {
+		exit(statusPartial)
+	}
+}
+
+// resolvedAlerts returns the keys, sorted, of alert drafts on the board whose
+// alert is no longer open. Only kinds fetched this run, in repos whose
+// alerts were all fetched (checked), count: anything else may just not have
+// been looked at.
+func resolvedAlerts(onBoard, open map[string]bool, checked []string, dependabot, advisories bool) []string {
+	var resolved []string
+	for key := range onBoard {
+		fetched := advisories
+		if strings.Contains(key, " "+security.KindDependabot+"#") {
+			fetched = dependabot
+		}
+		if fetched && !open[key] && securityKeyInRepos(key, checked) {
+			resolved = append(resolved, key)
+		}
+	}
+	sort.Strings(resolved)
+	return resolved
+}
+
+// securityDraftKey extracts the alert key from a draft created by runSecurity
+// ("[owner/repo dependabot#12] ...").
+func securityDraftKey(it board.ProjectItemWithFields) (string, bool) {
+	if it.Type != "DraftIssue" || !strings.HasPrefix(it.Title, "[") {
+		return "", false
+	}
+	key, _, ok := strings.Cut(it.Title[1:], "]")
+	if !ok || !strings.Contains(key, " ") {
+		return "", false
+	}
+	return key, true
+}
+
+// securityKeyInRepos reports whether key belongs to one of repos, so drafts
+// for repositories outside this run (or whose fetch failed) are never
+// reported as resolved.
+func securityKeyInRepos(key string, repos []string) bool {
+	repo, _, _ := strings.Cut(key, " ")
+	for _, r := range repos {
+		if strings.EqualFold(r, repo) {
+			return true
+		}
+	}
+	return false
+}
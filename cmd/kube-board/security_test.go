@@ -0,0 +1,76 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+func TestSecurityDraftKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		it     board.ProjectItemWithFields
+		want   string
+		wantOK bool
+	}{
+		{name: "dependabot", it: board.ProjectItemWithFields{Type: "DraftIssue", Title: "[my-org/api dependabot#12] go/x/net: HTTP/2 reset"}, want: "my-org/api dependabot#12", wantOK: true},
+		{name: "advisory", it: board.ProjectItemWithFields{Type: "DraftIssue", Title: "[my-org/api GHSA-xxxx-yyyy-zzzz] go/a: bypass"}, want: "my-org/api GHSA-xxxx-yyyy-zzzz", wantOK: true},
+		{name: "issue", it: board.ProjectItemWithFields{Type: "Issue", Title: "[my-org/api dependabot#12] x"}},
+		{name: "no bracket", it: board.ProjectItemWithFields{Type: "DraftIssue", Title: "Plan the release"}},
+		{name: "unclosed", it: board.ProjectItemWithFields{Type: "DraftIssue", Title: "[my-org/api dependabot#12"}},
+		{name: "not a key", it: board.ProjectItemWithFields{Type: "DraftIssue", Title: "[WIP] rotate keys"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := securityDraftKey(tt.it)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("securityDraftKey(%q) = %q, %v; want %q, %v", tt.it.Title, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSecurityKeyInRepos(t *testing.T) {
+	repos := []string{"my-org/api", "my-org/web"}
+	for key, want := range map[string]bool{
+		"my-org/api dependabot#1": true,
+		"My-Org/Web GHSA-1":       true,
+		"my-org/cli dependabot#1": false,
+		"my-org/apiary GHSA-1":    false,
+	} {
+		if got := securityKeyInRepos(key, repos); got != want {
+			t.Errorf("securityKeyInRepos(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestResolvedAlerts(t *testing.T) {
+	onBoard := map[string]bool{
+		"my-org/api dependabot#1": true, // still open
+		"my-org/api dependabot#2": true, // fixed
+		"my-org/api GHSA-1":       true, // published
+		"my-org/web dependabot#3": true, // its repo's fetch failed
+		"my-org/cli dependabot#4": true, // repo not in this run
+	}
+	open := map[string]bool{"my-org/api dependabot#1": true}
+	checked := []string{"my-org/api"}
+
+	tests := []struct {
+		name                   string
+		dependabot, advisories bool
+		want                   []string
+	}{
+		{name: "both kinds", dependabot: true, advisories: true, want: []string{"my-org/api GHSA-1", "my-org/api dependabot#2"}},
+		{name: "dependabot only", dependabot: true, want: []string{"my-org/api dependabot#2"}},
+		{name: "advisories only", advisories: true, want: []string{"my-org/api GHSA-1"}},
+		{name: "neither", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolvedAlerts(onBoard, open, checked, tt.dependabot, tt.advisories); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolvedAlerts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
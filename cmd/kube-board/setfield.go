@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// setFieldOptions holds the `kube-board set-field` flags.
+type setFieldOptions struct {
+	owner   *string
+	number  *int
+	field   *string
+	value   *string
+	all     *bool
+	dryRun  *bool
+	filters *listFlags
+}
+
+// registerSetFieldFlags adds the set-field flags to fs.
+func registerSetFieldFlags(fs *flag.FlagSet) *setFieldOptions {
+	return &setFieldOptions{
+		owner:   fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number:  fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		field:   fs.String("field", "", "Field to set, e.g. \"Priority\""),
+		value:   fs.String("value", "", "Value to set: an option name, YYYY-MM-DD date, number, or text (\"\" clears the field)"),
+		all:     fs.Bool("all", false, "Allow running without --filter, updating every item on the board"),
+		dryRun:  fs.Bool("dry-run", false, "List the items that would change without writing to the board"),
+		filters: registerListFlags(fs),
+	}
+}
+
+// runSetField implements `kube-board set-field`: set one field to the same
+// value on every board item matching --filter.
+func runSetField(args []string) {
+	fs := flag.NewFlagSet("set-field", flag.ExitOnError)
+	opts := registerSetFieldFlags(fs)
+	parseFlags(fs, args)
+	opts.filters.validate()
+
+	if *opts.field == "" {
+		fatal("--field is required")
+	}
+	if opts.filters.filter == "" && opts.filters.filters == "" && !*opts.all {
+		fatal("--filter or --filters is required (pass --all to update every item)")
+	}
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+	def, ok := project.Fields[*opts.field]
+	if !ok {
+		fatalf("Field %q not found on %s", *opts.field, project.Title)
+	}
+	var fv board.FieldValue
+	if *opts.value != "" {
+		var err error
+		if fv, err = board.ParseFieldValue(def, *opts.value); err != nil {
+			if def.Type == "SINGLE_SELECT" {
+				fatalf("%v (options: %s)", err, optionNames(def))
+			}
+			fatal(err)
+		}
+	}
+
+	list, err := opts.filters.apply(fetchBoardItems(gql, project))
+	if err != nil {
+		fatal(err)
+	}
+	var changing []board.ProjectItemWithFields
+	for _, it := range list {
+		current := it.Fields[*opts.field]
+		if current == *opts.value || (def.Type == "SINGLE_SELECT" && strings.EqualFold(current, *opts.value)) {
+			continue
+		}
+		changing = append(changing, it)
+	}
+
+	fmt.Printf("\n=== %s = %q on %d item(s) (%d already set) ===\n", *opts.field, *opts.value, len(changing), len(list)-len(changing))
+	for _, it := range changing {
+		fmt.Printf("  [%s] %s#%-6d %-50s %s → %s\n", it.Type, it.Repo, it.Number, items.Truncate(it.Title, 50), orDash(it.Fields[*opts.field]), orDash(*opts.value))
+	}
+	if *opts.dryRun {
+		return
+	}
+
+	updated, failed := 0, 0
+	for _, it := range changing {
+		var err error
+		if *opts.value == "" {
+			err = board.ClearItemField(gql, project.ID, it.ItemID, def.ID)
+		} else {
+			err = board.UpdateItemField(gql, project.ID, it.ItemID, def.ID, fv)
+		}
+		if err != nil {
+			slog.Error("Could not update item", "item", itemRef(it), "err", err)
+			failed++
+			continue
+		}
+		updated++
+	}
+	slog.Info("Updated items", "count", updated, "failed", failed)
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+// optionNames lists a single-select field's options for error messages.
+func optionNames(def board.FieldDef) string {
+	names := make([]string, len(def.Options))
+	for i, opt := range def.Options {
+		names[i] = opt.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
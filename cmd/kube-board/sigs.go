@@ -0,0 +1,283 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ratelimit"
+)
+
+// sigsConfig is the YAML structure of a multi-SIG orchestration file. Each
+// entry's flags are sync-boards flag names (without dashes) and values,
+// layered over defaults, e.g.
+//
+//	defaults:
+//	  owner: kubernetes
+//	  sync: "true"
+//	sigs:
+//	  - name: sig-auth
+//	    flags:
+//	      name: SIG Auth
+//	      source: kubernetes/projects/241
+//	      filter: '"sig/auth" in item.labels'
+//
+// Entries may be listed under jobs instead of sigs; both run the same way.
+type sigsConfig struct {
+	Defaults map[string]string `yaml:"defaults"`
+	SIGs     []sigEntry        `yaml:"sigs"`
+	Jobs     []sigEntry        `yaml:"jobs"`
+}
+
+type sigEntry struct {
+	Name  string            `yaml:"name"`
+	Flags map[string]string `yaml:"flags"`
+}
+
+// sigResult is one row of the end-of-run summary.
+type sigResult struct {
+	name     string
+	status   string // "ok", "partial", "failed", "skipped"
+	items    int
+	points   int // GraphQL points spent, -1 if unknown
+	duration time.Duration
+	err      error
+	exit     int // exit status the entry alone would have had
+}
+
+// runSIGs implements `kube-board sync-sigs`: run sync-boards for every entry
+// in a config file in one invocation. Source boards shared between entries
+// are fetched once, and the run stops before an entry when the GraphQL
+// budget falls below --min-budget.
+func runSIGs(args []string) {
+	runJobs("sync-sigs", "SIG", registerSIGsFlags, args)
+}
+
+// runAll implements `kube-board run-all`: sync-sigs for a jobs file, for
+// boards that aren't one-per-SIG.
+func runAll(args []string) {
+	runJobs("run-all", "job", registerRunAllFlags, args)
+}
+
+// jobsOptions holds the `kube-board sync-sigs` and `kube-board run-all`
+// flags.
+type jobsOptions struct {
+	configPath *string
+	only       *string
+	minBudget  *int
+}
+
+// registerJobsFlags adds the sync-sigs or run-all flags to fs; noun names an
+// entry of the config file, which defaults to defaultConfig.
+func registerJobsFlags(fs *flag.FlagSet, noun, defaultConfig string) *jobsOptions {
+	return &jobsOptions{
+		configPath: fs.String("config", defaultConfig, "Path to the YAML config file"),
+		only:       fs.String("only", "", fmt.Sprintf("Comma-separated %s names to run (default all)", noun)),
+		minBudget:  fs.Int("min-budget", 500, fmt.Sprintf("Skip remaining %ss when fewer than N GraphQL points remain", noun)),
+	}
+}
+
+// registerSIGsFlags adds the sync-sigs flags to fs.
+func registerSIGsFlags(fs *flag.FlagSet) *jobsOptions {
+	return registerJobsFlags(fs, "SIG", "cmd/kube-board/sigs.yaml")
+}
+
+// registerRunAllFlags adds the run-all flags to fs.
+func registerRunAllFlags(fs *flag.FlagSet) *jobsOptions {
+	return registerJobsFlags(fs, "job", "cmd/kube-board/jobs.yaml")
+}
+
+// runJobs runs every entry of a sigsConfig file; noun names an entry in
+// flags, logs, and the summary table.
+func runJobs(command, noun string, register func(fs *flag.FlagSet) *jobsOptions, args []string) {
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	opts := register(fs)
+	parseFlags(fs, args)
+
+	cfg, err := loadSIGsConfig(*opts.configPath)
+	if err != nil {
+		fatalf("Error loading %s: %v", *opts.configPath, err)
+	}
+	selected := make(map[string]bool)
+	for _, name := range splitList(*opts.only) {
+		selected[name] = true
+	}
+
+	// Validate every entry up front so a typo in the last one doesn't
+	// surface after the first nineteen have already spent their budget.
+	var names []string
+	var plans []*syncPlan
+	for _, sig := range cfg.SIGs {
+		if len(selected) > 0 && !selected[sig.Name] {
+			continue
+		}
+		p, err := sigPlan(sig, cfg.Defaults)
+		if err != nil {
+			fatalf("%s %q: %v", noun, sig.Name, err)
+		}
+		names = append(names, sig.Name)
+		plans = append(plans, p)
+	}
+	if len(plans) == 0 {
+		fatalf("no %ss selected", noun)
+	}
+	slog.Info("Loaded config", "path", *opts.configPath, "count", len(plans))
+	runPlans(command, noun, names, plans, *opts.minBudget, false)
+}
+
+// runPlans runs plans in order with one Syncer, so sources and searches they
+// share are fetched once, prints a summary table, and exits with the worst
+// status. It stops before a plan when the GraphQL budget is below
+// minBudget, or on SIGINT.
+func runPlans(command, noun string, names []string, plans []*syncPlan, minBudget int, dryRun bool) {
+	s, token := newSyncer()
+	s.CacheSources = true // entries often share source boards and searches
+	var linkRepos []string
+	for _, p := range plans {
+		for _, repo := range splitList(*p.linkRepos) {
+			if !slices.Contains(linkRepos, repo) {
+				linkRepos = append(linkRepos, repo)
+			}
+		}
+	}
+	requireFeatures(s.WriteClient(), plans...)
+	if !dryRun {
+		requireProjectAccess(s.WriteClient(), plans...)
+	}
+	requireRepos(s.Client(), "link-repos", linkRepos)
+
+	ctx := interruptContext()
+	var results []sigResult
+	for i, p := range plans {
+		res := sigResult{name: names[i], points: -1}
+		if ctx.Err() != nil {
+			slog.Warn("Interrupted — skipping the rest", noun, names[i], "count", len(names)-i)
+			for _, name := range names[i:] {
+				results = append(results, sigResult{name: name, status: "skipped", points: -1, exit: statusInterrupted})
+			}
+			break
+		}
+		before, budgetErr := graphQLRemaining(token)
+		if budgetErr == nil && before < minBudget {
+			slog.Warn("Out of GraphQL budget — skipping the rest", noun, names[i], "count", len(names)-i, "remaining", before, "min", minBudget)
+			for _, name := range names[i:] {
+				results = append(results, sigResult{name: name, status: "skipped", points: -1, exit: statusRateLimit})
+			}
+			break
+		}
+
+		slog.Info("Syncing", noun, names[i], "progress", fmt.Sprintf("%d/%d", i+1, len(plans)))
+		_, summary, err := p.run(ctx, s, token, command, dryRun)
+		res.duration = time.Duration(summary.Duration * float64(time.Second))
+		res.items = summary.Items
+		res.points = summary.Points
+		res.status, res.exit = "ok", summary.status(err)
+		logErrorSummary(summary)
+		if err != nil {
+			res.status, res.err = "failed", err
+			slog.Error("Sync failed", noun, names[i], "err", err)
+		} else if len(summary.Failures) > 0 {
+			res.status = "partial"
+			res.err = fmt.Errorf("%d mutation(s) failed", len(summary.Failures))
+		}
+		results = append(results, res)
+	}
+
+	fmt.Println()
+	printSIGSummary(os.Stdout, strings.ToUpper(noun), results)
+	// An interrupt outranks what the entries it skipped would have said.
+	if ctx.Err() != nil {
+		exit(statusInterrupted)
+	}
+	status := statusOK
+	for _, r := range results {
+		status = worseStatus(status, r.exit)
+	}
+	exit(status)
+}
+
+// loadSIGsConfig reads and validates a multi-SIG config file.
+func loadSIGsConfig(path string) (*sigsConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	var cfg sigsConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	cfg.SIGs = append(cfg.SIGs, cfg.Jobs...)
+	seen := make(map[string]bool)
+	for i, sig := range cfg.SIGs {
+		if sig.Name == "" {
+			return nil, fmt.Errorf("entry %d has no name", i+1)
+		}
+		if seen[sig.Name] {
+			return nil, fmt.Errorf("duplicate entry name %q", sig.Name)
+		}
+		seen[sig.Name] = true
+	}
+	return &cfg, nil
+}
+
+// sigPlan builds a sync plan for one entry by applying defaults and then
+// the entry's own flags to a fresh sync-boards flag set.
+func sigPlan(sig sigEntry, defaults map[string]string) (*syncPlan, error) {
+	fs := flag.NewFlagSet(sig.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	opts := registerSyncFlags(fs)
+	for _, layer := range []map[string]string{defaults, sig.Flags} {
+		keys := make([]string, 0, len(layer))
+		for k := range layer {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := fs.Set(k, layer[k]); err != nil {
+				return nil, fmt.Errorf("flag %q: %w", k, err)
+			}
+		}
+	}
+	return opts.plan(true), nil
+}
+
+// graphQLRemaining returns the remaining GraphQL points via the free REST
+// rate-limit endpoint.
+func graphQLRemaining(token string) (int, error) {
+	rl, err := ratelimit.FetchREST(token, clientOptions...)
+	if err != nil {
+		return 0, err
+	}
+	return rl.GraphQL.Remaining, nil
+}
+
+func printSIGSummary(out io.Writer, heading string, results []sigResult) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, heading+"\tSTATUS\tITEMS\tPOINTS\tDURATION\tERROR")
+	total, totalPoints := 0, 0
+	for _, r := range results {
+		points := "?"
+		if r.points >= 0 {
+			points = fmt.Sprint(r.points)
+			totalPoints += r.points
+		}
+		errMsg := ""
+		if r.err != nil {
+			errMsg = items.Truncate(r.err.Error(), 60)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", r.name, r.status, r.items, points, r.duration.Round(time.Second), errMsg)
+		total += r.items
+	}
+	fmt.Fprintf(w, "TOTAL\t\t%d\t%d\t\t\n", total, totalPoints)
+	w.Flush()
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/expr"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/slack"
+)
+
+// botConfig is the YAML file of named queries the slash command can run, e.g.
+//
+//	queries:
+//	  - name: triage
+//	    description: Open items with no status
+//	    filter: 'item.state == "OPEN" && item.fields["Status"] == ""'
+//	    sortBy: item.priorityScore
+//	    desc: true
+//	    limit: 15
+type botConfig struct {
+	Queries []botQuery `yaml:"queries"`
+}
+
+type botQuery struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Filter      string `yaml:"filter"`
+	SortBy      string `yaml:"sortBy"`
+	Desc        bool   `yaml:"desc"`
+	Limit       int    `yaml:"limit"`
+
+	filterProg *expr.Program
+	sortByProg *expr.Program
+}
+
+// loadBotConfig reads and compiles a slash-command query file.
+func loadBotConfig(path string) (*botConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	var cfg botConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	if len(cfg.Queries) == 0 {
+		return nil, fmt.Errorf("%s defines no queries", path)
+	}
+	seen := make(map[string]bool)
+	for i := range cfg.Queries {
+		q := &cfg.Queries[i]
+		if q.Name == "" || strings.ContainsAny(q.Name, " \t") {
+			return nil, fmt.Errorf("query %d: name must be a single word", i+1)
+		}
+		if seen[q.Name] {
+			return nil, fmt.Errorf("duplicate query name %q", q.Name)
+		}
+		seen[q.Name] = true
+		if q.Filter != "" {
+			if q.filterProg, err = expr.Compile(q.Filter); err != nil {
+				return nil, fmt.Errorf("query %q filter: %w", q.Name, err)
+			}
+		}
+		if q.SortBy != "" {
+			if q.sortByProg, err = expr.Compile(q.SortBy); err != nil {
+				return nil, fmt.Errorf("query %q sortBy: %w", q.Name, err)
+			}
+		}
+		if q.Limit <= 0 {
+			q.Limit = 10
+		}
+	}
+	return &cfg, nil
+}
+
+// slackBot answers slash commands from an in-memory copy of one board,
+// refetched when older than ttl and the GraphQL budget allows.
+type slackBot struct {
+	cfg       *botConfig
+	gql       *ghgql.Client
+	token     string
+	secret    string
+	owner     string
+	number    int
+	ttl       time.Duration
+	minBudget int
+	inChannel bool
+
+	refresh   sync.Mutex // held while fetching, so concurrent commands share one fetch
+	mu        sync.Mutex
+	title     string
+	list      []board.ProjectItemWithFields
+	fetchedAt time.Time
+}
+
+// slackBotOptions holds the `kube-board slack-bot` flags.
+type slackBotOptions struct {
+	owner      *string
+	number     *int
+	configPath *string
+	listen     *string
+	ttl        *time.Duration
+	minBudget  *int
+	inChannel  *bool
+}
+
+// registerSlackBotFlags adds the slack-bot flags to fs.
+func registerSlackBotFlags(fs *flag.FlagSet) *slackBotOptions {
+	return &slackBotOptions{
+		owner:      fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board owner (user or org)"),
+		number:     fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		configPath: fs.String("config", "cmd/kube-board/slack-queries.yaml", "Path to the slash-command query YAML file"),
+		listen:     fs.String("listen", ":8080", "Address to serve /slack/command and /healthz on"),
+		ttl:        fs.Duration("cache-ttl", 15*time.Minute, "Refetch the board when the cached copy is older than this"),
+		minBudget:  fs.Int("min-budget", 500, "Answer from the stale cache instead of refetching when fewer than N GraphQL points remain"),
+		inChannel:  fs.Bool("in-channel", false, "Post replies to the whole channel instead of only to the invoking user"),
+	}
+}
+
+// runSlackBot implements `kube-board slack-bot`: serve a Slack slash command
+// (e.g. `/sigauth triage`) that runs a named query against the board and
+// replies with a summary.
+func runSlackBot(args []string) {
+	fs := flag.NewFlagSet("slack-bot", flag.ExitOnError)
+	opts := registerSlackBotFlags(fs)
+	parseFlags(fs, args)
+
+	// The signing secret is environment-only, like the other secrets, to
+	// keep it out of shell history and process listings.
+	secret := os.Getenv("SLACK_SIGNING_SECRET")
+	if secret == "" {
+		fatal("SLACK_SIGNING_SECRET is required")
+	}
+	if *opts.owner == "" || *opts.number <= 0 {
+		fatal("board owner and number are required (--owner/--number or GITHUB_DEST_BOARD_OWNER/GITHUB_DEST_BOARD_NUMBER)")
+	}
+	cfg, err := loadBotConfig(*opts.configPath)
+	if err != nil {
+		fatalf("Error loading query config: %v", err)
+	}
+	token := requireToken()
+	bot := &slackBot{
+		cfg: cfg, gql: ghgql.NewClient(token, clientOptions...), token: token, secret: secret,
+		owner: *opts.owner, number: *opts.number, ttl: *opts.ttl, minBudget: *opts.minBudget, inChannel: *opts.inChannel,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slack/command", bot.handleCommand)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok\n")) })
+	srv := &http.Server{Addr: *opts.listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		slog.Info("Serving /slack/command", "listen", *opts.listen, "queries", len(cfg.Queries))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatalf("Slack bot server: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	slog.Info("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(shutdownCtx)
+}
+
+// handleCommand verifies and answers one slash-command request. Slack wants a
+// reply within three seconds, so when the board must be refetched the request
+// is acknowledged at once and the answer is posted to its response_url.
+func (b *slackBot) handleCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST only", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if err := slack.VerifyRequest(b.secret, r.Header, body, time.Now()); err != nil {
+		slog.Warn("Rejected slash command", "err", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	name := strings.Fields(form.Get("text") + " help")[0]
+	slog.Info("Slash command", "command", form.Get("command"), "query", name, "user", form.Get("user_name"), "channel", form.Get("channel_name"))
+	q := b.query(name)
+	if q == nil {
+		b.reply(w, slack.Message{Text: b.help(form.Get("command"), name), ResponseType: "ephemeral"})
+		return
+	}
+
+	if b.fresh() {
+		b.reply(w, b.answer(q))
+		return
+	}
+	b.reply(w, slack.Message{Text: "Fetching the latest board data…", ResponseType: "ephemeral"})
+	responseURL := form.Get("response_url")
+	go func() {
+		if err := slack.PostMessage(responseURL, b.answer(q)); err != nil {
+			slog.Error("Could not post slash-command reply", "err", err)
+		}
+	}()
+}
+
+func (b *slackBot) reply(w http.ResponseWriter, msg slack.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(msg)
+}
+
+func (b *slackBot) query(name string) *botQuery {
+	for i := range b.cfg.Queries {
+		if b.cfg.Queries[i].Name == name {
+			return &b.cfg.Queries[i]
+		}
+	}
+	return nil
+}
+
+// help lists the configured queries; name is the unrecognized request, if any.
+func (b *slackBot) help(command, name string) string {
+	var sb strings.Builder
+	if name != "help" {
+		fmt.Fprintf(&sb, "Unknown query `%s`.\n", slackEscape(name))
+	}
+	sb.WriteString("Available queries:\n")
+	for _, q := range b.cfg.Queries {
+		fmt.Fprintf(&sb, "• `%s %s` — %s\n", command, q.Name, slackEscape(q.Description))
+	}
+	return sb.String()
+}
+
+func (b *slackBot) fresh() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.fetchedAt.IsZero() && time.Since(b.fetchedAt) < b.ttl
+}
+
+// items returns the board's items, refetching them when the cached copy has
+// expired and enough GraphQL budget remains. note explains stale data.
+func (b *slackBot) items() (title string, list []board.ProjectItemWithFields, fetchedAt time.Time, note string, err error) {
+	b.refresh.Lock()
+	defer b.refresh.Unlock()
+
+	if !b.fresh() {
+		remaining, budgetErr := graphQLRemaining(b.token)
+		if budgetErr == nil && remaining < b.minBudget {
+			note = fmt.Sprintf("GraphQL budget low (%d points left), showing cached data", remaining)
+		} else if err := b.fetch(); err != nil {
+			slog.Error("Could not refresh board", "err", err)
+			note = "refresh failed, showing cached data"
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.fetchedAt.IsZero() {
+		return "", nil, time.Time{}, "", fmt.Errorf("board data unavailable: %s", note)
+	}
+	return b.title, b.list, b.fetchedAt, note, nil
+}
+
+func (b *slackBot) fetch() error {
+	slog.Info("Fetching board", "board", fmt.Sprintf("%s/projects/%d", b.owner, b.number))
+	project, err := board.FindProjectByOwnerNumber(b.gql, b.owner, b.number)
+	if err != nil {
+		return err
+	}
+	list, err := board.FetchProjectItems(b.gql, project.ID)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.title, b.list, b.fetchedAt = project.Title, list, time.Now()
+	b.mu.Unlock()
+	slog.Info("Cached board items", "board", project.Title, "count", len(list))
+	return nil
+}
+
+// answer runs q and formats the reply.
+func (b *slackBot) answer(q *botQuery) slack.Message {
+	msg := slack.Message{ResponseType: "ephemeral"}
+	if b.inChannel {
+		msg.ResponseType = "in_channel"
+	}
+
+	title, list, fetchedAt, note, err := b.items()
+	if err != nil {
+		msg.Text = "Sorry — " + err.Error()
+		return msg
+	}
+	now := time.Now()
+	if q.filterProg != nil {
+		if list, err = items.FilterExpr(list, q.filterProg, now); err != nil {
+			msg.Text = fmt.Sprintf("Query `%s` failed: %s", q.Name, slackEscape(err.Error()))
+			return msg
+		}
+	} else {
+		list = append([]board.ProjectItemWithFields(nil), list...) // SortExpr sorts in place
+	}
+	if q.sortByProg != nil {
+		if err := items.SortExpr(list, q.sortByProg, q.Desc, now); err != nil {
+			msg.Text = fmt.Sprintf("Query `%s` failed: %s", q.Name, slackEscape(err.Error()))
+			return msg
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%s* — %d item(s) on %s (data from %s)", q.Name, len(list), slackEscape(title), items.Ago(fetchedAt, now))
+	if note != "" {
+		fmt.Fprintf(&sb, " _%s_", note)
+	}
+	sb.WriteString("\n")
+	for i, it := range list {
+		if i == q.Limit {
+			fmt.Fprintf(&sb, "…and %d more\n", len(list)-q.Limit)
+			break
+		}
+		ref := slackEscape(it.Title)
+		if it.URL != "" {
+			ref = fmt.Sprintf("<%s|%s#%d> %s", it.URL, slackEscape(it.Repo), it.Number, slackEscape(it.Title))
+		}
+		if status := it.Fields["Status"]; status != "" {
+			ref += " · " + slackEscape(status)
+		}
+		fmt.Fprintf(&sb, "• %s\n", ref)
+	}
+	msg.Text = sb.String()
+	return msg
+}
+
+// slackEscape escapes the characters Slack's mrkdwn treats as control
+// sequences.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// copyableFieldTypes are the custom field types split can recreate and write
+// on the destination board; built-in fields (Title, Assignees, Labels, ...)
+// follow the issue or PR and iterations are board-specific.
+var copyableFieldTypes = map[string]bool{"TEXT": true, "NUMBER": true, "DATE": true, "SINGLE_SELECT": true}
+
+// splitOptions holds the `kube-board split` flags.
+type splitOptions struct {
+	owner   *string
+	number  *int
+	to      *string
+	keep    *bool
+	dryRun  *bool
+	filters *listFlags
+}
+
+// registerSplitFlags adds the split flags to fs.
+func registerSplitFlags(fs *flag.FlagSet) *splitOptions {
+	return &splitOptions{
+		owner:   fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Board to split (user or org)"),
+		number:  fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number to split"),
+		to:      fs.String("to", "", "Destination board as owner/title (created if missing)"),
+		keep:    fs.Bool("keep", false, "Copy matching items instead of moving them (leave them on the original board)"),
+		dryRun:  fs.Bool("dry-run", false, "Print the items that would move without changing either board"),
+		filters: registerListFlags(fs),
+	}
+}
+
+// runSplit implements `kube-board split`: move the items matching --filter
+// from an existing board onto another board, creating it (private) and the
+// fields the moved items use as needed.
+func runSplit(args []string) {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	opts := registerSplitFlags(fs)
+	parseFlags(fs, args)
+	opts.filters.validate()
+
+	if opts.filters.filter == "" && opts.filters.filters == "" {
+		fatal("--filter or --filters is required (e.g. '\"area/serviceaccount\" in item.labels')")
+	}
+	destOwner, destName, ok := strings.Cut(*opts.to, "/")
+	if !ok || destOwner == "" || destName == "" {
+		fatalf("--to must be owner/title, got %q", *opts.to)
+	}
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+	list, err := opts.filters.apply(fetchBoardItems(gql, project))
+	if err != nil {
+		fatal(err)
+	}
+
+	var moving []board.ProjectItemWithFields
+	for _, it := range list {
+		if it.Type == "DraftIssue" {
+			slog.Warn("Drafts can't be moved between boards — leaving it in place", "item", it.Title)
+			continue
+		}
+		moving = append(moving, it)
+	}
+	specs := splitFieldSpecs(project.Fields, moving)
+
+	fmt.Printf("\n=== %d item(s) from %s → %s/%s ===\n", len(moving), project.Title, destOwner, destName)
+	for _, it := range moving {
+		fmt.Printf("  [%s] %s#%-6d %s\n", it.Type, it.Repo, it.Number, items.Truncate(it.Title, 60))
+	}
+	if len(specs) > 0 {
+		names := make([]string, len(specs))
+		for i, spec := range specs {
+			names[i] = spec.Name
+		}
+		fmt.Printf("Fields copied: %s\n", strings.Join(names, ", "))
+	}
+	if *opts.dryRun || len(moving) == 0 {
+		return
+	}
+
+	dest, err := board.FindProject(gql, destOwner, destName)
+	if err != nil {
+		fatalf("Error searching for destination: %v", err)
+	}
+	if dest == nil {
+		slog.Info("Project not found, creating it", "board", destName)
+		if dest, err = board.CreateProject(gql, destOwner, destName); err != nil {
+			fatalf("Error creating destination: %v", err)
+		}
+		slog.Info("Created project", "url", dest.URL)
+	}
+	if dest.ID == project.ID {
+		fatal("--to names the board being split")
+	}
+
+	copied, err := ensureCopiedFields(gql, dest.ID, specs)
+	if err != nil {
+		fatal(err)
+	}
+
+	moved, failed := 0, 0
+	for _, it := range moving {
+		if err := moveItem(gql, project.ID, dest.ID, it, copied, *opts.keep); err != nil {
+			slog.Error("Could not move item", "item", itemRef(it), "err", err)
+			failed++
+			continue
+		}
+		moved++
+	}
+	verb := "Moved"
+	if *opts.keep {
+		verb = "Copied"
+	}
+	slog.Info(verb+" items", "count", moved, "failed", failed)
+	fmt.Printf("\nProject board: %s\n", dest.URL)
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+// splitFieldSpecs returns the copyable fields that any of list sets, with the
+// single-select options actually in use, sorted by name.
+func splitFieldSpecs(fields board.FieldMap, list []board.ProjectItemWithFields) []board.FieldSpec {
+	used := make(map[string]map[string]bool)
+	for _, it := range list {
+		for name, value := range it.Fields {
+			if def, ok := fields[name]; !ok || value == "" || !copyableFieldTypes[def.Type] {
+				continue
+			}
+			if used[name] == nil {
+				used[name] = make(map[string]bool)
+			}
+			used[name][value] = true
+		}
+	}
+
+	var specs []board.FieldSpec
+	for name, values := range used {
+		def := fields[name]
+		spec := board.FieldSpec{Name: name, Type: def.Type}
+		if def.Type == "SINGLE_SELECT" {
+			for _, opt := range def.Options { // keep the source board's option order
+				if values[opt.Name] {
+					spec.Options = append(spec.Options, opt.Name)
+				}
+			}
+		}
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs
+}
+
+// ensureCopiedFields creates the fields in specs on a destination board and
+// returns their definitions there. Existing single-selects (such as the
+// built-in Status) may lack options the copied items use; those are added so
+// no value is dropped.
+func ensureCopiedFields(gql *ghgql.Client, destID string, specs []board.FieldSpec) (board.FieldMap, error) {
+	existing, err := board.GetProjectFields(gql, destID)
+	if err != nil {
+		return nil, fmt.Errorf("reading destination fields: %w", err)
+	}
+	slog.Info("Ensuring fields on the destination", "count", len(specs))
+	destFields := board.EnsureFields(gql, destID, specs, existing)
+	copied := make(board.FieldMap, len(specs))
+	for _, spec := range specs {
+		field, ok := destFields[spec.Name]
+		if !ok {
+			continue
+		}
+		if field.Type == "SINGLE_SELECT" {
+			for _, opt := range spec.Options {
+				if field, err = board.EnsureOption(gql, field, opt); err != nil {
+					slog.Warn("Could not add field option", "field", field.Name, "option", opt, "err", err)
+				}
+			}
+		}
+		copied[spec.Name] = field
+	}
+	return copied, nil
+}
+
+// moveItem adds it to the destination with its values for the copied fields,
+// then removes it from the source board unless keep is set. An item whose
+// fields couldn't all be copied stays on the source board, so nothing is
+// lost and a re-run can finish the move.
+func moveItem(gql *ghgql.Client, sourceID, destID string, it board.ProjectItemWithFields, copied board.FieldMap, keep bool) error {
+	itemID, err := board.AddItem(gql, destID, it.ContentID)
+	if err != nil {
+		return fmt.Errorf("adding to destination: %w", err)
+	}
+	if itemID != "" {
+		values := make(map[string]string)
+		for name, value := range it.Fields {
+			if _, ok := copied[name]; ok {
+				values[name] = value
+			}
+		}
+		if err := board.SetItemFields(gql, destID, itemID, values, copied); err != nil {
+			return fmt.Errorf("copying fields to destination (left on the source board): %w", err)
+		}
+	}
+	slog.Debug("Moved item to destination", "item", itemRef(it))
+	if keep {
+		return nil
+	}
+	if err := board.DeleteItem(gql, sourceID, it.ItemID); err != nil {
+		return fmt.Errorf("removing from source: %w", err)
+	}
+	return nil
+}
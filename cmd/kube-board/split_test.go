@@ -0,0 +1,78 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+func TestMoveItem(t *testing.T) {
+	it := board.ProjectItemWithFields{
+		ItemID:    "PVTI_source",
+		ContentID: "I_1",
+		Repo:      "kubernetes/kubernetes",
+		Number:    1,
+		Fields:    map[string]string{"Notes": "keep me", "Status": "Done"},
+	}
+	copied := board.FieldMap{"Notes": {ID: "PVTF_notes", Name: "Notes", Type: "TEXT"}}
+
+	tests := []struct {
+		name        string
+		setFails    bool
+		keep        bool
+		wantErr     string
+		wantDeleted bool
+	}{
+		{name: "move", wantDeleted: true},
+		{name: "copy", keep: true},
+		{name: "field copy fails", setFails: true, wantErr: "left on the source board"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var set, deleted bool
+			gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				switch q := string(body); {
+				case strings.Contains(q, "addProjectV2ItemById"):
+					w.Write([]byte(`{"data":{"addProjectV2ItemById":{"item":{"id":"PVTI_dest"}}}}`))
+				case strings.Contains(q, "updateProjectV2ItemFieldValue"):
+					set = true
+					if !strings.Contains(q, "keep me") {
+						t.Errorf("field update %s doesn't carry the Notes value", q)
+					}
+					if tt.setFails {
+						w.Write([]byte(`{"errors":[{"message":"field is read-only"}]}`))
+						return
+					}
+					w.Write([]byte(`{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"PVTI_dest"}}}}`))
+				case strings.Contains(q, "deleteProjectV2Item"):
+					deleted = true
+					w.Write([]byte(`{"data":{"deleteProjectV2Item":{"deletedItemId":"PVTI_source"}}}`))
+				default:
+					t.Errorf("unexpected request %s", q)
+				}
+			})
+
+			err := moveItem(gql, "PVT_source", "PVT_dest", it, copied, tt.keep)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("moveItem error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("moveItem: %v", err)
+			}
+			if !set {
+				t.Error("the copied field wasn't set")
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted from source = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/cache"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// defaultCacheDir is where fetched data and snapshots are kept between runs.
+var defaultCacheDir = filepath.Join(".cache", "team-board")
+
+// statusFlags configures time-in-status tracking.
+type statusFlags struct {
+	field      string
+	stuck      string
+	stuckDays  int
+	setStuck   bool
+	stuckField string
+	keep       int
+}
+
+func registerStatusFlags(fs *flag.FlagSet) *statusFlags {
+	f := &statusFlags{}
+	fs.StringVar(&f.field, "status-field", "Status", "Board field holding each item's status")
+	fs.StringVar(&f.stuck, "stuck-status", "In Review", "Status to watch for stuck items (case-insensitive substring)")
+	fs.IntVar(&f.stuckDays, "stuck-days", 7, "Flag items in --stuck-status for more than N days (0 = off)")
+	fs.BoolVar(&f.setStuck, "set-stuck", false, "Write the date each stuck item entered --stuck-status to a date field")
+	fs.StringVar(&f.stuckField, "stuck-field", "Stuck since", "Date field used by --set-stuck")
+	fs.IntVar(&f.keep, "snapshot-keep", 120, "Number of status snapshots to keep per board")
+	return f
+}
+
+// statusTracker holds the computed time-in-status for one board.
+type statusTracker struct {
+	flags *statusFlags
+	since map[string]time.Time // project item ID → in current status since
+	now   time.Time
+}
+
+// trackStatus loads the board's status history, computes how long each item
+// has been in its current status, and, if record is set, records a new
+// snapshot for next time.
+func trackStatus(gql *ghgql.Client, cacheDir string, ref boardsync.Source, list []board.ProjectItemWithFields, f *statusFlags, record bool) *statusTracker {
+	prefix := cache.SafeString(fmt.Sprintf("status_%s_%d_", ref.Owner, ref.Number))
+	now := time.Now()
+
+	history, err := cache.ReadSnapshots[items.StatusEntry](cacheDir, prefix)
+	if err != nil {
+		slog.Warn("Could not read status history", "err", err)
+	}
+	migrateSnapshotIDs(gql, history)
+	slog.Info("Loaded status snapshots", "board", ref, "count", len(history))
+
+	t := &statusTracker{
+		flags: f,
+		since: items.StatusSince(list, f.field, history, now),
+		now:   now,
+	}
+
+	if !record {
+		slog.Info("[DRY-RUN] Would save a status snapshot", "board", ref)
+		return t
+	}
+	cache.Write(cacheDir, prefix+cache.Timestamp()+".json", items.StatusEntries(list, f.field))
+	if _, err := cache.Clean(cacheDir, prefix, f.keep); err != nil {
+		slog.Warn("Could not prune status snapshots", "err", err)
+	}
+	return t
+}
+
+// migrateSnapshotIDs rewrites legacy-format item IDs in snapshots written
+// before GitHub's node ID migration to their new-format equivalents, so they
+// still match the IDs on the board. IDs that can't be resolved are kept as
+// they are; those items just look new for one run.
+func migrateSnapshotIDs(gql *ghgql.Client, history []cache.Snapshot[items.StatusEntry]) {
+	var ids []string
+	for _, snap := range history {
+		for _, e := range snap.Items {
+			if ghgql.IsLegacyID(e.ItemID) {
+				ids = append(ids, e.ItemID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	next, err := ghgql.NextIDs(gql, ids)
+	if err != nil {
+		slog.Warn("Could not re-resolve legacy item IDs", "err", err)
+		return
+	}
+	for _, snap := range history {
+		for i, e := range snap.Items {
+			if id, ok := next[e.ItemID]; ok {
+				snap.Items[i].ItemID = id
+			}
+		}
+	}
+	slog.Info("Re-resolved legacy item IDs in cached snapshots", "count", len(next))
+}
+
+// isStuck reports whether item has sat in the watched status past the threshold.
+func (t *statusTracker) isStuck(item board.ProjectItemWithFields) bool {
+	if t.flags.stuckDays <= 0 || !items.StatusMatches(item.Fields[t.flags.field], t.flags.stuck) {
+		return false
+	}
+	return items.DaysSince(t.since[item.ItemID], t.now) > t.flags.stuckDays
+}
+
+// annotate is an items.Annotator printing time-in-status and stuck flags.
+func (t *statusTracker) annotate(item board.ProjectItemWithFields) []items.Annotation {
+	status := item.Fields[t.flags.field]
+	if status == "" {
+		return nil
+	}
+	since := t.since[item.ItemID]
+	out := []items.Annotation{{
+		Label: "In status",
+		Value: fmt.Sprintf("%s for %dd (since %s)", status, items.DaysSince(since, t.now), since.Local().Format("2006-01-02")),
+	}}
+	if t.isStuck(item) {
+		out = append(out, items.Annotation{Label: "STUCK", Value: fmt.Sprintf("over %dd threshold", t.flags.stuckDays)})
+	}
+	return out
+}
+
+// report prints a summary of stuck items.
+func (t *statusTracker) report(list []board.ProjectItemWithFields) {
+	if t.flags.stuckDays <= 0 {
+		return
+	}
+	var stuck []board.ProjectItemWithFields
+	for _, item := range list {
+		if t.isStuck(item) {
+			stuck = append(stuck, item)
+		}
+	}
+	fmt.Printf("\n=== Stuck in %q for more than %d day(s): %d ===\n", t.flags.stuck, t.flags.stuckDays, len(stuck))
+	for _, item := range stuck {
+		fmt.Printf("  #%-5d %-60s %dd  %s\n", item.Number, items.Truncate(item.Title, 60),
+			items.DaysSince(t.since[item.ItemID], t.now), item.URL)
+	}
+}
+
+// writeStuckField sets the stuck-since date on stuck items and clears it on
+// items that are no longer stuck.
+func (t *statusTracker) writeStuckField(gql *ghgql.Client, project *board.ProjectWithFields, list []board.ProjectItemWithFields) {
+	name := t.flags.stuckField
+	fields := board.EnsureFields(gql, project.ID, []board.FieldSpec{{Name: name, Type: "DATE"}}, project.Fields)
+	def, ok := fields[name]
+	if !ok {
+		slog.Warn("Field not available on the board, skipping --set-stuck", "field", name)
+		return
+	}
+
+	set, cleared, errors := 0, 0, 0
+	for _, item := range list {
+		current := item.Fields[name]
+		if !t.isStuck(item) {
+			if current == "" {
+				continue
+			}
+			if err := board.ClearItemField(gql, project.ID, item.ItemID, def.ID); err != nil {
+				slog.Error("Could not clear field", "field", name, "item", itemRef(item), "err", err)
+				errors++
+				continue
+			}
+			cleared++
+			continue
+		}
+		want := t.since[item.ItemID].Format("2006-01-02")
+		if current == want {
+			continue
+		}
+		if err := board.UpdateItemField(gql, project.ID, item.ItemID, def.ID, board.FieldValue{Date: want}); err != nil {
+			slog.Error("Could not set field", "field", name, "item", itemRef(item), "err", err)
+			errors++
+			continue
+		}
+		set++
+	}
+	slog.Info("Updated stuck dates", "field", name, "set", set, "cleared", cleared, "errors", errors)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+)
+
+func TestTrackStatusRecord(t *testing.T) {
+	list := []board.ProjectItemWithFields{
+		{ItemID: "PVTI_1", Repo: "kubernetes/kubernetes", Number: 1, Fields: map[string]string{"Status": "In Review"}},
+	}
+	for _, record := range []bool{false, true} {
+		dir := t.TempDir()
+		gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		})
+		f := &statusFlags{field: "Status", stuck: "In Review", stuckDays: 7, keep: 120}
+		tracker := trackStatus(gql, dir, boardsync.Source{Owner: "my-org", Number: 1}, list, f, record)
+		if _, ok := tracker.since["PVTI_1"]; !ok {
+			t.Errorf("record=%v: no time in status for PVTI_1", record)
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		want := 0
+		if record {
+			want = 1
+		}
+		if len(entries) != want {
+			t.Errorf("record=%v: saved %d snapshots, want %d", record, len(entries), want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/expr"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// builtinStatusField is the single-select field every board starts with.
+const builtinStatusField = "Status"
+
+// statusRule is one --status-rules entry: items matching prog start in
+// status.
+type statusRule struct {
+	status string
+	prog   *expr.Program
+}
+
+// parseStatusRules parses --status-rules' semicolon-separated
+// Status=expression entries, e.g.
+// 'Triaged="triage/accepted" in item.labels; In Progress=item.state == "OPEN"'.
+func parseStatusRules(spec string) ([]statusRule, error) {
+	var rules []statusRule
+	for _, entry := range strings.Split(spec, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		status, src, ok := strings.Cut(entry, "=")
+		status, src = strings.TrimSpace(status), strings.TrimSpace(src)
+		if !ok || status == "" || src == "" {
+			return nil, fmt.Errorf("invalid entry %q (expected Status=expression)", entry)
+		}
+		prog, err := expr.Compile(src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", status, err)
+		}
+		rules = append(rules, statusRule{status: status, prog: prog})
+	}
+	return rules, nil
+}
+
+// statusFor returns the status of the first rule it matches, or "" if it
+// matches none. A rule that fails to evaluate for it is skipped with a
+// warning.
+func statusFor(rules []statusRule, it board.ProjectItemWithFields, now time.Time) string {
+	if len(rules) == 0 || it.Type == "DraftIssue" {
+		return ""
+	}
+	env := items.ExprEnv(it, now)
+	for _, r := range rules {
+		match, err := r.prog.EvalBool(env)
+		if err != nil {
+			slog.Warn("Could not evaluate --status-rules", "status", r.status, "item", itemRef(it), "err", err)
+			continue
+		}
+		if match {
+			return r.status
+		}
+	}
+	return ""
+}
+
+// ruleStatuses returns the statuses rules can set, in order, once each.
+func ruleStatuses(rules []statusRule) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		if !seen[strings.ToLower(r.status)] {
+			seen[strings.ToLower(r.status)] = true
+			out = append(out, r.status)
+		}
+	}
+	return out
+}
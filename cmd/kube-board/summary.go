@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/cache"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/version"
+)
+
+// summaryKeep is how many run summaries are kept per board.
+const summaryKeep = 20
+
+// runSummary is the machine-readable record of one sync, written to the
+// cache directory after every run so dashboards and other automation don't
+// have to scrape logs.
+type runSummary struct {
+	Command     string          `json:"command"`
+	Version     string          `json:"version"`
+	Board       string          `json:"board"`       // destination, owner/title
+	Fingerprint string          `json:"fingerprint"` // same sources and filters → same value
+	DryRun      bool            `json:"dry_run,omitempty"`
+	StartedAt   time.Time       `json:"started_at"`
+	Duration    float64         `json:"duration_seconds"`
+	Stages      []filterStage   `json:"stages"` // items left after each filter
+	Items       int             `json:"items"`  // items synced
+	Mutations   mutationCounts  `json:"mutations"`
+	Points      int             `json:"points"`   // GraphQL points consumed (see ghgql.Spent)
+	Requests    int             `json:"requests"` // GraphQL requests sent
+	Errors      []string        `json:"errors,omitempty"`
+	Failures    []failureRecord `json:"failures,omitempty"` // mutations GitHub rejected
+}
+
+type mutationCounts struct {
+	Added         int `json:"added"`
+	FieldsUpdated int `json:"fields_updated"`
+	Removed       int `json:"removed"`
+	Unlinked      int `json:"unlinked"`
+	Deferred      int `json:"deferred"`
+	Failed        int `json:"failed"`
+}
+
+// fingerprint identifies what the plan syncs: its sources, destination, and
+// filters. Runs of the same configuration share a fingerprint.
+func (p *syncPlan) fingerprint() string {
+	f := p.filters
+	parts := []string{
+		*p.sources, *p.searches, *p.owner, *p.name,
+		f.maxSize, fmt.Sprint(f.minAge), fmt.Sprint(f.maxAge), f.filter, f.repoFilter, f.filters,
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:6])
+}
+
+// run collects and, unless dryRun, writes the plan's items, then records a
+// run summary in the cache directory (and, under GitHub Actions, the job
+// summary and step outputs). It returns the items synced and the
+// summary; the summary's Errors hold err's message too, and its Failures the
+// mutations GitHub rejected while the sync carried on.
+func (p *syncPlan) run(ctx context.Context, sy *boardsync.Syncer, token, command string, dryRun bool) ([]board.ProjectItemWithFields, *runSummary, error) {
+	s := &runSummary{
+		Command:     command,
+		Version:     version.Get().Version,
+		Board:       *p.owner + "/" + *p.name,
+		Fingerprint: p.fingerprint(),
+		DryRun:      dryRun,
+		StartedAt:   time.Now(),
+	}
+	before, budgetErr := graphQLRemaining(token)
+	spentBefore, requestsBefore := ghgql.Spent()
+	driftBefore := ghgql.Drift()
+
+	list, err := p.collect(ctx, sy)
+	s.Stages, s.Items = p.filters.stages, len(list)
+	var changes *board.Changes
+	if err == nil && !dryRun {
+		changes, err = p.write(ctx, sy, token, list)
+		if changes != nil {
+			s.Mutations = mutationCounts{
+				Added:         len(changes.Added),
+				FieldsUpdated: len(changes.Updated),
+				Removed:       len(changes.Removed),
+				Unlinked:      len(changes.Unlinked),
+				Deferred:      len(changes.Deferred),
+				Failed:        len(changes.Failed),
+			}
+			s.Failures = failureRecords(changes.Failed)
+		}
+	}
+
+	s.Duration = time.Since(s.StartedAt).Seconds()
+	spent, requests := ghgql.Spent()
+	s.Points, s.Requests = spent-spentBefore, requests-requestsBefore
+	if drift := ghgql.Drift() - driftBefore; drift > 0 {
+		slog.Info("GraphQL cost; something else spent from this token's budget meanwhile", "points", s.Points, "requests", s.Requests, "other", drift)
+	} else if after, afterErr := graphQLRemaining(token); afterErr == nil && budgetErr == nil && after <= before && before-after != s.Points {
+		slog.Info("GraphQL cost; something else is spending this token too", "points", s.Points, "requests", s.Requests, "spent", before-after)
+	} else {
+		slog.Info("GraphQL cost", "points", s.Points, "requests", s.Requests)
+	}
+	if err != nil {
+		s.Errors = append(s.Errors, err.Error())
+	}
+	writeSummary(s)
+	reportToActions(s, changes)
+	return list, s, err
+}
+
+// exitInterrupted reports how far a sync cancelled by SIGINT got and exits
+// with the conventional status 130.
+func exitInterrupted(s *runSummary) {
+	m := s.Mutations
+	slog.Warn("Interrupted",
+		"seconds", int(s.Duration), "added", m.Added, "updated", m.FieldsUpdated, "removed", m.Removed, "deferred", m.Deferred)
+	if m.Deferred > 0 {
+		slog.Warn("Run kube-board resume to finish the deferred mutations, or sync again")
+	}
+	exit(statusInterrupted)
+}
+
+// writeSummary saves s as summary_<board>_<timestamp>.json, pruning old ones.
+func writeSummary(s *runSummary) {
+	prefix := cache.SafeString(fmt.Sprintf("summary_%s_", s.Board))
+	cache.Write(defaultCacheDir, prefix+cache.Timestamp()+".json", s)
+	if _, err := cache.Clean(defaultCacheDir, prefix, summaryKeep); err != nil {
+		slog.Warn("Could not prune run summaries", "err", err)
+	}
+}
@@ -0,0 +1,530 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// syncOptions holds the flags shared by `sync-boards` and `daemon`.
+type syncOptions struct {
+	sources        *string
+	searches       *string
+	owner          *string
+	name           *string
+	visibility     *string
+	description    *string
+	readme         *string
+	linkRepos      *string
+	unlinkRepos    *bool
+	template       *string
+	templateDrafts *bool
+	templateViews  *bool
+	collaborators  *string
+	adminTeams     *string
+	readTeams      *string
+	pruneCollabs   *bool
+	pruneFields    *bool
+	removeStale    *bool
+	staleAction    *string
+	newItems       *string
+	ageField       *string
+	sourceField    *string
+	visField       *string
+	priorityField  *string
+	priorityType   *string
+	priorityPath   *string
+	statusField    *string
+	statusMap      *string
+	statusRules    *string
+	copyFields     *string
+	conflict       *string
+	statusOrder    *string
+	preferSource   *string
+	changelog      *string
+	changelogTitle *string
+	changelogKeep  *int
+	budgetReserve  *int
+	filters        *listFlags
+}
+
+// --new-items values.
+const (
+	newItemsBottom   = "bottom"
+	newItemsTop      = "top"
+	newItemsPriority = "priority"
+)
+
+// registerSyncFlags adds the sync flags to fs.
+func registerSyncFlags(fs *flag.FlagSet) *syncOptions {
+	return &syncOptions{
+		sources:        fs.String("source", os.Getenv("GITHUB_SOURCE_BOARDS"), "Comma-separated source boards (owner/projects/N)"),
+		searches:       fs.String("search", os.Getenv("GITHUB_SOURCE_SEARCHES"), "Semicolon-separated issue/PR searches whose results are synced along with the source boards' items, e.g. 'repo:kubernetes/enhancements label:sig/auth milestone:v1.36'"),
+		owner:          fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Destination board owner (user or org)"),
+		name:           fs.String("name", os.Getenv("GITHUB_DEST_BOARD_NAME"), "Destination board title (created if missing); may use {{.Milestone}}, {{.Org}}, and {{.Date}}, e.g. \"SIG Auth {{.Milestone}}\""),
+		visibility:     fs.String("visibility", cmp.Or(os.Getenv("GITHUB_DEST_BOARD_VISIBILITY"), board.Private), "Visibility enforced on the destination board every run, new or existing: private or public (a change is logged as a warning)"),
+		description:    fs.String("description", os.Getenv("GITHUB_DEST_BOARD_DESCRIPTION"), "Short description set on the destination board; may use --name's fields and {{.Query}} (the sources and searches), e.g. \"Generated from {{.Query}} every hour — do not edit\""),
+		readme:         fs.String("readme", os.Getenv("GITHUB_DEST_BOARD_README"), "README (Markdown) set on the destination board, or @file to read it from a file; may use the same fields as --description"),
+		linkRepos:      fs.String("link-repos", os.Getenv("GITHUB_LINK_REPOS"), "Comma-separated repos (owner/name) to link to the destination board"),
+		unlinkRepos:    fs.Bool("unlink-repos", false, "Unlink repos from the destination board that aren't in --link-repos (default: report only)"),
+		template:       fs.String("template", os.Getenv("GITHUB_DEST_TEMPLATE_PROJECT"), "Create a missing destination board by copying this template project (owner/N) instead of starting empty"),
+		templateDrafts: fs.Bool("template-drafts", false, "Also copy the --template project's draft items"),
+		templateViews:  fs.Bool("template-views", false, "If the destination board already exists, recreate any --template views it is missing"),
+		collaborators:  fs.String("collaborators", os.Getenv("GITHUB_DEST_BOARD_COLLABORATORS"), "Share the destination board: login=role entries (read, write, admin, none); \"org/team\" logins are teams"),
+		adminTeams:     fs.String("admin-team", "", "Grant these teams (org/team-slug, comma-separated) admin on the destination board, e.g. kubernetes/sig-auth-leads"),
+		readTeams:      fs.String("read-team", "", "Grant these teams (org/team-slug, comma-separated) read access on the destination board, e.g. kubernetes/sig-auth"),
+		pruneCollabs:   fs.Bool("prune-collaborators", false, "Revoke access that earlier runs of this tool granted and the collaborator policy no longer covers (default: report only); collaborators added by hand are not seen, as GitHub's API can't list them"),
+		pruneFields:    fs.Bool("prune-fields", false, "Delete fields an earlier sync created that are no longer synced, with their values (default: report only)"),
+		removeStale:    fs.Bool("sync", false, "Remove items from the destination board that are no longer in the source set"),
+		newItems:       fs.String("new-items", newItemsBottom, "Where items added this run go: bottom (GitHub's default), top, or priority (the top, highest --priority-config score first)"),
+		staleAction:    fs.String("stale-action", board.StaleDelete, "What --sync does with stale items: delete, archive (restored if they return), or status:<option> to move them to a Status column such as status:Done"),
+		ageField:       fs.String("age-field", "", "Write each item's age in days to this number field (e.g. \"Age\")"),
+		sourceField:    fs.String("source-field", "Source project", "Write the title of the board each item came from to this text field (\"\" to disable)"),
+		visField:       fs.String("visibility-field", "Visibility", "Write public/private (the item's repository visibility) to this single-select field (\"\" to disable)"),
+		priorityField:  fs.String("priority-field", "", "Write each item's computed priority to this field (e.g. \"Priority\")"),
+		priorityType:   fs.String("priority-type", "single-select", "Field type for --priority-field: single-select (bucket name) or number (raw score)"),
+		priorityPath:   fs.String("priority-config", "", "Path to a priority heuristic YAML file (see cmd/kube-board/priority.yaml; default built in)"),
+		statusField:    fs.String("copy-status", "", "Copy this single-select field (e.g. \"Status\") from the source boards to the destination"),
+		statusMap:      fs.String("status-map", "", "Translate copied statuses: From=To pairs (\"Todo=Backlog,*=Triage\") or a YAML file"),
+		statusRules:    fs.String("status-rules", "", "Set the Status of items added to the board (and any still without one): semicolon-separated Status=expression entries, first match wins, e.g. 'Triaged=\"triage/accepted\" in item.labels; In Progress=item.state == \"OPEN\"'. Fields: as --filter"),
+		copyFields:     fs.String("copy-fields", "", "Copy these fields (comma-separated, e.g. \"Stage,PRR\") from the source boards to the destination, creating any it lacks like the source's"),
+		conflict:       fs.String("conflict-policy", policyFirstWins, "Which copy wins when an item is on several source boards: first-wins, most-advanced-status, or project-priority"),
+		statusOrder:    fs.String("status-order", defaultStatusOrder, "Statuses from least to most advanced, for --conflict-policy most-advanced-status"),
+		preferSource:   fs.String("prefer-source", "", "Source boards in order of precedence, for --conflict-policy project-priority"),
+		changelog:      fs.String("changelog", "", "After each sync, record what changed on the board: draft (a pinned draft item) or status (a project status update)"),
+		changelogTitle: fs.String("changelog-title", "Sync changelog", "Title of the --changelog draft item"),
+		changelogKeep:  fs.Int("changelog-keep", 10, "Number of runs kept in the --changelog draft item"),
+		budgetReserve:  fs.Int("budget-reserve", 100, "GraphQL points to leave unspent; item adds, field writes, and removals beyond that are deferred to kube-board resume"),
+		filters:        registerListFlags(fs),
+	}
+}
+
+// syncPlan is a validated set of sync options, ready to run repeatedly.
+type syncPlan struct {
+	*syncOptions
+	refs     []boardsync.Source
+	queries  []string
+	priority *items.PriorityConfig
+	statuses items.StatusMap
+	rules    []statusRule
+	collabs  []board.Collaborator
+	template *board.Template
+	policy   conflictPolicy
+}
+
+// plan validates the options, exiting on invalid input. requireDest is false
+// for dry runs, which never touch the destination board.
+func (o *syncOptions) plan(requireDest bool) *syncPlan {
+	o.filters.validate()
+
+	p := &syncPlan{syncOptions: o, priority: items.DefaultPriorityConfig()}
+	for _, s := range splitList(*o.sources) {
+		ref, err := boardsync.ParseSource(s)
+		if err != nil {
+			fatalf("--source: %v", err)
+		}
+		p.refs = append(p.refs, ref)
+	}
+	for _, q := range strings.Split(*o.searches, ";") {
+		if q = strings.TrimSpace(q); q != "" {
+			p.queries = append(p.queries, q)
+		}
+	}
+	if len(p.refs) == 0 && len(p.queries) == 0 {
+		fatal("at least one source board or search is required (--source/--search or GITHUB_SOURCE_BOARDS/GITHUB_SOURCE_SEARCHES)")
+	}
+	var err error
+	if *o.priorityPath != "" {
+		if p.priority, err = items.LoadPriorityConfig(*o.priorityPath); err != nil {
+			fatalf("Error loading priority config: %v", err)
+		}
+	}
+	if p.statuses, err = items.ParseStatusMap(*o.statusMap); err != nil {
+		fatalf("--status-map: %v", err)
+	}
+	if p.rules, err = parseStatusRules(*o.statusRules); err != nil {
+		fatalf("--status-rules: %v", err)
+	}
+	if *o.template != "" {
+		ref, err := boardsync.ParseSource(*o.template)
+		if err != nil {
+			fatalf("--template: %v", err)
+		}
+		p.template = &board.Template{Owner: ref.Owner, Number: ref.Number, IncludeDrafts: *o.templateDrafts, Views: *o.templateViews}
+	} else if *o.templateViews {
+		fatal("--template-views requires --template")
+	}
+	if p.collabs, err = collaboratorPolicy(*o.adminTeams, *o.readTeams, *o.collaborators); err != nil {
+		fatalf("--collaborators: %v", err)
+	}
+	switch *o.newItems {
+	case newItemsBottom, newItemsTop, newItemsPriority:
+	default:
+		fatalf("--new-items must be bottom, top, or priority, got %q", *o.newItems)
+	}
+	if err := board.CheckStaleAction(*o.staleAction); err != nil {
+		fatalf("--stale-action: %v", err)
+	}
+	if err := validateVisibility(*o.visibility); err != nil {
+		fatalf("--visibility: %v", err)
+	}
+	if *o.unlinkRepos && len(splitList(*o.linkRepos)) == 0 {
+		fatal("--unlink-repos requires --link-repos; refusing to unlink every repository")
+	}
+	if len(p.statuses) > 0 && *o.statusField == "" {
+		fatal("--status-map requires --copy-status")
+	}
+	statusField := *o.statusField
+	if statusField == "" {
+		statusField = "Status"
+	}
+	if p.policy, err = newConflictPolicy(*o.conflict, statusField, *o.statusOrder, *o.preferSource); err != nil {
+		fatalf("--conflict-policy: %v", err)
+	}
+	if c := *o.changelog; c != "" && c != changelogDraft && c != changelogStatus {
+		fatalf("--changelog must be %s or %s, got %q", changelogDraft, changelogStatus, c)
+	}
+	if *o.changelogKeep < 1 {
+		fatal("--changelog-keep must be at least 1")
+	}
+	if *o.priorityType != "single-select" && *o.priorityType != "number" {
+		fatalf("--priority-type must be single-select or number, got %q", *o.priorityType)
+	}
+	if requireDest && (*o.owner == "" || *o.name == "") {
+		fatal("destination board owner and name are required (--owner/--name or GITHUB_DEST_BOARD_OWNER/GITHUB_DEST_BOARD_NAME)")
+	}
+	if *o.name, err = expandBoardName(*o.name, *o.owner); err != nil {
+		fatalf("--name: %v", err)
+	}
+	query := strings.Join(append(splitList(*o.sources), p.queries...), "; ")
+	if *o.description, err = expandBoardText(*o.description, *o.owner, query); err != nil {
+		fatalf("--description: %v", err)
+	}
+	if path, ok := strings.CutPrefix(*o.readme, "@"); ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fatalf("--readme: %v", err)
+		}
+		*o.readme = string(data)
+	}
+	if *o.readme, err = expandBoardText(*o.readme, *o.owner, query); err != nil {
+		fatalf("--readme: %v", err)
+	}
+	return p
+}
+
+// unsupported describes the settings of p that need a Projects API feature
+// the server lacks.
+func (p *syncPlan) unsupported(f *ghgql.Features) []string {
+	var gaps []string
+	if !f.ProjectsV2 {
+		return []string{"no Projects (ProjectV2) support"}
+	}
+	if p.template != nil && !f.CopyProject {
+		gaps = append(gaps, "--template needs copyProjectV2")
+	}
+	if *p.templateViews && !f.Views {
+		gaps = append(gaps, "--template-views needs ProjectV2View")
+	}
+	if len(splitList(*p.linkRepos)) > 0 && !f.LinkRepository {
+		gaps = append(gaps, "--link-repos needs linkProjectV2ToRepository")
+	}
+	if *p.changelog == changelogStatus && !f.StatusUpdates {
+		gaps = append(gaps, "--changelog status needs createProjectV2StatusUpdate")
+	}
+	return gaps
+}
+
+// syncBoardsOptions holds the `kube-board sync-boards` flags.
+type syncBoardsOptions struct {
+	*syncOptions
+	dryRun *bool
+}
+
+// registerSyncBoardsFlags adds the sync-boards flags to fs.
+func registerSyncBoardsFlags(fs *flag.FlagSet) *syncBoardsOptions {
+	return &syncBoardsOptions{
+		syncOptions: registerSyncFlags(fs),
+		dryRun:      fs.Bool("dry-run", false, "Print the items that would be synced without writing to the board"),
+	}
+}
+
+// runSync implements `kube-board sync-boards`: gather items from one or more source
+// boards and searches, filter them, and mirror the result onto the destination board.
+func runSync(args []string) {
+	fs := flag.NewFlagSet("sync-boards", flag.ExitOnError)
+	opts := registerSyncBoardsFlags(fs)
+	parseFlags(fs, args)
+	if len(configDestinations) > 0 {
+		runDestinations(fs, *opts.dryRun)
+		return
+	}
+	p := opts.plan(!*opts.dryRun)
+
+	s, token := newSyncer()
+	requireFeatures(s.WriteClient(), p)
+	if !*opts.dryRun {
+		requireProjectAccess(s.WriteClient(), p)
+	}
+	requireRepos(s.Client(), "--link-repos", splitList(*p.linkRepos))
+
+	ctx := interruptContext()
+	list, summary, err := p.run(ctx, s, token, "sync-boards", *opts.dryRun)
+	if ctx.Err() != nil {
+		exitInterrupted(summary)
+	}
+	logErrorSummary(summary)
+	if err != nil {
+		failf(summary.status(err), "Error syncing: %v", err)
+	}
+
+	if *opts.dryRun {
+		var annotators []items.Annotator
+		if *p.priorityField != "" {
+			annotators = append(annotators, items.PriorityAnnotator(p.priority, time.Now()))
+		}
+		items.PrintItems("Items to sync (dry run)", list, annotators...)
+	}
+	if status := summary.status(nil); status != statusOK {
+		exit(status)
+	}
+}
+
+// collect fetches and filters the items from every source board and search.
+func (p *syncPlan) collect(ctx context.Context, s *boardsync.Syncer) ([]board.ProjectItemWithFields, error) {
+	conflicts := 0
+	list, err := s.Collect(ctx, boardsync.Query{
+		Sources:  p.refs,
+		Searches: p.queries,
+		Resolve: func(copies []boardsync.Copy) boardsync.Copy {
+			kept := p.policy.resolve(copies)
+			if p.policy.logConflict(kept, copies) {
+				conflicts++
+			}
+			return kept
+		},
+		Filter: p.filters.apply,
+	})
+	if conflicts > 0 {
+		slog.Warn("Shared items have conflicting fields", "count", conflicts, "policy", p.policy.policy)
+	}
+	return list, err
+}
+
+// checkVisibility refuses to mirror items from private repositories onto a
+// public board, where anyone could read their titles.
+func checkVisibility(visibility string, list []board.ProjectItemWithFields) error {
+	if visibility != board.Public {
+		return nil
+	}
+	if n := items.CountPrivate(list); n > 0 {
+		return fmt.Errorf("%d items come from private repositories — they must only go on a private board (drop --visibility public, or filter them out with --filter '!item.private')", n)
+	}
+	return nil
+}
+
+// write mirrors list onto the destination board, computing any configured
+// field values first.
+func (p *syncPlan) write(ctx context.Context, s *boardsync.Syncer, token string, list []board.ProjectItemWithFields) (*board.Changes, error) {
+	if err := checkVisibility(*p.visibility, list); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	dest := boardsync.Destination{
+		Owner:       *p.owner,
+		Name:        *p.name,
+		LinkRepos:   splitList(*p.linkRepos),
+		UnlinkRepos: *p.unlinkRepos,
+		Visibility:  *p.visibility,
+		Description: *p.description,
+		Readme:      *p.readme,
+		RemoveStale: *p.removeStale,
+		StaleAction: *p.staleAction,
+
+		NewItemsOnTop: *p.newItems != newItemsBottom,
+
+		Collaborators: p.collabs,
+		Template:      p.template,
+	}
+	ageField, sourceField, priorityField := *p.ageField, *p.sourceField, *p.priorityField
+	if ageField != "" {
+		dest.Fields = append(dest.Fields, board.FieldSpec{Name: ageField, Type: "NUMBER"})
+	}
+	if sourceField != "" {
+		dest.Fields = append(dest.Fields, board.FieldSpec{Name: sourceField, Type: "TEXT"})
+	}
+	visField := *p.visField
+	if visField != "" {
+		dest.Fields = append(dest.Fields, board.FieldSpec{Name: visField, Type: "SINGLE_SELECT", Options: []string{items.VisibilityPublic, items.VisibilityPrivate}})
+	}
+	if priorityField != "" {
+		if *p.priorityType == "number" {
+			dest.Fields = append(dest.Fields, board.FieldSpec{Name: priorityField, Type: "NUMBER"})
+		} else {
+			dest.Fields = append(dest.Fields, board.FieldSpec{Name: priorityField, Type: "SINGLE_SELECT", Options: p.priority.BucketNames()})
+		}
+	}
+
+	statusField := *p.statusField
+	if statusField != "" {
+		// Ensure every option the mapped statuses need, in first-seen order.
+		var options []string
+		seen := make(map[string]bool)
+		for _, it := range list {
+			if s := p.statuses.Map(it.Fields[statusField]); s != "" && !seen[s] {
+				seen[s] = true
+				options = append(options, s)
+			}
+		}
+		if statusField == builtinStatusField {
+			for _, s := range ruleStatuses(p.rules) {
+				if !seen[s] {
+					seen[s] = true
+					options = append(options, s)
+				}
+			}
+		}
+		dest.Fields = append(dest.Fields, board.FieldSpec{Name: statusField, Type: "SINGLE_SELECT", Options: options})
+	}
+	var copied []string
+	for _, spec := range s.SourceFields(p.refs, splitList(*p.copyFields)) {
+		if spec.Name == statusField {
+			continue // --copy-status maps it
+		}
+		dest.Fields = append(dest.Fields, spec)
+		copied = append(copied, spec.Name)
+	}
+	if len(p.rules) > 0 && statusField != builtinStatusField {
+		dest.Fields = append(dest.Fields, board.FieldSpec{Name: builtinStatusField, Type: "SINGLE_SELECT", Options: ruleStatuses(p.rules)})
+	}
+	if len(p.rules) > 0 {
+		dest.InitialValues = func(it board.ProjectItemWithFields) map[string]string {
+			if s := statusFor(p.rules, it, now); s != "" {
+				return map[string]string{builtinStatusField: s}
+			}
+			return nil
+		}
+	}
+
+	dest.Values = func(it board.ProjectItemWithFields) map[string]string {
+		values := make(map[string]string)
+		if ageField != "" {
+			if age := items.AgeDays(it, now); age >= 0 {
+				values[ageField] = strconv.Itoa(age)
+			}
+		}
+		if sourceField != "" && it.ProjectTitle != "" {
+			values[sourceField] = it.ProjectTitle
+		}
+		if visField != "" {
+			if v := items.Visibility(it); v != "" {
+				values[visField] = v
+			}
+		}
+		if priorityField != "" && it.Type != "DraftIssue" {
+			score := p.priority.Score(it, now)
+			if *p.priorityType == "number" {
+				values[priorityField] = strconv.Itoa(int(math.Round(score))) // board echoes numbers back rounded
+			} else {
+				values[priorityField] = p.priority.Bucket(score)
+			}
+		}
+		if statusField != "" {
+			if s := p.statuses.Map(it.Fields[statusField]); s != "" {
+				values[statusField] = s
+			}
+		}
+		for _, name := range copied {
+			if v := it.Fields[name]; v != "" {
+				values[name] = v
+			}
+		}
+		return values
+	}
+
+	if *p.newItems == newItemsPriority {
+		// New items are moved to the top in list order.
+		list = slices.Clone(list)
+		scores := make(map[string]float64, len(list))
+		for _, it := range list {
+			scores[it.ContentID] = p.priority.Score(it, now)
+		}
+		slices.SortStableFunc(list, func(a, b board.ProjectItemWithFields) int {
+			return cmp.Compare(scores[b.ContentID], scores[a.ContentID])
+		})
+	}
+
+	var resetAt time.Time
+	dest.Budget, resetAt = mutationBudget(writeBudget(token), *p.budgetReserve)
+	changes, err := s.Write(ctx, dest, list)
+	if changes != nil {
+		saveDeferred(*p.owner, *p.name, changes.Deferred, resetAt)
+	}
+	if err != nil {
+		return changes, err
+	}
+	gql := s.WriteClient().WithContext(ctx)
+	if len(p.collabs) > 0 || *p.pruneCollabs {
+		if err := p.reconcileCollaborators(gql, changes.Project.ID); err != nil {
+			slog.Warn("Could not reconcile collaborators", "err", err)
+		}
+	}
+	if err := p.reconcileFields(gql, changes.Project.ID, dest.Fields, changes.Created); err != nil {
+		slog.Warn("Could not reconcile fields", "err", err)
+	}
+	if *p.changelog != "" {
+		if err := p.postChangelog(gql, changes); err != nil {
+			slog.Warn("Could not post the changelog", "changelog", *p.changelog, "err", err)
+		}
+	}
+	return changes, nil
+}
+
+// runDestinations implements sync-boards with a config file's destinations:
+// the query (sources, searches, and the shared filters) is collected once
+// and mirrored onto every destination board, each narrowed further by its
+// own filter. A destination's flags override the shared ones, except filter
+// and filters, which are combined with them.
+func runDestinations(fs *flag.FlagSet, dryRun bool) {
+	shared := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "dry-run" {
+			shared[f.Name] = f.Value.String()
+		}
+	})
+	var names []string
+	var plans []*syncPlan
+	for _, d := range configDestinations {
+		entry := sigEntry{Name: d.Name, Flags: make(map[string]string, len(d.Flags))}
+		for k, v := range d.Flags {
+			entry.Flags[k] = v
+		}
+		if f, g := shared["filter"], entry.Flags["filter"]; f != "" && g != "" {
+			entry.Flags["filter"] = "(" + f + ") && (" + g + ")"
+		}
+		if f, g := shared["filters"], entry.Flags["filters"]; f != "" && g != "" {
+			entry.Flags["filters"] = f + ";" + g
+		}
+		p, err := sigPlan(entry, shared)
+		if err != nil {
+			fatalf("destination %q: %v", d.Name, err)
+		}
+		names = append(names, d.Name)
+		plans = append(plans, p)
+	}
+	slog.Info("Syncing to destination boards", "count", len(plans))
+	runPlans("sync-boards", "destination", names, plans, 0, dryRun)
+}
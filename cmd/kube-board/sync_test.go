@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+func TestCheckVisibility(t *testing.T) {
+	public := board.ProjectItemWithFields{Repo: "kubernetes/kubernetes", Number: 1}
+	private := board.ProjectItemWithFields{Repo: "my-org/secret", Number: 2, Private: true}
+	tests := []struct {
+		name       string
+		visibility string
+		list       []board.ProjectItemWithFields
+		wantErr    string
+	}{
+		{name: "private board, private items", visibility: board.Private, list: []board.ProjectItemWithFields{public, private}},
+		{name: "public board, public items", visibility: board.Public, list: []board.ProjectItemWithFields{public}},
+		{name: "public board, no items", visibility: board.Public},
+		{name: "public board, private items", visibility: board.Public, list: []board.ProjectItemWithFields{public, private, private}, wantErr: "2 items come from private repositories"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkVisibility(tt.visibility, tt.list)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkVisibility(%q) = %v, want nil", tt.visibility, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkVisibility(%q) = %v, want %q", tt.visibility, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunDryRunWritesNothing(t *testing.T) {
+	saved := defaultCacheDir
+	defaultCacheDir = t.TempDir()
+	t.Cleanup(func() { defaultCacheDir = saved })
+	t.Setenv("GITHUB_ACTIONS", "")
+
+	var queries []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		if !strings.HasSuffix(req.URL.Path, "/graphql") {
+			http.NotFound(rec, req) // the REST rate limit; run carries on without it
+		} else {
+			var body struct {
+				Query string `json:"query"`
+			}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			queries = append(queries, body.Query)
+			if !strings.Contains(body.Query, "search(") {
+				t.Errorf("dry run went past the search: %s", body.Query) // e.g. a mutation, or reading the destination
+			}
+			io.WriteString(rec, `{"data": {"search": {"issueCount": 1, "nodes": [
+				{"__typename": "Issue", "id": "I_1", "number": 1, "title": "Fix it", "repository": {"nameWithOwner": "kubernetes/kubernetes"}}
+			], "pageInfo": {"hasNextPage": false}}}}`)
+		}
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	})
+	savedOpts := clientOptions
+	clientOptions = []ghgql.Option{ghgql.WithTransport(transport)}
+	t.Cleanup(func() { clientOptions = savedOpts })
+
+	fs := flag.NewFlagSet("sync-boards", flag.ContinueOnError)
+	opts := registerSyncBoardsFlags(fs)
+	if err := fs.Parse([]string{"--search", "repo:kubernetes/kubernetes label:sig/auth", "--owner", "o", "--name", "Board", "--dry-run"}); err != nil {
+		t.Fatal(err)
+	}
+	p := opts.plan(!*opts.dryRun)
+	sy := boardsync.New("test-token", clientOptions...)
+	for _, c := range []*ghgql.Client{sy.Client(), sy.WriteClient()} {
+		c.MinDelay, c.MaxRetries, c.RetryBackoff = 0, 0, 0
+	}
+
+	list, s, err := p.run(context.Background(), sy, "test-token", "sync-boards", *opts.dryRun)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || s.Items != 1 {
+		t.Errorf("collected %d items (summary %d), want 1", len(list), s.Items)
+	}
+	if len(queries) == 0 {
+		t.Error("no search was sent")
+	}
+	if !s.DryRun || s.Mutations != (mutationCounts{}) {
+		t.Errorf("summary = %+v, want a dry run with no mutations", s)
+	}
+	summaries, err := filepath.Glob(filepath.Join(defaultCacheDir, "summary_*.json"))
+	if err != nil || len(summaries) != 1 {
+		t.Errorf("summaries written = %q, %v; want one", summaries, err)
+	}
+}
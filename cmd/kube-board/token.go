@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/boardsync"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// tokenCmdTimeout bounds how long GITHUB_TOKEN_CMD and keychain lookups may
+// take, e.g. waiting on a password-manager prompt.
+const tokenCmdTimeout = time.Minute
+
+var (
+	tokenOnce   sync.Once
+	tokenValue  string
+	tokenSource string
+	tokenErr    error
+)
+
+// resolveToken returns the GitHub token and where it came from, looking in
+// turn at GITHUB_TOKEN, GITHUB_TOKEN_FILE, GITHUB_TOKEN_CMD, and
+// GITHUB_TOKEN_KEYCHAIN; the first that is set is used. With none set, the
+// gh CLI's token is used if gh is logged in (see ghToken). Keeping the raw
+// token out of the environment keeps it out of shell history, CI logs, and
+// `ps e`. The lookup runs once per process.
+func resolveToken() (token, source string, err error) {
+	tokenOnce.Do(func() {
+		tokenValue, tokenSource, tokenErr = lookupToken()
+		if tokenErr == nil && tokenValue != "" {
+			if err := validateToken(tokenValue); err != nil {
+				tokenErr = fmt.Errorf("token from %s: %w", tokenSource, err)
+			}
+		}
+	})
+	return tokenValue, tokenSource, tokenErr
+}
+
+func lookupToken() (token, source string, err error) {
+	if v := os.Getenv("GITHUB_TOKEN"); v != "" {
+		return v, "GITHUB_TOKEN", nil
+	}
+	if path := os.Getenv("GITHUB_TOKEN_FILE"); path != "" {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", "", fmt.Errorf("GITHUB_TOKEN_FILE: %w", err)
+		}
+		if info.Mode().Perm()&0o077 != 0 {
+			slog.Warn("Token file is readable by other users; chmod 600 it", "path", path, "mode", info.Mode().Perm())
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", "", fmt.Errorf("GITHUB_TOKEN_FILE: %w", err)
+		}
+		return strings.TrimSpace(string(data)), "GITHUB_TOKEN_FILE", nil
+	}
+	if command := os.Getenv("GITHUB_TOKEN_CMD"); command != "" {
+		shell, flag := "sh", "-c"
+		if runtime.GOOS == "windows" {
+			shell, flag = "cmd", "/C"
+		}
+		token, err := runTokenCommand(shell, flag, command)
+		if err != nil {
+			return "", "", fmt.Errorf("GITHUB_TOKEN_CMD: %w", err)
+		}
+		return token, "GITHUB_TOKEN_CMD", nil
+	}
+	if service := os.Getenv("GITHUB_TOKEN_KEYCHAIN"); service != "" {
+		var argv []string
+		switch runtime.GOOS {
+		case "darwin":
+			argv = []string{"security", "find-generic-password", "-s", service, "-w"}
+		case "linux", "freebsd", "openbsd":
+			argv = []string{"secret-tool", "lookup", "service", service}
+		default:
+			return "", "", fmt.Errorf("GITHUB_TOKEN_KEYCHAIN isn't supported on %s; use GITHUB_TOKEN_CMD with your credential manager", runtime.GOOS)
+		}
+		token, err := runTokenCommand(argv[0], argv[1:]...)
+		if err != nil {
+			return "", "", fmt.Errorf("GITHUB_TOKEN_KEYCHAIN %q: %w", service, err)
+		}
+		return token, "GITHUB_TOKEN_KEYCHAIN", nil
+	}
+	token, source = ghToken()
+	if token == "" && os.Getenv("GITHUB_REPLAY") != "" {
+		return "replay", "GITHUB_REPLAY", nil // replayed responses need no token
+	}
+	return token, source, nil
+}
+
+// ghToken returns the token the gh CLI uses, and where it came from, so
+// people already logged in with `gh auth login` need no second PAT: GH_TOKEN,
+// then `gh auth token` (which also reads gh's keyring), then, without gh on
+// the PATH, the oauth_token in gh's hosts.yml. It returns "" when gh isn't
+// logged in.
+func ghToken() (token, source string) {
+	if v := os.Getenv("GH_TOKEN"); v != "" {
+		return v, "GH_TOKEN"
+	}
+	if _, err := exec.LookPath("gh"); err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), tokenCmdTimeout)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, "gh", "auth", "token", "--hostname", ghgql.Host()).Output()
+		if err != nil {
+			return "", "" // not logged in; gh's message isn't ours to print
+		}
+		return strings.TrimSpace(string(out)), "gh auth token"
+	}
+	path := filepath.Join(ghConfigDir(), "hosts.yml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", ""
+	}
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		slog.Warn("Ignoring unreadable hosts file", "path", path, "err", err)
+		return "", ""
+	}
+	if t := hosts[ghgql.Host()].OAuthToken; t != "" {
+		return t, path
+	}
+	return "", ""
+}
+
+// ghConfigDir is where the gh CLI keeps its configuration.
+func ghConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	if dir := os.Getenv("AppData"); runtime.GOOS == "windows" && dir != "" {
+		return filepath.Join(dir, "GitHub CLI")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "gh")
+}
+
+// newSyncer returns a Syncer that reads with the GitHub token and, when
+// GITHUB_WRITE_TOKEN is set, writes the destination board with that one.
+// budget is the token list whose combined GraphQL budget a sync spends,
+// for the budget checks.
+func newSyncer() (s *boardsync.Syncer, budget string) {
+	token := requireToken()
+	write := strings.TrimSpace(os.Getenv("GITHUB_WRITE_TOKEN"))
+	if write == "" || write == token {
+		return boardsync.New(token, clientOptions...), token
+	}
+	slog.Info("Reading with one token and writing with GITHUB_WRITE_TOKEN", "source", tokenSource)
+	return boardsync.NewReadWrite(token, write, clientOptions...), token + "," + write
+}
+
+// writeBudget returns the token(s) whose budget the destination board's
+// mutations spend: GITHUB_WRITE_TOKEN when set, else budget.
+func writeBudget(budget string) string {
+	if write := strings.TrimSpace(os.Getenv("GITHUB_WRITE_TOKEN")); write != "" {
+		return write
+	}
+	return budget
+}
+
+// runTokenCommand runs name with args and returns its trimmed standard
+// output, which must not be empty. Standard error goes to the terminal, so
+// a password-manager prompt still reaches the user.
+func runTokenCommand(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenCmdTimeout)
+	defer cancel()
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, &stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err // the output may hold part of the token; never include it
+	}
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("printed nothing")
+	}
+	return token, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/version"
+)
+
+// versionOptions holds the `kube-board version` flags.
+type versionOptions struct {
+	short *bool
+}
+
+// registerVersionFlags adds the version flags to fs.
+func registerVersionFlags(fs *flag.FlagSet) *versionOptions {
+	return &versionOptions{
+		short: fs.Bool("short", false, "Print only the version"),
+	}
+}
+
+// runVersion implements `kube-board version`: print build information and
+// the GitHub API surface this build was written against, for bug reports.
+func runVersion(args []string) {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	opts := registerVersionFlags(fs)
+	parseFlags(fs, args)
+
+	info := version.Get()
+	if *opts.short {
+		fmt.Println(info.Version)
+		return
+	}
+
+	commit := info.Commit
+	if info.Modified {
+		commit += " (modified)"
+	}
+	fmt.Printf("kube-board %s\n", info.Version)
+	fmt.Printf("  Commit:       %s\n", commit)
+	fmt.Printf("  Built:        %s\n", info.Date)
+	fmt.Printf("  Go:           %s %s\n", info.GoVersion, info.Platform)
+	graphql, rest := ghgql.Endpoints()
+	fmt.Printf("  GraphQL API:  %s (Projects V2)\n", graphql)
+	fmt.Printf("  REST API:     %s (X-GitHub-Api-Version %s)\n", rest, ghgql.RESTAPIVersion)
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/upstream"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/webhook"
+)
+
+// Enforcement actions for --action.
+const (
+	enforceFlag    = "flag"    // write the missing requirements to --flag-field
+	enforceComment = "comment" // also ask for them in a comment on the issue/PR
+)
+
+// requireAssignee in --require means the issue or PR must have an assignee;
+// every other entry names a board field that must be set.
+const requireAssignee = "assignee"
+
+// enforceCommentMarker is appended to requirement comments so each item is
+// asked at most once.
+const enforceCommentMarker = "<!-- kube-board:needs-info -->"
+
+// enforcer applies the field-requirement policy to one board's items as
+// projects_v2_item events arrive.
+type enforcer struct {
+	gql         *ghgql.Client
+	project     *board.ProjectWithFields
+	statusField string
+	status      string
+	require     []string
+	action      string
+	flagField   string
+	dryRun      bool
+
+	queue chan string // item node IDs awaiting a check
+}
+
+// webhookOptions holds the `kube-board webhook` flags.
+type webhookOptions struct {
+	owner       *string
+	number      *int
+	listen      *string
+	statusField *string
+	status      *string
+	require     *string
+	action      *string
+	flagField   *string
+	dryRun      *bool
+}
+
+// registerWebhookFlags adds the webhook flags to fs.
+func registerWebhookFlags(fs *flag.FlagSet) *webhookOptions {
+	return &webhookOptions{
+		owner:       fs.String("owner", os.Getenv("GITHUB_DEST_BOARD_OWNER"), "Organization owning the board to govern"),
+		number:      fs.Int("number", envInt("GITHUB_DEST_BOARD_NUMBER", 0), "Board number"),
+		listen:      fs.String("listen", ":8080", "Address to serve /webhook and /healthz on"),
+		statusField: fs.String("status-field", "Status", "Single-select field holding each item's column"),
+		status:      fs.String("status", "In progress", "Status the requirements apply to (case-insensitive substring)"),
+		require:     fs.String("require", "assignee,Priority", "Comma-separated requirements: \"assignee\" and/or board field names"),
+		action:      fs.String("action", enforceFlag, "What to do about a missing requirement: flag or comment (comment also flags)"),
+		flagField:   fs.String("flag-field", "Needs info", "Text field listing an item's missing requirements (created if missing)"),
+		dryRun:      fs.Bool("dry-run", false, "Log what would change without writing to the board or upstream"),
+	}
+}
+
+// runWebhook implements `kube-board webhook`: receive projects_v2_item
+// deliveries from an organization webhook and enforce lightweight board
+// governance — an item moved to --status without an assignee or the
+// --require'd fields gets them listed in --flag-field (and, with
+// --action=comment, a comment asking for them). The flag is cleared once the
+// item is complete or leaves the status.
+func runWebhook(args []string) {
+	fs := flag.NewFlagSet("webhook", flag.ExitOnError)
+	opts := registerWebhookFlags(fs)
+	parseFlags(fs, args)
+
+	// The secret is environment-only, like the other secrets, to keep it
+	// out of shell history and process listings.
+	secret := os.Getenv("GITHUB_WEBHOOK_SECRET")
+	if secret == "" {
+		fatal("GITHUB_WEBHOOK_SECRET is required")
+	}
+	if *opts.action != enforceFlag && *opts.action != enforceComment {
+		fatalf("--action must be %q or %q, got %q", enforceFlag, enforceComment, *opts.action)
+	}
+	reqs := splitList(*opts.require)
+	if len(reqs) == 0 {
+		fatal("--require lists no requirements")
+	}
+
+	gql := newClient()
+	project := openBoard(gql, *opts.owner, *opts.number)
+	if _, ok := project.Fields[*opts.statusField]; !ok {
+		fatalf("Board has no %q field", *opts.statusField)
+	}
+	for _, r := range reqs {
+		if _, ok := project.Fields[r]; r != requireAssignee && !ok {
+			fatalf("Board has no %q field to require", r)
+		}
+	}
+	if !*opts.dryRun {
+		project.Fields = board.EnsureFields(gql, project.ID, []board.FieldSpec{{Name: *opts.flagField, Type: "TEXT"}}, project.Fields)
+	}
+
+	e := &enforcer{
+		gql: gql, project: project, statusField: *opts.statusField, status: *opts.status,
+		require: reqs, action: *opts.action, flagField: *opts.flagField, dryRun: *opts.dryRun,
+		queue: make(chan string, 100),
+	}
+	go e.work()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) { e.handle(w, r, secret) })
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok\n")) })
+	srv := &http.Server{Addr: *opts.listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		slog.Info("Serving /webhook", "listen", *opts.listen, "status", *opts.status, "require", strings.Join(reqs, ", "))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatalf("Webhook server: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	slog.Info("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(shutdownCtx)
+}
+
+// handle verifies a delivery and queues the item it concerns. GitHub expects
+// a reply within ten seconds, so the check itself runs on the worker.
+func (e *enforcer) handle(w http.ResponseWriter, r *http.Request, secret string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST only", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 5<<20))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if err := webhook.VerifySignature(secret, r.Header, body); err != nil {
+		slog.Warn("Rejected delivery", "err", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	switch event := webhook.Event(r.Header); event {
+	case "ping":
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case "projects_v2_item":
+	default:
+		slog.Debug("Ignoring delivery", "event", event)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	var ev webhook.ProjectsV2ItemEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		http.Error(w, "bad payload", http.StatusBadRequest)
+		return
+	}
+	if ev.Item.ProjectNodeID != e.project.ID || ev.Item.ContentType == "DraftIssue" ||
+		(ev.Action != "created" && ev.Action != "edited" && ev.Action != "restored") {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	select {
+	case e.queue <- ev.Item.NodeID:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		slog.Warn("Check queue full, dropping delivery", "item", ev.Item.NodeID)
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}
+}
+
+// work checks queued items one at a time, so two deliveries for the same item
+// can't race each other's writes.
+func (e *enforcer) work() {
+	for itemID := range e.queue {
+		if err := e.check(itemID); err != nil {
+			slog.Error("Could not check item", "item", itemID, "err", err)
+		}
+	}
+}
+
+// missing returns the requirements item lacks.
+func (e *enforcer) missing(it board.ProjectItemWithFields) []string {
+	var out []string
+	for _, r := range e.require {
+		if r == requireAssignee {
+			if len(it.Assignees) == 0 {
+				out = append(out, "an assignee")
+			}
+		} else if it.Fields[r] == "" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// check reads the item's current state and brings its flag field (and, with
+// --action=comment, its upstream comment) in line with the policy. Writes are
+// skipped when the flag already holds the right value, so the events they
+// trigger settle at once.
+func (e *enforcer) check(itemID string) error {
+	it, _, err := board.FetchProjectItem(e.gql, itemID)
+	if err != nil {
+		return err
+	}
+	ref := itemRef(*it)
+
+	var missing []string
+	if items.StatusMatches(it.Fields[e.statusField], e.status) {
+		missing = e.missing(*it)
+	}
+	want := strings.Join(missing, ", ")
+	if it.Fields[e.flagField] == want {
+		return nil
+	}
+
+	if want == "" {
+		slog.Info("Requirements met, clearing flag", "item", ref, "field", e.flagField)
+	} else {
+		slog.Info("Item is missing requirements", "item", ref, "status", it.Fields[e.statusField], "missing", want)
+	}
+	if e.dryRun {
+		return nil
+	}
+
+	field := e.project.Fields[e.flagField]
+	if want == "" {
+		err = board.ClearItemField(e.gql, e.project.ID, it.ItemID, field.ID)
+	} else {
+		err = board.UpdateItemField(e.gql, e.project.ID, it.ItemID, field.ID, board.FieldValue{Text: want})
+	}
+	if err != nil {
+		return fmt.Errorf("setting %q: %w", e.flagField, err)
+	}
+
+	if want != "" && e.action == enforceComment {
+		asked, err := upstream.HasComment(e.gql, it.ContentID, enforceCommentMarker)
+		if err != nil {
+			return fmt.Errorf("reading comments: %w", err)
+		}
+		if !asked {
+			body := fmt.Sprintf("This was moved to **%s** but is missing %s. Please add it so the work can be tracked.\n\n%s",
+				it.Fields[e.statusField], want, enforceCommentMarker)
+			if err := upstream.AddComment(e.gql, it.ContentID, body); err != nil {
+				return fmt.Errorf("commenting: %w", err)
+			}
+			slog.Info("Asked for missing requirements", "item", ref, "missing", want)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+// whereOptions holds the `kube-board where` flags.
+type whereOptions struct {
+	issue         *string
+	statusField   *string
+	includeClosed *bool
+}
+
+// registerWhereFlags adds the where flags to fs.
+func registerWhereFlags(fs *flag.FlagSet) *whereOptions {
+	return &whereOptions{
+		issue:         fs.String("issue", "", "Issue or PR to look up, as owner/repo#number or its URL"),
+		statusField:   fs.String("status-field", "Status", "Field to report from each board"),
+		includeClosed: fs.Bool("include-closed", false, "Also list closed projects"),
+	}
+}
+
+// runWhere implements `kube-board where`: list every board an issue or PR
+// is on, with its status on each.
+func runWhere(args []string) {
+	fs := flag.NewFlagSet("where", flag.ExitOnError)
+	opts := registerWhereFlags(fs)
+	parseFlags(fs, args)
+
+	owner, repo, number, err := parseIssueRef(*opts.issue)
+	if err != nil {
+		fatalf("--issue: %v", err)
+	}
+
+	gql := newClient()
+	boards, err := board.FindItemBoards(gql, owner, repo, number, *opts.statusField)
+	if err != nil {
+		fatalf("Error looking up boards: %v", err)
+	}
+
+	var shown []board.Membership
+	for _, m := range boards {
+		if m.Closed && !*opts.includeClosed {
+			continue
+		}
+		shown = append(shown, m)
+	}
+
+	fmt.Printf("%s/%s#%d is on %d board(s)\n\n", owner, repo, number, len(shown))
+	if len(shown) == 0 {
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "BOARD\t%s\tNOTE\tURL\n", strings.ToUpper(*opts.statusField))
+	for _, m := range shown {
+		status := m.Status
+		if status == "" {
+			status = "-"
+		}
+		var notes []string
+		if m.Archived {
+			notes = append(notes, "archived")
+		}
+		if m.Closed {
+			notes = append(notes, "project closed")
+		}
+		fmt.Fprintf(w, "%s/%d %s\t%s\t%s\t%s\n", m.Owner, m.ProjectNumber, m.ProjectTitle, status, strings.Join(notes, ", "), m.ProjectURL)
+	}
+	w.Flush()
+}
+
+// parseIssueRef accepts "owner/repo#123" or an issue/PR URL such as
+// https://github.com/owner/repo/issues/123.
+func parseIssueRef(s string) (owner, repo string, number int, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", "", 0, fmt.Errorf("required (owner/repo#number)")
+	}
+	var path, num string
+	if rest, ok := strings.CutPrefix(s, "https://github.com/"); ok {
+		parts := strings.Split(strings.Trim(rest, "/"), "/")
+		if len(parts) < 4 || (parts[2] != "issues" && parts[2] != "pull") {
+			return "", "", 0, fmt.Errorf("unrecognized URL %q", s)
+		}
+		path, num = parts[0]+"/"+parts[1], parts[3]
+	} else {
+		var ok bool
+		if path, num, ok = strings.Cut(s, "#"); !ok {
+			return "", "", 0, fmt.Errorf("invalid reference %q (expected owner/repo#number)", s)
+		}
+	}
+	owner, repo, ok := strings.Cut(path, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", 0, fmt.Errorf("invalid repository in %q", s)
+	}
+	if number, err = strconv.Atoi(num); err != nil || number <= 0 {
+		return "", "", 0, fmt.Errorf("invalid number in %q", s)
+	}
+	return owner, repo, number, nil
+}
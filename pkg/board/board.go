@@ -2,12 +2,19 @@
 package board
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
+	"sort"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/tracing"
 )
 
 // Info holds basic information about a GitHub Projects V2 project.
@@ -19,79 +26,280 @@ type Info struct {
 }
 
 // Item is a minimal representation of a content item (issue, PR, or draft)
-// that can be added to a project board.
+// that can be added to a project board. A draft can't be added by NodeID,
+// so it is copied instead, and its NodeID kept in SourceIDField.
 type Item struct {
 	NodeID string
 	Number int
 	Title  string
-	Type   string // "Issue", "PullRequest", "DraftIssue"
+	Type   string            // "Issue", "PullRequest", "DraftIssue"
+	Body   string            // a draft's body
+	Fields map[string]string // destination field name → value to set (optional)
+
+	// InitialFields are set only while the field is empty on the board,
+	// as it is on a newly added item, so they never undo a manual change.
+	// Fields wins for a field in both.
+	InitialFields map[string]string
+
+	itemID string // the board item, once added this run
 }
 
 // Config holds the parameters for board operations.
 type Config struct {
-	Token     string   // GitHub PAT
-	Owner     string   // User/org owning the project board
-	Name      string   // Project board title
-	LinkRepos []string // "owner/repo" entries to link to the board
-	Sync      bool     // Remove stale items not in the current set
+	Token     string      // GitHub PAT
+	Owner     string      // User/org owning the project board
+	Name      string      // Project board title
+	LinkRepos []string    // "owner/repo" entries to link to the board
+	Sync      bool        // Remove stale items not in the current set
+	Fields    []FieldSpec // Fields to ensure on the board before writing Item.Fields
+
+	// StaleAction is what Sync does with a stale item: StaleDelete (the
+	// default, also for ""), StaleArchive, or "status:<option>" to move it
+	// to that Status column. See CheckStaleAction.
+	StaleAction string
+
+	// NewItemsOnTop moves the items added this run to the top of the
+	// board, in the order they're given, instead of leaving them at the
+	// bottom where triage misses them.
+	NewItemsOnTop bool
+
+	// Budget, when set, caps the item adds, field writes, and removals sent
+	// this run; the rest are returned in Changes.Deferred.
+	Budget *Budget
+
+	// ClientOptions configure the client UpdateBoard makes with Token,
+	// e.g. ghgql.WithTransport.
+	ClientOptions []ghgql.Option
+
+	// Context, when set, bounds the run. Once it is cancelled the mutation
+	// in flight finishes, the remaining adds are deferred, and UpdateBoard
+	// returns what it changed along with the context's error.
+	Context context.Context
+
+	// UnlinkRepos unlinks repositories that aren't in LinkRepos, so the
+	// board's repository list tracks the configuration. Without it they are
+	// only reported.
+	UnlinkRepos bool
+
+	// Collaborators are granted their roles on the board every run.
+	Collaborators []Collaborator
+
+	// Visibility is enforced on the board every run: Private (the default,
+	// also "") or Public. A change is logged as a warning.
+	Visibility string
+
+	// Description and Readme, when set, are the board's short description
+	// and README (see SetProjectDetails).
+	Description string
+	Readme      string
+
+	// Template, when set, is copied to create the board if it doesn't exist,
+	// instead of starting from an empty project.
+	Template *Template
+}
+
+// Board visibilities, for Config.Visibility and Spec.Visibility.
+const (
+	Private = "private"
+	Public  = "public"
+)
+
+// Template identifies a curated project that new boards are copied from.
+type Template struct {
+	Owner         string
+	Number        int
+	IncludeDrafts bool // also copy the template's draft items
+	Views         bool // recreate the template's views on a board that already exists
+}
+
+// Changes summarizes what UpdateBoard did to the board.
+type Changes struct {
+	Project   *Info
+	Added     []Item         // items newly added
+	Skipped   int            // items already present or that failed to add
+	Updated   []Item         // items whose field values were written
+	Unchanged int            // items whose field values were already current
+	Removed   []string       // titles of stale items removed, archived, or moved (Config.Sync)
+	Unlinked  []string       // repositories unlinked (Config.UnlinkRepos)
+	Created   []string       // names of Config.Fields that had to be created
+	Moved     map[string]int // Status option → items newly set to it
+	Deferred  []Op           // mutations left over when Config.Budget ran out or Config.Context was cancelled
+	Failed    []Failure
+}
+
+// Failure is one mutation UpdateBoard sent that GitHub rejected. The run
+// carries on past it; see ghgql.Category for telling the errors apart.
+type Failure struct {
+	Kind  string // OpAdd, OpSetField, OpRemove, OpArchive, or OpUnarchive
+	Label string // what the mutation was about, for logs
+	Err   error
 }
 
-// UpdateBoard creates or updates a GitHub Projects V2 board with the given items.
-func UpdateBoard(config Config, items []Item) error {
-	gql := ghgql.NewClient(config.Token)
+// UpdateBoard creates or updates a GitHub Projects V2 board with the given
+// items, returning what changed.
+func UpdateBoard(config Config, items []Item) (changes *Changes, err error) {
+	gql := ghgql.NewClient(config.Token, config.ClientOptions...)
+	if config.Context != nil {
+		gql = gql.WithContext(config.Context)
+	}
+
+	span := tracing.Start("board.UpdateBoard", attribute.Int("items.count", len(items)))
+	defer func() { tracing.EndWithError(span, err) }()
 
-	log.Printf("Board name: %q", config.Name)
-	log.Printf("Board owner: %s", config.Owner)
+	slog.Info("Updating board", "board", config.Name, "owner", config.Owner)
 
 	// Find or create the project
 	project, err := FindProject(gql, config.Owner, config.Name)
 	if err != nil {
-		return fmt.Errorf("searching for project: %w", err)
+		return nil, fmt.Errorf("searching for project: %w", err)
 	}
 
-	if project == nil {
-		log.Printf("Project %q not found, creating...", config.Name)
+	if project == nil && config.Template != nil {
+		t := config.Template
+		slog.Info("Project not found, copying template", "board", config.Name, "template", fmt.Sprintf("%s/projects/%d", t.Owner, t.Number))
+		project, err = CopyTemplate(gql, *t, config.Owner, config.Name)
+		if err != nil {
+			return nil, fmt.Errorf("copying template: %w", err)
+		}
+		slog.Info("Created project", "url", project.URL)
+	} else if project == nil {
+		slog.Info("Project not found, creating it", "board", config.Name)
 		project, err = CreateProject(gql, config.Owner, config.Name)
 		if err != nil {
-			return fmt.Errorf("creating project: %w", err)
+			return nil, fmt.Errorf("creating project: %w", err)
 		}
-		log.Printf("Created project: %s", project.URL)
+		slog.Info("Created project", "url", project.URL)
 	} else {
-		log.Printf("Found existing project: %s", project.URL)
+		slog.Info("Found existing project", "url", project.URL)
+		if t := config.Template; t != nil && t.Views {
+			slog.Info("Ensuring the template's views", "template", fmt.Sprintf("%s/projects/%d", t.Owner, t.Number))
+			if err := EnsureTemplateViews(gql, *t, config.Owner, project); err != nil {
+				slog.Warn("Could not copy template views", "err", err)
+			}
+		}
+	}
+	changes = &Changes{Project: project}
+
+	if err := SetVisibility(gql, project, config.Visibility == Public); err != nil {
+		return changes, fmt.Errorf("setting visibility: %w", err)
+	}
+
+	if err := SetProjectDetails(gql, project.ID, config.Description, config.Readme); err != nil {
+		slog.Warn("Could not set project details", "err", err)
 	}
 
 	// Add items to the board
-	log.Printf("Adding %d item(s) to project board...", len(items))
-	added, skipped, err := addItems(gql, project.ID, items)
+	slog.Info("Adding items to project board", "count", len(items))
+	phase := tracing.Start("board.addItems")
+	changes.Added, changes.Skipped, changes.Deferred, changes.Failed, err = addItems(gql, project.ID, items, config.Budget)
+	phase.SetAttributes(attribute.Int("items.added", len(changes.Added)), attribute.Int("items.skipped", changes.Skipped))
+	tracing.EndWithError(phase, err)
 	if err != nil {
-		return fmt.Errorf("adding items: %w", err)
+		return changes, fmt.Errorf("adding items: %w", err)
+	}
+	slog.Info("Added items", "added", len(changes.Added), "skipped", changes.Skipped)
+	if err := gql.Context().Err(); err != nil {
+		return changes, fmt.Errorf("interrupted after adding items: %w", err)
+	}
+
+	// Copy source drafts, and bring earlier copies up to date
+	if drafts := draftItems(items); len(drafts) > 0 {
+		slog.Info("Syncing draft issues", "count", len(drafts))
+		phase := tracing.Start("board.syncDrafts")
+		added, updated, unchanged, failed, err := syncDrafts(gql, project.ID, drafts, config.Budget)
+		changes.Added = append(changes.Added, added...)
+		changes.Updated = append(changes.Updated, updated...)
+		changes.Unchanged += unchanged
+		changes.Failed = append(changes.Failed, failed...)
+		phase.SetAttributes(attribute.Int("drafts.added", len(added)), attribute.Int("drafts.updated", len(updated)))
+		tracing.EndWithError(phase, err)
+		if err != nil {
+			slog.Warn("Could not sync drafts", "err", err)
+		} else {
+			slog.Info("Synced draft issues", "added", len(added), "updated", len(updated), "unchanged", unchanged)
+		}
+		if err := gql.Context().Err(); err != nil {
+			return changes, fmt.Errorf("interrupted after syncing drafts: %w", err)
+		}
+	}
+
+	// Move new items to the top, where triage sees them
+	if config.NewItemsOnTop && len(changes.Added) > 0 {
+		slog.Info("Moving new items to the top of the board", "count", len(changes.Added))
+		moved, err := moveToTop(gql, project.ID, changes.Added, config.Budget)
+		if err != nil {
+			slog.Warn("Could not move new items", "err", err)
+		} else {
+			slog.Info("Moved new items", "count", moved)
+		}
+	}
+
+	// Write per-item field values
+	if hasItemFields(items) {
+		slog.Info("Writing field values")
+		phase := tracing.Start("board.writeItemFields")
+		updated, unchanged, created, moved, deferred, failed, err := writeItemFields(gql, project.ID, config.Fields, items, config.Budget, changes.Deferred)
+		changes.Created = created
+		changes.Moved = moved
+		changes.Deferred = append(changes.Deferred, deferred...)
+		changes.Failed = append(changes.Failed, failed...)
+		phase.SetAttributes(attribute.Int("items.updated", len(updated)), attribute.Int("items.unchanged", unchanged))
+		tracing.EndWithError(phase, err)
+		if err != nil {
+			slog.Warn("Could not write field values", "err", err)
+		} else {
+			slog.Info("Wrote field values", "updated", len(updated), "unchanged", unchanged)
+		}
+		changes.Updated = append(changes.Updated, updated...)
+		changes.Unchanged += unchanged
+		if err := gql.Context().Err(); err != nil {
+			return changes, fmt.Errorf("interrupted after writing fields: %w", err)
+		}
 	}
-	log.Printf("Done: %d added, %d skipped (already present or error)", added, skipped)
 
 	// Link repos if configured
 	if len(config.LinkRepos) > 0 {
-		log.Printf("Linking project to %d repository(ies)...", len(config.LinkRepos))
+		slog.Info("Linking project to repositories", "count", len(config.LinkRepos))
 		linked, linkSkipped, err := LinkProjectToRepositories(gql, project.ID, config.LinkRepos)
 		if err != nil {
-			log.Printf("Warning: error linking repositories: %v", err)
+			slog.Warn("Could not link repositories", "err", err)
 		} else {
-			log.Printf("Done: %d linked, %d skipped (already linked or error)", linked, linkSkipped)
+			slog.Info("Linked repositories", "linked", linked, "skipped", linkSkipped)
+		}
+		changes.Unlinked = unlinkStaleRepositories(gql, project.ID, config.LinkRepos, config.UnlinkRepos)
+	}
+
+	// Share the board with the configured collaborators
+	if len(config.Collaborators) > 0 {
+		slog.Info("Setting collaborator roles", "count", len(config.Collaborators))
+		set, err := SetCollaborators(gql, project.ID, config.Collaborators)
+		if err != nil {
+			slog.Warn("Could not set collaborators", "err", err)
+		} else {
+			slog.Info("Set collaborator roles", "count", set)
 		}
 	}
 
 	// Optionally remove stale items
 	if config.Sync {
-		log.Printf("Syncing: removing stale items not in current query...")
-		removed, err := removeStaleItems(gql, project.ID, items)
+		verb := staleVerb(config.StaleAction)
+		slog.Info("Looking for stale items not in the current query")
+		phase := tracing.Start("board.removeStaleItems")
+		removed, deferred, failed, err := removeStaleItems(gql, project.ID, items, config.StaleAction, config.Budget)
+		changes.Deferred = append(changes.Deferred, deferred...)
+		changes.Failed = append(changes.Failed, failed...)
+		phase.SetAttributes(attribute.Int("items.removed", len(removed)))
+		tracing.EndWithError(phase, err)
 		if err != nil {
-			log.Printf("Warning: error removing stale items: %v", err)
+			slog.Warn("Could not handle stale items", "err", err)
 		} else {
-			log.Printf("Removed %d stale item(s)", removed)
+			slog.Info("Handled stale items", "action", verb, "count", len(removed))
 		}
+		changes.Removed = removed
 	}
 
 	fmt.Printf("\nProject board: %s\n", project.URL)
-	return nil
+	return changes, nil
 }
 
 // ---------- Find Project ----------
@@ -121,49 +329,31 @@ func findUserProject(gql *ghgql.Client, owner, title string) (*Info, error) {
 		}
 	}`
 
-	var cursor *string
-	for {
-		vars := map[string]any{"owner": owner}
-		if cursor != nil {
-			vars["cursor"] = *cursor
-		}
-
-		var result struct {
-			User struct {
-				ProjectsV2 struct {
-					Nodes []struct {
-						ID     string `json:"id"`
-						Number int    `json:"number"`
-						Title  string `json:"title"`
-						URL    string `json:"url"`
-						Closed bool   `json:"closed"`
-					} `json:"nodes"`
-					PageInfo struct {
-						HasNextPage bool   `json:"hasNextPage"`
-						EndCursor   string `json:"endCursor"`
-					} `json:"pageInfo"`
-				} `json:"projectsV2"`
-			} `json:"user"`
-		}
-
-		err := gql.Do(ghgql.Request{Query: query, Variables: vars}, &result)
-		if err != nil {
-			return nil, err
-		}
-
+	type page struct {
+		User struct {
+			ProjectsV2 struct {
+				Nodes []struct {
+					ID     string `json:"id"`
+					Number int    `json:"number"`
+					Title  string `json:"title"`
+					URL    string `json:"url"`
+					Closed bool   `json:"closed"`
+				} `json:"nodes"`
+				PageInfo ghgql.PageInfo `json:"pageInfo"`
+			} `json:"projectsV2"`
+		} `json:"user"`
+	}
+	var found *Info
+	err := ghgql.Paginate(gql, ghgql.Request{Query: query, Variables: map[string]any{"owner": owner}}, func(result *page) (ghgql.PageInfo, error) {
 		for _, p := range result.User.ProjectsV2.Nodes {
 			if p.Title == title && !p.Closed {
-				return &Info{ID: p.ID, Number: p.Number, Title: p.Title, URL: p.URL}, nil
+				found = &Info{ID: p.ID, Number: p.Number, Title: p.Title, URL: p.URL}
+				return ghgql.PageInfo{}, nil // found it: stop
 			}
 		}
-
-		if !result.User.ProjectsV2.PageInfo.HasNextPage {
-			break
-		}
-		c := result.User.ProjectsV2.PageInfo.EndCursor
-		cursor = &c
-	}
-	return nil, nil
+		return result.User.ProjectsV2.PageInfo, nil
+	})
+	return found, err
 }
 
 func findOrgProject(gql *ghgql.Client, owner, title string) (*Info, error) {
@@ -176,56 +366,38 @@ func findOrgProject(gql *ghgql.Client, owner, title string) (*Info, error) {
 		}
 	}`
 
-	var cursor *string
-	for {
-		vars := map[string]any{"owner": owner}
-		if cursor != nil {
-			vars["cursor"] = *cursor
-		}
-
-		var result struct {
-			Organization struct {
-				ProjectsV2 struct {
-					Nodes []struct {
-						ID     string `json:"id"`
-						Number int    `json:"number"`
-						Title  string `json:"title"`
-						URL    string `json:"url"`
-						Closed bool   `json:"closed"`
-					} `json:"nodes"`
-					PageInfo struct {
-						HasNextPage bool   `json:"hasNextPage"`
-						EndCursor   string `json:"endCursor"`
-					} `json:"pageInfo"`
-				} `json:"projectsV2"`
-			} `json:"organization"`
-		}
-
-		err := gql.Do(ghgql.Request{Query: query, Variables: vars}, &result)
-		if err != nil {
-			return nil, err
-		}
-
+	type page struct {
+		Organization struct {
+			ProjectsV2 struct {
+				Nodes []struct {
+					ID     string `json:"id"`
+					Number int    `json:"number"`
+					Title  string `json:"title"`
+					URL    string `json:"url"`
+					Closed bool   `json:"closed"`
+				} `json:"nodes"`
+				PageInfo ghgql.PageInfo `json:"pageInfo"`
+			} `json:"projectsV2"`
+		} `json:"organization"`
+	}
+	var found *Info
+	err := ghgql.Paginate(gql, ghgql.Request{Query: query, Variables: map[string]any{"owner": owner}}, func(result *page) (ghgql.PageInfo, error) {
 		for _, p := range result.Organization.ProjectsV2.Nodes {
 			if p.Title == title && !p.Closed {
-				return &Info{ID: p.ID, Number: p.Number, Title: p.Title, URL: p.URL}, nil
+				found = &Info{ID: p.ID, Number: p.Number, Title: p.Title, URL: p.URL}
+				return ghgql.PageInfo{}, nil // found it: stop
 			}
 		}
-
-		if !result.Organization.ProjectsV2.PageInfo.HasNextPage {
-			break
-		}
-		c := result.Organization.ProjectsV2.PageInfo.EndCursor
-		cursor = &c
-	}
-	return nil, nil
+		return result.Organization.ProjectsV2.PageInfo, nil
+	})
+	return found, err
 }
 
 // ---------- Create Project ----------
 
 // CreateProject creates a new GitHub Projects V2 project.
 func CreateProject(gql *ghgql.Client, boardOwner, title string) (*Info, error) {
-	ownerID, err := resolveOwnerNodeID(gql, boardOwner)
+	ownerID, err := ResolveOwnerNodeID(gql, boardOwner)
 	if err != nil {
 		return nil, fmt.Errorf("resolving owner node ID: %w", err)
 	}
@@ -259,7 +431,107 @@ func CreateProject(gql *ghgql.Client, boardOwner, title string) (*Info, error) {
 	return &Info{ID: p.ID, Number: p.Number, Title: p.Title, URL: p.URL}, nil
 }
 
-func resolveOwnerNodeID(gql *ghgql.Client, login string) (string, error) {
+// CopyTemplate creates a project titled title under boardOwner as a copy of
+// the template — its fields, views, workflows, and optionally its draft
+// items — in a single copyProjectV2 mutation. The copy is made private
+// whatever the template's visibility.
+func CopyTemplate(gql *ghgql.Client, t Template, boardOwner, title string) (*Info, error) {
+	tpl, err := FindProjectByOwnerNumber(gql, t.Owner, t.Number)
+	if err != nil {
+		return nil, fmt.Errorf("finding template: %w", err)
+	}
+	ownerID, err := ResolveOwnerNodeID(gql, boardOwner)
+	if err != nil {
+		return nil, fmt.Errorf("resolving owner node ID: %w", err)
+	}
+
+	mutation := `mutation($projectId: ID!, $ownerId: ID!, $title: String!, $includeDraftIssues: Boolean!) {
+		copyProjectV2(input: {projectId: $projectId, ownerId: $ownerId, title: $title, includeDraftIssues: $includeDraftIssues}) {
+			projectV2 { id number title url }
+		}
+	}`
+
+	var result struct {
+		CopyProjectV2 struct {
+			ProjectV2 struct {
+				ID     string `json:"id"`
+				Number int    `json:"number"`
+				Title  string `json:"title"`
+				URL    string `json:"url"`
+			} `json:"projectV2"`
+		} `json:"copyProjectV2"`
+	}
+
+	err = gql.Do(ghgql.Request{
+		Query: mutation,
+		Variables: map[string]any{
+			"projectId":          tpl.ID,
+			"ownerId":            ownerID,
+			"title":              title,
+			"includeDraftIssues": t.IncludeDrafts,
+		},
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	p := result.CopyProjectV2.ProjectV2
+	if err := EnsureVisibility(gql, p.ID, false); err != nil {
+		return nil, fmt.Errorf("making the copy private: %w", err)
+	}
+	return &Info{ID: p.ID, Number: p.Number, Title: p.Title, URL: p.URL}, nil
+}
+
+// EnsureTemplateViews recreates the template's views — layout, filter, and
+// visible fields — that project lacks, matching views by name. Existing
+// views are left as they are.
+func EnsureTemplateViews(gql *ghgql.Client, t Template, boardOwner string, project *Info) error {
+	tpl, err := FindProjectByOwnerNumber(gql, t.Owner, t.Number)
+	if err != nil {
+		return fmt.Errorf("finding template: %w", err)
+	}
+	views, err := ListViews(gql, tpl.ID)
+	if err != nil {
+		return fmt.Errorf("listing template views: %w", err)
+	}
+	EnsureViews(gql, boardOwner, project, ViewConfigs(views))
+	return nil
+}
+
+// RenameProject changes a project's title.
+func RenameProject(gql *ghgql.Client, projectID, title string) error {
+	mutation := `mutation($projectId: ID!, $title: String!) {
+		updateProjectV2(input: {projectId: $projectId, title: $title}) {
+			projectV2 { id title }
+		}
+	}`
+
+	var result json.RawMessage
+	return gql.Do(ghgql.Request{
+		Query:     mutation,
+		Variables: map[string]any{"projectId": projectID, "title": title},
+	}, &result)
+}
+
+// CloseProject closes a project. Closed projects drop out of the owner's
+// project list but keep their items and can be reopened.
+func CloseProject(gql *ghgql.Client, projectID string) error {
+	mutation := `mutation($projectId: ID!) {
+		updateProjectV2(input: {projectId: $projectId, closed: true}) {
+			projectV2 { id closed }
+		}
+	}`
+
+	var result json.RawMessage
+	return gql.Do(ghgql.Request{
+		Query:     mutation,
+		Variables: map[string]any{"projectId": projectID},
+	}, &result)
+}
+
+// ResolveOwnerNodeID returns the node ID of the user or org login, trying
+// GraphQL first and falling back to REST.
+func ResolveOwnerNodeID(gql *ghgql.Client, login string) (string, error) {
 	// Try GraphQL user query
 	query := `query($login: String!) { user(login: $login) { id } }`
 	var userResult struct {
@@ -290,7 +562,7 @@ func resolveOwnerNodeID(gql *ghgql.Client, login string) (string, error) {
 	}
 	restErr := gql.DoREST("GET", fmt.Sprintf("/orgs/%s", login), nil, &restOrg)
 	if restErr == nil && restOrg.NodeID != "" {
-		log.Printf("  Resolved %q via REST API (node_id: %s)", login, restOrg.NodeID)
+		slog.Debug("Resolved owner via REST API", "owner", login, "node_id", restOrg.NodeID)
 		return restOrg.NodeID, nil
 	}
 
@@ -300,7 +572,7 @@ func resolveOwnerNodeID(gql *ghgql.Client, login string) (string, error) {
 	}
 	restErr = gql.DoREST("GET", fmt.Sprintf("/users/%s", login), nil, &restUser)
 	if restErr == nil && restUser.NodeID != "" {
-		log.Printf("  Resolved %q via REST API (node_id: %s)", login, restUser.NodeID)
+		slog.Debug("Resolved owner via REST API", "owner", login, "node_id", restUser.NodeID)
 		return restUser.NodeID, nil
 	}
 
@@ -309,62 +581,123 @@ func resolveOwnerNodeID(gql *ghgql.Client, login string) (string, error) {
 
 // ---------- Add Items ----------
 
-func addItems(gql *ghgql.Client, projectID string, items []Item) (added, skipped int, err error) {
+func addItems(gql *ghgql.Client, projectID string, items []Item, budget *Budget) (added []Item, skipped int, deferred []Op, failed []Failure, err error) {
 	existingIDs, err := getProjectItemContentIDs(gql, projectID)
 	if err != nil {
-		log.Printf("Warning: could not check existing items: %v", err)
+		slog.Warn("Could not check existing items", "err", err)
 		existingIDs = make(map[string]bool)
 	}
 
-	mutation := `mutation($projectId: ID!, $contentId: ID!) {
-		addProjectV2ItemById(input: {projectId: $projectId, contentId: $contentId}) {
-			item { id }
+	// Adds are sent ghgql.DefaultBatchSize to a request. addProjectV2ItemById
+	// returns the existing item when the content is already on the board, so
+	// an overlapping run adding the same item between the check below and
+	// the batch's request is harmless.
+	var batch []Item
+	flush := func() {
+		if len(batch) == 0 {
+			return
 		}
-	}`
+		mutations := make([]ghgql.Mutation, len(batch))
+		for i, item := range batch {
+			mutations[i] = ghgql.Mutation{
+				Field:     "addProjectV2ItemById",
+				Input:     map[string]any{"projectId": projectID, "contentId": item.NodeID},
+				Selection: "item { id }",
+			}
+		}
+		results, errs := gql.DoBatch(mutations, len(mutations))
+		for i, item := range batch {
+			if err := errs[i]; err != nil {
+				slog.Error("Could not add item", "item", itemLabel(item), "err", err)
+				skipped++
+				failed = append(failed, Failure{Kind: OpAdd, Label: itemLabel(item), Err: err})
+				continue
+			}
+			var result struct {
+				Item struct {
+					ID string `json:"id"`
+				} `json:"item"`
+			}
+			if json.Unmarshal(results[i], &result) == nil {
+				item.itemID = result.Item.ID
+			}
+			slog.Debug("Added item", "item", itemLabel(item))
+			added = append(added, item)
+		}
+		batch = batch[:0]
+	}
 
 	for _, item := range items {
-		if item.NodeID == "" {
-			log.Printf("  Skipping %q (no node ID)", item.Title)
-			skipped++
+		// Draft issues can't be added by content ID; syncDrafts copies them
+		if item.Type == "DraftIssue" {
 			continue
 		}
-
-		// Draft issues can't be added by content ID
-		if item.Type == "DraftIssue" {
-			log.Printf("  Skipping draft issue %q (draft issues cannot be added by content ID)", item.Title)
+		if item.NodeID == "" {
+			slog.Debug("Skipping item with no node ID", "item", item.Title)
 			skipped++
 			continue
 		}
 
 		if existingIDs[item.NodeID] {
-			log.Printf("  #%d already on board, skipping", item.Number)
+			slog.Debug("Item already on board, skipping", "item", itemLabel(item))
 			skipped++
 			continue
 		}
-
-		var result struct {
-			AddProjectV2ItemById struct {
-				Item struct {
-					ID string `json:"id"`
-				} `json:"item"`
-			} `json:"addProjectV2ItemById"`
+		if !afford(gql, budget, 1) {
+			deferred = append(deferred, Op{Kind: OpAdd, ProjectID: projectID, ContentID: item.NodeID, Label: itemLabel(item)})
+			continue
 		}
+		if batch = append(batch, item); len(batch) == ghgql.DefaultBatchSize {
+			flush()
+		}
+	}
+	flush()
 
-		err := gql.Do(ghgql.Request{
-			Query:     mutation,
-			Variables: map[string]any{"projectId": projectID, "contentId": item.NodeID},
-		}, &result)
-		if err != nil {
-			log.Printf("  Error adding #%d: %v", item.Number, err)
-			skipped++
+	if len(deferred) > 0 {
+		slog.Warn("Out of budget or interrupted: deferred adding items", "count", len(deferred))
+	}
+	return added, skipped, deferred, failed, nil
+}
+
+// moveToTop moves added to the top of the board, keeping their order, and
+// returns how many were moved. Items the budget can't cover stay where they
+// were added.
+func moveToTop(gql *ghgql.Client, projectID string, added []Item, budget *Budget) (int, error) {
+	var mutations []ghgql.Mutation
+	afterID := "" // the top
+	for _, item := range added {
+		if item.itemID == "" {
 			continue
 		}
-
-		log.Printf("  Added #%d: %s", item.Number, item.Title)
-		added++
+		if !afford(gql, budget, 1) {
+			slog.Warn("Out of budget or interrupted: left new items at the bottom", "count", len(added)-len(mutations))
+			break
+		}
+		input := map[string]any{"projectId": projectID, "itemId": item.itemID}
+		if afterID != "" {
+			input["afterId"] = afterID
+		}
+		mutations = append(mutations, ghgql.Mutation{
+			Field:     "updateProjectV2ItemPosition",
+			Input:     input,
+			Selection: "clientMutationId",
+		})
+		afterID = item.itemID
 	}
+	// Mutations in a request run in order, so each lands after the last.
+	_, errs := gql.DoBatch(mutations, 0)
+	moved := 0
+	for _, err := range errs {
+		if err == nil {
+			moved++
+		}
+	}
+	return moved, errors.Join(errs...)
+}
 
-	return added, skipped, nil
+// itemLabel describes item for deferred-op logs.
+func itemLabel(item Item) string {
+	return fmt.Sprintf("#%d %s", item.Number, item.Title)
 }
 
 func getProjectItemContentIDs(gql *ghgql.Client, projectID string) (map[string]bool, error) {
@@ -385,54 +718,187 @@ func getProjectItemContentIDs(gql *ghgql.Client, projectID string) (map[string]b
 	}`
 
 	ids := make(map[string]bool)
-	var cursor *string
-
-	for {
-		vars := map[string]any{"projectId": projectID}
-		if cursor != nil {
-			vars["cursor"] = *cursor
-		}
-
-		var result struct {
-			Node struct {
-				Items struct {
-					Nodes []struct {
-						Content struct {
-							ID string `json:"id"`
-						} `json:"content"`
-					} `json:"nodes"`
-					PageInfo struct {
-						HasNextPage bool   `json:"hasNextPage"`
-						EndCursor   string `json:"endCursor"`
-					} `json:"pageInfo"`
-				} `json:"items"`
-			} `json:"node"`
-		}
-
-		err := gql.Do(ghgql.Request{Query: query, Variables: vars}, &result)
-		if err != nil {
-			return nil, err
-		}
-
+	type page struct {
+		Node struct {
+			Items struct {
+				Nodes []struct {
+					Content struct {
+						ID string `json:"id"`
+					} `json:"content"`
+				} `json:"nodes"`
+				PageInfo ghgql.PageInfo `json:"pageInfo"`
+			} `json:"items"`
+		} `json:"node"`
+	}
+	err := ghgql.Paginate(gql, ghgql.Request{Query: query, Variables: map[string]any{"projectId": projectID}}, func(result *page) (ghgql.PageInfo, error) {
 		for _, item := range result.Node.Items.Nodes {
 			if item.Content.ID != "" {
 				ids[item.Content.ID] = true
 			}
 		}
+		return result.Node.Items.PageInfo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if !result.Node.Items.PageInfo.HasNextPage {
-			break
+	return ids, nil
+}
+
+// ---------- Write Item Fields ----------
+
+func hasItemFields(items []Item) bool {
+	for _, item := range items {
+		if len(item.Fields) > 0 || len(item.InitialFields) > 0 {
+			return true
 		}
-		c := result.Node.Items.PageInfo.EndCursor
-		cursor = &c
 	}
+	return false
+}
 
-	return ids, nil
+// writeItemFields ensures the given fields exist on the board, then sets each
+// item's Fields on its board entry. Values that already match are skipped so
+// repeated syncs only spend mutations on what changed.
+//
+// Field writes over budget, and those for items whose add was deferred
+// (pending), are returned as ops; created names the specs whose field had
+// to be created, and moved counts the items newly set to each Status.
+func writeItemFields(gql *ghgql.Client, projectID string, specs []FieldSpec, items []Item, budget *Budget, pending []Op) (set []Item, unchanged int, created []string, moved map[string]int, deferred []Op, failed []Failure, err error) {
+	destFields, err := GetProjectFields(gql, projectID)
+	if err != nil {
+		return nil, 0, nil, nil, nil, nil, fmt.Errorf("listing fields: %w", err)
+	}
+	had := make(map[string]bool, len(destFields))
+	for name := range destFields {
+		had[name] = true
+	}
+	destFields = EnsureFields(gql, projectID, specs, destFields)
+	for _, spec := range specs {
+		if _, ok := destFields[spec.Name]; ok && !had[spec.Name] {
+			created = append(created, spec.Name)
+		}
+	}
+
+	boardItems, err := FetchProjectItems(gql, projectID)
+	if err != nil {
+		return nil, 0, created, nil, nil, nil, fmt.Errorf("listing project items: %w", err)
+	}
+	adding := make(map[string]bool, len(pending))
+	for _, op := range pending {
+		if op.Kind == OpAdd {
+			adding[op.ContentID] = true
+		}
+	}
+	deferFields := func(item Item, values map[string]string) {
+		for name, value := range values {
+			if value != "" {
+				deferred = append(deferred, Op{Kind: OpSetField, ProjectID: projectID, ContentID: item.NodeID, Field: name, Value: value, Label: itemLabel(item)})
+			}
+		}
+	}
+	byContent := make(map[string]ProjectItemWithFields, len(boardItems))
+	for _, bi := range boardItems {
+		if bi.ContentID != "" {
+			byContent[bi.ContentID] = bi
+		}
+		if id := bi.Fields[SourceIDField]; bi.Type == "DraftIssue" && id != "" {
+			byContent[id] = bi // a copied draft answers for its source
+		}
+	}
+
+	for _, item := range items {
+		if len(item.Fields) == 0 && len(item.InitialFields) == 0 {
+			continue
+		}
+		bi, ok := byContent[item.NodeID]
+		if !ok {
+			if adding[item.NodeID] {
+				all := make(map[string]string, len(item.Fields)+len(item.InitialFields))
+				maps.Copy(all, item.InitialFields)
+				maps.Copy(all, item.Fields)
+				deferFields(item, all)
+			}
+			continue // not on the board (skipped or failed to add)
+		}
+		changed := make(map[string]string)
+		for name, value := range item.InitialFields {
+			if _, set := item.Fields[name]; !set && value != "" && bi.Fields[name] == "" {
+				changed[name] = value
+			}
+		}
+		for name, value := range item.Fields {
+			if bi.Fields[name] != value {
+				changed[name] = value
+			}
+		}
+		if len(changed) == 0 {
+			unchanged++
+			continue
+		}
+		if !afford(gql, budget, len(changed)) {
+			deferFields(item, changed)
+			continue
+		}
+		if err := SetItemFields(gql, projectID, bi.ItemID, changed, destFields); err != nil {
+			failed = append(failed, Failure{Kind: OpSetField, Label: itemLabel(item), Err: err})
+			continue
+		}
+		if status := changed[statusFieldName]; status != "" {
+			if moved == nil {
+				moved = make(map[string]int)
+			}
+			moved[status]++
+		}
+		set = append(set, item)
+	}
+	if len(deferred) > 0 {
+		slog.Warn("Out of budget or interrupted: deferred field writes", "count", len(deferred))
+	}
+	return set, unchanged, created, moved, deferred, failed, nil
 }
 
 // ---------- Remove Stale Items ----------
 
-func removeStaleItems(gql *ghgql.Client, projectID string, currentItems []Item) (int, error) {
+// Stale item actions for Config.StaleAction, besides "status:<option>".
+const (
+	StaleDelete  = "delete"  // delete the item from the board
+	StaleArchive = "archive" // archive it, keeping its fields and history
+)
+
+// staleStatusPrefix starts a StaleAction that moves stale items to a
+// Status column.
+const staleStatusPrefix = "status:"
+
+// CheckStaleAction returns an error unless action is a valid
+// Config.StaleAction.
+func CheckStaleAction(action string) error {
+	switch status, ok := strings.CutPrefix(action, staleStatusPrefix); {
+	case action == "" || action == StaleDelete || action == StaleArchive:
+		return nil
+	case ok && strings.TrimSpace(status) != "":
+		return nil
+	}
+	return fmt.Errorf("unknown stale action %q (want %s, %s, or %s<option>)", action, StaleDelete, StaleArchive, staleStatusPrefix)
+}
+
+// staleVerb describes what action does to an item, for logs.
+func staleVerb(action string) string {
+	if status, ok := strings.CutPrefix(action, staleStatusPrefix); ok {
+		return fmt.Sprintf("Moved to %q", strings.TrimSpace(status))
+	}
+	if action == StaleArchive {
+		return "Archived"
+	}
+	return "Removed"
+}
+
+// removeStaleItems deletes, archives, or moves to a Status column (per
+// action; see Config.StaleAction) the issues, PRs, and copied drafts not in
+// currentItems; a copied draft is matched by its SourceIDField. Other
+// drafts are left alone: they were added by hand (or hold a sync
+// changelog). With StaleArchive, archived items that are back in
+// currentItems are restored.
+func removeStaleItems(gql *ghgql.Client, projectID string, currentItems []Item, action string, budget *Budget) (removed []string, deferred []Op, failed []Failure, err error) {
 	currentIDs := make(map[string]bool, len(currentItems))
 	for _, item := range currentItems {
 		if item.NodeID != "" {
@@ -442,49 +908,215 @@ func removeStaleItems(gql *ghgql.Client, projectID string, currentItems []Item)
 
 	items, err := getProjectItems(gql, projectID)
 	if err != nil {
-		return 0, fmt.Errorf("listing project items: %w", err)
+		return nil, nil, nil, fmt.Errorf("listing project items: %w", err)
 	}
 
-	mutation := `mutation($projectId: ID!, $itemId: ID!) {
-		deleteProjectV2Item(input: {projectId: $projectId, itemId: $itemId}) {
-			deletedItemId
+	// Each stale item gets one mutation, built by stale; restore builds the
+	// one unarchiving a returning item.
+	kind := OpRemove
+	stale := func(item boardItem) ghgql.Mutation {
+		return ghgql.Mutation{
+			Field:     "deleteProjectV2Item",
+			Input:     map[string]any{"projectId": projectID, "itemId": item.itemID},
+			Selection: "deletedItemId",
 		}
-	}`
+	}
+	restore := func(item boardItem) ghgql.Mutation {
+		return ghgql.Mutation{
+			Field:     "unarchiveProjectV2Item",
+			Input:     map[string]any{"projectId": projectID, "itemId": item.itemID},
+			Selection: "item { id }",
+		}
+	}
+	done := func(item boardItem) bool { return false } // already in the stale state
+	var status string
+	switch action {
+	case StaleArchive:
+		kind = OpArchive
+		stale = func(item boardItem) ghgql.Mutation {
+			return ghgql.Mutation{
+				Field:     "archiveProjectV2Item",
+				Input:     map[string]any{"projectId": projectID, "itemId": item.itemID},
+				Selection: "item { id }",
+			}
+		}
+		done = func(item boardItem) bool { return item.archived }
+	case "", StaleDelete:
+	default:
+		status = strings.TrimSpace(strings.TrimPrefix(action, staleStatusPrefix))
+		fields, err := GetProjectFields(gql, projectID)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("listing fields: %w", err)
+		}
+		field, ok := fields[statusFieldName]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("board has no %s field", statusFieldName)
+		}
+		optionID, ok := ResolveOptionID(field, status)
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("%s field has no option %q", statusFieldName, status)
+		}
+		kind = OpSetField
+		stale = func(item boardItem) ghgql.Mutation {
+			return ghgql.Mutation{
+				Field: "updateProjectV2ItemFieldValue",
+				Input: map[string]any{
+					"projectId": projectID, "itemId": item.itemID, "fieldId": field.ID,
+					"value": map[string]any{"singleSelectOptionId": optionID},
+				},
+				Selection: "projectV2Item { id }",
+			}
+		}
+		done = func(item boardItem) bool { return strings.EqualFold(item.status, status) }
+	}
+	verb := staleVerb(action)
 
-	removed := 0
-	for _, item := range items {
-		if item.contentID != "" && !currentIDs[item.contentID] {
-			var result json.RawMessage
-			err := gql.Do(ghgql.Request{
-				Query:     mutation,
-				Variables: map[string]any{"projectId": projectID, "itemId": item.itemID},
-			}, &result)
-			if err != nil {
-				log.Printf("  Error removing stale item %s: %v", item.itemID, err)
+	// Mutations are sent ghgql.DefaultBatchSize to a request, like adds.
+	type pending struct {
+		item    boardItem
+		restore bool
+	}
+	var batch []pending
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		mutations := make([]ghgql.Mutation, len(batch))
+		for i, p := range batch {
+			if p.restore {
+				mutations[i] = restore(p.item)
+			} else {
+				mutations[i] = stale(p.item)
+			}
+		}
+		_, errs := gql.DoBatch(mutations, len(mutations))
+		for i, p := range batch {
+			item := p.item
+			if p.restore {
+				if err := errs[i]; err != nil {
+					slog.Error("Could not unarchive item", "item", item.title, "err", err)
+					failed = append(failed, Failure{Kind: OpUnarchive, Label: item.title, Err: err})
+				} else {
+					slog.Debug("Unarchived item: back in the query", "item", item.title)
+				}
 				continue
 			}
-			log.Printf("  Removed stale item: %s", item.title)
-			removed++
+			if err := errs[i]; err != nil {
+				// An overlapping run may have removed it first; that's success too.
+				if exists, lookupErr := itemExists(gql, item.itemID); lookupErr != nil || exists || kind != OpRemove {
+					slog.Error("Could not handle stale item", "item", item.title, "err", err)
+					failed = append(failed, Failure{Kind: kind, Label: item.title, Err: err})
+					continue
+				}
+				slog.Debug("Item already removed", "item", item.title)
+			}
+			slog.Debug("Handled stale item", "action", verb, "item", item.title)
+			removed = append(removed, item.title)
 		}
+		batch = batch[:0]
+	}
+	queue := func(p pending, op Op) {
+		if !afford(gql, budget, 1) {
+			deferred = append(deferred, op)
+			return
+		}
+		if batch = append(batch, p); len(batch) == ghgql.DefaultBatchSize {
+			flush()
+		}
+	}
+
+	for _, item := range items {
+		source := item.contentID
+		if item.typename == "DraftIssue" {
+			source = item.sourceID
+		}
+		if source == "" {
+			continue
+		}
+		if currentIDs[source] {
+			if action == StaleArchive && item.archived {
+				queue(pending{item: item, restore: true}, Op{Kind: OpUnarchive, ProjectID: projectID, ItemID: item.itemID, Label: item.title})
+			}
+			continue
+		}
+		if done(item) {
+			continue
+		}
+		op := Op{Kind: kind, ProjectID: projectID, ItemID: item.itemID, Label: item.title}
+		if kind == OpSetField {
+			op = Op{Kind: OpSetField, ProjectID: projectID, ContentID: item.contentID, Field: statusFieldName, Value: status, Label: item.title}
+		}
+		queue(pending{item: item}, op)
+	}
+	flush()
+
+	if len(deferred) > 0 {
+		slog.Warn("Out of budget or interrupted: deferred stale item changes", "count", len(deferred))
 	}
+	return removed, deferred, failed, nil
+}
 
-	return removed, nil
+// ArchiveItem archives a project item, or restores it if archive is false.
+func ArchiveItem(gql *ghgql.Client, projectID, itemID string, archive bool) error {
+	field := "unarchiveProjectV2Item"
+	if archive {
+		field = "archiveProjectV2Item"
+	}
+	mutation := fmt.Sprintf(`mutation($projectId: ID!, $itemId: ID!) {
+		%s(input: {projectId: $projectId, itemId: $itemId}) {
+			item { id }
+		}
+	}`, field)
+	var result json.RawMessage
+	return gql.Do(ghgql.Request{
+		Query:     mutation,
+		Variables: map[string]any{"projectId": projectID, "itemId": itemID},
+	}, &result)
+}
+
+// FindDraftItem returns the item and draft content IDs of the first draft
+// issue titled title on a project, or empty strings if there is none.
+func FindDraftItem(gql *ghgql.Client, projectID, title string) (itemID, draftID string, err error) {
+	items, err := getProjectItems(gql, projectID)
+	if err != nil {
+		return "", "", err
+	}
+	for _, item := range items {
+		if item.typename == "DraftIssue" && item.title == title {
+			return item.itemID, item.contentID, nil
+		}
+	}
+	return "", "", nil
 }
 
 type boardItem struct {
 	itemID    string
 	contentID string
+	typename  string
 	title     string
+	archived  bool
+	status    string // the Status field's value
+	sourceID  string // a copied draft's SourceIDField
 }
 
+// statusFieldName is the single-select field every board starts with.
+const statusFieldName = "Status"
+
 func getProjectItems(gql *ghgql.Client, projectID string) ([]boardItem, error) {
 	query := `query($projectId: ID!, $cursor: String) {
 		node(id: $projectId) {
 			... on ProjectV2 {
 				items(first: 100, after: $cursor) {
 					nodes {
-						id
+						id isArchived
+						status: fieldValueByName(name: "Status") {
+							... on ProjectV2ItemFieldSingleSelectValue { name }
+						}
+						sourceId: fieldValueByName(name: "` + SourceIDField + `") {
+							... on ProjectV2ItemFieldTextValue { text }
+						}
 						content {
+							__typename
 							... on Issue { id title }
 							... on PullRequest { id title }
 							... on DraftIssue { id title }
@@ -497,50 +1129,49 @@ func getProjectItems(gql *ghgql.Client, projectID string) ([]boardItem, error) {
 	}`
 
 	var items []boardItem
-	var cursor *string
-
-	for {
-		vars := map[string]any{"projectId": projectID}
-		if cursor != nil {
-			vars["cursor"] = *cursor
-		}
-
-		var result struct {
-			Node struct {
-				Items struct {
-					Nodes []struct {
-						ID      string `json:"id"`
-						Content struct {
-							ID    string `json:"id"`
-							Title string `json:"title"`
-						} `json:"content"`
-					} `json:"nodes"`
-					PageInfo struct {
-						HasNextPage bool   `json:"hasNextPage"`
-						EndCursor   string `json:"endCursor"`
-					} `json:"pageInfo"`
-				} `json:"items"`
-			} `json:"node"`
-		}
-
-		err := gql.Do(ghgql.Request{Query: query, Variables: vars}, &result)
-		if err != nil {
-			return nil, err
-		}
-
+	type page struct {
+		Node struct {
+			Items struct {
+				Nodes []struct {
+					ID          string `json:"id"`
+					IsArchived  bool   `json:"isArchived"`
+					StatusValue *struct {
+						Name string `json:"name"`
+					} `json:"status"`
+					SourceIDValue *struct {
+						Text string `json:"text"`
+					} `json:"sourceId"`
+					Content struct {
+						Typename string `json:"__typename"`
+						ID       string `json:"id"`
+						Title    string `json:"title"`
+					} `json:"content"`
+				} `json:"nodes"`
+				PageInfo ghgql.PageInfo `json:"pageInfo"`
+			} `json:"items"`
+		} `json:"node"`
+	}
+	err := ghgql.Paginate(gql, ghgql.Request{Query: query, Variables: map[string]any{"projectId": projectID}}, func(result *page) (ghgql.PageInfo, error) {
 		for _, n := range result.Node.Items.Nodes {
-			items = append(items, boardItem{
+			item := boardItem{
 				itemID:    n.ID,
 				contentID: n.Content.ID,
+				typename:  n.Content.Typename,
 				title:     n.Content.Title,
-			})
-		}
-
-		if !result.Node.Items.PageInfo.HasNextPage {
-			break
+				archived:  n.IsArchived,
+			}
+			if n.StatusValue != nil {
+				item.status = n.StatusValue.Name
+			}
+			if n.SourceIDValue != nil {
+				item.sourceID = n.SourceIDValue.Text
+			}
+			items = append(items, item)
 		}
-		c := result.Node.Items.PageInfo.EndCursor
-		cursor = &c
+		return result.Node.Items.PageInfo, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return items, nil
@@ -549,20 +1180,30 @@ func getProjectItems(gql *ghgql.Client, projectID string) ([]boardItem, error) {
 // ---------- Link Repos ----------
 
 // LinkProjectToRepositories links a project board to repositories.
-// Repos should be in "owner/name" format. Already-linked repos are skipped.
+// Repos should be in "owner/name" format. Already-linked repos are skipped
+// without a mutation.
 func LinkProjectToRepositories(gql *ghgql.Client, projectID string, repos []string) (linked, skipped int, err error) {
+	already, err := LinkedRepositories(gql, projectID)
+	if err != nil {
+		slog.Warn("Could not list linked repositories", "err", err)
+	}
 	for _, repo := range repos {
 		parts := strings.SplitN(repo, "/", 2)
 		if len(parts) != 2 {
-			log.Printf("  Skipping invalid repo %q (expected owner/name)", repo)
+			slog.Warn("Skipping invalid repo (expected owner/name)", "repo", repo)
 			skipped++
 			continue
 		}
 		owner, name := parts[0], parts[1]
+		if already[strings.ToLower(repo)] != "" {
+			slog.Debug("Repo already linked, skipping", "repo", repo)
+			skipped++
+			continue
+		}
 
 		repoID, err := resolveRepoNodeID(gql, owner, name)
 		if err != nil {
-			log.Printf("  Error resolving repo %s: %v", repo, err)
+			slog.Error("Could not resolve repo", "repo", repo, "err", err)
 			skipped++
 			continue
 		}
@@ -579,23 +1220,156 @@ func LinkProjectToRepositories(gql *ghgql.Client, projectID string, repos []stri
 			Variables: map[string]any{"projectId": projectID, "repositoryId": repoID},
 		}, &result)
 		if linkErr != nil {
-			if strings.Contains(linkErr.Error(), "already linked") || strings.Contains(linkErr.Error(), "already exists") {
-				log.Printf("  %s already linked, skipping", repo)
+			// An overlapping run may have linked it since the list above;
+			// GitHub's error for that has no type to tell it apart.
+			if now, err := LinkedRepositories(gql, projectID); err == nil && now[strings.ToLower(repo)] != "" {
+				slog.Debug("Repo already linked, skipping", "repo", repo)
 				skipped++
 				continue
 			}
-			log.Printf("  Error linking %s: %v", repo, linkErr)
+			slog.Error("Could not link repo", "repo", repo, "err", linkErr)
 			skipped++
 			continue
 		}
 
-		log.Printf("  Linked project to %s", repo)
+		slog.Debug("Linked project to repo", "repo", repo)
 		linked++
 	}
 
 	return linked, skipped, nil
 }
 
+// unlinkStaleRepositories finds repositories linked to the project that
+// aren't in keep and, with unlink, unlinks them; otherwise it only warns.
+// It returns the repositories unlinked.
+func unlinkStaleRepositories(gql *ghgql.Client, projectID string, keep []string, unlink bool) []string {
+	linked, err := LinkedRepositories(gql, projectID)
+	if err != nil {
+		slog.Warn("Could not list linked repositories", "err", err)
+		return nil
+	}
+	for _, repo := range keep {
+		delete(linked, strings.ToLower(repo))
+	}
+	if len(linked) == 0 {
+		return nil
+	}
+	stale := make([]string, 0, len(linked))
+	for repo := range linked {
+		stale = append(stale, repo)
+	}
+	sort.Strings(stale)
+	if !unlink {
+		slog.Warn("Board is linked to repos not in the configuration — add them, or unlink them (--unlink-repos)", "repos", strings.Join(stale, ", "))
+		return nil
+	}
+
+	var unlinked []string
+	for _, repo := range stale {
+		if err := UnlinkProjectFromRepository(gql, projectID, linked[repo]); err != nil {
+			slog.Error("Could not unlink repo", "repo", repo, "err", err)
+			continue
+		}
+		slog.Debug("Unlinked project from repo", "repo", repo)
+		unlinked = append(unlinked, repo)
+	}
+	return unlinked
+}
+
+// UnlinkProjectFromRepository removes the link between a project and a
+// repository (by node ID). Unlinking a repository that isn't linked succeeds.
+func UnlinkProjectFromRepository(gql *ghgql.Client, projectID, repoID string) error {
+	mutation := `mutation($projectId: ID!, $repositoryId: ID!) {
+		unlinkProjectV2FromRepository(input: {projectId: $projectId, repositoryId: $repositoryId}) {
+			repository { id }
+		}
+	}`
+
+	var result json.RawMessage
+	err := gql.Do(ghgql.Request{
+		Query:     mutation,
+		Variables: map[string]any{"projectId": projectID, "repositoryId": repoID},
+	}, &result)
+	if err == nil {
+		return nil
+	}
+	// GitHub's error for a link that isn't there has no type to tell it
+	// apart, so look.
+	linked, listErr := LinkedRepositories(gql, projectID)
+	if listErr != nil {
+		return err
+	}
+	for _, id := range linked {
+		if id == repoID {
+			return err
+		}
+	}
+	return nil
+}
+
+// LinkedRepositories returns the repositories a project is linked to, keyed
+// by lowercased "owner/name", with each repository's node ID.
+func LinkedRepositories(gql *ghgql.Client, projectID string) (map[string]string, error) {
+	query := `query($projectId: ID!, $cursor: String) {
+		node(id: $projectId) {
+			... on ProjectV2 {
+				repositories(first: 100, after: $cursor) {
+					nodes { id nameWithOwner }
+					pageInfo { hasNextPage endCursor }
+				}
+			}
+		}
+	}`
+
+	repos := make(map[string]string)
+	type page struct {
+		Node struct {
+			Repositories struct {
+				Nodes []struct {
+					ID            string `json:"id"`
+					NameWithOwner string `json:"nameWithOwner"`
+				} `json:"nodes"`
+				PageInfo ghgql.PageInfo `json:"pageInfo"`
+			} `json:"repositories"`
+		} `json:"node"`
+	}
+	err := ghgql.Paginate(gql, ghgql.Request{Query: query, Variables: map[string]any{"projectId": projectID}}, func(result *page) (ghgql.PageInfo, error) {
+		for _, r := range result.Node.Repositories.Nodes {
+			if r.NameWithOwner == "" {
+				continue // no longer accessible with this token
+			}
+			repos[strings.ToLower(r.NameWithOwner)] = r.ID
+		}
+		return result.Node.Repositories.PageInfo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+// CheckRepositories verifies that every "owner/name" in repos exists and is
+// readable with the client's token, returning one error per repository that
+// isn't (malformed, misspelled, or private to someone else). GitHub doesn't
+// distinguish a missing repository from an inaccessible one.
+func CheckRepositories(gql *ghgql.Client, repos []string) []error {
+	var errs []error
+	for _, repo := range repos {
+		owner, name, ok := strings.Cut(repo, "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			errs = append(errs, fmt.Errorf("%s: expected owner/name", repo))
+			continue
+		}
+		if _, err := resolveRepoNodeID(gql, owner, name); err != nil {
+			if errors.Is(err, ghgql.ErrNotFound) {
+				err = errors.New("not found, or not accessible with this token")
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", repo, err))
+		}
+	}
+	return errs
+}
+
 func resolveRepoNodeID(gql *ghgql.Client, owner, name string) (string, error) {
 	query := `query($owner: String!, $name: String!) {
 		repository(owner: $owner, name: $name) { id }
@@ -615,7 +1389,7 @@ func resolveRepoNodeID(gql *ghgql.Client, owner, name string) (string, error) {
 		return "", err
 	}
 	if result.Repository.ID == "" {
-		return "", fmt.Errorf("repository %s/%s not found", owner, name)
+		return "", fmt.Errorf("repository %s/%s %w", owner, name, ghgql.ErrNotFound)
 	}
 	return result.Repository.ID, nil
 }
@@ -0,0 +1,55 @@
+package board
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWriteItemFieldsLeavesFailedWritesOut(t *testing.T) {
+	gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case strings.Contains(req.Query, "fields(first: 50)") && !strings.Contains(req.Query, "items("):
+			w.Write([]byte(`{"data":{"node":{"fields":{"nodes":[{"id":"PVTF_notes","name":"Notes","dataType":"TEXT"}]}}}}`))
+		case strings.Contains(req.Query, "items(first: 100"):
+			w.Write([]byte(`{"data":{"node":{"title":"Board","items":{"nodes":[
+				{"id":"PVTI_ok","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","id":"I_ok","number":1,"repository":{"nameWithOwner":"o/r"}}},
+				{"id":"PVTI_bad","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","id":"I_bad","number":2,"repository":{"nameWithOwner":"o/r"}}}
+			],"pageInfo":{"hasNextPage":false}}}}}`))
+		case strings.Contains(req.Query, "updateProjectV2ItemFieldValue"):
+			if req.Variables["itemId"] == "PVTI_bad" {
+				w.Write([]byte(`{"errors":[{"message":"something went wrong"}]}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"PVTI_ok"}}}}`))
+		default:
+			t.Fatalf("unexpected query: %s", req.Query)
+		}
+	})
+
+	items := []Item{
+		{NodeID: "I_ok", Number: 1, Type: "Issue", Fields: map[string]string{"Notes": "fine"}},
+		{NodeID: "I_bad", Number: 2, Type: "Issue", Fields: map[string]string{"Notes": "broken"}},
+	}
+	set, unchanged, _, _, deferred, failed, err := writeItemFields(gql, "PVT_1", nil, items, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 1 || set[0].NodeID != "I_ok" {
+		t.Errorf("set = %+v, want only I_ok", set)
+	}
+	if len(failed) != 1 || failed[0].Kind != OpSetField {
+		t.Errorf("failed = %+v, want one field write", failed)
+	}
+	if unchanged != 0 || len(deferred) != 0 {
+		t.Errorf("unchanged = %d, deferred = %d, want 0 and 0", unchanged, len(deferred))
+	}
+}
@@ -0,0 +1,139 @@
+package board
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// Collaborator roles, as accepted by ParseCollaborators.
+var collaboratorRoles = map[string]string{
+	"read":  "READER",
+	"write": "WRITER",
+	"admin": "ADMIN",
+	"none":  "NONE", // revoke access granted earlier
+}
+
+// Collaborator grants a user or team a role on a project.
+type Collaborator struct {
+	Login string // user login, or "org/team-slug" for a team
+	Role  string // READER, WRITER, ADMIN, or NONE
+}
+
+// IsTeam reports whether c names a team rather than a user.
+func (c Collaborator) IsTeam() bool {
+	return strings.Contains(c.Login, "/")
+}
+
+// ParseCollaborators parses a comma- or newline-separated list of login=role
+// entries, e.g. "alice=admin, my-org/sig-auth-leads=write, bob=none". Logins
+// containing a slash are teams; roles are read, write, admin, or none.
+func ParseCollaborators(s string) ([]Collaborator, error) {
+	var out []Collaborator
+	for _, entry := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		login, role, ok := strings.Cut(entry, "=")
+		login, role = strings.TrimSpace(login), strings.ToLower(strings.TrimSpace(role))
+		if !ok || login == "" {
+			return nil, fmt.Errorf("invalid collaborator %q (expected login=role)", entry)
+		}
+		r, ok := collaboratorRoles[role]
+		if !ok {
+			return nil, fmt.Errorf("invalid role %q for %s (expected read, write, admin, or none)", role, login)
+		}
+		out = append(out, Collaborator{Login: strings.TrimPrefix(login, "@"), Role: r})
+	}
+	return out, nil
+}
+
+// SetCollaborators grants each collaborator its role on a project in one
+// mutation. Roles are set, not added, so running it again with the same list
+// changes nothing; collaborators not in the list keep their access (use the
+// NONE role to revoke it). Users and teams that can't be resolved are logged
+// and skipped.
+func SetCollaborators(gql *ghgql.Client, projectID string, list []Collaborator) (set int, err error) {
+	var inputs []map[string]any
+	for _, c := range list {
+		var id string
+		var err error
+		key := "userId"
+		if c.IsTeam() {
+			key = "teamId"
+			id, err = resolveTeamNodeID(gql, c.Login)
+		} else {
+			id, err = resolveUserNodeID(gql, c.Login)
+		}
+		if err != nil {
+			slog.Warn("Skipping collaborator", "login", c.Login, "err", err)
+			continue
+		}
+		inputs = append(inputs, map[string]any{key: id, "role": c.Role})
+	}
+	if len(inputs) == 0 {
+		return 0, nil
+	}
+
+	mutation := `mutation($projectId: ID!, $collaborators: [ProjectV2Collaborator!]!) {
+		updateProjectV2Collaborators(input: {projectId: $projectId, collaborators: $collaborators}) {
+			collaborators { totalCount }
+		}
+	}`
+
+	var result json.RawMessage
+	err = gql.Do(ghgql.Request{
+		Query:     mutation,
+		Variables: map[string]any{"projectId": projectID, "collaborators": inputs},
+	}, &result)
+	if err != nil {
+		return 0, err
+	}
+	return len(inputs), nil
+}
+
+// resolveUserNodeID returns the node ID of a user (not an organization).
+func resolveUserNodeID(gql *ghgql.Client, login string) (string, error) {
+	query := `query($login: String!) { user(login: $login) { id } }`
+
+	var result struct {
+		User *struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	if err := gql.Do(ghgql.Request{Query: query, Variables: map[string]any{"login": login}}, &result); err != nil {
+		return "", err
+	}
+	if result.User == nil {
+		return "", fmt.Errorf("user %s not found", login)
+	}
+	return result.User.ID, nil
+}
+
+// resolveTeamNodeID returns the node ID of the team "org/team-slug".
+func resolveTeamNodeID(gql *ghgql.Client, team string) (string, error) {
+	org, slug, _ := strings.Cut(team, "/")
+	query := `query($org: String!, $slug: String!) {
+		organization(login: $org) { team(slug: $slug) { id } }
+	}`
+
+	var result struct {
+		Organization struct {
+			Team *struct {
+				ID string `json:"id"`
+			} `json:"team"`
+		} `json:"organization"`
+	}
+	err := gql.Do(ghgql.Request{Query: query, Variables: map[string]any{"org": org, "slug": slug}}, &result)
+	if err != nil {
+		return "", err
+	}
+	if result.Organization.Team == nil {
+		return "", fmt.Errorf("team %s not found", team)
+	}
+	return result.Organization.Team.ID, nil
+}
@@ -0,0 +1,58 @@
+package board
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCollaborators(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []Collaborator
+		wantErr string
+	}{
+		{name: "empty", spec: " ,\n", want: nil},
+		{
+			name: "users and teams",
+			spec: "alice=admin, my-org/sig-auth-leads=write\n@bob = READ,carol=none",
+			want: []Collaborator{
+				{Login: "alice", Role: "ADMIN"},
+				{Login: "my-org/sig-auth-leads", Role: "WRITER"},
+				{Login: "bob", Role: "READER"},
+				{Login: "carol", Role: "NONE"},
+			},
+		},
+		{name: "missing role", spec: "alice", wantErr: `invalid collaborator "alice"`},
+		{name: "missing login", spec: "=read", wantErr: "invalid collaborator"},
+		{name: "unknown role", spec: "alice=owner", wantErr: `invalid role "owner" for alice`},
+		{name: "empty role", spec: "alice=", wantErr: `invalid role "" for alice`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCollaborators(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseCollaborators(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCollaborators(%q): %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCollaborators(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollaboratorIsTeam(t *testing.T) {
+	if (Collaborator{Login: "alice"}).IsTeam() {
+		t.Error("alice is a user, not a team")
+	}
+	if !(Collaborator{Login: "my-org/leads"}).IsTeam() {
+		t.Error("my-org/leads is a team")
+	}
+}
@@ -0,0 +1,148 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// Kinds of deferred board mutation.
+const (
+	OpAdd       = "add"       // add ContentID to the project
+	OpSetField  = "set-field" // set Field to Value on ContentID's item
+	OpRemove    = "remove"    // delete ItemID from the project
+	OpArchive   = "archive"   // archive ItemID
+	OpUnarchive = "unarchive" // restore the archived ItemID
+)
+
+// Op is one board mutation UpdateBoard couldn't afford and left for
+// ReplayOps.
+type Op struct {
+	Kind      string `json:"kind"`
+	ProjectID string `json:"project_id"`
+	ContentID string `json:"content_id,omitempty"`
+	ItemID    string `json:"item_id,omitempty"`
+	Field     string `json:"field,omitempty"`
+	Value     string `json:"value,omitempty"`
+	Label     string `json:"label"` // what the op is about, for logs
+}
+
+// DeferredOps is a queue of mutations saved until the rate limit resets.
+type DeferredOps struct {
+	Owner   string    `json:"owner"`
+	Name    string    `json:"name"`
+	ResetAt time.Time `json:"reset_at"` // when the GraphQL budget refills
+	Ops     []Op      `json:"ops"`
+}
+
+// Budget caps how many mutations a run sends. Once Limit is spent, the
+// remaining item adds, field writes, and removals are returned as Ops
+// instead. A nil *Budget is unlimited.
+type Budget struct {
+	Limit int
+	spent int
+}
+
+// afford reports whether n more mutations may be sent: budget has them and
+// gql's context hasn't been cancelled. Once it is, the rest are deferred
+// just as if the budget had run out.
+func afford(gql *ghgql.Client, budget *Budget, n int) bool {
+	return gql.Context().Err() == nil && budget.take(n)
+}
+
+// take spends n mutations if the budget still has them.
+func (b *Budget) take(n int) bool {
+	if b == nil {
+		return true
+	}
+	if b.spent+n > b.Limit {
+		return false
+	}
+	b.spent += n
+	return true
+}
+
+// ReplayOps performs queued mutations in order, stopping when budget runs
+// out or gql's context is cancelled, and returns how many were done and the ops still to do. An op that
+// fails is logged and dropped; the next sync will retry whatever it was
+// meant to change.
+func ReplayOps(gql *ghgql.Client, ops []Op, budget *Budget) (done int, remaining []Op) {
+	itemIDs := make(map[string]map[string]string) // project → content → item ID
+	fields := make(map[string]FieldMap)           // project → fields
+
+	itemID := func(projectID, contentID string) (string, error) {
+		if itemIDs[projectID] == nil {
+			list, err := FetchProjectItems(gql, projectID)
+			if err != nil {
+				return "", fmt.Errorf("listing project items: %w", err)
+			}
+			m := make(map[string]string, len(list))
+			for _, it := range list {
+				if it.ContentID != "" {
+					m[it.ContentID] = it.ItemID
+				}
+			}
+			itemIDs[projectID] = m
+		}
+		id := itemIDs[projectID][contentID]
+		if id == "" {
+			return "", errors.New("not on the board")
+		}
+		return id, nil
+	}
+
+	for i, op := range ops {
+		if !afford(gql, budget, 1) {
+			return done, ops[i:]
+		}
+		var err error
+		switch op.Kind {
+		case OpAdd:
+			var id string
+			if id, err = AddItem(gql, op.ProjectID, op.ContentID); err == nil && itemIDs[op.ProjectID] != nil {
+				itemIDs[op.ProjectID][op.ContentID] = id
+			}
+		case OpSetField:
+			if fields[op.ProjectID] == nil {
+				if fields[op.ProjectID], err = GetProjectFields(gql, op.ProjectID); err != nil {
+					break
+				}
+			}
+			field, ok := fields[op.ProjectID][op.Field]
+			if !ok {
+				err = fmt.Errorf("field %q not found", op.Field)
+				break
+			}
+			var id string
+			var fv FieldValue
+			if id, err = itemID(op.ProjectID, op.ContentID); err != nil {
+				break
+			}
+			if fv, err = ParseFieldValue(field, op.Value); err != nil {
+				break
+			}
+			err = UpdateItemField(gql, op.ProjectID, id, field.ID, fv)
+		case OpRemove:
+			err = DeleteItem(gql, op.ProjectID, op.ItemID)
+		case OpArchive:
+			err = ArchiveItem(gql, op.ProjectID, op.ItemID, true)
+		case OpUnarchive:
+			err = ArchiveItem(gql, op.ProjectID, op.ItemID, false)
+		default:
+			err = fmt.Errorf("unknown op %q", op.Kind)
+		}
+		if err != nil && gql.Context().Err() != nil {
+			return done, ops[i:] // interrupted; keep the op for next time
+		}
+		if err != nil {
+			slog.Error("Could not replay deferred mutation", "op", op.Kind, "item", op.Label, "err", err)
+			continue
+		}
+		slog.Debug("Replayed deferred mutation", "op", op.Kind, "item", op.Label)
+		done++
+	}
+	return done, nil
+}
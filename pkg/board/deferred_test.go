@@ -0,0 +1,112 @@
+package board
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReplayOps(t *testing.T) {
+	ops := []Op{
+		{Kind: OpRemove, ProjectID: "PVT_1", ItemID: "PVTI_1", Label: "o/r#1"},
+		{Kind: OpArchive, ProjectID: "PVT_1", ItemID: "PVTI_2", Label: "o/r#2"},
+		{Kind: OpSetField, ProjectID: "PVT_1", ContentID: "I_3", Field: "Notes", Value: "hi", Label: "o/r#3"},
+		{Kind: OpUnarchive, ProjectID: "PVT_1", ItemID: "PVTI_4", Label: "o/r#4"},
+	}
+	tests := []struct {
+		name          string
+		budget        *Budget
+		fail          string // item ID whose mutation GitHub rejects
+		cancelAt      int    // cancel the context while sending this mutation (1-based)
+		wantDone      int
+		wantRemaining []Op
+		wantSent      []string // item IDs mutated, in order
+	}{
+		{
+			name:     "all",
+			wantDone: 4,
+			wantSent: []string{"PVTI_1", "PVTI_2", "PVTI_3", "PVTI_4"},
+		},
+		{
+			name:          "budget runs out",
+			budget:        &Budget{Limit: 2},
+			wantDone:      2,
+			wantRemaining: ops[2:],
+			wantSent:      []string{"PVTI_1", "PVTI_2"},
+		},
+		{
+			name:     "failed op is dropped",
+			fail:     "PVTI_2",
+			wantDone: 3,
+			wantSent: []string{"PVTI_1", "PVTI_2", "PVTI_3", "PVTI_4"},
+		},
+		{
+			name:          "cancelled partway",
+			cancelAt:      2,
+			wantDone:      2,
+			wantRemaining: ops[2:],
+			wantSent:      []string{"PVTI_1", "PVTI_2"},
+		},
+		{
+			name:          "op interrupted by cancellation is kept",
+			fail:          "PVTI_2",
+			cancelAt:      2,
+			wantDone:      1,
+			wantRemaining: ops[1:],
+			wantSent:      []string{"PVTI_1", "PVTI_2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var sent []string
+			gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query     string         `json:"query"`
+					Variables map[string]any `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				if !strings.HasPrefix(strings.TrimSpace(req.Query), "mutation") {
+					switch {
+					case strings.Contains(req.Query, "items(first: 100"):
+						w.Write([]byte(`{"data":{"node":{"title":"Board","items":{"nodes":[
+							{"id":"PVTI_3","fieldValues":{"nodes":[]},"content":{"__typename":"Issue","id":"I_3","number":3,"repository":{"nameWithOwner":"o/r"}}}
+						],"pageInfo":{"hasNextPage":false}}}}}`))
+					case strings.Contains(req.Query, "fields(first: 50)"):
+						w.Write([]byte(`{"data":{"node":{"fields":{"nodes":[{"id":"PVTF_notes","name":"Notes","dataType":"TEXT"}]}}}}`))
+					default:
+						t.Fatalf("unexpected query: %s", req.Query)
+					}
+					return
+				}
+				id, _ := req.Variables["itemId"].(string)
+				sent = append(sent, id)
+				if len(sent) == tt.cancelAt {
+					cancel()
+				}
+				if id == tt.fail {
+					w.Write([]byte(`{"errors":[{"message":"something went wrong"}]}`))
+					return
+				}
+				w.Write([]byte(`{"data":{}}`))
+			}).WithContext(ctx)
+
+			done, remaining := ReplayOps(gql, ops, tt.budget)
+			if done != tt.wantDone {
+				t.Errorf("done = %d, want %d", done, tt.wantDone)
+			}
+			if !reflect.DeepEqual(remaining, tt.wantRemaining) {
+				t.Errorf("remaining = %+v, want %+v", remaining, tt.wantRemaining)
+			}
+			if !reflect.DeepEqual(sent, tt.wantSent) {
+				t.Errorf("mutated %q, want %q", sent, tt.wantSent)
+			}
+		})
+	}
+}
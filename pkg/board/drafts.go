@@ -0,0 +1,94 @@
+package board
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// SourceIDField is the text field holding, on a draft copied from a source
+// board, the source draft's content ID, so later syncs find the copy again.
+// Board views don't show it unless one is set to.
+const SourceIDField = "Source ID"
+
+// draftItems returns the drafts among items.
+func draftItems(items []Item) []Item {
+	var drafts []Item
+	for _, item := range items {
+		if item.Type == "DraftIssue" && item.NodeID != "" {
+			drafts = append(drafts, item)
+		}
+	}
+	return drafts
+}
+
+// syncDrafts copies drafts (from source boards) onto the board, recording
+// each one's NodeID in SourceIDField, and updates the title and body of
+// copies made earlier whose source has changed. Drafts the budget can't
+// cover are left for the next run.
+func syncDrafts(gql *ghgql.Client, projectID string, drafts []Item, budget *Budget) (added, updated []Item, unchanged int, failed []Failure, err error) {
+	fields, err := GetProjectFields(gql, projectID)
+	if err != nil {
+		return nil, nil, 0, nil, fmt.Errorf("listing fields: %w", err)
+	}
+	fields = EnsureFields(gql, projectID, []FieldSpec{{Name: SourceIDField, Type: "TEXT"}}, fields)
+	if _, ok := fields[SourceIDField]; !ok {
+		return nil, nil, 0, nil, fmt.Errorf("board has no %s field", SourceIDField)
+	}
+
+	boardItems, err := FetchProjectItems(gql, projectID)
+	if err != nil {
+		return nil, nil, 0, nil, fmt.Errorf("listing project items: %w", err)
+	}
+	copies := make(map[string]ProjectItemWithFields)
+	for _, bi := range boardItems {
+		if id := bi.Fields[SourceIDField]; bi.Type == "DraftIssue" && id != "" {
+			copies[id] = bi
+		}
+	}
+
+	left := 0
+	for _, item := range drafts {
+		bi, ok := copies[item.NodeID]
+		switch {
+		case ok && bi.Title == item.Title && bi.Body == item.Body:
+			unchanged++
+		case ok:
+			if !afford(gql, budget, 1) {
+				left++
+				continue
+			}
+			if err := UpdateDraftIssue(gql, bi.ContentID, item.Title, item.Body); err != nil {
+				slog.Error("Could not update draft", "item", item.Title, "err", err)
+				failed = append(failed, Failure{Kind: OpSetField, Label: "draft " + item.Title, Err: err})
+				continue
+			}
+			slog.Debug("Updated draft", "item", item.Title)
+			updated = append(updated, item)
+		default:
+			if !afford(gql, budget, 2) {
+				left++
+				continue
+			}
+			itemID, err := AddDraftIssue(gql, projectID, item.Title, item.Body)
+			if err != nil {
+				slog.Error("Could not add draft", "item", item.Title, "err", err)
+				failed = append(failed, Failure{Kind: OpAdd, Label: "draft " + item.Title, Err: err})
+				continue
+			}
+			// Without its source ID, the next run would add the draft again.
+			if err := SetItemFields(gql, projectID, itemID, map[string]string{SourceIDField: item.NodeID}, fields); err != nil {
+				slog.Error("Could not record the source of draft", "item", item.Title, "err", err)
+				failed = append(failed, Failure{Kind: OpSetField, Label: "draft " + item.Title, Err: err})
+			}
+			slog.Debug("Added draft", "item", item.Title)
+			item.itemID = itemID
+			added = append(added, item)
+		}
+	}
+	if left > 0 {
+		slog.Warn("Out of budget or interrupted: left drafts for the next run", "count", left)
+	}
+	return added, updated, unchanged, failed, nil
+}
@@ -0,0 +1,150 @@
+package board
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSyncDrafts(t *testing.T) {
+	// The board holds copies of DI_same and DI_edit, and a draft added by
+	// hand; DI_new has no copy yet.
+	const boardItems = `{"data":{"node":{"title":"Board","items":{"nodes":[
+		{"id":"PVTI_same","fieldValues":{"nodes":[{"text":"DI_same","field":{"name":"Source ID"}}]},"content":{"__typename":"DraftIssue","id":"DI_samecopy","title":"Same","body":"unchanged"}},
+		{"id":"PVTI_edit","fieldValues":{"nodes":[{"text":"DI_edit","field":{"name":"Source ID"}}]},"content":{"__typename":"DraftIssue","id":"DI_editcopy","title":"Old title","body":"old"}},
+		{"id":"PVTI_hand","fieldValues":{"nodes":[]},"content":{"__typename":"DraftIssue","id":"DI_hand","title":"New","body":"added by hand"}}
+	],"pageInfo":{"hasNextPage":false}}}}}`
+	drafts := []Item{
+		{NodeID: "DI_same", Type: "DraftIssue", Title: "Same", Body: "unchanged"},
+		{NodeID: "DI_edit", Type: "DraftIssue", Title: "New title", Body: "new"},
+		{NodeID: "DI_new", Type: "DraftIssue", Title: "New", Body: "fresh"},
+	}
+
+	tests := []struct {
+		name          string
+		budget        *Budget
+		fail          string // mutation GitHub rejects
+		wantSent      []string
+		wantAdded     []string // titles
+		wantUpdated   []string
+		wantUnchanged int
+		wantFailed    []string
+	}{
+		{
+			name: "create, update, and leave alone",
+			wantSent: []string{
+				"update DI_editcopy New title/new",
+				"add New/fresh",
+				"set PVTI_new Source ID=DI_new",
+			},
+			wantAdded:     []string{"New"},
+			wantUpdated:   []string{"New title"},
+			wantUnchanged: 1,
+		},
+		{
+			name:          "budget for the update only",
+			budget:        &Budget{Limit: 2},
+			wantSent:      []string{"update DI_editcopy New title/new"},
+			wantUpdated:   []string{"New title"},
+			wantUnchanged: 1,
+		},
+		{
+			name:          "update fails",
+			fail:          "updateProjectV2DraftIssue",
+			wantSent:      []string{"update DI_editcopy New title/new", "add New/fresh", "set PVTI_new Source ID=DI_new"},
+			wantAdded:     []string{"New"},
+			wantUnchanged: 1,
+			wantFailed:    []string{OpSetField},
+		},
+		{
+			name:          "add fails",
+			fail:          "addProjectV2DraftIssue",
+			wantSent:      []string{"update DI_editcopy New title/new", "add New/fresh"},
+			wantUpdated:   []string{"New title"},
+			wantUnchanged: 1,
+			wantFailed:    []string{OpAdd},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query     string         `json:"query"`
+					Variables map[string]any `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				v := req.Variables
+				var mutation string
+				switch {
+				case strings.Contains(req.Query, "items(first: 100"):
+					w.Write([]byte(boardItems))
+					return
+				case strings.Contains(req.Query, "fields(first: 50)"):
+					w.Write([]byte(`{"data":{"node":{"fields":{"nodes":[{"id":"PVTF_source","name":"Source ID","dataType":"TEXT"}]}}}}`))
+					return
+				case strings.Contains(req.Query, "updateProjectV2DraftIssue"):
+					mutation = "updateProjectV2DraftIssue"
+					sent = append(sent, fmt.Sprintf("update %s %s/%s", v["draftIssueId"], v["title"], v["body"]))
+				case strings.Contains(req.Query, "addProjectV2DraftIssue"):
+					mutation = "addProjectV2DraftIssue"
+					sent = append(sent, fmt.Sprintf("add %s/%s", v["title"], v["body"]))
+				case strings.Contains(req.Query, "updateProjectV2ItemFieldValue"):
+					mutation = "updateProjectV2ItemFieldValue"
+					value, _ := v["value"].(map[string]any)
+					if v["fieldId"] != "PVTF_source" {
+						t.Errorf("set field %v, want PVTF_source", v["fieldId"])
+					}
+					sent = append(sent, fmt.Sprintf("set %s Source ID=%s", v["itemId"], value["text"]))
+				default:
+					t.Fatalf("unexpected query: %s", req.Query)
+				}
+				if mutation == tt.fail {
+					w.Write([]byte(`{"errors":[{"message":"something went wrong"}]}`))
+					return
+				}
+				w.Write([]byte(`{"data":{"addProjectV2DraftIssue":{"projectItem":{"id":"PVTI_new"}}}}`))
+			})
+
+			added, updated, unchanged, failed, err := syncDrafts(gql, "PVT_1", drafts, tt.budget)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(sent, tt.wantSent) {
+				t.Errorf("sent %q, want %q", sent, tt.wantSent)
+			}
+			if got := titles(added); !reflect.DeepEqual(got, tt.wantAdded) {
+				t.Errorf("added %q, want %q", got, tt.wantAdded)
+			}
+			if got := titles(updated); !reflect.DeepEqual(got, tt.wantUpdated) {
+				t.Errorf("updated %q, want %q", got, tt.wantUpdated)
+			}
+			if unchanged != tt.wantUnchanged {
+				t.Errorf("unchanged = %d, want %d", unchanged, tt.wantUnchanged)
+			}
+			var kinds []string
+			for _, f := range failed {
+				kinds = append(kinds, f.Kind)
+			}
+			if !reflect.DeepEqual(kinds, tt.wantFailed) {
+				t.Errorf("failed %v, want %v", kinds, tt.wantFailed)
+			}
+			if len(added) > 0 && added[0].itemID != "PVTI_new" {
+				t.Errorf("added item ID = %q, want PVTI_new", added[0].itemID)
+			}
+		})
+	}
+}
+
+func titles(items []Item) []string {
+	var out []string
+	for _, item := range items {
+		out = append(out, item.Title)
+	}
+	return out
+}
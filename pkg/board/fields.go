@@ -2,11 +2,18 @@ package board
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/tracing"
 )
 
 // FieldDef describes a GitHub Projects V2 field and its options.
@@ -32,13 +39,15 @@ type FieldMap map[string]FieldDef
 type FieldValue struct {
 	SingleSelectOptionID string
 	Text                 string
-	Date                 string // YYYY-MM-DD format
+	Date                 string   // YYYY-MM-DD format
+	Number               *float64 // nil = unset (0 is a valid number)
 }
 
 // ProjectWithFields holds a project's info along with its field definitions.
 type ProjectWithFields struct {
 	Info
 	Public bool
+	Closed bool
 	Fields FieldMap
 }
 
@@ -49,7 +58,7 @@ func FindProjectByNumber(gql *ghgql.Client, org string, number int) (*ProjectWit
 	query := `query($org: String!, $number: Int!) {
 		organization(login: $org) {
 			projectV2(number: $number) {
-				id title number url public
+				id title number url public closed
 				fields(first: 50) {
 					nodes {
 						... on ProjectV2SingleSelectField {
@@ -73,6 +82,7 @@ func FindProjectByNumber(gql *ghgql.Client, org string, number int) (*ProjectWit
 				Number int    `json:"number"`
 				URL    string `json:"url"`
 				Public bool   `json:"public"`
+				Closed bool   `json:"closed"`
 				Fields struct {
 					Nodes []projectFieldNode `json:"nodes"`
 				} `json:"fields"`
@@ -103,6 +113,7 @@ func FindProjectByNumber(gql *ghgql.Client, org string, number int) (*ProjectWit
 			URL:    p.URL,
 		},
 		Public: p.Public,
+		Closed: p.Closed,
 		Fields: fields,
 	}, nil
 }
@@ -112,7 +123,7 @@ func FindUserProjectByNumber(gql *ghgql.Client, user string, number int) (*Proje
 	query := `query($user: String!, $number: Int!) {
 		user(login: $user) {
 			projectV2(number: $number) {
-				id title number url public
+				id title number url public closed
 				fields(first: 50) {
 					nodes {
 						... on ProjectV2SingleSelectField {
@@ -136,6 +147,7 @@ func FindUserProjectByNumber(gql *ghgql.Client, user string, number int) (*Proje
 				Number int    `json:"number"`
 				URL    string `json:"url"`
 				Public bool   `json:"public"`
+				Closed bool   `json:"closed"`
 				Fields struct {
 					Nodes []projectFieldNode `json:"nodes"`
 				} `json:"fields"`
@@ -166,10 +178,26 @@ func FindUserProjectByNumber(gql *ghgql.Client, user string, number int) (*Proje
 			URL:    p.URL,
 		},
 		Public: p.Public,
+		Closed: p.Closed,
 		Fields: fields,
 	}, nil
 }
 
+// FindProjectByOwnerNumber queries a project by number under an owner that
+// may be either an organization or a user. The organization lookup is tried
+// first since most shared boards are org-owned.
+func FindProjectByOwnerNumber(gql *ghgql.Client, owner string, number int) (*ProjectWithFields, error) {
+	p, orgErr := FindProjectByNumber(gql, owner, number)
+	if orgErr == nil {
+		return p, nil
+	}
+	p, userErr := FindUserProjectByNumber(gql, owner, number)
+	if userErr == nil {
+		return p, nil
+	}
+	return nil, fmt.Errorf("project #%d not found for %s (org: %v; user: %v)", number, owner, orgErr, userErr)
+}
+
 type projectFieldNode struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
@@ -263,6 +291,88 @@ func EnsureVisibility(gql *ghgql.Client, projectID string, public bool) error {
 	}, &result)
 }
 
+// SetVisibility makes a project public or private if it isn't already,
+// warning when it changes it, since a board changing visibility behind its
+// owners' backs should never go unnoticed.
+func SetVisibility(gql *ghgql.Client, project *Info, public bool) error {
+	query := `query($projectId: ID!) {
+		node(id: $projectId) { ... on ProjectV2 { public } }
+	}`
+	var current struct {
+		Node struct {
+			Public bool `json:"public"`
+		} `json:"node"`
+	}
+	if err := gql.Do(ghgql.Request{Query: query, Variables: map[string]any{"projectId": project.ID}}, &current); err != nil {
+		return fmt.Errorf("reading visibility: %w", err)
+	}
+	if current.Node.Public == public {
+		return nil
+	}
+	if err := EnsureVisibility(gql, project.ID, public); err != nil {
+		return err
+	}
+	if public {
+		slog.Warn("Made board public — anyone can now see it", "url", project.URL)
+	} else {
+		slog.Warn("Made board private — it was public", "url", project.URL)
+	}
+	return nil
+}
+
+// ---------- Project Details ----------
+
+// SetProjectDetails sets a project's short description and README where
+// they differ from what it has; an empty one is left as it is. Boards made
+// by a sync can say so, e.g. "Generated from <query> every hour — edits are
+// overwritten".
+func SetProjectDetails(gql *ghgql.Client, projectID, shortDescription, readme string) error {
+	if shortDescription == "" && readme == "" {
+		return nil
+	}
+	query := `query($projectId: ID!) {
+		node(id: $projectId) {
+			... on ProjectV2 { shortDescription readme }
+		}
+	}`
+	var current struct {
+		Node struct {
+			ShortDescription string `json:"shortDescription"`
+			Readme           string `json:"readme"`
+		} `json:"node"`
+	}
+	if err := gql.Do(ghgql.Request{Query: query, Variables: map[string]any{"projectId": projectID}}, &current); err != nil {
+		return fmt.Errorf("reading project details: %w", err)
+	}
+
+	input := map[string]any{"projectId": projectID}
+	if shortDescription != "" && shortDescription != current.Node.ShortDescription {
+		input["shortDescription"] = shortDescription
+	}
+	if readme != "" && strings.TrimSpace(readme) != strings.TrimSpace(current.Node.Readme) {
+		input["readme"] = readme
+	}
+	if len(input) == 1 {
+		return nil
+	}
+	mutation := `mutation($input: UpdateProjectV2Input!) {
+		updateProjectV2(input: $input) {
+			projectV2 { id }
+		}
+	}`
+	var result json.RawMessage
+	if err := gql.Do(ghgql.Request{Query: mutation, Variables: map[string]any{"input": input}}, &result); err != nil {
+		return fmt.Errorf("updating project details: %w", err)
+	}
+	if _, ok := input["shortDescription"]; ok {
+		slog.Debug("Set the board's description")
+	}
+	if _, ok := input["readme"]; ok {
+		slog.Debug("Set the board's README")
+	}
+	return nil
+}
+
 // ---------- Update Item Field ----------
 
 // UpdateItemField sets a field value on a project item.
@@ -272,6 +382,8 @@ func UpdateItemField(gql *ghgql.Client, projectID, itemID, fieldID string, value
 		valueMap = map[string]any{"singleSelectOptionId": value.SingleSelectOptionID}
 	} else if value.Date != "" {
 		valueMap = map[string]any{"date": value.Date}
+	} else if value.Number != nil {
+		valueMap = map[string]any{"number": *value.Number}
 	} else if value.Text != "" {
 		valueMap = map[string]any{"text": value.Text}
 	} else {
@@ -354,57 +466,193 @@ func AddItem(gql *ghgql.Client, projectID, contentID string) (string, error) {
 	return result.AddProjectV2ItemById.Item.ID, nil
 }
 
+// ---------- Add Draft Issue ----------
+
+// AddDraftIssue creates a draft issue on a project and returns its project
+// item ID. Drafts live only on the board; nothing is created in a repository.
+func AddDraftIssue(gql *ghgql.Client, projectID, title, body string) (string, error) {
+	mutation := `mutation($projectId: ID!, $title: String!, $body: String) {
+		addProjectV2DraftIssue(input: {projectId: $projectId, title: $title, body: $body}) {
+			projectItem { id }
+		}
+	}`
+
+	var result struct {
+		AddProjectV2DraftIssue struct {
+			ProjectItem struct {
+				ID string `json:"id"`
+			} `json:"projectItem"`
+		} `json:"addProjectV2DraftIssue"`
+	}
+
+	err := gql.Do(ghgql.Request{
+		Query:     mutation,
+		Variables: map[string]any{"projectId": projectID, "title": title, "body": body},
+	}, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.AddProjectV2DraftIssue.ProjectItem.ID, nil
+}
+
+// UpdateDraftIssue replaces the title and body of a draft issue. draftID is
+// the draft's content ID (ProjectItemWithFields.ContentID), not its item ID.
+func UpdateDraftIssue(gql *ghgql.Client, draftID, title, body string) error {
+	mutation := `mutation($draftIssueId: ID!, $title: String!, $body: String) {
+		updateProjectV2DraftIssue(input: {draftIssueId: $draftIssueId, title: $title, body: $body}) {
+			draftIssue { id }
+		}
+	}`
+
+	var result json.RawMessage
+	return gql.Do(ghgql.Request{
+		Query:     mutation,
+		Variables: map[string]any{"draftIssueId": draftID, "title": title, "body": body},
+	}, &result)
+}
+
+// DraftIssueBody returns the body of a draft issue by its content ID.
+func DraftIssueBody(gql *ghgql.Client, draftID string) (string, error) {
+	query := `query($id: ID!) {
+		node(id: $id) { ... on DraftIssue { body } }
+	}`
+
+	var result struct {
+		Node struct {
+			Body string `json:"body"`
+		} `json:"node"`
+	}
+	if err := gql.Do(ghgql.Request{Query: query, Variables: map[string]any{"id": draftID}}, &result); err != nil {
+		return "", err
+	}
+	return result.Node.Body, nil
+}
+
+// ---------- Delete Item ----------
+
+// DeleteItem removes an item from a project. The underlying issue or PR is
+// not affected.
+func DeleteItem(gql *ghgql.Client, projectID, itemID string) error {
+	mutation := `mutation($projectId: ID!, $itemId: ID!) {
+		deleteProjectV2Item(input: {projectId: $projectId, itemId: $itemId}) {
+			deletedItemId
+		}
+	}`
+
+	var result json.RawMessage
+	err := gql.Do(ghgql.Request{
+		Query:     mutation,
+		Variables: map[string]any{"projectId": projectID, "itemId": itemID},
+	}, &result)
+	if err != nil {
+		// An overlapping run may have removed it first; that's success too.
+		if exists, lookupErr := itemExists(gql, itemID); lookupErr == nil && !exists {
+			slog.Debug("Item already removed", "item", itemID)
+			return nil
+		}
+	}
+	return err
+}
+
+// itemExists reports whether a project item still exists.
+func itemExists(gql *ghgql.Client, itemID string) (bool, error) {
+	query := `query($id: ID!) { node(id: $id) { ... on ProjectV2Item { id } } }`
+
+	var result struct {
+		Node *struct {
+			ID string `json:"id"`
+		} `json:"node"`
+	}
+	err := gql.Do(ghgql.Request{Query: query, Variables: map[string]any{"id": itemID}}, &result)
+	if err != nil {
+		if errors.Is(err, ghgql.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return result.Node != nil && result.Node.ID != "", nil
+}
+
+// ---------- Item Position ----------
+
+// SetItemPosition moves an item so it sits directly after afterID in the
+// board's default ordering. An empty afterID moves the item to the top.
+func SetItemPosition(gql *ghgql.Client, projectID, itemID, afterID string) error {
+	mutation := `mutation($projectId: ID!, $itemId: ID!, $afterId: ID) {
+		updateProjectV2ItemPosition(input: {projectId: $projectId, itemId: $itemId, afterId: $afterId}) {
+			clientMutationId
+		}
+	}`
+
+	vars := map[string]any{"projectId": projectID, "itemId": itemID}
+	if afterID != "" {
+		vars["afterId"] = afterID
+	}
+
+	var result json.RawMessage
+	return gql.Do(ghgql.Request{Query: mutation, Variables: vars}, &result)
+}
+
 // ---------- Fetch Project Items with Fields ----------
 
 // ProjectItemWithFields represents an item on a board with its custom field values.
 type ProjectItemWithFields struct {
-	ItemID    string            // project-level item ID (for mutations)
-	ContentID string            // underlying issue/PR node ID
+	ItemID    string // project-level item ID (for mutations)
+	ContentID string // underlying issue/PR node ID
 	Number    int
 	Title     string
+	Type      string // "Issue", "PullRequest", "DraftIssue"
+	URL       string
+	Repo      string // "owner/name" (empty for drafts)
+	Private   bool   // Repo is a private repository
+	State     string // OPEN, CLOSED, MERGED
+	Author    string
+	Labels    []string
+	Assignees []string // logins
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Body      string            // a draft's body (empty for issues and PRs)
 	Fields    map[string]string // field name → value
+
+	// ProjectTitle is the title of the board the item was fetched from.
+	ProjectTitle string
+
+	// Discussion activity (zero for drafts).
+	Comments  int
+	Reactions int
+
+	// Milestone title and due date (zero when unset or no due date).
+	Milestone      string
+	MilestoneDueOn time.Time
+
+	// Pull request size (zero for issues and drafts).
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+
+	// Pull request review state: IsDraft marks draft PRs, and ReviewDecision
+	// is APPROVED, CHANGES_REQUESTED, REVIEW_REQUIRED, or "" when the repo
+	// requires no review.
+	IsDraft        bool
+	ReviewDecision string
 }
 
 // FetchProjectItems returns all items on a project with their custom field values.
-func FetchProjectItems(gql *ghgql.Client, projectID string) ([]ProjectItemWithFields, error) {
+func FetchProjectItems(gql *ghgql.Client, projectID string) (list []ProjectItemWithFields, err error) {
+	span := tracing.Start("board.FetchProjectItems")
+	defer func() {
+		span.SetAttributes(attribute.Int("items.count", len(list)))
+		tracing.EndWithError(span, err)
+	}()
+
 	query := `query($projectId: ID!, $cursor: String) {
 		node(id: $projectId) {
 			... on ProjectV2 {
+				title
 				items(first: 100, after: $cursor) {
 					nodes {
-						id
-						fieldValues(first: 50) {
-							nodes {
-								... on ProjectV2ItemFieldSingleSelectValue {
-									name
-									field { ... on ProjectV2FieldCommon { name } }
-								}
-								... on ProjectV2ItemFieldTextValue {
-									text
-									field { ... on ProjectV2FieldCommon { name } }
-								}
-								... on ProjectV2ItemFieldDateValue {
-									date
-									field { ... on ProjectV2FieldCommon { name } }
-								}
-								... on ProjectV2ItemFieldNumberValue {
-									number
-									field { ... on ProjectV2FieldCommon { name } }
-								}
-								... on ProjectV2ItemFieldIterationValue {
-									title
-									field { ... on ProjectV2FieldCommon { name } }
-								}
-							}
-						}
-						content {
-							... on Issue {
-								id number title
-							}
-							... on PullRequest {
-								id number title
-							}
-						}
+						` + projectItemSelection + `
 					}
 					pageInfo { hasNextPage endCursor }
 				}
@@ -413,78 +661,226 @@ func FetchProjectItems(gql *ghgql.Client, projectID string) ([]ProjectItemWithFi
 	}`
 
 	var items []ProjectItemWithFields
-	var cursor *string
-
-	for {
-		vars := map[string]any{"projectId": projectID}
-		if cursor != nil {
-			vars["cursor"] = *cursor
-		}
-
-		var result struct {
-			Node struct {
-				Items struct {
-					Nodes []struct {
-						ID          string `json:"id"`
-						FieldValues struct {
-							Nodes []fieldValNode `json:"nodes"`
-						} `json:"fieldValues"`
-						Content struct {
-							ID     string `json:"id"`
-							Number int    `json:"number"`
-							Title  string `json:"title"`
-						} `json:"content"`
-					} `json:"nodes"`
-					PageInfo struct {
-						HasNextPage bool   `json:"hasNextPage"`
-						EndCursor   string `json:"endCursor"`
-					} `json:"pageInfo"`
-				} `json:"items"`
-			} `json:"node"`
-		}
-
-		err := gql.Do(ghgql.Request{Query: query, Variables: vars}, &result)
-		if err != nil {
-			return nil, err
+	type page struct {
+		Node struct {
+			Title string `json:"title"`
+			Items struct {
+				Nodes    []projectItemNode `json:"nodes"`
+				PageInfo ghgql.PageInfo    `json:"pageInfo"`
+			} `json:"items"`
+		} `json:"node"`
+	}
+	err = ghgql.Paginate(gql, ghgql.Request{Query: query, Variables: map[string]any{"projectId": projectID}}, func(result *page) (ghgql.PageInfo, error) {
+		for _, n := range result.Node.Items.Nodes {
+			items = append(items, n.toItem(result.Node.Title))
 		}
+		return result.Node.Items.PageInfo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		for _, n := range result.Node.Items.Nodes {
-			fields := make(map[string]string)
-			for _, fv := range n.FieldValues.Nodes {
-				fieldName := fv.Field.Name
-				if fieldName == "" {
-					continue
-				}
-				switch {
-				case fv.Name != "":
-					fields[fieldName] = fv.Name
-				case fv.Text != "":
-					fields[fieldName] = fv.Text
-				case fv.Date != "":
-					fields[fieldName] = fv.Date
-				case fv.Number != 0:
-					fields[fieldName] = fmt.Sprintf("%.0f", fv.Number)
-				case fv.Title != "":
-					fields[fieldName] = fv.Title
-				}
+	return items, nil
+}
+
+// projectItemSelection selects a ProjectV2Item's ID, field values, and
+// content, as decoded by projectItemNode.
+const projectItemSelection = `id
+	fieldValues(first: 50) {
+		nodes {
+			... on ProjectV2ItemFieldSingleSelectValue {
+				name
+				field { ... on ProjectV2FieldCommon { name } }
 			}
-			items = append(items, ProjectItemWithFields{
-				ItemID:    n.ID,
-				ContentID: n.Content.ID,
-				Number:    n.Content.Number,
-				Title:     n.Content.Title,
-				Fields:    fields,
-			})
+			... on ProjectV2ItemFieldTextValue {
+				text
+				field { ... on ProjectV2FieldCommon { name } }
+			}
+			... on ProjectV2ItemFieldDateValue {
+				date
+				field { ... on ProjectV2FieldCommon { name } }
+			}
+			... on ProjectV2ItemFieldNumberValue {
+				number
+				field { ... on ProjectV2FieldCommon { name } }
+			}
+			... on ProjectV2ItemFieldIterationValue {
+				title
+				field { ... on ProjectV2FieldCommon { name } }
+			}
+		}
+	}
+	content {
+		__typename
+		... on Issue {
+			id number title url state createdAt updatedAt
+			repository { nameWithOwner isPrivate }
+			author { login }
+			labels(first: 20) { nodes { name } }
+			assignees(first: 10) { nodes { login } }
+			comments { totalCount }
+			reactions { totalCount }
+			milestone { title dueOn }
+		}
+		... on PullRequest {
+			id number title url state createdAt updatedAt
+			repository { nameWithOwner isPrivate }
+			author { login }
+			labels(first: 20) { nodes { name } }
+			assignees(first: 10) { nodes { login } }
+			comments { totalCount }
+			reactions { totalCount }
+			milestone { title dueOn }
+			additions deletions changedFiles
+			isDraft reviewDecision
+		}
+		... on DraftIssue {
+			id title body createdAt updatedAt
 		}
+	}`
+
+// projectItemNode is a ProjectV2Item selected with projectItemSelection.
+type projectItemNode struct {
+	ID          string `json:"id"`
+	FieldValues struct {
+		Nodes []fieldValNode `json:"nodes"`
+	} `json:"fieldValues"`
+	Content itemContentNode `json:"content"`
+}
 
-		if !result.Node.Items.PageInfo.HasNextPage {
-			break
+// toItem converts n into a ProjectItemWithFields on the board projectTitle.
+func (n projectItemNode) toItem(projectTitle string) ProjectItemWithFields {
+	fields := make(map[string]string)
+	for _, fv := range n.FieldValues.Nodes {
+		fieldName := fv.Field.Name
+		if fieldName == "" {
+			continue
+		}
+		switch {
+		case fv.Name != "":
+			fields[fieldName] = fv.Name
+		case fv.Text != "":
+			fields[fieldName] = fv.Text
+		case fv.Date != "":
+			fields[fieldName] = fv.Date
+		case fv.Number != 0:
+			fields[fieldName] = fmt.Sprintf("%.0f", fv.Number)
+		case fv.Title != "":
+			fields[fieldName] = fv.Title
 		}
-		c := result.Node.Items.PageInfo.EndCursor
-		cursor = &c
 	}
+	c := n.Content
+	var labels []string
+	for _, l := range c.Labels.Nodes {
+		labels = append(labels, l.Name)
+	}
+	var assignees []string
+	for _, a := range c.Assignees.Nodes {
+		assignees = append(assignees, a.Login)
+	}
+	return ProjectItemWithFields{
+		ItemID:         n.ID,
+		ContentID:      c.ID,
+		Number:         c.Number,
+		Title:          c.Title,
+		Type:           c.Typename,
+		URL:            c.URL,
+		Repo:           c.Repository.NameWithOwner,
+		Private:        c.Repository.IsPrivate,
+		State:          c.State,
+		Author:         c.Author.Login,
+		Labels:         labels,
+		Assignees:      assignees,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+		Body:           c.Body,
+		Fields:         fields,
+		ProjectTitle:   projectTitle,
+		Comments:       c.Comments.TotalCount,
+		Reactions:      c.Reactions.TotalCount,
+		Milestone:      c.Milestone.Title,
+		MilestoneDueOn: c.Milestone.DueOn,
+		Additions:      c.Additions,
+		Deletions:      c.Deletions,
+		ChangedFiles:   c.ChangedFiles,
+		IsDraft:        c.IsDraft,
+		ReviewDecision: c.ReviewDecision,
+	}
+}
 
-	return items, nil
+// FetchProjectItem returns one project item, by its item node ID, with its
+// field values, along with the ID of the project it belongs to.
+func FetchProjectItem(gql *ghgql.Client, itemID string) (item *ProjectItemWithFields, projectID string, err error) {
+	query := `query($id: ID!) {
+		node(id: $id) {
+			... on ProjectV2Item {
+				` + projectItemSelection + `
+				project { id title }
+			}
+		}
+	}`
+	var result struct {
+		Node *struct {
+			projectItemNode
+			Project struct {
+				ID    string `json:"id"`
+				Title string `json:"title"`
+			} `json:"project"`
+		} `json:"node"`
+	}
+	if err := gql.Do(ghgql.Request{Query: query, Variables: map[string]any{"id": itemID}}, &result); err != nil {
+		return nil, "", err
+	}
+	if result.Node == nil || result.Node.ID == "" {
+		return nil, "", fmt.Errorf("project item %s not found", itemID)
+	}
+	it := result.Node.toItem(result.Node.Project.Title)
+	return &it, result.Node.Project.ID, nil
+}
+
+// itemContentNode is the issue/PR/draft content of a project item.
+type itemContentNode struct {
+	Typename   string    `json:"__typename"`
+	ID         string    `json:"id"`
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"` // drafts only
+	URL        string    `json:"url"`
+	State      string    `json:"state"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+		IsPrivate     bool   `json:"isPrivate"`
+	} `json:"repository"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Assignees struct {
+		Nodes []struct {
+			Login string `json:"login"`
+		} `json:"nodes"`
+	} `json:"assignees"`
+	Comments struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"comments"`
+	Reactions struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"reactions"`
+	Milestone struct {
+		Title string    `json:"title"`
+		DueOn time.Time `json:"dueOn"`
+	} `json:"milestone"`
+	Additions      int    `json:"additions"`
+	Deletions      int    `json:"deletions"`
+	ChangedFiles   int    `json:"changedFiles"`
+	IsDraft        bool   `json:"isDraft"`
+	ReviewDecision string `json:"reviewDecision"`
 }
 
 type fieldValNode struct {
@@ -512,10 +908,53 @@ func ResolveOptionID(field FieldDef, optionName string) (string, bool) {
 	return "", false
 }
 
+// RenameField changes a field's name; its values are kept.
+func RenameField(gql *ghgql.Client, fieldID, name string) error {
+	mutation := `mutation($fieldId: ID!, $name: String!) {
+		updateProjectV2Field(input: {fieldId: $fieldId, name: $name}) {
+			projectV2Field { ... on ProjectV2FieldCommon { id name } }
+		}
+	}`
+	var result json.RawMessage
+	if err := gql.Do(ghgql.Request{Query: mutation, Variables: map[string]any{"fieldId": fieldID, "name": name}}, &result); err != nil {
+		return fmt.Errorf("failed to rename field to %q: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteField deletes a field, and with it every item's value for it.
+// Built-in fields such as Title and Status can't be deleted.
+func DeleteField(gql *ghgql.Client, fieldID string) error {
+	mutation := `mutation($fieldId: ID!) {
+		deleteProjectV2Field(input: {fieldId: $fieldId}) {
+			projectV2Field { ... on ProjectV2FieldCommon { id } }
+		}
+	}`
+	var result json.RawMessage
+	if err := gql.Do(ghgql.Request{Query: mutation, Variables: map[string]any{"fieldId": fieldID}}, &result); err != nil {
+		return fmt.Errorf("failed to delete field: %w", err)
+	}
+	return nil
+}
+
 // EnsureOption adds a single-select option to a field if it doesn't already
 // exist. Returns the updated FieldDef with the new option included.
 func EnsureOption(gql *ghgql.Client, field FieldDef, optionName string) (FieldDef, error) {
-	if _, found := ResolveOptionID(field, optionName); found {
+	return EnsureOptions(gql, field, []string{optionName})
+}
+
+// EnsureOptions appends the options in names that a single-select field
+// lacks (compared case-insensitively), in one update. Existing options are
+// sent back with their IDs, so items keep their values. Returns the updated
+// FieldDef.
+func EnsureOptions(gql *ghgql.Client, field FieldDef, names []string) (FieldDef, error) {
+	var missing []string
+	for _, name := range names {
+		if _, found := ResolveOptionID(field, name); !found && !slices.ContainsFunc(missing, func(m string) bool { return strings.EqualFold(m, name) }) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
 		return field, nil
 	}
 
@@ -527,16 +966,19 @@ func EnsureOption(gql *ghgql.Client, field FieldDef, optionName string) (FieldDe
 			color = "GRAY"
 		}
 		opts = append(opts, map[string]any{
+			"id":          existing.ID,
 			"name":        existing.Name,
 			"color":       color,
 			"description": existing.Description,
 		})
 	}
-	opts = append(opts, map[string]any{
-		"name":        optionName,
-		"color":       colors[len(field.Options)%len(colors)],
-		"description": "",
-	})
+	for _, name := range missing {
+		opts = append(opts, map[string]any{
+			"name":        name,
+			"color":       colors[len(opts)%len(colors)],
+			"description": "",
+		})
+	}
 
 	mutation := `mutation($fieldId: ID!, $opts: [ProjectV2SingleSelectFieldOptionInput!]!) {
 		updateProjectV2Field(input: {
@@ -572,7 +1014,7 @@ func EnsureOption(gql *ghgql.Client, field FieldDef, optionName string) (FieldDe
 		Variables: map[string]any{"fieldId": field.ID, "opts": opts},
 	}, &result)
 	if err != nil {
-		return field, fmt.Errorf("failed to add option %q to field %q: %w", optionName, field.Name, err)
+		return field, fmt.Errorf("failed to add option(s) %s to field %q: %w", quoteList(missing), field.Name, err)
 	}
 
 	updated := FieldDef{
@@ -583,17 +1025,57 @@ func EnsureOption(gql *ghgql.Client, field FieldDef, optionName string) (FieldDe
 	for _, opt := range result.UpdateProjectV2Field.ProjectV2Field.Options {
 		updated.Options = append(updated.Options, FieldOption{ID: opt.ID, Name: opt.Name, Color: opt.Color, Description: opt.Description})
 	}
-	log.Printf("  Added option %q to field %q (%d options now)", optionName, field.Name, len(updated.Options))
+	slog.Info("Added field options", "field", field.Name, "options", quoteList(missing), "count", len(updated.Options))
 	return updated, nil
 }
 
+// quoteList formats names for logs, e.g. `"Done", "Blocked"`.
+func quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
 // ---------- Set Item Fields ----------
 
+// ParseFieldValue converts a display value into the FieldValue for field:
+// an option name for single-select fields, YYYY-MM-DD for dates, a decimal
+// for numbers, and anything for text.
+func ParseFieldValue(field FieldDef, value string) (FieldValue, error) {
+	var fv FieldValue
+	switch field.Type {
+	case "SINGLE_SELECT":
+		optID, found := ResolveOptionID(field, value)
+		if !found {
+			return fv, fmt.Errorf("option %q not found for field %q", value, field.Name)
+		}
+		fv.SingleSelectOptionID = optID
+	case "DATE":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return fv, fmt.Errorf("value %q is not a YYYY-MM-DD date for field %q", value, field.Name)
+		}
+		fv.Date = value
+	case "NUMBER":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fv, fmt.Errorf("value %q is not a number for field %q", value, field.Name)
+		}
+		fv.Number = &n
+	default:
+		fv.Text = value
+	}
+	return fv, nil
+}
+
 // SetItemFields sets multiple field values on a project item.
 // fieldValues maps field names to desired string values.
 // destFields provides the field IDs and option IDs for the destination board.
-// Logs warnings for unresolvable fields/options.
-func SetItemFields(gql *ghgql.Client, projectID, itemID string, fieldValues map[string]string, destFields FieldMap) {
+// Logs warnings for unresolvable fields/options, and returns the errors of
+// any writes GitHub rejected, joined.
+func SetItemFields(gql *ghgql.Client, projectID, itemID string, fieldValues map[string]string, destFields FieldMap) error {
+	var errs []error
 	for fieldName, desiredValue := range fieldValues {
 		if desiredValue == "" {
 			continue
@@ -601,29 +1083,22 @@ func SetItemFields(gql *ghgql.Client, projectID, itemID string, fieldValues map[
 
 		destField, ok := destFields[fieldName]
 		if !ok {
-			log.Printf("    Field %q not found on destination board, skipping", fieldName)
+			slog.Debug("Field not found on destination board, skipping", "field", fieldName)
 			continue
 		}
 
-		var fv FieldValue
-		switch destField.Type {
-		case "SINGLE_SELECT":
-			optID, found := ResolveOptionID(destField, desiredValue)
-			if !found {
-				log.Printf("    Option %q not found for field %q, skipping", desiredValue, fieldName)
-				continue
-			}
-			fv.SingleSelectOptionID = optID
-		case "DATE":
-			fv.Date = desiredValue
-		default:
-			fv.Text = desiredValue
+		fv, err := ParseFieldValue(destField, desiredValue)
+		if err != nil {
+			slog.Debug("Skipping field value", "field", fieldName, "err", err)
+			continue
 		}
 
 		if err := UpdateItemField(gql, projectID, itemID, destField.ID, fv); err != nil {
-			log.Printf("    Error setting %s=%s: %v", fieldName, desiredValue, err)
+			slog.Error("Could not set field", "field", fieldName, "value", desiredValue, "err", err)
+			errs = append(errs, fmt.Errorf("setting %s: %w", fieldName, err))
 		}
 	}
+	return errors.Join(errs...)
 }
 
 // ---------- Create Custom Fields ----------
@@ -645,6 +1120,11 @@ func CreateDateField(gql *ghgql.Client, projectID, name string) (*FieldDef, erro
 	return createField(gql, projectID, name, "DATE", nil)
 }
 
+// CreateNumberField creates a number custom field on a project.
+func CreateNumberField(gql *ghgql.Client, projectID, name string) (*FieldDef, error) {
+	return createField(gql, projectID, name, "NUMBER", nil)
+}
+
 // CreateSingleSelectField creates a single-select custom field with the given options.
 func CreateSingleSelectField(gql *ghgql.Client, projectID, name string, options []string) (*FieldDef, error) {
 	return createField(gql, projectID, name, "SINGLE_SELECT", options)
@@ -732,13 +1212,18 @@ func EnsureFields(gql *ghgql.Client, projectID string, needed []FieldSpec, exist
 			if spec.Type == "SINGLE_SELECT" && len(spec.Options) > 0 {
 				missing := countMissingOptions(existingField, spec.Options)
 				if missing > 0 {
-					log.Printf("  Field %q exists but is missing %d of %d option(s) — delete field on board and re-run to fix",
-						spec.Name, missing, len(spec.Options))
+					slog.Info("Field is missing options, adding them", "field", spec.Name, "missing", missing, "count", len(spec.Options))
+					updated, err := EnsureOptions(gql, existingField, spec.Options)
+					if err != nil {
+						slog.Warn("Could not add field options — add them on the destination board by hand", "field", spec.Name, "err", err)
+						continue
+					}
+					existing[spec.Name] = updated
 				} else {
-					log.Printf("  Field %q already exists (%d option(s))", spec.Name, len(existingField.Options))
+					slog.Debug("Field already exists", "field", spec.Name, "count", len(existingField.Options))
 				}
 			} else {
-				log.Printf("  Field %q already exists", spec.Name)
+				slog.Debug("Field already exists", "field", spec.Name)
 			}
 			continue
 		}
@@ -748,22 +1233,32 @@ func EnsureFields(gql *ghgql.Client, projectID string, needed []FieldSpec, exist
 
 		switch spec.Type {
 		case "SINGLE_SELECT":
-			log.Printf("  Creating single-select field %q with %d options...", spec.Name, len(spec.Options))
+			slog.Info("Creating single-select field", "field", spec.Name, "count", len(spec.Options))
 			newField, err = CreateSingleSelectField(gql, projectID, spec.Name, spec.Options)
 		case "DATE":
-			log.Printf("  Creating date field %q...", spec.Name)
+			slog.Info("Creating date field", "field", spec.Name)
 			newField, err = CreateDateField(gql, projectID, spec.Name)
+		case "NUMBER":
+			slog.Info("Creating number field", "field", spec.Name)
+			newField, err = CreateNumberField(gql, projectID, spec.Name)
 		default:
-			log.Printf("  Creating text field %q...", spec.Name)
+			slog.Info("Creating text field", "field", spec.Name)
 			newField, err = CreateTextField(gql, projectID, spec.Name)
 		}
 
 		if err != nil {
-			log.Printf("  Warning: could not create field %q: %v", spec.Name, err)
-			log.Printf("  Please create this field manually on your destination board.")
+			// An overlapping run may have created it in the meantime.
+			if current, lookupErr := GetProjectFields(gql, projectID); lookupErr == nil {
+				if field, ok := current[spec.Name]; ok {
+					slog.Debug("Field was created concurrently, using it", "field", spec.Name)
+					existing[spec.Name] = field
+					continue
+				}
+			}
+			slog.Warn("Could not create field — create it on the destination board by hand", "field", spec.Name, "err", err)
 			continue
 		}
-		log.Printf("  Created field %q (ID: %s)", newField.Name, newField.ID)
+		slog.Debug("Created field", "field", newField.Name, "id", newField.ID)
 		existing[spec.Name] = *newField
 	}
 
@@ -0,0 +1,150 @@
+package board
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnsureOptions(t *testing.T) {
+	status := FieldDef{ID: "PVTF_status", Name: "Status", Type: "SINGLE_SELECT", Options: []FieldOption{
+		{ID: "opt_todo", Name: "Todo", Color: "BLUE", Description: "Not started"},
+		{ID: "opt_done", Name: "Done"}, // no color: sent back as GRAY
+	}}
+	var eight []FieldOption
+	for i, c := range []string{"GRAY", "BLUE", "GREEN", "YELLOW", "ORANGE", "RED", "PINK", "PURPLE"} {
+		eight = append(eight, FieldOption{ID: fmt.Sprintf("opt_%d", i), Name: fmt.Sprintf("P%d", i), Color: c})
+	}
+	tests := []struct {
+		name     string
+		field    FieldDef
+		names    []string
+		fail     bool
+		wantOpts []any // the singleSelectOptions sent; nil if no update
+		wantErr  string
+	}{
+		{
+			name:  "all present, any case",
+			field: status,
+			names: []string{"todo", "DONE"},
+		},
+		{
+			name:  "adds the missing once",
+			field: status,
+			names: []string{"todo", "In Progress", "in progress", "Blocked"},
+			wantOpts: []any{
+				map[string]any{"id": "opt_todo", "name": "Todo", "color": "BLUE", "description": "Not started"},
+				map[string]any{"id": "opt_done", "name": "Done", "color": "GRAY", "description": ""},
+				map[string]any{"name": "In Progress", "color": "GREEN", "description": ""},
+				map[string]any{"name": "Blocked", "color": "YELLOW", "description": ""},
+			},
+		},
+		{
+			name:  "colours cycle",
+			field: FieldDef{ID: "PVTF_p", Name: "Priority", Type: "SINGLE_SELECT", Options: eight},
+			names: []string{"P8", "P9"},
+			wantOpts: func() []any {
+				var opts []any
+				for _, o := range eight {
+					opts = append(opts, map[string]any{"id": o.ID, "name": o.Name, "color": o.Color, "description": ""})
+				}
+				return append(opts,
+					map[string]any{"name": "P8", "color": "GRAY", "description": ""},
+					map[string]any{"name": "P9", "color": "BLUE", "description": ""})
+			}(),
+		},
+		{
+			name:    "update fails",
+			field:   status,
+			names:   []string{"Blocked"},
+			fail:    true,
+			wantErr: `"Blocked" to field "Status"`,
+			wantOpts: []any{
+				map[string]any{"id": "opt_todo", "name": "Todo", "color": "BLUE", "description": "Not started"},
+				map[string]any{"id": "opt_done", "name": "Done", "color": "GRAY", "description": ""},
+				map[string]any{"name": "Blocked", "color": "GREEN", "description": ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []any
+			updates := 0
+			gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query     string         `json:"query"`
+					Variables map[string]any `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(req.Query, "updateProjectV2Field") {
+					t.Fatalf("unexpected query: %s", req.Query)
+				}
+				updates++
+				if req.Variables["fieldId"] != tt.field.ID {
+					t.Errorf("fieldId = %v, want %s", req.Variables["fieldId"], tt.field.ID)
+				}
+				sent, _ = req.Variables["opts"].([]any)
+				if tt.fail {
+					w.Write([]byte(`{"errors":[{"message":"something went wrong"}]}`))
+					return
+				}
+				var options []map[string]any
+				for _, o := range sent {
+					opt := maps.Clone(o.(map[string]any))
+					if opt["id"] == nil {
+						opt["id"] = "opt_new_" + opt["name"].(string)
+					}
+					options = append(options, opt)
+				}
+				resp, _ := json.Marshal(map[string]any{"data": map[string]any{"updateProjectV2Field": map[string]any{
+					"projectV2Field": map[string]any{"id": tt.field.ID, "name": tt.field.Name, "options": options},
+				}}})
+				w.Write(resp)
+			})
+
+			got, err := EnsureOptions(gql, tt.field, tt.names)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("EnsureOptions error = %v, want %q", err, tt.wantErr)
+				}
+				if !reflect.DeepEqual(got, tt.field) {
+					t.Errorf("EnsureOptions = %+v, want the field unchanged", got)
+				}
+			} else if err != nil {
+				t.Fatalf("EnsureOptions: %v", err)
+			}
+			if tt.wantOpts == nil {
+				if updates != 0 {
+					t.Errorf("sent %d updates, want none", updates)
+				}
+				if !reflect.DeepEqual(got, tt.field) {
+					t.Errorf("EnsureOptions = %+v, want the field unchanged", got)
+				}
+				return
+			}
+			if updates != 1 {
+				t.Errorf("sent %d updates, want 1", updates)
+			}
+			if !reflect.DeepEqual(sent, tt.wantOpts) {
+				t.Errorf("options sent = %v, want %v", sent, tt.wantOpts)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if len(got.Options) != len(tt.wantOpts) {
+				t.Fatalf("EnsureOptions returned %d options, want %d", len(got.Options), len(tt.wantOpts))
+			}
+			for _, name := range tt.names {
+				if _, ok := ResolveOptionID(got, name); !ok {
+					t.Errorf("option %q missing from the returned field", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package board
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// Spec is a declarative board template: the fields, views, visibility, and
+// description a board should have, read from YAML with LoadSpec and applied to a new or
+// existing board with ApplySpec, so each SIG or release board comes out the
+// same. For example:
+//
+//	title: SIG Auth {{.Milestone}}
+//	visibility: private
+//	description: SIG Auth's work for the release
+//	readme: |
+//	  Generated by kube-board; edits to fields and views are overwritten.
+//	fields:
+//	  - name: Priority
+//	    type: single-select
+//	    options: [P0, P1, P2]
+//	  - name: Notes
+//	    type: text
+//	views:
+//	  - name: Kanban
+//	    layout: board
+//	    columnsBy: [Status]
+//	  - name: Needs PRR
+//	    filter: prr:missing
+//	    columns: [Title, Status, PRR]
+//	    sortBy: [Priority desc]
+type Spec struct {
+	Title       string      `yaml:"title"`       // board title, if not given elsewhere
+	Visibility  string      `yaml:"visibility"`  // private (default) or public
+	Description string      `yaml:"description"` // short description
+	Readme      string      `yaml:"readme"`      // README, in Markdown
+	Fields      []SpecField `yaml:"fields"`
+	Views       []SpecView  `yaml:"views"`
+}
+
+// SpecField is a field a Spec board has.
+type SpecField struct {
+	Name    string   `yaml:"name"`
+	Type    string   `yaml:"type"`    // text (default), number, date, or single-select
+	Options []string `yaml:"options"` // single-select options, in order
+}
+
+// SpecView is a view (tab) a Spec board has.
+type SpecView struct {
+	Name      string   `yaml:"name"`
+	Layout    string   `yaml:"layout"`    // table (default), board, or roadmap
+	Filter    string   `yaml:"filter"`    // e.g. "assignee:@me is:open"
+	Columns   []string `yaml:"columns"`   // visible fields, in order
+	SortBy    []string `yaml:"sortBy"`    // "Field" or "Field desc", most significant first
+	GroupBy   []string `yaml:"groupBy"`   // a table's row groups or a board's swimlanes
+	ColumnsBy []string `yaml:"columnsBy"` // a board layout's columns, e.g. Status
+}
+
+// LoadSpec reads and checks a board template file.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := spec.check(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// check reports the first problem with s, if any.
+func (s *Spec) check() error {
+	if v := s.Visibility; v != "" && v != Private && v != Public {
+		return fmt.Errorf("visibility must be private or public, got %q", v)
+	}
+	seen := make(map[string]bool)
+	for _, f := range s.FieldSpecs() {
+		switch {
+		case f.Name == "":
+			return fmt.Errorf("a field has no name")
+		case seen[f.Name]:
+			return fmt.Errorf("field %q is listed twice", f.Name)
+		case !slices.Contains([]string{"TEXT", "NUMBER", "DATE", "SINGLE_SELECT"}, f.Type):
+			return fmt.Errorf("field %q: unknown type %q (want text, number, date, or single-select)", f.Name, f.Type)
+		case f.Type == "SINGLE_SELECT" && len(f.Options) == 0:
+			return fmt.Errorf("field %q: a single-select field needs options", f.Name)
+		}
+		seen[f.Name] = true
+	}
+	clear(seen)
+	for _, v := range s.ViewConfigs() {
+		switch {
+		case v.Name == "":
+			return fmt.Errorf("a view has no name")
+		case seen[v.Name]:
+			return fmt.Errorf("view %q is listed twice", v.Name)
+		}
+		if _, ok := restLayouts[viewLayout(v.Layout)]; !ok {
+			return fmt.Errorf("view %q: unknown layout %q (want table, board, or roadmap)", v.Name, v.Layout)
+		}
+		for _, key := range v.SortBy {
+			if key.Direction != "ASC" && key.Direction != "DESC" {
+				return fmt.Errorf("view %q: sort direction %q for %s (want asc or desc)", v.Name, key.Direction, key.Field)
+			}
+		}
+		seen[v.Name] = true
+	}
+	return nil
+}
+
+// FieldSpecs returns s's fields as EnsureFields takes them.
+func (s *Spec) FieldSpecs() []FieldSpec {
+	specs := make([]FieldSpec, len(s.Fields))
+	for i, f := range s.Fields {
+		typ := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(f.Type), "-", "_"))
+		if typ == "" {
+			typ = "TEXT"
+		}
+		specs[i] = FieldSpec{Name: f.Name, Type: typ, Options: f.Options}
+	}
+	return specs
+}
+
+// ViewConfigs returns s's views as EnsureViews takes them.
+func (s *Spec) ViewConfigs() []ViewConfig {
+	configs := make([]ViewConfig, len(s.Views))
+	for i, v := range s.Views {
+		c := ViewConfig{
+			Name:            v.Name,
+			FieldNames:      v.Columns,
+			Layout:          viewLayout(v.Layout),
+			Filter:          v.Filter,
+			GroupBy:         v.GroupBy,
+			VerticalGroupBy: v.ColumnsBy,
+		}
+		for _, key := range v.SortBy {
+			field, direction := key, "ASC"
+			if i := strings.LastIndexByte(key, ' '); i >= 0 {
+				if d := strings.ToUpper(key[i+1:]); d == "ASC" || d == "DESC" {
+					field, direction = key[:i], d
+				}
+			}
+			c.SortBy = append(c.SortBy, ViewSort{Field: strings.TrimSpace(field), Direction: direction})
+		}
+		configs[i] = c
+	}
+	return configs
+}
+
+// ApplySpec reconciles the board titled title under owner with spec,
+// creating it if it doesn't exist: it sets the board's visibility,
+// description, and README, creates missing fields and adds missing
+// single-select options, creates missing views, and brings the filter,
+// columns, sort, and grouping of existing views of the same name into line. Nothing the spec doesn't mention is
+// removed, and an existing view's layout is left as it is.
+func ApplySpec(gql *ghgql.Client, owner, title string, spec Spec) (*Info, error) {
+	project, err := FindProject(gql, owner, title)
+	if err != nil {
+		return nil, fmt.Errorf("finding project: %w", err)
+	}
+	if project == nil {
+		slog.Info("Project not found, creating it", "board", title)
+		if project, err = CreateProject(gql, owner, title); err != nil {
+			return nil, fmt.Errorf("creating project: %w", err)
+		}
+		slog.Info("Created project", "url", project.URL)
+	} else {
+		slog.Info("Found existing project", "url", project.URL)
+	}
+
+	if err := SetVisibility(gql, project, spec.Visibility == Public); err != nil {
+		return project, fmt.Errorf("setting visibility: %w", err)
+	}
+	if err := SetProjectDetails(gql, project.ID, spec.Description, spec.Readme); err != nil {
+		return project, err
+	}
+
+	fields, err := GetProjectFields(gql, project.ID)
+	if err != nil {
+		return project, fmt.Errorf("listing fields: %w", err)
+	}
+	if len(spec.Fields) > 0 {
+		slog.Info("Ensuring fields", "count", len(spec.Fields))
+		fields = EnsureFields(gql, project.ID, spec.FieldSpecs(), fields)
+		for _, want := range spec.FieldSpecs() {
+			if f, ok := fields[want.Name]; ok && f.Type != "" && f.Type != want.Type {
+				slog.Warn("Field has the wrong type on the board — change it by hand", "field", want.Name, "type", f.Type, "want", want.Type)
+			}
+		}
+	}
+
+	views := spec.ViewConfigs()
+	if len(views) == 0 {
+		return project, nil
+	}
+	slog.Info("Ensuring views", "count", len(views))
+	existing, err := ListViews(gql, project.ID)
+	if err != nil {
+		return project, fmt.Errorf("listing views: %w", err)
+	}
+	for _, have := range existing {
+		i := slices.IndexFunc(views, func(v ViewConfig) bool { return v.Name == have.Name })
+		if i < 0 {
+			continue
+		}
+		want := views[i]
+		if want.Layout != "" && want.Layout != have.Layout {
+			slog.Warn("View has the wrong layout — the API can't change a view's layout", "view", have.Name, "layout", have.Layout, "want", want.Layout)
+		}
+		if viewMatches(have, want) {
+			continue
+		}
+		slog.Info("Updating view", "view", have.Name)
+		if err := configureView(gql, have.ID, fields, want, len(want.FieldNames) > 0); err != nil {
+			slog.Warn("Could not update view", "view", have.Name, "err", err)
+		}
+	}
+	EnsureViews(gql, owner, project, views)
+	return project, nil
+}
+
+// viewMatches reports whether have already has want's filter, columns,
+// sort, and grouping; settings want leaves empty match anything.
+func viewMatches(have ViewDef, want ViewConfig) bool {
+	matches := func(have, want []string) bool {
+		return len(want) == 0 || slices.Equal(have, want)
+	}
+	return (want.Filter == "" || have.Filter == want.Filter) &&
+		matches(have.VisibleFields, want.FieldNames) &&
+		matches(have.GroupBy, want.GroupBy) &&
+		matches(have.VerticalGroupBy, want.VerticalGroupBy) &&
+		(len(want.SortBy) == 0 || slices.Equal(have.SortBy, want.SortBy))
+}
@@ -0,0 +1,122 @@
+package board
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRemoveStaleItems(t *testing.T) {
+	// The board holds one item of each kind; current lists what the query
+	// still returns.
+	const boardItems = `{"data":{"node":{"items":{"nodes":[
+		{"id":"PVTI_cur","isArchived":false,"content":{"__typename":"Issue","id":"I_cur","title":"Current"}},
+		{"id":"PVTI_stale","isArchived":false,"content":{"__typename":"Issue","id":"I_stale","title":"Stale"}},
+		{"id":"PVTI_archived","isArchived":true,"content":{"__typename":"Issue","id":"I_archived","title":"Stale, archived"}},
+		{"id":"PVTI_hand","isArchived":false,"content":{"__typename":"DraftIssue","id":"DI_hand","title":"Added by hand"}},
+		{"id":"PVTI_copy","isArchived":false,"sourceId":{"text":"DI_gone"},"content":{"__typename":"DraftIssue","id":"DI_copy","title":"Copied draft, gone"}},
+		{"id":"PVTI_copycur","isArchived":false,"sourceId":{"text":"DI_src"},"content":{"__typename":"DraftIssue","id":"DI_copycur","title":"Copied draft, current"}},
+		{"id":"PVTI_done","isArchived":false,"status":{"name":"Done"},"content":{"__typename":"PullRequest","id":"PR_done","title":"Stale, done"}},
+		{"id":"PVTI_back","isArchived":true,"content":{"__typename":"Issue","id":"I_back","title":"Back in the query"}}
+	],"pageInfo":{"hasNextPage":false}}}}}`
+	current := []Item{
+		{NodeID: "I_cur", Type: "Issue"},
+		{NodeID: "DI_src", Type: "DraftIssue"},
+		{NodeID: "I_back", Type: "Issue"},
+	}
+
+	tests := []struct {
+		action      string
+		wantSent    []string // mutation field and item ID, in order
+		wantRemoved []string
+	}{
+		{
+			action: StaleDelete,
+			wantSent: []string{
+				"deleteProjectV2Item PVTI_stale",
+				"deleteProjectV2Item PVTI_archived",
+				"deleteProjectV2Item PVTI_copy",
+				"deleteProjectV2Item PVTI_done",
+			},
+			wantRemoved: []string{"Stale", "Stale, archived", "Copied draft, gone", "Stale, done"},
+		},
+		{
+			action: StaleArchive,
+			wantSent: []string{
+				"archiveProjectV2Item PVTI_stale",
+				"archiveProjectV2Item PVTI_copy",
+				"archiveProjectV2Item PVTI_done",
+				"unarchiveProjectV2Item PVTI_back",
+			},
+			wantRemoved: []string{"Stale", "Copied draft, gone", "Stale, done"},
+		},
+		{
+			action: "status: done",
+			wantSent: []string{
+				"updateProjectV2ItemFieldValue PVTI_stale",
+				"updateProjectV2ItemFieldValue PVTI_archived",
+				"updateProjectV2ItemFieldValue PVTI_copy",
+			},
+			wantRemoved: []string{"Stale", "Stale, archived", "Copied draft, gone"},
+		},
+	}
+	alias := regexp.MustCompile(`(m\d+): (\w+)\(input: \$(i\d+)\)`)
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			var sent []string
+			gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query     string         `json:"query"`
+					Variables map[string]any `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				if !strings.HasPrefix(strings.TrimSpace(req.Query), "mutation") {
+					switch {
+					case strings.Contains(req.Query, "items(first: 100"):
+						w.Write([]byte(boardItems))
+					case strings.Contains(req.Query, "fields(first: 50)"):
+						w.Write([]byte(`{"data":{"node":{"fields":{"nodes":[
+							{"id":"PVTF_status","name":"Status","dataType":"SINGLE_SELECT","options":[{"id":"opt_todo","name":"Todo"},{"id":"opt_done","name":"Done"}]}
+						]}}}}`))
+					default:
+						t.Fatalf("unexpected query: %s", req.Query)
+					}
+					return
+				}
+				data := map[string]any{}
+				for _, m := range alias.FindAllStringSubmatch(req.Query, -1) {
+					input, _ := req.Variables[m[3]].(map[string]any)
+					if m[2] == "updateProjectV2ItemFieldValue" {
+						value, _ := input["value"].(map[string]any)
+						if input["fieldId"] != "PVTF_status" || value["singleSelectOptionId"] != "opt_done" {
+							t.Errorf("%s input = %v, want Status set to Done", m[1], input)
+						}
+					}
+					sent = append(sent, fmt.Sprintf("%s %s", m[2], input["itemId"]))
+					data[m[1]] = map[string]any{"item": map[string]any{"id": input["itemId"]}}
+				}
+				json.NewEncoder(w).Encode(map[string]any{"data": data})
+			})
+
+			removed, deferred, failed, err := removeStaleItems(gql, "PVT_1", current, tt.action, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(sent, tt.wantSent) {
+				t.Errorf("mutated %q, want %q", sent, tt.wantSent)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed = %q, want %q", removed, tt.wantRemoved)
+			}
+			if len(deferred) != 0 || len(failed) != 0 {
+				t.Errorf("deferred = %+v, failed = %+v, want none", deferred, failed)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package board
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// ---------- Project Status Updates ----------
+
+// Project status update states, for CreateStatusUpdate.
+const (
+	StatusOnTrack  = "ON_TRACK"
+	StatusAtRisk   = "AT_RISK"
+	StatusOffTrack = "OFF_TRACK"
+	StatusComplete = "COMPLETE"
+	StatusInactive = "INACTIVE"
+)
+
+// CreateStatusUpdate posts a status update (the dated notes shown in a
+// project's side panel) with a Markdown body. status is one of the Status*
+// constants, or "" to leave the update without one.
+func CreateStatusUpdate(gql *ghgql.Client, projectID, body, status string) error {
+	mutation := `mutation($projectId: ID!, $body: String!, $status: ProjectV2StatusUpdateStatus) {
+		createProjectV2StatusUpdate(input: {projectId: $projectId, body: $body, status: $status}) {
+			statusUpdate { id }
+		}
+	}`
+
+	vars := map[string]any{"projectId": projectID, "body": body}
+	if status != "" {
+		vars["status"] = status
+	}
+	var result json.RawMessage
+	return gql.Do(ghgql.Request{Query: mutation, Variables: vars}, &result)
+}
+
+// StatusUpdate is a status update posted on a project.
+type StatusUpdate struct {
+	ID        string
+	Body      string
+	Status    string // a Status* constant, or ""
+	Creator   string // login
+	CreatedAt time.Time
+}
+
+// ListStatusUpdates returns a project's n most recent status updates,
+// newest first.
+func ListStatusUpdates(gql *ghgql.Client, projectID string, n int) ([]StatusUpdate, error) {
+	query := `query($projectId: ID!, $n: Int!) {
+		node(id: $projectId) {
+			... on ProjectV2 {
+				statusUpdates(first: $n, orderBy: {field: CREATED_AT, direction: DESC}) {
+					nodes { id body status createdAt creator { login } }
+				}
+			}
+		}
+	}`
+
+	var result struct {
+		Node struct {
+			StatusUpdates struct {
+				Nodes []struct {
+					ID        string    `json:"id"`
+					Body      string    `json:"body"`
+					Status    string    `json:"status"`
+					CreatedAt time.Time `json:"createdAt"`
+					Creator   *struct {
+						Login string `json:"login"`
+					} `json:"creator"`
+				} `json:"nodes"`
+			} `json:"statusUpdates"`
+		} `json:"node"`
+	}
+	err := gql.Do(ghgql.Request{Query: query, Variables: map[string]any{"projectId": projectID, "n": n}}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]StatusUpdate, 0, len(result.Node.StatusUpdates.Nodes))
+	for _, u := range result.Node.StatusUpdates.Nodes {
+		su := StatusUpdate{ID: u.ID, Body: u.Body, Status: u.Status, CreatedAt: u.CreatedAt}
+		if u.Creator != nil {
+			su.Creator = u.Creator.Login
+		}
+		updates = append(updates, su)
+	}
+	return updates, nil
+}
@@ -1,8 +1,10 @@
 package board
 
 import (
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
 	"strings"
 
 	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
@@ -13,14 +15,57 @@ type ViewDef struct {
 	ID     string
 	Name   string
 	Number int
-	Layout string // TABLE, BOARD, ROADMAP
+	Layout string // TABLE_LAYOUT, BOARD_LAYOUT, ROADMAP_LAYOUT
 	Filter string
+
+	VisibleFields   []string   // visible columns, in order
+	SortBy          []ViewSort // sort order, most significant first
+	GroupBy         []string   // fields the table or roadmap is grouped by
+	VerticalGroupBy []string   // fields a board layout's columns come from
+}
+
+// ViewSort is one sort key of a view.
+type ViewSort struct {
+	Field     string
+	Direction string // ASC (default) or DESC
 }
 
 // ViewConfig describes a desired view on the destination board.
 type ViewConfig struct {
 	Name       string   // View/tab name
-	FieldNames []string // Field names that should be visible as columns (empty = no change)
+	FieldNames []string // Field names that should be visible as columns, in order (empty = no change)
+	Layout     string   // TABLE_LAYOUT (default), BOARD_LAYOUT, or ROADMAP_LAYOUT; TABLE, BOARD, and ROADMAP also work
+	Filter     string   // e.g. "prr:missing" or "assignee:@me is:open"
+
+	// GroupBy groups a table or roadmap's rows (a board's swimlanes), and
+	// VerticalGroupBy picks a board's columns, e.g. Status, GitHub's own
+	// default. SortBy orders items, most significant key first, e.g.
+	// Priority DESC. Layout, Filter, and FieldNames are sent when the view
+	// is created; GroupBy, VerticalGroupBy, SortBy, and the order of
+	// FieldNames, which the REST create doesn't keep, are set with
+	// updateProjectV2View right after. If that fails, the view is listed
+	// for a one-time manual touch-up.
+	GroupBy         []string
+	VerticalGroupBy []string
+	SortBy          []ViewSort
+}
+
+// ViewConfigs converts views read from a template project into the configs
+// EnsureViews recreates them from.
+func ViewConfigs(views []ViewDef) []ViewConfig {
+	configs := make([]ViewConfig, len(views))
+	for i, v := range views {
+		configs[i] = ViewConfig{
+			Name:            v.Name,
+			FieldNames:      v.VisibleFields,
+			Layout:          v.Layout,
+			Filter:          v.Filter,
+			SortBy:          v.SortBy,
+			GroupBy:         v.GroupBy,
+			VerticalGroupBy: v.VerticalGroupBy,
+		}
+	}
+	return configs
 }
 
 // ---------- List Views (GraphQL — reliable for reads) ----------
@@ -37,6 +82,21 @@ func ListViews(gql *ghgql.Client, projectID string) ([]ViewDef, error) {
 						number
 						layout
 						filter
+						fields(first: 50) {
+							nodes { ... on ProjectV2FieldCommon { name } }
+						}
+						sortByFields(first: 10) {
+							nodes {
+								direction
+								field { ... on ProjectV2FieldCommon { name } }
+							}
+						}
+						groupByFields(first: 5) {
+							nodes { ... on ProjectV2FieldCommon { name } }
+						}
+						verticalGroupByFields(first: 5) {
+							nodes { ... on ProjectV2FieldCommon { name } }
+						}
 					}
 					pageInfo { hasNextPage endCursor }
 				}
@@ -45,55 +105,72 @@ func ListViews(gql *ghgql.Client, projectID string) ([]ViewDef, error) {
 	}`
 
 	var views []ViewDef
-	var cursor *string
-
-	for {
-		vars := map[string]any{"projectId": projectID}
-		if cursor != nil {
-			vars["cursor"] = *cursor
-		}
-
-		var result struct {
-			Node struct {
-				Views struct {
-					Nodes []struct {
-						ID     string `json:"id"`
-						Name   string `json:"name"`
-						Number int    `json:"number"`
-						Layout string `json:"layout"`
-						Filter string `json:"filter"`
-					} `json:"nodes"`
-					PageInfo struct {
-						HasNextPage bool   `json:"hasNextPage"`
-						EndCursor   string `json:"endCursor"`
-					} `json:"pageInfo"`
-				} `json:"views"`
-			} `json:"node"`
+	type page struct {
+		Node struct {
+			Views struct {
+				Nodes []struct {
+					ID           string        `json:"id"`
+					Name         string        `json:"name"`
+					Number       int           `json:"number"`
+					Layout       string        `json:"layout"`
+					Filter       string        `json:"filter"`
+					Fields       viewFieldList `json:"fields"`
+					SortByFields struct {
+						Nodes []struct {
+							Direction string `json:"direction"`
+							Field     struct {
+								Name string `json:"name"`
+							} `json:"field"`
+						} `json:"nodes"`
+					} `json:"sortByFields"`
+					GroupByFields         viewFieldList `json:"groupByFields"`
+					VerticalGroupByFields viewFieldList `json:"verticalGroupByFields"`
+				} `json:"nodes"`
+				PageInfo ghgql.PageInfo `json:"pageInfo"`
+			} `json:"views"`
+		} `json:"node"`
+	}
+	err := ghgql.Paginate(gql, ghgql.Request{Query: query, Variables: map[string]any{"projectId": projectID}}, func(result *page) (ghgql.PageInfo, error) {
+		for _, v := range result.Node.Views.Nodes {
+			def := ViewDef{
+				ID:              v.ID,
+				Name:            v.Name,
+				Number:          v.Number,
+				Layout:          v.Layout,
+				Filter:          v.Filter,
+				VisibleFields:   v.Fields.names(),
+				GroupBy:         v.GroupByFields.names(),
+				VerticalGroupBy: v.VerticalGroupByFields.names(),
+			}
+			for _, sf := range v.SortByFields.Nodes {
+				def.SortBy = append(def.SortBy, ViewSort{Field: sf.Field.Name, Direction: sf.Direction})
+			}
+			views = append(views, def)
 		}
+		return result.Node.Views.PageInfo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		err := gql.Do(ghgql.Request{Query: query, Variables: vars}, &result)
-		if err != nil {
-			return nil, err
-		}
+	return views, nil
+}
 
-		for _, v := range result.Node.Views.Nodes {
-			views = append(views, ViewDef{
-				ID:     v.ID,
-				Name:   v.Name,
-				Number: v.Number,
-				Layout: v.Layout,
-				Filter: v.Filter,
-			})
-		}
+// viewFieldList is a connection of fields as selected on a view.
+type viewFieldList struct {
+	Nodes []struct {
+		Name string `json:"name"`
+	} `json:"nodes"`
+}
 
-		if !result.Node.Views.PageInfo.HasNextPage {
-			break
+func (l viewFieldList) names() []string {
+	var names []string
+	for _, n := range l.Nodes {
+		if n.Name != "" {
+			names = append(names, n.Name)
 		}
-		c := result.Node.Views.PageInfo.EndCursor
-		cursor = &c
 	}
-
-	return views, nil
+	return names
 }
 
 // ---------- REST API Types ----------
@@ -137,17 +214,43 @@ func listFieldsREST(gql *ghgql.Client, ownerType, owner string, projectNum int)
 	return fields, err
 }
 
-// createViewREST creates a new table view via the REST API.
+// restLayouts maps GraphQL view layouts to the REST API's names.
+var restLayouts = map[string]string{
+	"":               "table",
+	"TABLE_LAYOUT":   "table",
+	"BOARD_LAYOUT":   "board",
+	"ROADMAP_LAYOUT": "roadmap",
+}
+
+// viewLayout returns layout as a GraphQL view layout, so "board" or
+// "BOARD" name BOARD_LAYOUT. "" stays "" (a table).
+func viewLayout(layout string) string {
+	layout = strings.ToUpper(strings.TrimSpace(layout))
+	if layout != "" && !strings.HasSuffix(layout, "_LAYOUT") {
+		layout += "_LAYOUT"
+	}
+	return layout
+}
+
+// createViewREST creates a new view via the REST API.
 // The REST API for project views only supports POST (create). There are no
 // GET (list) or PATCH (update) endpoints — those return 404.
-// visible_fields must be set at creation time as an array of integer field IDs.
-func createViewREST(gql *ghgql.Client, ownerType, owner string, projectNum int, name string, fieldIntIDs []int) (*restView, error) {
+// The layout, filter, and visible_fields (an array of integer field IDs)
+// are set in the POST body.
+func createViewREST(gql *ghgql.Client, ownerType, owner string, projectNum int, want ViewConfig, fieldIntIDs []int) (*restView, error) {
+	layout, ok := restLayouts[viewLayout(want.Layout)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported view layout %q", want.Layout)
+	}
 	path := fmt.Sprintf("/%s/%s/projectsV2/%d/views", ownerType, owner, projectNum)
 	body := map[string]any{
-		"name":   name,
-		"layout": "table",
+		"name":   want.Name,
+		"layout": layout,
 	}
-	if len(fieldIntIDs) > 0 {
+	if want.Filter != "" {
+		body["filter"] = want.Filter
+	}
+	if len(fieldIntIDs) > 0 && layout != "board" { // board columns come from the grouping field
 		body["visible_fields"] = fieldIntIDs
 	}
 	var view restView
@@ -160,14 +263,15 @@ func createViewREST(gql *ghgql.Client, ownerType, owner string, projectNum int,
 
 // ---------- Ensure Views ----------
 
-// EnsureViews creates any missing views and sets visible columns on each.
+// EnsureViews creates any missing views and sets the layout, filter,
+// visible columns, sort, and grouping of each.
 //
-// Listing uses GraphQL (reliable for reads). Creating uses the REST API,
-// which is the only API that supports view creation — GraphQL has no mutation
-// for views. Note: The REST views API only has a POST (create) endpoint;
-// there are no GET (list) or PATCH (update) endpoints.
-// visible_fields are set at view creation time in the POST body.
-// For views that already exist, columns cannot be updated via API.
+// Views are listed with GraphQL and created with the REST API: GraphQL can
+// update a view but has no mutation that creates one, and the REST views
+// API has a POST (create) endpoint but no GET (list) or PATCH (update).
+// The layout, filter, and visible columns go in the POST body; the sort,
+// grouping, and column order, which REST can't set, follow with GraphQL's
+// updateProjectV2View. Views that already exist are left as they are.
 func EnsureViews(gql *ghgql.Client, owner string, project *Info, desired []ViewConfig) {
 	if len(desired) == 0 {
 		return
@@ -178,7 +282,7 @@ func EnsureViews(gql *ghgql.Client, owner string, project *Info, desired []ViewC
 	// Always list views via GraphQL — the REST API has no list endpoint.
 	gqlViews, err := ListViews(gql, project.ID)
 	if err != nil {
-		log.Printf("Warning: could not list project views via GraphQL: %v", err)
+		slog.Warn("Could not list project views via GraphQL", "err", err)
 		return
 	}
 
@@ -193,16 +297,19 @@ func EnsureViews(gql *ghgql.Client, owner string, project *Info, desired []ViewC
 		viewsByName[v.Name] = viewInfo{NodeID: v.ID, Name: v.Name, Number: v.Number}
 	}
 
-	// Collect views that need manual creation (when REST create fails)
-	var manualViews []ViewConfig
+	// Collect views that need manual creation (when REST create fails), and
+	// created views whose sort, grouping, or column order must be set by hand.
+	var manualViews, touchUps []ViewConfig
 	restCreateWorks := true
 
 	// Lazily populated: maps field name → REST integer ID for visible_fields.
 	var restFieldsByName map[string]int
+	// Lazily populated: the board's fields, whose node IDs set grouping.
+	var fields FieldMap
 
 	for _, want := range desired {
 		if _, exists := viewsByName[want.Name]; exists {
-			log.Printf("  View %q already exists", want.Name)
+			slog.Debug("View already exists", "view", want.Name)
 			continue
 		}
 
@@ -217,7 +324,7 @@ func EnsureViews(gql *ghgql.Client, owner string, project *Info, desired []ViewC
 			if restFieldsByName == nil {
 				rfList, rfErr := listFieldsREST(gql, ownerType, owner, project.Number)
 				if rfErr != nil {
-					log.Printf("    Warning: could not list fields via REST for visible_fields: %v", rfErr)
+					slog.Warn("Could not list fields via REST for visible_fields", "err", rfErr)
 				} else {
 					restFieldsByName = make(map[string]int, len(rfList))
 					for _, rf := range rfList {
@@ -230,78 +337,184 @@ func EnsureViews(gql *ghgql.Client, owner string, project *Info, desired []ViewC
 			}
 		}
 
-		log.Printf("  Creating view %q via REST API...", want.Name)
-		created, createErr := createViewREST(gql, ownerType, owner, project.Number, want.Name, fieldIDs)
+		slog.Debug("Creating view via REST API", "view", want.Name)
+		created, createErr := createViewREST(gql, ownerType, owner, project.Number, want, fieldIDs)
 		if createErr != nil {
-			log.Printf("  REST create failed for %q: %v", want.Name, createErr)
+			slog.Error("Could not create view via REST API", "view", want.Name, "err", createErr)
 			restCreateWorks = false
 			manualViews = append(manualViews, want)
 			continue
 		}
-		log.Printf("  Created view %q (number: %d)", want.Name, created.Number)
-		if len(fieldIDs) > 0 {
-			log.Printf("    Set %d visible column(s): %v", len(fieldIDs), want.FieldNames)
+		slog.Info("Created view", "view", want.Name, "number", created.Number, "columns", len(fieldIDs))
+		if len(want.GroupBy) > 0 || len(want.VerticalGroupBy) > 0 || len(want.SortBy) > 0 || len(fieldIDs) > 1 {
+			if fields == nil {
+				if fields, err = GetProjectFields(gql, project.ID); err != nil {
+					slog.Warn("Could not list fields to set sort, grouping, or column order", "err", err)
+				}
+			}
+			rest := want
+			rest.Filter = "" // set on create
+			if err := configureView(gql, created.NodeID, fields, rest, len(fieldIDs) > 1); err != nil {
+				slog.Warn("Could not finish setting up view", "view", want.Name, "err", err)
+				touchUps = append(touchUps, want)
+			}
 		}
 	}
 
+	for _, v := range touchUps {
+		slog.Warn("View created without its sort, grouping, or column order (updateProjectV2View failed) — set them in the board UI", "view", v.Name, "arrangement", strings.TrimSpace(viewArrangement(v)))
+	}
+
 	// Print manual-creation summary if REST failed
 	if len(manualViews) > 0 {
-		log.Println()
-		log.Printf("╔══════════════════════════════════════════════════════════════════╗")
-		log.Printf("║  MANUAL ACTION REQUIRED: %d view(s) could not be created        ║", len(manualViews))
-		log.Printf("╟──────────────────────────────────────────────────────────────────╢")
-		log.Printf("║  The REST API returned an error for this org, and GitHub's      ║")
-		log.Printf("║  GraphQL API has no mutation for creating project views.         ║")
-		log.Printf("║                                                                  ║")
-		log.Printf("║  Please create these views manually in the board UI:             ║")
-		log.Printf("║  %s", project.URL)
-		log.Printf("║                                                                  ║")
-		for i, v := range manualViews {
-			log.Printf("║  %2d. %s", i+1, v.Name)
+		slog.Warn("MANUAL ACTION REQUIRED: views could not be created — the REST API returned an error for this org, and GraphQL has no mutation that creates a view; create them in the board UI, then re-run to verify they are detected",
+			"count", len(manualViews), "url", project.URL)
+		for _, v := range manualViews {
+			attrs := []any{"view", v.Name}
 			if len(v.FieldNames) > 0 {
-				log.Printf("║      columns: %s", strings.Join(v.FieldNames, ", "))
+				attrs = append(attrs, "columns", strings.Join(v.FieldNames, ", "))
 			}
+			if a := viewArrangement(v); a != "" {
+				attrs = append(attrs, "arrangement", strings.TrimSpace(a))
+			}
+			slog.Warn("Create this view by hand", attrs...)
 		}
-		log.Printf("║                                                                  ║")
-		log.Printf("║  Once created, re-run to verify they are detected.               ║")
-		log.Printf("╚══════════════════════════════════════════════════════════════════╝")
-		log.Println()
 	}
 }
 
+// viewArrangement describes a view's layout, filter, sort, and grouping for
+// the manual-setup notices, e.g. " layout: BOARD_LAYOUT; columns by: Status".
+func viewArrangement(v ViewConfig) string {
+	var parts []string
+	if v.Layout != "" {
+		parts = append(parts, "layout: "+viewLayout(v.Layout))
+	}
+	if v.Filter != "" {
+		parts = append(parts, "filter: "+v.Filter)
+	}
+	if len(v.SortBy) > 0 {
+		parts = append(parts, "sort by: "+sortKeys(v.SortBy))
+	}
+	if len(v.GroupBy) > 0 {
+		parts = append(parts, "group by: "+strings.Join(v.GroupBy, ", "))
+	}
+	if len(v.VerticalGroupBy) > 0 {
+		parts = append(parts, "columns by: "+strings.Join(v.VerticalGroupBy, ", "))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, "; ")
+}
+
+// sortKeys describes sort keys for logs, e.g. "Priority desc, Title asc".
+func sortKeys(sortBy []ViewSort) string {
+	keys := make([]string, len(sortBy))
+	for i, s := range sortBy {
+		keys[i] = strings.TrimSpace(s.Field + " " + strings.ToLower(s.Direction))
+	}
+	return strings.Join(keys, ", ")
+}
+
 // ---------- Update View Filter ----------
 
 // UpdateViewFilter sets the filter string on an existing project view.
 // Uses the GraphQL updateProjectV2View mutation.
 func UpdateViewFilter(gql *ghgql.Client, viewID, filter string) error {
-	mutation := `mutation($viewId: ID!, $filter: String) {
-		updateProjectV2View(input: {viewId: $viewId, filter: $filter}) {
-			projectV2View { id filter }
+	if err := updateView(gql, viewID, map[string]any{"filter": filter}); err != nil {
+		return fmt.Errorf("failed to update view filter: %w", err)
+	}
+	return nil
+}
+
+// updateView sends updateProjectV2View for the view viewID with input's
+// settings (UpdateProjectV2ViewInput fields besides viewId).
+func updateView(gql *ghgql.Client, viewID string, input map[string]any) error {
+	mutation := `mutation($input: UpdateProjectV2ViewInput!) {
+		updateProjectV2View(input: $input) {
+			projectV2View { id }
 		}
 	}`
+	vars := map[string]any{"viewId": viewID}
+	maps.Copy(vars, input)
+	var result json.RawMessage
+	return gql.Do(ghgql.Request{Query: mutation, Variables: map[string]any{"input": vars}}, &result)
+}
 
-	var result struct {
-		UpdateProjectV2View struct {
-			ProjectV2View struct {
-				ID     string `json:"id"`
-				Filter string `json:"filter"`
-			} `json:"projectV2View"`
-		} `json:"updateProjectV2View"`
-	}
-
-	err := gql.Do(ghgql.Request{
-		Query: mutation,
-		Variables: map[string]any{
-			"viewId": viewID,
-			"filter": filter,
-		},
-	}, &result)
+// ---------- Configure Created Views ----------
+
+// configureView sets want's Filter, SortBy, GroupBy, and VerticalGroupBy
+// on the view viewID in one updateProjectV2View, resolving
+// field names against fields (the board's); with order, it also puts the
+// visible columns in FieldNames' order, which the REST create doesn't keep.
+// An unknown grouping or sort field is an error, since a board grouped by
+// the wrong field is worse than one left for a touch-up; unknown columns
+// are skipped, as on create.
+func configureView(gql *ghgql.Client, viewID string, fields FieldMap, want ViewConfig, order bool) error {
+	groupBy, err := resolveFieldNodeIDs(want.GroupBy, fields)
 	if err != nil {
-		return fmt.Errorf("failed to update view filter: %w", err)
+		return err
 	}
+	verticalGroupBy, err := resolveFieldNodeIDs(want.VerticalGroupBy, fields)
+	if err != nil {
+		return err
+	}
+	var sortBy []map[string]any
+	for _, s := range want.SortBy {
+		ids, err := resolveFieldNodeIDs([]string{s.Field}, fields)
+		if err != nil {
+			return err
+		}
+		direction := strings.ToUpper(s.Direction)
+		if direction == "" {
+			direction = "ASC"
+		} else if direction != "ASC" && direction != "DESC" {
+			return fmt.Errorf("sort direction %q for %s (want ASC or DESC)", s.Direction, s.Field)
+		}
+		sortBy = append(sortBy, map[string]any{"fieldId": ids[0], "direction": direction})
+	}
+	input := make(map[string]any)
+	if want.Filter != "" {
+		input["filter"] = want.Filter
+	}
+	if len(sortBy) > 0 {
+		input["sortByFields"] = sortBy
+	}
+	if order {
+		var visible []string
+		for _, name := range want.FieldNames {
+			if f, ok := fields[name]; ok {
+				visible = append(visible, f.ID)
+			}
+		}
+		input["visibleFieldIds"] = visible
+	}
+	if len(groupBy) > 0 {
+		input["groupByFieldIds"] = groupBy
+	}
+	if len(verticalGroupBy) > 0 {
+		input["verticalGroupByFieldIds"] = verticalGroupBy
+	}
+	if err := updateView(gql, viewID, input); err != nil {
+		return err
+	}
+	slog.Debug("Configured view", "view", want.Name, "arrangement", strings.TrimSpace(viewArrangement(want)))
 	return nil
 }
 
+// resolveFieldNodeIDs maps field names to their GraphQL node IDs in fields.
+func resolveFieldNodeIDs(names []string, fields FieldMap) ([]string, error) {
+	var ids []string
+	for _, name := range names {
+		f, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("field %q not found on board", name)
+		}
+		ids = append(ids, f.ID)
+	}
+	return ids, nil
+}
+
 // resolveFieldIntIDs maps field names to REST integer field IDs.
 func resolveFieldIntIDs(names []string, fieldsByName map[string]int) []int {
 	var ids []int
@@ -309,7 +522,7 @@ func resolveFieldIntIDs(names []string, fieldsByName map[string]int) []int {
 		if id, ok := fieldsByName[name]; ok {
 			ids = append(ids, id)
 		} else {
-			log.Printf("    Field %q not found on board, skipping column", name)
+			slog.Warn("Field not found on board, skipping column", "field", name)
 		}
 	}
 	return ids
@@ -0,0 +1,121 @@
+package board
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// roundTripFunc lets a plain function serve a client's requests.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// newFakeClient returns a client whose requests are answered by handler,
+// without pacing or retries.
+func newFakeClient(t *testing.T, handler http.HandlerFunc) *ghgql.Client {
+	t.Helper()
+	c := ghgql.NewClientWithTransport("test-token", roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	}))
+	c.MinDelay = 0
+	c.MaxRetries = 0
+	c.RetryBackoff = 0
+	return c
+}
+
+func TestEnsureViewsSendsFilterOnCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		view       ViewConfig
+		wantUpdate map[string]any // the updateProjectV2View input; nil if none is sent
+	}{
+		{
+			name: "filter only",
+			view: ViewConfig{Name: "Needs PRR", Filter: "prr:missing"},
+		},
+		{
+			name:       "filter and sort",
+			view:       ViewConfig{Name: "By priority", Filter: "is:open", SortBy: []ViewSort{{Field: "Priority", Direction: "desc"}}},
+			wantUpdate: map[string]any{"viewId": "PVTV_new", "sortByFields": []any{map[string]any{"fieldId": "PVTF_priority", "direction": "DESC"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created map[string]any
+			var update map[string]any
+			gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if r.URL.Path != "/graphql" {
+					if r.Method != "POST" || r.URL.Path != "/orgs/my-org/projectsV2/1/views" {
+						t.Errorf("unexpected REST request %s %s", r.Method, r.URL.Path)
+					}
+					if err := json.Unmarshal(body, &created); err != nil {
+						t.Fatal(err)
+					}
+					io.WriteString(w, `{"id": 1, "node_id": "PVTV_new", "number": 2}`)
+					return
+				}
+				var req struct {
+					Query     string         `json:"query"`
+					Variables map[string]any `json:"variables"`
+				}
+				if err := json.Unmarshal(body, &req); err != nil {
+					t.Fatal(err)
+				}
+				switch {
+				case strings.Contains(req.Query, "updateProjectV2View"):
+					update = req.Variables["input"].(map[string]any)
+					io.WriteString(w, `{"data": {"updateProjectV2View": {"projectV2View": {"id": "PVTV_new"}}}}`)
+				case strings.Contains(req.Query, "views(first"):
+					io.WriteString(w, `{"data": {"node": {"views": {"nodes": [], "pageInfo": {"hasNextPage": false}}}}}`)
+				case strings.Contains(req.Query, "fields(first"):
+					io.WriteString(w, `{"data": {"node": {"fields": {"nodes": [{"id": "PVTF_priority", "name": "Priority", "dataType": "NUMBER"}]}}}}`)
+				default:
+					t.Errorf("unexpected query %s", req.Query)
+				}
+			})
+
+			project := &Info{ID: "PVT_1", Number: 1, URL: "https://github.com/orgs/my-org/projects/1"}
+			EnsureViews(gql, "my-org", project, []ViewConfig{tt.view})
+
+			if created["filter"] != tt.view.Filter {
+				t.Errorf("REST create filter = %v, want %q", created["filter"], tt.view.Filter)
+			}
+			if tt.wantUpdate == nil {
+				if update != nil {
+					t.Errorf("sent updateProjectV2View %v, want none", update)
+				}
+				return
+			}
+			if _, ok := update["filter"]; ok {
+				t.Errorf("updateProjectV2View resent the filter: %v", update)
+			}
+			if got, want := mustJSON(t, update), mustJSON(t, tt.wantUpdate); got != want {
+				t.Errorf("updateProjectV2View input = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+// mustJSON marshals v, for comparing decoded JSON.
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,107 @@
+package board
+
+import (
+	"fmt"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// Membership is one project board an issue or PR appears on.
+type Membership struct {
+	ProjectTitle  string
+	ProjectNumber int
+	ProjectURL    string
+	Owner         string
+	Closed        bool   // the project itself is closed
+	Archived      bool   // the item is archived on the project
+	Status        string // value of the requested status field ("" if unset)
+}
+
+// ---------- Find Item Boards ----------
+
+// FindItemBoards lists every project the issue or PR owner/repo#number is on,
+// with the value of statusField on each. Only projects the token can read are
+// returned.
+func FindItemBoards(gql *ghgql.Client, owner, repo string, number int, statusField string) ([]Membership, error) {
+	query := `query($owner: String!, $repo: String!, $number: Int!, $status: String!, $cursor: String) {
+		repository(owner: $owner, name: $repo) {
+			issueOrPullRequest(number: $number) {
+				... on Issue { projectItems(first: 50, after: $cursor, includeArchived: true) { ...items } }
+				... on PullRequest { projectItems(first: 50, after: $cursor, includeArchived: true) { ...items } }
+			}
+		}
+	}
+	fragment items on ProjectV2ItemConnection {
+		nodes {
+			isArchived
+			project {
+				title number url closed
+				owner { ... on Organization { login } ... on User { login } }
+			}
+			fieldValueByName(name: $status) {
+				... on ProjectV2ItemFieldSingleSelectValue { name }
+				... on ProjectV2ItemFieldTextValue { text }
+			}
+		}
+		pageInfo { hasNextPage endCursor }
+	}`
+
+	var out []Membership
+	type page struct {
+		Repository *struct {
+			IssueOrPullRequest *struct {
+				ProjectItems struct {
+					Nodes []struct {
+						IsArchived bool `json:"isArchived"`
+						Project    struct {
+							Title  string `json:"title"`
+							Number int    `json:"number"`
+							URL    string `json:"url"`
+							Closed bool   `json:"closed"`
+							Owner  struct {
+								Login string `json:"login"`
+							} `json:"owner"`
+						} `json:"project"`
+						FieldValueByName *struct {
+							Name string `json:"name"`
+							Text string `json:"text"`
+						} `json:"fieldValueByName"`
+					} `json:"nodes"`
+					PageInfo ghgql.PageInfo `json:"pageInfo"`
+				} `json:"projectItems"`
+			} `json:"issueOrPullRequest"`
+		} `json:"repository"`
+	}
+	err := ghgql.Paginate(gql, ghgql.Request{Query: query, Variables: map[string]any{"owner": owner, "repo": repo, "number": number, "status": statusField}}, func(result *page) (ghgql.PageInfo, error) {
+		if result.Repository == nil {
+			return ghgql.PageInfo{}, fmt.Errorf("repository %s/%s not found", owner, repo)
+		}
+		if result.Repository.IssueOrPullRequest == nil {
+			return ghgql.PageInfo{}, fmt.Errorf("%s/%s#%d not found", owner, repo, number)
+		}
+
+		conn := result.Repository.IssueOrPullRequest.ProjectItems
+		for _, n := range conn.Nodes {
+			m := Membership{
+				ProjectTitle:  n.Project.Title,
+				ProjectNumber: n.Project.Number,
+				ProjectURL:    n.Project.URL,
+				Owner:         n.Project.Owner.Login,
+				Closed:        n.Project.Closed,
+				Archived:      n.IsArchived,
+			}
+			if v := n.FieldValueByName; v != nil {
+				m.Status = v.Name
+				if m.Status == "" {
+					m.Status = v.Text
+				}
+			}
+			out = append(out, m)
+		}
+		return conn.PageInfo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,378 @@
+// Package boardsync mirrors the items on one or more source boards, plus
+// the results of issue/PR searches, onto a destination board. It is the
+// engine behind kube-board's sync-boards, sync-sigs, and daemon commands,
+// exported so other automation can embed it instead of shelling out:
+//
+//	s := boardsync.New(token)
+//	res, err := s.Sync(ctx,
+//		boardsync.Query{Sources: []boardsync.Source{{Owner: "kubernetes", Number: 241}}},
+//		boardsync.Destination{Owner: "my-org", Name: "SIG Auth", RemoveStale: true},
+//	)
+//	fmt.Println(len(res.Added), len(res.Removed), res.Skipped)
+package boardsync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/search"
+)
+
+// Source identifies a source board by owner login and number.
+type Source struct {
+	Owner  string
+	Number int
+}
+
+func (s Source) String() string {
+	return fmt.Sprintf("%s/projects/%d", s.Owner, s.Number)
+}
+
+// ParseSource accepts "owner/projects/N" (the path of a board URL) or the
+// shorter "owner/N".
+func ParseSource(s string) (Source, error) {
+	parts := strings.Split(strings.Trim(strings.TrimSpace(s), "/"), "/")
+	if len(parts) == 3 && parts[1] == "projects" {
+		parts = []string{parts[0], parts[2]}
+	}
+	if len(parts) != 2 || parts[0] == "" {
+		return Source{}, fmt.Errorf("invalid board %q (expected owner/projects/N)", s)
+	}
+	n, err := strconv.Atoi(parts[1])
+	if err != nil || n <= 0 {
+		return Source{}, fmt.Errorf("invalid board number in %q", s)
+	}
+	return Source{Owner: parts[0], Number: n}, nil
+}
+
+// Copy is one source board's copy of an item.
+type Copy struct {
+	Source Source
+	Item   board.ProjectItemWithFields
+}
+
+// Query selects the items to sync: everything on the source boards plus
+// every result of the searches, merged into one set.
+type Query struct {
+	Sources []Source
+
+	// Searches are GitHub issue/PR searches (e.g. `repo:kubernetes/enhancements
+	// label:sig/auth milestone:v1.36`). Their results join the board items;
+	// an item also on a source board keeps the board's copy, which carries
+	// field values.
+	Searches []string
+
+	// Resolve picks which copy of an item found on several sources is
+	// kept (and so whose field values are mirrored). Copies are in Sources
+	// order; nil keeps the first.
+	Resolve func(copies []Copy) Copy
+
+	// Filter narrows (and may reorder) the collected items; nil keeps all.
+	Filter func([]board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error)
+}
+
+// Destination describes the board items are mirrored onto and how.
+type Destination struct {
+	Owner string // user or org owning the board
+	Name  string // board title; created if missing
+
+	LinkRepos   []string // "owner/name" repos to link to the board
+	UnlinkRepos bool     // unlink repos not in LinkRepos
+	RemoveStale bool     // remove items no longer in the query
+	StaleAction string   // what removing does; see board.Config.StaleAction
+
+	// NewItemsOnTop moves added items to the top of the board, in query
+	// order; see board.Config.NewItemsOnTop.
+	NewItemsOnTop bool
+
+	// Fields are ensured on the board, and Values returns the ones to
+	// write for each item (nil writes none).
+	Fields []board.FieldSpec
+	Values func(board.ProjectItemWithFields) map[string]string
+
+	// InitialValues returns fields to write for each item only while
+	// they're empty on the board, such as a newly added item's Status
+	// (see board.Item.InitialFields); nil writes none.
+	InitialValues func(board.ProjectItemWithFields) map[string]string
+
+	Visibility  string // board.Private (the default) or board.Public
+	Description string // the board's short description; "" leaves it
+	Readme      string // the board's README; "" leaves it
+
+	Collaborators []board.Collaborator
+	Template      *board.Template // copied to create a missing board
+	Budget        *board.Budget   // caps mutations; the rest are deferred
+}
+
+// Result is the outcome of a Sync: the items that matched the query and
+// what changed on the destination board.
+type Result struct {
+	Items []board.ProjectItemWithFields
+	board.Changes
+}
+
+// Syncer runs syncs with one token, or with one token for reading the
+// sources and another for writing the destination board (see NewReadWrite).
+// It is safe to use from several goroutines.
+type Syncer struct {
+	token      string
+	gql        *ghgql.Client
+	writeToken string
+	writeGQL   *ghgql.Client
+	clientOpts []ghgql.Option // for the client UpdateBoard makes
+
+	// CacheSources keeps each source board's items and each search's
+	// results after the first fetch, so several syncs sharing a source or
+	// search read it once. Leave it off for long-running loops that must see
+	// fresh data every time.
+	CacheSources bool
+
+	mu       sync.Mutex // guards cache, searches, and fields
+	cache    map[Source][]board.ProjectItemWithFields
+	searches map[string]*search.Result
+	fields   map[Source]board.FieldMap // each source board's fields, for SourceFields
+}
+
+// New returns a Syncer authenticated with token. opts configure its
+// clients, e.g. ghgql.WithTransport.
+func New(token string, opts ...ghgql.Option) *Syncer {
+	return NewReadWrite(token, token, opts...)
+}
+
+// NewReadWrite returns a Syncer that collects items with readToken and
+// writes the destination board with writeToken, so a cross-org sync can
+// pair a read-only token with broad access and a token that can only write
+// to the destination owner. The write token still needs read access to the
+// items it adds.
+func NewReadWrite(readToken, writeToken string, opts ...ghgql.Option) *Syncer {
+	s := &Syncer{
+		token:      readToken,
+		gql:        ghgql.NewClient(readToken, opts...),
+		writeToken: writeToken,
+		clientOpts: opts,
+		cache:      make(map[Source][]board.ProjectItemWithFields),
+		searches:   make(map[string]*search.Result),
+		fields:     make(map[Source]board.FieldMap),
+	}
+	s.writeGQL = s.gql
+	if writeToken != readToken {
+		s.writeGQL = ghgql.NewClient(writeToken, opts...)
+	}
+	return s
+}
+
+// Client returns the Syncer's GraphQL client for reading, for follow-up
+// calls.
+func (s *Syncer) Client() *ghgql.Client {
+	return s.gql
+}
+
+// WriteClient returns the Syncer's GraphQL client for writing the
+// destination board, for follow-up calls that change it.
+func (s *Syncer) WriteClient() *ghgql.Client {
+	return s.writeGQL
+}
+
+// Sync collects the query's items and mirrors them onto the destination.
+// On a write error the Result still holds the collected items and any
+// changes made before the error. Cancelling ctx stops the sync after the
+// mutation in flight; see board.Config.Context.
+func (s *Syncer) Sync(ctx context.Context, q Query, d Destination) (*Result, error) {
+	list, err := s.Collect(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Items: list}
+	changes, err := s.Write(ctx, d, list)
+	if changes != nil {
+		res.Changes = *changes
+	}
+	return res, err
+}
+
+// Collect fetches every item on the query's source boards and searches,
+// de-duplicated by content node ID, and applies its filter.
+func (s *Syncer) Collect(ctx context.Context, q Query) ([]board.ProjectItemWithFields, error) {
+	gql := s.gql.WithContext(ctx)
+	copies := make(map[string][]Copy)
+	var order []string // content IDs in first-seen order
+	for _, src := range q.Sources {
+		s.mu.Lock()
+		fetched, ok := s.cache[src]
+		s.mu.Unlock()
+		if ok && s.CacheSources {
+			slog.Info("Using cached items", "board", src, "count", len(fetched))
+		} else {
+			project, err := board.FindProjectByOwnerNumber(gql, src.Owner, src.Number)
+			if err != nil {
+				return nil, fmt.Errorf("finding source board %s: %w", src, err)
+			}
+			if fetched, err = board.FetchProjectItems(gql, project.ID); err != nil {
+				return nil, fmt.Errorf("fetching items from %s: %w", src, err)
+			}
+			s.mu.Lock()
+			s.fields[src] = project.Fields
+			if s.CacheSources {
+				s.cache[src] = fetched
+			}
+			s.mu.Unlock()
+		}
+		dupes := 0
+		for _, it := range fetched {
+			if it.ContentID == "" {
+				continue // redacted or inaccessible content
+			}
+			if _, ok := copies[it.ContentID]; ok {
+				dupes++
+			} else {
+				order = append(order, it.ContentID)
+			}
+			copies[it.ContentID] = append(copies[it.ContentID], Copy{Source: src, Item: it})
+		}
+		slog.Info("Fetched items", "board", src, "count", len(fetched), "duplicates", dupes)
+	}
+
+	var found []board.ProjectItemWithFields // search results on no source board
+	for _, query := range q.Searches {
+		s.mu.Lock()
+		res, ok := s.searches[query]
+		s.mu.Unlock()
+		if ok && s.CacheSources {
+			slog.Info("Using cached search results", "query", query, "count", len(res.Items))
+		} else {
+			var err error
+			if res, err = search.Issues(gql, query); err != nil {
+				return nil, fmt.Errorf("searching %q: %w", query, err)
+			}
+			if s.CacheSources {
+				s.mu.Lock()
+				s.searches[query] = res
+				s.mu.Unlock()
+			}
+		}
+		added := 0
+		for _, it := range res.Items {
+			if _, ok := copies[it.ContentID]; ok || it.ContentID == "" {
+				continue
+			}
+			copies[it.ContentID] = nil
+			found = append(found, it)
+			added++
+		}
+		slog.Info("Searched", "query", query, "count", len(res.Items), "new", added)
+	}
+
+	list := make([]board.ProjectItemWithFields, 0, len(order)+len(found))
+	shared := 0
+	for _, id := range order {
+		cs := copies[id]
+		kept := cs[0]
+		if len(cs) > 1 {
+			shared++
+			if q.Resolve != nil {
+				kept = q.Resolve(cs)
+			}
+		}
+		list = append(list, kept.Item)
+	}
+	list = append(list, found...)
+	slog.Info("Collected unique items", "count", len(list), "boards", len(q.Sources), "searches", len(q.Searches), "shared", shared)
+
+	if q.Filter == nil {
+		return list, nil
+	}
+	return q.Filter(list)
+}
+
+// SourceFields returns the named fields as the source boards Collect read
+// define them, for a Destination to ensure before copying their values: a
+// field's type comes from the first board that has it, and a single-select
+// field gets every board's options, in order. Fields no source board has,
+// and those of a type that can't be created (iterations, and GitHub's own
+// such as Assignees), are logged and left out.
+func (s *Syncer) SourceFields(sources []Source, names []string) []board.FieldSpec {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var specs []board.FieldSpec
+	for _, name := range names {
+		var spec *board.FieldSpec
+		seen := make(map[string]bool)
+		for _, src := range sources {
+			def, ok := s.fields[src][name]
+			if !ok {
+				continue
+			}
+			if spec == nil {
+				spec = &board.FieldSpec{Name: name, Type: def.Type}
+			}
+			for _, opt := range def.Options {
+				if !seen[strings.ToLower(opt.Name)] {
+					seen[strings.ToLower(opt.Name)] = true
+					spec.Options = append(spec.Options, opt.Name)
+				}
+			}
+		}
+		switch {
+		case spec == nil:
+			slog.Warn("No source board has the field to copy", "field", name)
+		case !copyableField(spec.Type):
+			slog.Warn("Can't copy field: fields of its type can't be created on the destination", "field", name, "type", spec.Type)
+		default:
+			specs = append(specs, *spec)
+		}
+	}
+	return specs
+}
+
+// copyableField reports whether board.EnsureFields can create a field of
+// type t.
+func copyableField(t string) bool {
+	switch t {
+	case "SINGLE_SELECT", "TEXT", "NUMBER", "DATE":
+		return true
+	}
+	return false
+}
+
+// Write mirrors list onto the destination board.
+func (s *Syncer) Write(ctx context.Context, d Destination, list []board.ProjectItemWithFields) (*board.Changes, error) {
+	config := board.Config{
+		Context:       ctx,
+		Token:         s.writeToken,
+		ClientOptions: s.clientOpts,
+		Owner:         d.Owner,
+		Name:          d.Name,
+		LinkRepos:     d.LinkRepos,
+		Sync:          d.RemoveStale,
+		StaleAction:   d.StaleAction,
+		NewItemsOnTop: d.NewItemsOnTop,
+		Fields:        d.Fields,
+		Budget:        d.Budget,
+		UnlinkRepos:   d.UnlinkRepos,
+		Collaborators: d.Collaborators,
+		Template:      d.Template,
+		Visibility:    d.Visibility,
+		Description:   d.Description,
+		Readme:        d.Readme,
+	}
+	toSync := make([]board.Item, 0, len(list))
+	for _, it := range list {
+		bi := board.Item{NodeID: it.ContentID, Number: it.Number, Title: it.Title, Type: it.Type, Body: it.Body, Fields: map[string]string{}}
+		if d.Values != nil {
+			for name, value := range d.Values(it) {
+				bi.Fields[name] = value
+			}
+		}
+		if d.InitialValues != nil {
+			bi.InitialFields = d.InitialValues(it)
+		}
+		toSync = append(toSync, bi)
+	}
+	return board.UpdateBoard(config, toSync)
+}
@@ -0,0 +1,110 @@
+package boardsync
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// roundTripFunc lets a plain function serve a client's requests.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// newFakeClient returns a client whose requests are answered by handler,
+// without pacing or retries.
+func newFakeClient(t *testing.T, handler http.HandlerFunc) *ghgql.Client {
+	t.Helper()
+	c := ghgql.NewClientWithTransport("test-token", roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	}))
+	c.MinDelay = 0
+	c.MaxRetries = 0
+	c.RetryBackoff = 0
+	return c
+}
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Source
+		wantErr string
+	}{
+		{in: "kubernetes/projects/241", want: Source{Owner: "kubernetes", Number: 241}},
+		{in: " /kubernetes/241/ ", want: Source{Owner: "kubernetes", Number: 241}},
+		{in: "kubernetes", wantErr: "invalid board"},
+		{in: "kubernetes/projects/241/views/1", wantErr: "invalid board"},
+		{in: "kubernetes/projects/x", wantErr: "invalid board number"},
+		{in: "kubernetes/0", wantErr: "invalid board number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSource(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseSource(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseSource(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestCollectConcurrent runs cached collects from several goroutines; run
+// it with -race to check the caches are guarded.
+func TestCollectConcurrent(t *testing.T) {
+	var requests atomic.Int32
+	gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		io.WriteString(w, `{"data": {"search": {"issueCount": 1, "nodes": [
+			{"__typename": "Issue", "id": "I_1", "number": 1, "title": "Fix it", "repository": {"nameWithOwner": "kubernetes/kubernetes"}}
+		], "pageInfo": {"hasNextPage": false}}}}`)
+	})
+	s := NewReadWrite("test-token", "test-token")
+	s.gql, s.writeGQL = gql, gql
+	s.CacheSources = true
+	q := Query{Searches: []string{"repo:kubernetes/kubernetes label:sig/auth"}}
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			list, err := s.Collect(context.Background(), q)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(list) != 1 || list[0].ContentID != "I_1" {
+				t.Errorf("Collect = %v, want I_1", list)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := requests.Load(); n < 1 || n > workers {
+		t.Fatalf("sent %d searches, want 1 to %d", n, workers)
+	}
+
+	before := requests.Load()
+	if _, err := s.Collect(context.Background(), q); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != before {
+		t.Errorf("a cached Collect sent %d more searches, want none", n-before)
+	}
+}
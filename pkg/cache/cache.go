@@ -4,7 +4,7 @@ package cache
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
@@ -29,7 +29,7 @@ func SafeString(s string) string {
 // Returns the full path of the created file.
 func Write(dir, key string, data any) string {
 	if err := os.MkdirAll(dir, 0o755); err != nil {
-		log.Printf("Warning: could not create cache dir: %v", err)
+		slog.Warn("Could not create cache dir", "err", err)
 		return ""
 	}
 
@@ -37,16 +37,16 @@ func Write(dir, key string, data any) string {
 
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		log.Printf("Warning: could not marshal cache data: %v", err)
+		slog.Warn("Could not marshal cache data", "err", err)
 		return ""
 	}
 
 	if err := os.WriteFile(path, jsonData, 0o644); err != nil {
-		log.Printf("Warning: could not write cache file: %v", err)
+		slog.Warn("Could not write cache file", "err", err)
 		return ""
 	}
 
-	log.Printf("Cached data to %s (%d bytes)", path, len(jsonData))
+	slog.Info("Cached data", "path", path, "bytes", len(jsonData))
 	return path
 }
 
@@ -80,10 +80,56 @@ func ReadLatest[T any](dir, prefix string) ([]T, error) {
 		return nil, fmt.Errorf("unmarshal %s: %w", path, err)
 	}
 
-	log.Printf("Loaded %d items from cache: %s", len(items), path)
+	slog.Info("Loaded items from cache", "path", path, "count", len(items))
 	return items, nil
 }
 
+// Snapshot is the contents of one timestamped cache file.
+type Snapshot[T any] struct {
+	At    time.Time
+	Items []T
+}
+
+// ReadSnapshots reads every cache file named prefix + Timestamp() + ".json"
+// in dir, oldest first. Files whose timestamp cannot be parsed are skipped.
+// A missing dir is not an error — it simply has no history yet.
+func ReadSnapshots[T any](dir, prefix string) ([]Snapshot[T], error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var snaps []Snapshot[T]
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".json")
+		at, err := time.ParseInLocation("2006-01-02T15-04-05", ts, time.Local)
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var items []T
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("unmarshal %s: %w", path, err)
+		}
+		snaps = append(snaps, Snapshot[T]{At: at, Items: items})
+	}
+
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].At.Before(snaps[j].At) })
+	return snaps, nil
+}
+
 // Clean removes old cache files in dir whose name starts with prefix,
 // keeping only the keep newest. Files are sorted by name (which embeds a
 // timestamp). Returns the number of files removed.
@@ -115,9 +161,9 @@ func Clean(dir, prefix string, keep int) (int, error) {
 	for _, name := range toRemove {
 		path := filepath.Join(dir, name)
 		if err := os.Remove(path); err != nil {
-			log.Printf("Warning: could not remove cache file %s: %v", path, err)
+			slog.Warn("Could not remove cache file", "path", path, "err", err)
 		} else {
-			log.Printf("  Removed old cache file: %s", name)
+			slog.Debug("Removed old cache file", "path", name)
 			removed++
 		}
 	}
@@ -136,11 +182,11 @@ func Enforce(dir string, limit int) {
 	}
 	removed, err := CleanAll(dir, limit)
 	if err != nil {
-		log.Printf("Warning: cache cleanup error: %v", err)
+		slog.Warn("Could not clean up the cache", "err", err)
 		return
 	}
 	if removed > 0 {
-		log.Printf("Cache cleanup: removed %d old file(s) (keeping %d per prefix)", removed, limit)
+		slog.Info("Removed old cache files", "count", removed, "keep", limit)
 	}
 }
 
@@ -0,0 +1,385 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+type node interface {
+	eval(env map[string]any) (any, error)
+}
+
+type literalNode struct{ value any }
+
+func (n *literalNode) eval(map[string]any) (any, error) { return n.value, nil }
+
+type varNode struct{ name string }
+
+func (n *varNode) eval(env map[string]any) (any, error) {
+	v, ok := env[n.name]
+	if !ok {
+		return nil, fmt.Errorf("expr: undefined variable %q", n.name)
+	}
+	return normalize(v), nil
+}
+
+type listNode struct{ elems []node }
+
+func (n *listNode) eval(env map[string]any) (any, error) {
+	out := make([]any, len(n.elems))
+	for i, e := range n.elems {
+		v, err := e.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+type indexNode struct{ target, index node }
+
+func (n *indexNode) eval(env map[string]any) (any, error) {
+	target, err := n.target.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := n.index.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch t := target.(type) {
+	case map[string]any:
+		key, ok := idx.(string)
+		if !ok {
+			return nil, fmt.Errorf("expr: map key must be a string, got %s", typeName(idx))
+		}
+		return normalize(t[key]), nil // missing keys are null
+	case []any:
+		i, ok := idx.(float64)
+		if !ok || i != math.Trunc(i) {
+			return nil, fmt.Errorf("expr: list index must be an integer, got %v", idx)
+		}
+		if i < 0 || int(i) >= len(t) {
+			return nil, fmt.Errorf("expr: list index %d out of range (length %d)", int(i), len(t))
+		}
+		return normalize(t[int(i)]), nil
+	case nil:
+		return nil, fmt.Errorf("expr: cannot index null with %v", idx)
+	default:
+		return nil, fmt.Errorf("expr: cannot index %s", typeName(target))
+	}
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(env map[string]any) (any, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expr: ! needs a bool, got %s", typeName(v))
+		}
+		return !b, nil
+	default: // "-"
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expr: unary - needs a number, got %s", typeName(v))
+		}
+		return -f, nil
+	}
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(env map[string]any) (any, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short-circuit the logical operators.
+	if n.op == "&&" || n.op == "||" {
+		lb, ok := l.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expr: %s needs bools, got %s", n.op, typeName(l))
+		}
+		if (n.op == "&&" && !lb) || (n.op == "||" && lb) {
+			return lb, nil
+		}
+		r, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		rb, ok := r.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expr: %s needs bools, got %s", n.op, typeName(r))
+		}
+		return rb, nil
+	}
+
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	case "in":
+		return contains(r, l)
+	case "<", "<=", ">", ">=":
+		c, err := compare(l, r)
+		if err != nil {
+			return nil, fmt.Errorf("expr: %s: %w", n.op, err)
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case "+":
+		if ls, ok := l.(string); ok {
+			if rs, ok := r.(string); ok {
+				return ls + rs, nil
+			}
+		}
+	}
+
+	lf, lok := l.(float64)
+	rf, rok := r.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("expr: %s needs numbers, got %s and %s", n.op, typeName(l), typeName(r))
+	}
+	switch n.op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, fmt.Errorf("expr: division by zero")
+		}
+		return lf / rf, nil
+	default: // "%"
+		if rf == 0 {
+			return nil, fmt.Errorf("expr: modulo by zero")
+		}
+		return math.Mod(lf, rf), nil
+	}
+}
+
+type callNode struct {
+	name string
+	fn   func(args []any) (any, error)
+	args []node
+}
+
+func (n *callNode) eval(env map[string]any) (any, error) {
+	args := make([]any, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("expr: %s(): %w", n.name, err)
+	}
+	return v, nil
+}
+
+// ---------- Builtins ----------
+
+type builtin struct {
+	arity int
+	call  func(args []any) (any, error)
+}
+
+var builtins = map[string]builtin{
+	"len": {1, func(a []any) (any, error) {
+		switch v := a[0].(type) {
+		case string:
+			return float64(len(v)), nil
+		case []any:
+			return float64(len(v)), nil
+		case map[string]any:
+			return float64(len(v)), nil
+		case nil:
+			return 0.0, nil
+		}
+		return nil, fmt.Errorf("cannot take length of %s", typeName(a[0]))
+	}},
+	"lower":      stringFunc(func(s string) any { return strings.ToLower(s) }),
+	"upper":      stringFunc(func(s string) any { return strings.ToUpper(s) }),
+	"contains":   stringPairFunc(strings.Contains),
+	"startsWith": stringPairFunc(strings.HasPrefix),
+	"endsWith":   stringPairFunc(strings.HasSuffix),
+}
+
+func stringFunc(f func(string) any) builtin {
+	return builtin{1, func(a []any) (any, error) {
+		s, ok := a[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("needs a string, got %s", typeName(a[0]))
+		}
+		return f(s), nil
+	}}
+}
+
+func stringPairFunc(f func(s, sub string) bool) builtin {
+	return builtin{2, func(a []any) (any, error) {
+		s, ok1 := a[0].(string)
+		sub, ok2 := a[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("needs two strings, got %s and %s", typeName(a[0]), typeName(a[1]))
+		}
+		return f(s, sub), nil
+	}}
+}
+
+// ---------- Values ----------
+
+// normalize converts Go values supplied in an environment into the
+// expression's value types: float64, string, bool, nil, []any, and
+// map[string]any.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case float32:
+		return float64(t)
+	case []string:
+		out := make([]any, len(t))
+		for i, s := range t {
+			out[i] = s
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]any, len(t))
+		for k, s := range t {
+			out[k] = s
+		}
+		return out
+	case time.Time:
+		if t.IsZero() {
+			return nil
+		}
+		return t.Format(time.RFC3339) // compares lexically in time order
+	}
+	return v
+}
+
+func equal(a, b any) bool {
+	switch av := a.(type) {
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		return false // maps only compare equal to themselves, which filters never need
+	}
+	switch b.(type) {
+	case []any, map[string]any:
+		return false
+	}
+	return a == b
+}
+
+func compare(a, b any) (int, error) {
+	switch av := a.(type) {
+	case float64:
+		if bv, ok := b.(float64); ok {
+			switch {
+			case av < bv:
+				return -1, nil
+			case av > bv:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %s with %s", typeName(a), typeName(b))
+}
+
+func contains(container, v any) (any, error) {
+	switch c := container.(type) {
+	case []any:
+		for _, e := range c {
+			if equal(e, v) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		k, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expr: map key must be a string, got %s", typeName(v))
+		}
+		_, found := c[k]
+		return found, nil
+	case string:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expr: substring must be a string, got %s", typeName(v))
+		}
+		return strings.Contains(c, s), nil
+	case nil:
+		return false, nil
+	}
+	return nil, fmt.Errorf("expr: cannot use 'in' with %s", typeName(container))
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,391 @@
+// Package expr implements a small CEL-like expression language for filtering
+// and ranking board items from the command line, e.g.
+//
+//	item.state == "OPEN" && "sig/auth" in item.labels && item.ageDays > 30
+//
+// Supported syntax:
+//
+//   - literals: numbers, "strings" or 'strings', true, false, null, [lists]
+//   - variables and member access: item.labels, item.fields["Status"]
+//   - operators, loosest first: ||, &&, == !=, < <= > >= in, + -, * / %, unary ! -
+//   - functions, also callable as methods: len(x), lower(s), upper(s),
+//     contains(s, sub), startsWith(s, p), endsWith(s, p) — item.title.contains("KEP")
+//
+// Numbers are float64. "x in list" tests membership, "k in map" tests for a
+// key, and "sub in string" tests for a substring. Missing map keys evaluate
+// to null rather than failing, so expressions over optional board fields
+// stay short.
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Program is a compiled expression, safe to evaluate repeatedly.
+type Program struct {
+	src  string
+	root node
+}
+
+// Compile parses src into a Program.
+func Compile(src string) (*Program, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("expr: unexpected %q at offset %d", t.text, t.pos)
+	}
+	return &Program{src: src, root: root}, nil
+}
+
+// String returns the source the program was compiled from.
+func (p *Program) String() string { return p.src }
+
+// Eval evaluates the program against env, whose keys are the top-level
+// variables (e.g. "item").
+func (p *Program) Eval(env map[string]any) (any, error) {
+	return p.root.eval(env)
+}
+
+// EvalBool evaluates the program and requires a boolean result.
+func (p *Program) EvalBool(env map[string]any) (bool, error) {
+	v, err := p.Eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expr: %q evaluated to %s, want bool", p.src, typeName(v))
+	}
+	return b, nil
+}
+
+// EvalNumber evaluates the program and requires a numeric result.
+func (p *Program) EvalNumber(env map[string]any) (float64, error) {
+	v, err := p.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("expr: %q evaluated to %s, want number", p.src, typeName(v))
+	}
+	return n, nil
+}
+
+// ---------- Lexer ----------
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokKind
+	text string // operator/identifier text, or the decoded string literal
+	num  float64
+	pos  int
+}
+
+// twoCharOps must be checked before single-character operators.
+var twoCharOps = []string{"&&", "||", "==", "!=", "<=", ">="}
+
+const oneCharOps = "()[],.!<>+-*/%"
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			// ParseFloat, unlike Sscan, rejects trailing junk such as the
+			// ".3" of "1.2.3" instead of stopping at it.
+			n, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("expr: bad number %q at offset %d", src[start:i], start)
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[start:i], num: n, pos: start})
+
+		case c == '"' || c == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, fmt.Errorf("expr: unterminated string at offset %d", start)
+				}
+				if src[i] == c {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+					switch src[i] {
+					case 'n':
+						b.WriteByte('\n')
+					case 't':
+						b.WriteByte('\t')
+					default:
+						b.WriteByte(src[i])
+					}
+					i++
+					continue
+				}
+				b.WriteByte(src[i])
+				i++
+			}
+			toks = append(toks, token{kind: tokString, text: b.String(), pos: start})
+
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] >= 'a' && src[i] <= 'z' || src[i] >= 'A' && src[i] <= 'Z' || src[i] >= '0' && src[i] <= '9') {
+				i++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[start:i], pos: start})
+
+		default:
+			matched := false
+			for _, op := range twoCharOps {
+				if strings.HasPrefix(src[i:], op) {
+					toks = append(toks, token{kind: tokOp, text: op, pos: i})
+					i += 2
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			if strings.IndexByte(oneCharOps, c) >= 0 {
+				toks = append(toks, token{kind: tokOp, text: string(c), pos: i})
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("expr: unexpected character %q at offset %d", c, i)
+		}
+	}
+	return append(toks, token{kind: tokEOF, text: "end of expression", pos: len(src)}), nil
+}
+
+// ---------- Parser ----------
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the operator or keyword text.
+func (p *parser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tokOp || t.kind == tokIdent) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		t := p.peek()
+		return fmt.Errorf("expr: expected %q at offset %d, found %q", text, t.pos, t.text)
+	}
+	return nil
+}
+
+// binaryLevels lists binary operators from loosest to tightest binding.
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">=", "in"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseExpr() (node, error) { return p.parseBinary(0) }
+
+func (p *parser) parseBinary(level int) (node, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, candidate := range binaryLevels[level] {
+			if p.accept(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	for _, op := range []string{"!", "-"} {
+		if p.accept(op) {
+			operand, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return &unaryNode{op: op, operand: operand}, nil
+		}
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, fmt.Errorf("expr: expected field name after '.' at offset %d", t.pos)
+			}
+			if p.accept("(") {
+				args, err := p.parseArgs(")")
+				if err != nil {
+					return nil, err
+				}
+				n, err = newCall(t, append([]node{n}, args...))
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			n = &indexNode{target: n, index: &literalNode{value: t.text}}
+		case p.accept("["):
+			idx, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			n = &indexNode{target: n, index: idx}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &literalNode{value: t.num}, nil
+	case tokString:
+		return &literalNode{value: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+		if p.accept("(") {
+			args, err := p.parseArgs(")")
+			if err != nil {
+				return nil, err
+			}
+			return newCall(t, args)
+		}
+		return &varNode{name: t.text}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			n, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		case "[":
+			elems, err := p.parseArgs("]")
+			if err != nil {
+				return nil, err
+			}
+			return &listNode{elems: elems}, nil
+		}
+	}
+	return nil, fmt.Errorf("expr: unexpected %q at offset %d", t.text, t.pos)
+}
+
+// parseArgs parses a comma-separated list terminated by end; the opening
+// bracket has already been consumed.
+func (p *parser) parseArgs(end string) ([]node, error) {
+	var args []node
+	if p.accept(end) {
+		return args, nil
+	}
+	for {
+		a, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, a)
+		if p.accept(end) {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func newCall(name token, args []node) (node, error) {
+	fn, ok := builtins[name.text]
+	if !ok {
+		return nil, fmt.Errorf("expr: unknown function %q at offset %d", name.text, name.pos)
+	}
+	if len(args) != fn.arity {
+		return nil, fmt.Errorf("expr: %s() takes %d argument(s), got %d", name.text, fn.arity, len(args))
+	}
+	return &callNode{name: name.text, fn: fn.call, args: args}, nil
+}
@@ -0,0 +1,225 @@
+package expr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLex(t *testing.T) {
+	tests := []struct {
+		src     string
+		want    []string // token texts, without the trailing EOF
+		wantErr string
+	}{
+		{src: "", want: nil},
+		{src: "a.b >= 30", want: []string{"a", ".", "b", ">=", "30"}},
+		{src: `"sig/auth" in labels`, want: []string{"sig/auth", "in", "labels"}},
+		{src: `'it\'s' + "a\tb"`, want: []string{"it's", "+", "a\tb"}},
+		{src: "!x&&y||z", want: []string{"!", "x", "&&", "y", "||", "z"}},
+		{src: "1.5", want: []string{"1.5"}},
+		{src: "1.2.3", wantErr: `bad number "1.2.3"`},
+		{src: "1..2", wantErr: `bad number "1..2"`},
+		{src: `"open`, wantErr: "unterminated string"},
+		{src: "a # b", wantErr: "unexpected character '#'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			toks, err := lex(tt.src)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("lex(%q) error = %v, want %q", tt.src, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("lex(%q): %v", tt.src, err)
+			}
+			if last := toks[len(toks)-1]; last.kind != tokEOF {
+				t.Fatalf("lex(%q) ends with %q, want EOF", tt.src, last.text)
+			}
+			var got []string
+			for _, tok := range toks[:len(toks)-1] {
+				got = append(got, tok.text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lex(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLexNumberValue(t *testing.T) {
+	toks, err := lex("2.25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toks[0].kind != tokNumber || toks[0].num != 2.25 {
+		t.Errorf("lex(2.25) = %+v, want number 2.25", toks[0])
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{src: "", wantErr: `unexpected "end of expression"`},
+		{src: "a b", wantErr: `unexpected "b"`},
+		{src: "(a", wantErr: `expected ")"`},
+		{src: "a[1", wantErr: `expected "]"`},
+		{src: "[1, 2", wantErr: `expected ","`},
+		{src: "a.", wantErr: "expected field name"},
+		{src: "nope(1)", wantErr: `unknown function "nope"`},
+		{src: "len(1, 2)", wantErr: "len() takes 1 argument(s), got 2"},
+		{src: "a.contains()", wantErr: "contains() takes 2 argument(s), got 1"},
+		{src: "a ==", wantErr: "unexpected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Compile(tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile(%q) error = %v, want %q", tt.src, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	env := map[string]any{
+		"item": map[string]any{
+			"state":   "OPEN",
+			"title":   "KEP-1234: Sidecars",
+			"labels":  []string{"sig/auth", "kind/feature"},
+			"ageDays": 45,
+			"fields":  map[string]string{"Status": "In Progress"},
+			"closed":  time.Time{},
+		},
+		"n": 7,
+	}
+	tests := []struct {
+		src  string
+		want any
+	}{
+		// Literals and arithmetic.
+		{src: "1 + 2 * 3", want: 7.0},
+		{src: "(1 + 2) * 3", want: 9.0},
+		{src: "-n + 10", want: 3.0},
+		{src: "n % 4", want: 3.0},
+		{src: "7 / 2", want: 3.5},
+		{src: `"a" + "b"`, want: "ab"},
+		{src: "null", want: nil},
+		{src: "[1, 'x']", want: []any{1.0, "x"}},
+
+		// Comparison and logic, loosest first.
+		{src: `item.state == "OPEN" && item.ageDays > 30`, want: true},
+		{src: `item.state != "OPEN" || item.ageDays <= 45`, want: true},
+		{src: "!(n >= 8)", want: true},
+		{src: `"a" < "b"`, want: true},
+		{src: "[1, 2] == [1, 2]", want: true},
+		{src: "[1, 2] == [2, 1]", want: false},
+		{src: "1 == '1'", want: false},
+
+		// Short-circuiting skips the failing right-hand side.
+		{src: "false && missing", want: false},
+		{src: "true || missing", want: true},
+
+		// Member access, indexing, and missing keys.
+		{src: `item.fields["Status"]`, want: "In Progress"},
+		{src: `item.fields.Priority`, want: nil},
+		{src: "item.labels[1]", want: "kind/feature"},
+		{src: "item.closed == null", want: true},
+
+		// in: list membership, map keys, substrings, and null.
+		{src: `"sig/auth" in item.labels`, want: true},
+		{src: `"Status" in item.fields`, want: true},
+		{src: `"KEP" in item.title`, want: true},
+		{src: `"x" in item.fields.Missing`, want: false},
+
+		// Functions, called directly and as methods.
+		{src: "len(item.labels)", want: 2.0},
+		{src: "len(item.fields.Missing)", want: 0.0},
+		{src: "item.title.lower()", want: "kep-1234: sidecars"},
+		{src: "upper(item.state)", want: "OPEN"},
+		{src: `item.title.contains("Sidecar")`, want: true},
+		{src: `item.title.startsWith("KEP")`, want: true},
+		{src: `endsWith(item.title, "cars")`, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			p, err := Compile(tt.src)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", tt.src, err)
+			}
+			got, err := p.Eval(env)
+			if err != nil {
+				t.Fatalf("Eval(%q): %v", tt.src, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eval(%q) = %#v, want %#v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	env := map[string]any{
+		"item": map[string]any{"labels": []string{"a"}, "title": "t"},
+	}
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{src: "missing", wantErr: `undefined variable "missing"`},
+		{src: "1 / 0", wantErr: "division by zero"},
+		{src: "1 % 0", wantErr: "modulo by zero"},
+		{src: "1 + 'a'", wantErr: "+ needs numbers, got number and string"},
+		{src: "1 < 'a'", wantErr: "cannot compare number with string"},
+		{src: "!1", wantErr: "! needs a bool"},
+		{src: "-'a'", wantErr: "unary - needs a number"},
+		{src: "1 && true", wantErr: "&& needs bools"},
+		{src: "item.labels[1]", wantErr: "out of range"},
+		{src: "item.labels[0.5]", wantErr: "must be an integer"},
+		{src: "item.title.x", wantErr: "cannot index string"},
+		{src: "item.nope.x", wantErr: "cannot index null"},
+		{src: "1 in 2", wantErr: "cannot use 'in' with number"},
+		{src: "lower(1)", wantErr: "lower(): needs a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			p, err := Compile(tt.src)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", tt.src, err)
+			}
+			_, err = p.Eval(env)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Eval(%q) error = %v, want %q", tt.src, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvalBoolAndNumber(t *testing.T) {
+	p, err := Compile("1 + 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := p.EvalNumber(nil); err != nil || n != 2 {
+		t.Errorf("EvalNumber = %v, %v; want 2", n, err)
+	}
+	if _, err := p.EvalBool(nil); err == nil || !strings.Contains(err.Error(), "want bool") {
+		t.Errorf("EvalBool error = %v, want a type error", err)
+	}
+
+	p, err = Compile("1 < 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := p.EvalBool(nil); err != nil || !b {
+		t.Errorf("EvalBool = %v, %v; want true", b, err)
+	}
+	if _, err := p.EvalNumber(nil); err == nil || !strings.Contains(err.Error(), "want number") {
+		t.Errorf("EvalNumber error = %v, want a type error", err)
+	}
+}
@@ -0,0 +1,111 @@
+package ghgql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultBatchSize is how many mutations DoBatch sends per request by
+// default. GitHub charges each mutation of a batch as if it were sent
+// alone, so batching saves round trips and requests (which the secondary
+// limits count), not points; small batches keep one failure from holding
+// up many writes.
+const DefaultBatchSize = 10
+
+// Mutation is one mutation of a batch sent by DoBatch.
+type Mutation struct {
+	Field     string         // mutation field, e.g. "addProjectV2ItemById"
+	Input     map[string]any // its input argument
+	Selection string         // what it returns, e.g. "item { id }"
+}
+
+// partialResponse receives both the data and the errors of a response, for
+// DoBatch, where one failed mutation mustn't fail the rest of its batch.
+type partialResponse struct {
+	Data   map[string]json.RawMessage
+	Errors []ResponseError
+}
+
+// DoBatch sends mutations as aliased fields of as few requests as possible,
+// at most size (DefaultBatchSize if size <= 0) per request:
+//
+//	mutation($i0: AddProjectV2ItemByIdInput!, $i1: AddProjectV2ItemByIdInput!) {
+//		m0: addProjectV2ItemById(input: $i0) { item { id } }
+//		m1: addProjectV2ItemById(input: $i1) { item { id } }
+//	}
+//
+// It returns each mutation's result (its Selection, as JSON) and error, in
+// the order given. A mutation's error leaves the rest of its batch alone;
+// a failed request fails every mutation in it.
+func (c *Client) DoBatch(mutations []Mutation, size int) ([]json.RawMessage, []error) {
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	results := make([]json.RawMessage, len(mutations))
+	errs := make([]error, len(mutations))
+	for start := 0; start < len(mutations); start += size {
+		end := min(start+size, len(mutations))
+		c.doBatch(mutations[start:end], results[start:end], errs[start:end])
+	}
+	return results, errs
+}
+
+// doBatch sends one batch, filling in results and errs.
+func (c *Client) doBatch(batch []Mutation, results []json.RawMessage, errs []error) {
+	var vars, fields strings.Builder
+	variables := make(map[string]any, len(batch))
+	for i, m := range batch {
+		if i > 0 {
+			vars.WriteString(", ")
+		}
+		fmt.Fprintf(&vars, "$i%d: %s!", i, inputType(m.Field))
+		fmt.Fprintf(&fields, "\tm%d: %s(input: $i%d) { %s }\n", i, m.Field, i, m.Selection)
+		variables[fmt.Sprintf("i%d", i)] = m.Input
+	}
+	query := fmt.Sprintf("mutation(%s) {\n%s}", vars.String(), fields.String())
+
+	var resp partialResponse
+	if err := c.Do(Request{Query: query, Variables: variables, mutations: len(batch)}, &resp); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return
+	}
+
+	failed := make(map[int][]ResponseError)
+	for _, e := range resp.Errors {
+		i := -1
+		if len(e.Path) > 0 {
+			if alias, ok := e.Path[0].(string); ok {
+				fmt.Sscanf(alias, "m%d", &i)
+			}
+		}
+		if i < 0 || i >= len(batch) {
+			// Not tied to one mutation, e.g. a malformed query: the request
+			// as a whole failed.
+			for j := range batch {
+				failed[j] = append(failed[j], e)
+			}
+			continue
+		}
+		failed[i] = append(failed[i], e)
+	}
+	for i := range batch {
+		results[i] = resp.Data[fmt.Sprintf("m%d", i)]
+		if list := failed[i]; len(list) > 0 {
+			errs[i] = c.graphQLError(list)
+		} else if len(results[i]) == 0 || string(results[i]) == "null" {
+			errs[i] = fmt.Errorf("graphql: no result for %s", batch[i].Field)
+		}
+	}
+}
+
+// inputType returns the input type GitHub names after a mutation field:
+// addProjectV2ItemById takes an AddProjectV2ItemByIdInput.
+func inputType(field string) string {
+	r, n := utf8.DecodeRuneInString(field)
+	return string(unicode.ToUpper(r)) + field[n:] + "Input"
+}
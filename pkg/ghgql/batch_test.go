@@ -0,0 +1,153 @@
+package ghgql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDoBatch(t *testing.T) {
+	add := func(content string) Mutation {
+		return Mutation{
+			Field:     "addProjectV2ItemById",
+			Input:     map[string]any{"projectId": "P", "contentId": content},
+			Selection: "item { id }",
+		}
+	}
+
+	tests := []struct {
+		name      string
+		mutations int
+		size      int
+		respond   func(w http.ResponseWriter, batch int)
+		wantReqs  int
+		wantErrs  []string // per mutation; "" for success
+		wantTypes map[int]error
+	}{
+		{
+			name:      "all succeed",
+			mutations: 3,
+			respond: func(w http.ResponseWriter, _ int) {
+				fmt.Fprint(w, `{"data": {"m0": {"item": {"id": "I0"}}, "m1": {"item": {"id": "I1"}}, "m2": {"item": {"id": "I2"}}}}`)
+			},
+			wantReqs: 1,
+			wantErrs: []string{"", "", ""},
+		},
+		{
+			name:      "an error maps to its alias",
+			mutations: 3,
+			respond: func(w http.ResponseWriter, _ int) {
+				fmt.Fprint(w, `{"data": {"m0": {"item": {"id": "I0"}}, "m1": null, "m2": {"item": {"id": "I2"}}},
+					"errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a node", "path": ["m1"]}]}`)
+			},
+			wantReqs:  1,
+			wantErrs:  []string{"", "Could not resolve to a node", ""},
+			wantTypes: map[int]error{1: ErrNotFound},
+		},
+		{
+			name:      "an error without a path fails the whole batch",
+			mutations: 2,
+			respond: func(w http.ResponseWriter, _ int) {
+				fmt.Fprint(w, `{"errors": [{"message": "Parse error on \"}\""}]}`)
+			},
+			wantReqs: 1,
+			wantErrs: []string{"Parse error", "Parse error"},
+		},
+		{
+			name:      "a missing result is an error",
+			mutations: 2,
+			respond: func(w http.ResponseWriter, _ int) {
+				fmt.Fprint(w, `{"data": {"m0": {"item": {"id": "I0"}}}}`)
+			},
+			wantReqs: 1,
+			wantErrs: []string{"", "no result for addProjectV2ItemById"},
+		},
+		{
+			name:      "a failed request fails only its batch",
+			mutations: 3,
+			size:      2,
+			respond: func(w http.ResponseWriter, batch int) {
+				if batch == 0 {
+					http.Error(w, "nope", http.StatusUnauthorized)
+					return
+				}
+				fmt.Fprint(w, `{"data": {"m0": {"item": {"id": "I2"}}}}`)
+			},
+			wantReqs:  2,
+			wantErrs:  []string{"graphql HTTP 401", "graphql HTTP 401", ""},
+			wantTypes: map[int]error{0: ErrAuth, 1: ErrAuth},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []string
+			c := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				var req Request
+				if err := json.Unmarshal(body, &req); err != nil {
+					t.Fatal(err)
+				}
+				queries = append(queries, req.Query)
+				tt.respond(w, len(queries)-1)
+			})
+			var mutations []Mutation
+			for i := range tt.mutations {
+				mutations = append(mutations, add(fmt.Sprintf("C%d", i)))
+			}
+			results, errs := c.DoBatch(mutations, tt.size)
+
+			if len(queries) != tt.wantReqs {
+				t.Errorf("sent %d requests, want %d", len(queries), tt.wantReqs)
+			}
+			for i, want := range tt.wantErrs {
+				switch {
+				case want == "" && errs[i] != nil:
+					t.Errorf("mutation %d: unexpected error %v", i, errs[i])
+				case want == "" && !strings.Contains(string(results[i]), `"id"`):
+					t.Errorf("mutation %d: result %s, want an item", i, results[i])
+				case want != "" && (errs[i] == nil || !strings.Contains(errs[i].Error(), want)):
+					t.Errorf("mutation %d: error = %v, want %q", i, errs[i], want)
+				}
+			}
+			for i, category := range tt.wantTypes {
+				if !errors.Is(errs[i], category) {
+					t.Errorf("mutation %d: error %v is not %v", i, errs[i], category)
+				}
+			}
+		})
+	}
+}
+
+func TestDoBatchQuery(t *testing.T) {
+	var req Request
+	c := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprint(w, `{"data": {"m0": {"deletedItemId": "I0"}, "m1": {"deletedItemId": "I1"}}}`)
+	})
+	del := func(id string) Mutation {
+		return Mutation{Field: "deleteProjectV2Item", Input: map[string]any{"projectId": "P", "itemId": id}, Selection: "deletedItemId"}
+	}
+	if _, errs := c.DoBatch([]Mutation{del("I0"), del("I1")}, 0); errs[0] != nil || errs[1] != nil {
+		t.Fatalf("DoBatch errors = %v", errs)
+	}
+	want := "mutation($i0: DeleteProjectV2ItemInput!, $i1: DeleteProjectV2ItemInput!) {\n" +
+		"\tm0: deleteProjectV2Item(input: $i0) { deletedItemId }\n" +
+		"\tm1: deleteProjectV2Item(input: $i1) { deletedItemId }\n}"
+	if req.Query != want {
+		t.Errorf("query =\n%s\nwant\n%s", req.Query, want)
+	}
+	if len(req.Variables) != 2 || req.Variables["i1"] == nil {
+		t.Errorf("variables = %v, want i0 and i1", req.Variables)
+	}
+}
+
+func TestOperationNameOfBatch(t *testing.T) {
+	opType, field := operationName("mutation($i0: X!) {\n\tm0: addProjectV2ItemById(input: $i0) { item { id } }\n}")
+	if opType != "mutation" || field != "addProjectV2ItemById" {
+		t.Errorf("operationName = %q, %q; want mutation, addProjectV2ItemById", opType, field)
+	}
+}
@@ -0,0 +1,205 @@
+package ghgql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrNotRecorded is the error a Replayer gives a request its cassette has
+// no response for. It isn't retried.
+var ErrNotRecorded = errors.New("not in the cassette")
+
+// Interaction is one request and its response, as a cassette stores them.
+// Only the headers clients read back are kept: never Authorization.
+type Interaction struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Request  string      `json:"request,omitempty"` // request body
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header,omitempty"`
+	Response string      `json:"response"` // response body
+}
+
+// cassetteHeaders are the response headers a cassette keeps.
+var cassetteHeaders = []string{
+	"Content-Type", "Retry-After", "X-Accepted-Github-Permissions", "X-Oauth-Scopes",
+	"X-Ratelimit-Limit", "X-Ratelimit-Remaining", "X-Ratelimit-Used", "X-Ratelimit-Reset", "X-Ratelimit-Resource",
+}
+
+// Recorder is a transport that sends requests on through another and
+// records each request and response to a cassette, which a Replayer can
+// play back offline. Clients use it when given WithTransport(recorder). A cassette is a file of JSON Interactions, one per
+// line, written as they happen, so one cut short by a crash is still good.
+type Recorder struct {
+	base http.RoundTripper
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewRecorder starts a cassette at path, sending requests on through base
+// (nil means DefaultTransport). The file is readable only by its
+// owner: a cassette holds whatever the requests read, private boards
+// included. Tokens never appear in it (see Interaction), but review a
+// cassette before sharing it, and share it privately.
+func NewRecorder(path string, base http.RoundTripper) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		base = DefaultTransport()
+	}
+	return &Recorder{base: base, file: f}, nil
+}
+
+// Close closes the cassette.
+func (r *Recorder) Close() error {
+	return r.file.Close()
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if reqBody != nil {
+		// Send a copy with a fresh body, leaving the caller's request as
+		// it was (a RoundTripper mustn't change it).
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	in := Interaction{
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Request:  string(reqBody),
+		Status:   resp.StatusCode,
+		Header:   make(http.Header),
+		Response: string(respBody),
+	}
+	for _, h := range cassetteHeaders {
+		if v := resp.Header.Values(h); len(v) > 0 {
+			in.Header[h] = v
+		}
+	}
+	line, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("recording cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// Replayer is a transport that answers requests from a cassette instead of
+// GitHub. A request gets the response recorded for the same method, URL,
+// and body; identical requests (the pages of a retried query, say) get
+// their responses in the order they were recorded, the last one repeating.
+type Replayer struct {
+	mu        sync.Mutex
+	responses map[string][]Interaction
+}
+
+// LoadCassette reads a cassette written by a Recorder.
+func LoadCassette(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Replayer{responses: make(map[string][]Interaction)}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var in Interaction
+		if err := json.Unmarshal(line, &in); err != nil {
+			return nil, fmt.Errorf("cassette %s line %d: %w", path, i+1, err)
+		}
+		key := cassetteKey(in.Method, in.URL, []byte(in.Request))
+		r.responses[key] = append(r.responses[key], in)
+	}
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	key := cassetteKey(req.Method, req.URL.String(), body)
+	r.mu.Lock()
+	queue := r.responses[key]
+	if len(queue) > 1 {
+		r.responses[key] = queue[1:]
+	}
+	r.mu.Unlock()
+	if len(queue) == 0 {
+		what := req.Method + " " + req.URL.Path
+		var gql Request
+		if json.Unmarshal(body, &gql) == nil && gql.Query != "" {
+			opType, field := operationName(gql.Query)
+			what = fmt.Sprintf("%s %s with these variables", opType, field)
+		}
+		return nil, fmt.Errorf("%s: %w", what, ErrNotRecorded)
+	}
+	in := queue[0]
+	header := in.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(in.Response)),
+		ContentLength: int64(len(in.Response)),
+		Request:       req,
+	}, nil
+}
+
+// cassetteKey identifies a request for replay. JSON bodies are compacted,
+// so formatting doesn't matter; Go marshals map keys sorted, so variables
+// in a different order still match.
+func cassetteKey(method, url string, body []byte) string {
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) == nil {
+		body = compact.Bytes()
+	}
+	return method + " " + url + "\n" + string(body)
+}
+
+// readBody reads and closes body, which may be nil or http.NoBody.
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil || body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,135 @@
+package ghgql
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorderLeavesRequestUntouched(t *testing.T) {
+	rec, err := NewRecorder(filepath.Join(t.TempDir(), "cassette.jsonl"), fakeTransport(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, `{"echo": %q}`, body)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Close()
+
+	req, err := http.NewRequest("POST", "https://api.github.com/graphql", strings.NewReader(`{"query": "{ viewer { login } }"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := req.Body
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Body != body {
+		t.Error("RoundTrip replaced the caller's request body")
+	}
+	got, _ := io.ReadAll(resp.Body)
+	if want := `{"echo": "{\"query\": \"{ viewer { login } }\"}"}`; string(got) != want {
+		t.Errorf("response body = %s, want %s", got, want)
+	}
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	t.Setenv("GITHUB_API_URL", "")
+	t.Setenv("GITHUB_GRAPHQL_URL", "")
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	server := fakeTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Remaining", "4999")
+		w.Header().Set("X-Secret", "not kept")
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{"login": "rest-user"}`)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"n":2`) {
+			fmt.Fprint(w, `{"data": {"viewer": {"login": "second"}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"viewer": {"login": "first"}}}`)
+	})
+
+	type viewer struct {
+		Viewer struct {
+			Login string `json:"login"`
+		} `json:"viewer"`
+	}
+	run := func(c *Client) (logins []string) {
+		t.Helper()
+		c.MinDelay, c.MaxRetries, c.RetryBackoff = 0, 0, 0
+		for _, n := range []int{1, 2} {
+			var v viewer
+			if err := c.Do(Request{Query: "query($n: Int) { viewer { login } }", Variables: map[string]any{"n": n}}, &v); err != nil {
+				t.Fatalf("query %d: %v", n, err)
+			}
+			logins = append(logins, v.Viewer.Login)
+		}
+		var user struct {
+			Login string `json:"login"`
+		}
+		if err := c.DoREST(http.MethodGet, "/user", nil, &user); err != nil {
+			t.Fatalf("REST: %v", err)
+		}
+		return append(logins, user.Login)
+	}
+
+	rec, err := NewRecorder(path, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := run(NewClient("secret-token", WithTransport(rec)))
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"first", "second", "rest-user"}; strings.Join(recorded, ",") != strings.Join(want, ",") {
+		t.Fatalf("recorded %q, want %q", recorded, want)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-token") || strings.Contains(string(data), "X-Secret") {
+		t.Errorf("cassette keeps the token or an unlisted header:\n%s", data)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Error(err)
+	} else if info.Mode().Perm() != 0o600 {
+		t.Errorf("cassette mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	replay, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient("another-token", WithTransport(replay))
+	if replayed := run(c); strings.Join(replayed, ",") != strings.Join(recorded, ",") {
+		t.Errorf("replayed %q, want %q", replayed, recorded)
+	}
+	err = c.Do(Request{Query: "query($n: Int) { viewer { login } }", Variables: map[string]any{"n": 3}}, nil)
+	if !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("unrecorded request: %v, want ErrNotRecorded", err)
+	}
+}
+
+func TestBaseTransport(t *testing.T) {
+	fake := &http.Transport{}
+	if got := BaseTransport(WithTransport(fake)); got != fake {
+		t.Error("BaseTransport(WithTransport(rt)) isn't rt")
+	}
+	if got := BaseTransport(WithTransport(nil)); got != DefaultTransport() {
+		t.Error("BaseTransport(WithTransport(nil)) isn't DefaultTransport")
+	}
+	if got := BaseTransport(); got != DefaultTransport() {
+		t.Error("BaseTransport() isn't DefaultTransport")
+	}
+}
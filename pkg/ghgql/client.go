@@ -5,82 +5,308 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/tracing"
 )
 
-// Endpoint is the GitHub GraphQL API URL.
+// Endpoint is the github.com GraphQL API URL (see Endpoints).
 const Endpoint = "https://api.github.com/graphql"
 
-// RESTEndpoint is the GitHub REST API base URL.
+// RESTEndpoint is the github.com REST API base URL (see Endpoints).
 const RESTEndpoint = "https://api.github.com"
 
+// RESTAPIVersion is the X-GitHub-Api-Version sent with every REST request.
+const RESTAPIVersion = "2022-11-28"
+
 // Default rate-limit settings.
 const (
 	DefaultMinDelay   = 350 * time.Millisecond // minimum gap between requests (~3 req/s)
-	DefaultMaxRetries = 5                       // max retries on rate-limit errors
+	DefaultMaxRetries = 5                      // max retries on rate-limit errors and transient failures
+	DefaultTimeout    = 2 * time.Minute        // limit on each HTTP request
+	DefaultConcurrent = 4                      // requests in flight at once
 )
 
 // Client is an authenticated GitHub GraphQL API client with built-in
 // rate-limit handling: request pacing, automatic retry with back-off,
 // and proactive sleep when the budget is nearly exhausted.
+//
+// A Client is safe for use by multiple goroutines once configured: pacing,
+// the write throttle, and MaxConcurrent apply across all of them.
 type Client struct {
 	HTTPClient *http.Client
 	Token      string
 
+	// GraphQLURL and RESTURL are the API endpoints; empty means github.com's.
+	// NewClient sets them from Endpoints.
+	GraphQLURL string
+	RESTURL    string
+
 	// MinDelay is the minimum interval between consecutive API requests.
 	// Set to 0 to disable pacing. Default: DefaultMinDelay.
 	MinDelay time.Duration
 
 	// MaxRetries is the maximum number of retries when a rate-limit error
-	// is encountered. Default: DefaultMaxRetries.
+	// or a transient failure (a network error or 5xx; see transient) is
+	// encountered. 0 (or less) sends each request once and never retries;
+	// it is not replaced with DefaultMaxRetries, so GITHUB_MAX_RETRIES=0
+	// can turn retries off. NewClient sets it from GITHUB_MAX_RETRIES, or
+	// DefaultMaxRetries; a Client built any other way must set it to retry.
 	MaxRetries int
 
+	// WaitOnRateLimit keeps waiting out rate limits (until Retry-After or
+	// the budget's reset) after MaxRetries instead of failing, so a long
+	// run resumes where it stopped rather than losing its progress.
+	// Default: GITHUB_WAIT_ON_RATE_LIMIT=true.
+	WaitOnRateLimit bool
+
+	// SelectRateLimit adds a rateLimit selection to every query that
+	// doesn't have one, so each response reports its exact cost and the
+	// budget left (Response.RateLimit, LastRateLimit, Drift) at no extra
+	// cost. Mutations can't select it. Default:
+	// GITHUB_SELECT_RATE_LIMIT=true.
+	SelectRateLimit bool
+
+	// MaxConcurrent limits how many requests the client, and the clients
+	// WithContext makes from it, have in flight at once; callers beyond it
+	// wait for a request to finish. It is read when the first request is
+	// sent. 0 or less means no limit. Default: GITHUB_MAX_CONCURRENT, or
+	// DefaultConcurrent.
+	MaxConcurrent int
+
+	// RetryBackoff is the first wait before retrying a transient failure;
+	// each further retry waits about twice as long. Default:
+	// DefaultRetryBackoff.
+	RetryBackoff time.Duration
+
+	// Timeout limits each HTTP request, from sending it to reading the
+	// whole response, so a hung connection can't stall a run. 0 means no
+	// limit. Default: GITHUB_REQUEST_TIMEOUT, or DefaultTimeout.
+	Timeout time.Duration
+
+	// OnRequest, if set, is called with every HTTP request the client is
+	// about to send, retries included; it may add headers. OnResponse, if
+	// set, is called with every response received. Both run on the
+	// goroutine making the call.
+	OnRequest  func(*http.Request)
+	OnResponse func(Response)
+
+	ctx    context.Context // see WithContext; nil means context.Background()
+	pacing *pacing         // shared with clients made by WithContext
+	slots  *slots          // likewise; see MaxConcurrent
+	writes *tokenBucket    // likewise; see throttleWrites
+	tokens *tokenPool      // set when NewClient was given several tokens
+}
+
+// pacing tracks when the last request was sent, or is due to be.
+type pacing struct {
 	mu      sync.Mutex
-	lastReq time.Time // timestamp of the most recent request
+	lastReq time.Time
+}
+
+// slots is the semaphore behind MaxConcurrent, made on first use.
+type slots struct {
+	once sync.Once
+	ch   chan struct{} // nil for no limit
 }
 
 // NewClient creates a new GraphQL client authenticated with the given PAT.
-func NewClient(token string) *Client {
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(context.Background(), ts)
-	return &Client{
-		HTTPClient: tc,
-		Token:      token,
-		MinDelay:   DefaultMinDelay,
-		MaxRetries: DefaultMaxRetries,
+// token may be a comma-separated list: the client then starts with the
+// first and moves to the next whenever one's GraphQL budget runs out.
+//
+// Requests go through BaseTransport(opts...): the transport WithTransport
+// names, else DefaultTransport, and so through the proxy named by
+// HTTPS_PROXY (or HTTP_PROXY) unless NO_PROXY exempts the host.
+func NewClient(token string, opts ...Option) *Client {
+	base := BaseTransport(opts...)
+	graphql, rest := Endpoints()
+	c := &Client{
+		Token:         token,
+		GraphQLURL:    graphql,
+		RESTURL:       rest,
+		MinDelay:      DefaultMinDelay,
+		MaxRetries:    DefaultMaxRetries,
+		MaxConcurrent: DefaultConcurrent,
+		Timeout:       RequestTimeout(),
+		pacing:        &pacing{},
+		slots:         &slots{},
+		writes:        newWriteBucket(),
+	}
+	if n, err := strconv.Atoi(os.Getenv("GITHUB_MAX_RETRIES")); err == nil && n >= 0 {
+		c.MaxRetries = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("GITHUB_MAX_CONCURRENT")); err == nil && n > 0 {
+		c.MaxConcurrent = n
 	}
+	c.WaitOnRateLimit = os.Getenv("GITHUB_WAIT_ON_RATE_LIMIT") == "true"
+	c.SelectRateLimit = os.Getenv("GITHUB_SELECT_RATE_LIMIT") == "true"
+	if tokens := SplitTokens(token); len(tokens) > 1 {
+		c.tokens = newTokenPool(tokens)
+		c.Token = tokens[0]
+		c.HTTPClient = &http.Client{Transport: &oauth2.Transport{Source: c.tokens, Base: base}}
+		return c
+	}
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: strings.TrimSpace(token)})
+	c.HTTPClient = &http.Client{Transport: &oauth2.Transport{Source: ts, Base: base}}
+	return c
+}
+
+// NewClientWithTransport is NewClient(token, WithTransport(base)).
+func NewClientWithTransport(token string, base http.RoundTripper) *Client {
+	return NewClient(token, WithTransport(base))
+}
+
+// An Option configures the clients NewClient makes.
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	base http.RoundTripper
+}
+
+// WithTransport sends a client's requests through base instead of
+// DefaultTransport: a Recorder or Replayer, a transport with its own proxy
+// or TLS settings, or a fake in tests. The client adds the Authorization
+// header before a request reaches base. A nil base changes nothing.
+func WithTransport(base http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		if base != nil {
+			o.base = base
+		}
+	}
+}
+
+// WithContext returns a copy of c whose requests are bound to ctx, sharing
+// c's pacing. Every function taking the client inherits the context. Once
+// ctx is done, no new request is sent and pacing and rate-limit sleeps end
+// early with ctx's error; a mutation already sent is allowed to finish, so
+// there is no doubt about whether it applied.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// DoCtx is Do bound to ctx: the request is sent with ctx, and pacing and
+// rate-limit sleeps end early once ctx is done (see WithContext).
+func (c *Client) DoCtx(ctx context.Context, req Request, result any) error {
+	return c.WithContext(ctx).Do(req, result)
+}
+
+// Context returns the client's context, context.Background() by default.
+func (c *Client) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 // pace sleeps if needed so that consecutive requests are spaced at least
 // MinDelay apart. This prevents burning through the budget too quickly.
-func (c *Client) pace() {
-	if c.MinDelay <= 0 {
-		return
-	}
-	c.mu.Lock()
-	elapsed := time.Since(c.lastReq)
-	if wait := c.MinDelay - elapsed; wait > 0 {
-		c.mu.Unlock()
-		time.Sleep(wait)
-		c.mu.Lock()
-	}
-	c.lastReq = time.Now()
-	c.mu.Unlock()
+func (c *Client) pace() error {
+	if c.MinDelay <= 0 || c.pacing == nil {
+		return c.Context().Err()
+	}
+	// Take the next free slot MinDelay after the last one, so concurrent
+	// callers are spaced out rather than all waking at once.
+	p := c.pacing
+	p.mu.Lock()
+	now := time.Now()
+	next := p.lastReq.Add(c.MinDelay)
+	if next.Before(now) {
+		next = now
+	}
+	p.lastReq = next
+	p.mu.Unlock()
+	if wait := next.Sub(now); wait > 0 {
+		if err := sleep(c.Context(), wait); err != nil {
+			return err
+		}
+	}
+	return c.Context().Err()
+}
+
+// acquire waits until fewer than MaxConcurrent requests are in flight, or
+// until the client's context is done, and returns the function that
+// releases the request's place.
+func (c *Client) acquire() (release func(), err error) {
+	if c.slots == nil {
+		return func() {}, nil
+	}
+	c.slots.once.Do(func() {
+		if c.MaxConcurrent > 0 {
+			c.slots.ch = make(chan struct{}, c.MaxConcurrent)
+		}
+	})
+	if c.slots.ch == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.slots.ch <- struct{}{}:
+		return func() { <-c.slots.ch }, nil
+	case <-c.Context().Done():
+		return nil, c.Context().Err()
+	}
+}
+
+// sleep waits for d or until ctx is done, returning ctx's error in the
+// latter case.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// sendContext is the context to send a request with: the client's own for
+// reads, and one that ignores cancellation for writes, so a mutation in
+// flight when the context is cancelled still completes.
+func (c *Client) sendContext(write bool) context.Context {
+	if write {
+		return context.WithoutCancel(c.Context())
+	}
+	return c.Context()
+}
+
+// requestContext is the context for one HTTP request: sendContext, limited
+// to Timeout. The caller must call cancel once the body has been read.
+func (c *Client) requestContext(write bool) (ctx context.Context, cancel context.CancelFunc) {
+	if c.Timeout <= 0 {
+		return context.WithCancel(c.sendContext(write))
+	}
+	return context.WithTimeout(c.sendContext(write), c.Timeout)
+}
+
+// timeoutError makes an error from a request that ran out of Timeout say
+// so, rather than just "context deadline exceeded".
+func (c *Client) timeoutError(err error) error {
+	if c.Timeout > 0 && errors.Is(err, context.DeadlineExceeded) && c.Context().Err() == nil {
+		return fmt.Errorf("no response within %s: %w", c.Timeout, err)
+	}
+	return err
 }
 
 // sleepForRateLimit computes and sleeps for the appropriate back-off duration.
 // It uses the Retry-After header when available, otherwise exponential back-off.
-// Returns true to signal the caller should retry.
-func sleepForRateLimit(attempt int, retryAfterHeader string, resp *http.Response) {
+// It returns ctx's error if ctx is done before the sleep ends.
+func sleepForRateLimit(ctx context.Context, attempt int, retryAfterHeader string, resp *http.Response) error {
 	var wait time.Duration
 
 	// 1) Try Retry-After header (seconds).
@@ -106,29 +332,54 @@ func sleepForRateLimit(attempt int, retryAfterHeader string, resp *http.Response
 		if wait > 5*time.Minute {
 			wait = 5 * time.Minute
 		}
+		wait += rand.N(wait / 5) // jitter, so clients limited together don't retry together
 	}
 
-	log.Printf("Rate limit hit (attempt %d) — sleeping %s before retrying...", attempt+1, wait.Round(time.Second))
-	time.Sleep(wait)
+	slog.Warn("Rate limit hit, sleeping before retrying", "attempt", attempt+1, "wait", wait.Round(time.Second))
+	return sleep(ctx, wait)
+}
+
+// waitForRateLimit sleeps out a rate limit reported by resp, reporting
+// whether the caller should retry. Once *attempt reaches maxRetries it
+// gives up, unless WaitOnRateLimit is set: then it keeps waiting and holds
+// *attempt in place, so rate-limit waits don't use up the retries left for
+// other failures.
+func (c *Client) waitForRateLimit(attempt *int, maxRetries int, resp *http.Response) (bool, error) {
+	if *attempt >= maxRetries && !c.WaitOnRateLimit {
+		return false, nil
+	}
+	if err := sleepForRateLimit(c.Context(), *attempt, resp.Header.Get("Retry-After"), resp); err != nil {
+		return false, err
+	}
+	if *attempt >= maxRetries {
+		*attempt--
+	}
+	return true, nil
 }
 
 // Request is a GraphQL request body.
 type Request struct {
 	Query     string         `json:"query"`
 	Variables map[string]any `json:"variables,omitempty"`
+
+	// Header holds extra HTTP headers for this request, e.g. NextIDHeader.
+	Header http.Header `json:"-"`
+
+	mutations int // mutations in a DoBatch request (else 1), for throttleWrites
 }
 
 type graphqlResponse struct {
 	Data   json.RawMessage `json:"data"`
-	Errors []struct {
-		Message string `json:"message"`
-	} `json:"errors,omitempty"`
+	Errors []ResponseError `json:"errors,omitempty"`
 }
 
 // isRateLimitGraphQLError checks whether a GraphQL error response contains
 // a rate-limit error message (HTTP 200 but the server says budget is exhausted).
 func isRateLimitGraphQLError(gqlResp *graphqlResponse) bool {
 	for _, e := range gqlResp.Errors {
+		if e.Type == "RATE_LIMITED" {
+			return true
+		}
 		lower := strings.ToLower(e.Message)
 		if strings.Contains(lower, "rate limit") ||
 			strings.Contains(lower, "abuse") ||
@@ -141,42 +392,103 @@ func isRateLimitGraphQLError(gqlResp *graphqlResponse) bool {
 
 // Do sends a GraphQL request and unmarshals the response data into result.
 // It automatically retries on rate-limit errors (HTTP 429 and GraphQL-level)
-// with exponential back-off and request pacing.
-func (c *Client) Do(req Request, result any) error {
+// with exponential back-off and request pacing. Its trace span records both
+// the estimated cost and the points actually charged (github.cost).
+func (c *Client) Do(req Request, result any) (err error) {
+	opType, opName := operationName(req.Query)
+	span := tracing.Start("graphql "+opType+" "+opName,
+		attribute.String("graphql.operation.type", opType),
+		attribute.String("graphql.operation.name", opName),
+		attribute.Int("github.cost.estimate", EstimateCost(req)),
+	)
+	defer func() { tracing.EndWithError(span, err) }()
+	return c.do(req, result, span)
+}
+
+func (c *Client) do(req Request, result any, span trace.Span) error {
+	if c.SelectRateLimit {
+		req.Query = selectRateLimit(req.Query)
+	}
 	body, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("marshal graphql request: %w", err)
 	}
 
-	maxRetries := c.MaxRetries
-	if maxRetries <= 0 {
-		maxRetries = DefaultMaxRetries
-	}
+	maxRetries := max(c.MaxRetries, 0)
 
+	opType, _ := operationName(req.Query)
+	spent := 0 // points charged so far, retries included
 	for attempt := 0; attempt <= maxRetries; attempt++ {
-		c.pace()
+		release, err := c.acquire()
+		if err != nil {
+			return err
+		}
+		if err := c.pace(); err != nil {
+			release()
+			return err
+		}
+		if opType == "mutation" {
+			if err := c.throttleWrites(max(req.mutations, 1)); err != nil {
+				release()
+				return err
+			}
+		}
 
-		httpReq, err := http.NewRequestWithContext(context.Background(), "POST", Endpoint, bytes.NewReader(body))
+		sendCtx, cancelSend := c.requestContext(opType == "mutation")
+		cancel := func() { cancelSend(); release() }
+		httpReq, err := http.NewRequestWithContext(sendCtx, "POST", c.graphQLURL(), bytes.NewReader(body))
 		if err != nil {
+			cancel()
 			return fmt.Errorf("create request: %w", err)
 		}
 		httpReq.Header.Set("Content-Type", "application/json")
+		for k, v := range req.Header {
+			httpReq.Header[k] = v
+		}
 
+		write := opType == "mutation"
+		seq := c.debugRequest("graphql "+opType, attempt, body)
+		if c.OnRequest != nil {
+			c.OnRequest(httpReq)
+		}
+		start := time.Now()
 		resp, err := c.HTTPClient.Do(httpReq)
 		if err != nil {
-			return fmt.Errorf("graphql request: %w", err)
+			cancel()
+			err = fmt.Errorf("graphql request: %w", c.timeoutError(err))
+			debugFailure(seq, err)
+			if c.retryTransient(attempt, maxRetries, write, err, 0, err.Error()) {
+				continue
+			}
+			return err
 		}
 
 		respBody, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
+		cancel()
 		if err != nil {
-			return fmt.Errorf("read response: %w", err)
+			err = fmt.Errorf("read response: %w", c.timeoutError(err))
+			debugFailure(seq, err)
+			if c.retryTransient(attempt, maxRetries, write, err, 0, err.Error()) {
+				continue
+			}
+			return err
+		}
+		spent += c.received(seq, resp, respBody, time.Since(start), true)
+		recordResponse(span, attempt, resp, len(respBody))
+		span.SetAttributes(attribute.Int("github.cost", spent))
+		if c.tokens != nil {
+			c.tokens.observe(resp)
 		}
 
 		// HTTP 429 — explicit rate limit.
 		if resp.StatusCode == http.StatusTooManyRequests {
-			if attempt < maxRetries {
-				sleepForRateLimit(attempt, resp.Header.Get("Retry-After"), resp)
+			if c.rotateForRateLimit(resp) {
+				continue
+			}
+			if retry, err := c.waitForRateLimit(&attempt, maxRetries, resp); err != nil {
+				return err
+			} else if retry {
 				continue
 			}
 			retryAfter := resp.Header.Get("Retry-After")
@@ -191,8 +503,12 @@ func (c *Client) Do(req Request, result any) error {
 		if resp.StatusCode == http.StatusForbidden {
 			bodyLower := strings.ToLower(string(respBody))
 			if strings.Contains(bodyLower, "rate limit") || strings.Contains(bodyLower, "abuse") {
-				if attempt < maxRetries {
-					sleepForRateLimit(attempt, resp.Header.Get("Retry-After"), resp)
+				if c.rotateForRateLimit(resp) {
+					continue
+				}
+				if retry, err := c.waitForRateLimit(&attempt, maxRetries, resp); err != nil {
+					return err
+				} else if retry {
 					continue
 				}
 				return &RateLimitError{
@@ -204,7 +520,10 @@ func (c *Client) Do(req Request, result any) error {
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("graphql HTTP %d: %s", resp.StatusCode, string(respBody))
+			if c.retryTransient(attempt, maxRetries, write, nil, resp.StatusCode, fmt.Sprintf("graphql HTTP %d", resp.StatusCode)) {
+				continue
+			}
+			return withCategory(fmt.Errorf("graphql HTTP %d: %s", resp.StatusCode, string(respBody)), statusCategory(resp.StatusCode))
 		}
 
 		var gqlResp graphqlResponse
@@ -214,23 +533,34 @@ func (c *Client) Do(req Request, result any) error {
 
 		// GraphQL-level rate limit error (HTTP 200 but error message).
 		if isRateLimitGraphQLError(&gqlResp) {
-			if attempt < maxRetries {
-				sleepForRateLimit(attempt, resp.Header.Get("Retry-After"), resp)
+			if c.rotateForRateLimit(resp) {
+				continue
+			}
+			if retry, err := c.waitForRateLimit(&attempt, maxRetries, resp); err != nil {
+				return err
+			} else if retry {
 				continue
 			}
 			msgs := make([]string, len(gqlResp.Errors))
 			for i, e := range gqlResp.Errors {
 				msgs[i] = e.Message
 			}
-			return fmt.Errorf("graphql rate limit exhausted after %d retries: %s", maxRetries, strings.Join(msgs, "; "))
+			return withCategory(fmt.Errorf("graphql rate limit exhausted after %d retries: %s", maxRetries, strings.Join(msgs, "; ")), ErrRateLimited)
 		}
 
-		if len(gqlResp.Errors) > 0 {
-			msgs := make([]string, len(gqlResp.Errors))
-			for i, e := range gqlResp.Errors {
-				msgs[i] = e.Message
+		if p, ok := result.(*partialResponse); ok {
+			p.Errors = gqlResp.Errors
+			if len(gqlResp.Data) == 0 || string(gqlResp.Data) == "null" {
+				return nil
 			}
-			return fmt.Errorf("graphql errors: %s", strings.Join(msgs, "; "))
+			if err := json.Unmarshal(gqlResp.Data, &p.Data); err != nil {
+				return fmt.Errorf("unmarshal data: %w", err)
+			}
+			return nil
+		}
+
+		if len(gqlResp.Errors) > 0 {
+			return c.graphQLError(gqlResp.Errors)
 		}
 
 		if result != nil {
@@ -245,13 +575,108 @@ func (c *Client) Do(req Request, result any) error {
 	return fmt.Errorf("graphql request failed after %d retries", maxRetries)
 }
 
+// operationName returns the operation type ("query" or "mutation") and the
+// first top-level field of a GraphQL document, e.g. ("mutation",
+// "addProjectV2ItemById"), for naming trace spans.
+func operationName(query string) (opType, field string) {
+	q := strings.TrimSpace(query)
+	opType = "query"
+	if strings.HasPrefix(q, "mutation") {
+		opType = "mutation"
+	}
+	// Skip the variable list, then take the identifier after the first brace.
+	depth, i := 0, 0
+	for ; i < len(q); i++ {
+		if q[i] == '(' {
+			depth++
+		} else if q[i] == ')' {
+			depth--
+		} else if q[i] == '{' && depth == 0 {
+			break
+		}
+	}
+	rest := strings.TrimLeft(q[min(i+1, len(q)):], " \t\r\n")
+	if alias, field, ok := strings.Cut(rest, ":"); ok && !strings.ContainsAny(alias, "({") {
+		rest = strings.TrimLeft(field, " \t\r\n") // an aliased field, as DoBatch sends
+	}
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if end < 0 {
+		end = len(rest)
+	}
+	return opType, rest[:end]
+}
+
+// recordResponse annotates span with the outcome of one HTTP attempt,
+// including the rate-limit budget GitHub reports in response headers.
+func recordResponse(span trace.Span, attempt int, resp *http.Response, size int) {
+	attrs := []attribute.KeyValue{
+		attribute.Int("http.response.status_code", resp.StatusCode),
+		attribute.Int("http.response.body.size", size),
+		attribute.Int("github.attempts", attempt+1),
+	}
+	for _, h := range []string{"x-ratelimit-remaining", "x-ratelimit-used", "x-ratelimit-limit"} {
+		if n, err := strconv.Atoi(resp.Header.Get(h)); err == nil {
+			attrs = append(attrs, attribute.Int("github."+strings.TrimPrefix(h, "x-"), n))
+		}
+	}
+	span.SetAttributes(attrs...)
+	if attempt > 0 {
+		span.AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", attempt+1)))
+	}
+}
+
 // DoREST sends a REST API request to the GitHub REST API.
 // method is the HTTP method (GET, POST, PATCH, DELETE).
 // path is the URL path (e.g., "/users/{owner}/projects/{number}/views").
 // body is marshaled to JSON for the request body (nil for GET/DELETE).
 // result is unmarshaled from the JSON response (nil to ignore response body).
 // It automatically retries on rate-limit errors with exponential back-off.
-func (c *Client) DoREST(method, path string, body any, result any) error {
+func (c *Client) DoREST(method, path string, body any, result any) (err error) {
+	span := tracing.Start("REST "+method+" "+path,
+		attribute.String("http.request.method", method),
+		attribute.String("url.path", path),
+	)
+	defer func() { tracing.EndWithError(span, err) }()
+	return c.doREST(method, path, body, result, nil, span)
+}
+
+// GetRESTPage GETs one page of a paginated REST list into result and
+// returns the path of the next page, from the Link header's rel="next"
+// entry, or "" on the last page. GitHub pages many lists, such as Dependabot
+// alerts and security advisories, by cursor rather than page number, so
+// follow the returned path rather than counting pages.
+func (c *Client) GetRESTPage(path string, result any) (next string, err error) {
+	span := tracing.Start("REST GET "+path,
+		attribute.String("http.request.method", http.MethodGet),
+		attribute.String("url.path", path),
+	)
+	defer func() { tracing.EndWithError(span, err) }()
+	var header http.Header
+	if err := c.doREST(http.MethodGet, path, nil, result, &header, span); err != nil {
+		return "", err
+	}
+	return c.nextPage(header.Get("Link")), nil
+}
+
+// nextPage returns the rel="next" URL of a Link header as a path under the
+// REST endpoint, or "" if there is none.
+func (c *Client) nextPage(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		target = strings.Trim(strings.TrimSpace(target), "<>")
+		return strings.TrimPrefix(target, strings.TrimSuffix(c.restURL(), "/"))
+	}
+	return ""
+}
+
+// doREST sends a REST request; if header is non-nil, it receives the
+// successful response's headers.
+func (c *Client) doREST(method, path string, body any, result any, header *http.Header, span trace.Span) error {
 	var reqJSON []byte
 	if body != nil {
 		b, err := json.Marshal(body)
@@ -261,44 +686,81 @@ func (c *Client) DoREST(method, path string, body any, result any) error {
 		reqJSON = b
 	}
 
-	maxRetries := c.MaxRetries
-	if maxRetries <= 0 {
-		maxRetries = DefaultMaxRetries
-	}
+	maxRetries := max(c.MaxRetries, 0)
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
-		c.pace()
+		release, err := c.acquire()
+		if err != nil {
+			return err
+		}
+		if err := c.pace(); err != nil {
+			release()
+			return err
+		}
+		if method != http.MethodGet {
+			if err := c.throttleWrites(1); err != nil {
+				release()
+				return err
+			}
+		}
 
 		var reqBody io.Reader
 		if reqJSON != nil {
 			reqBody = bytes.NewReader(reqJSON)
 		}
 
-		url := RESTEndpoint + path
-		httpReq, err := http.NewRequestWithContext(context.Background(), method, url, reqBody)
+		url := c.restURL() + path
+		sendCtx, cancelSend := c.requestContext(method != http.MethodGet)
+		cancel := func() { cancelSend(); release() }
+		httpReq, err := http.NewRequestWithContext(sendCtx, method, url, reqBody)
 		if err != nil {
+			cancel()
 			return fmt.Errorf("create REST request: %w", err)
 		}
 		httpReq.Header.Set("Accept", "application/vnd.github+json")
-		httpReq.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		httpReq.Header.Set("X-GitHub-Api-Version", RESTAPIVersion)
 		if body != nil {
 			httpReq.Header.Set("Content-Type", "application/json")
 		}
 
+		write := method != http.MethodGet
+		seq := c.debugRequest("REST "+method+" "+path, attempt, reqJSON)
+		if c.OnRequest != nil {
+			c.OnRequest(httpReq)
+		}
+		start := time.Now()
 		resp, err := c.HTTPClient.Do(httpReq)
 		if err != nil {
-			return fmt.Errorf("REST request: %w", err)
+			cancel()
+			err = fmt.Errorf("REST request: %w", c.timeoutError(err))
+			debugFailure(seq, err)
+			if c.retryTransient(attempt, maxRetries, write, err, 0, err.Error()) {
+				continue
+			}
+			return err
 		}
 
 		respBody, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
+		cancel()
 		if err != nil {
-			return fmt.Errorf("read REST response: %w", err)
+			err = fmt.Errorf("read REST response: %w", c.timeoutError(err))
+			debugFailure(seq, err)
+			if c.retryTransient(attempt, maxRetries, write, err, 0, err.Error()) {
+				continue
+			}
+			return err
 		}
+		c.received(seq, resp, respBody, time.Since(start), false)
+		recordResponse(span, attempt, resp, len(respBody))
 
 		if resp.StatusCode == http.StatusTooManyRequests {
-			if attempt < maxRetries {
-				sleepForRateLimit(attempt, resp.Header.Get("Retry-After"), resp)
+			if c.rotateForRateLimit(resp) {
+				continue
+			}
+			if retry, err := c.waitForRateLimit(&attempt, maxRetries, resp); err != nil {
+				return err
+			} else if retry {
 				continue
 			}
 			retryAfter := resp.Header.Get("Retry-After")
@@ -313,8 +775,12 @@ func (c *Client) DoREST(method, path string, body any, result any) error {
 		if resp.StatusCode == http.StatusForbidden {
 			bodyLower := strings.ToLower(string(respBody))
 			if strings.Contains(bodyLower, "rate limit") || strings.Contains(bodyLower, "abuse") {
-				if attempt < maxRetries {
-					sleepForRateLimit(attempt, resp.Header.Get("Retry-After"), resp)
+				if c.rotateForRateLimit(resp) {
+					continue
+				}
+				if retry, err := c.waitForRateLimit(&attempt, maxRetries, resp); err != nil {
+					return err
+				} else if retry {
 					continue
 				}
 				return &RateLimitError{
@@ -326,7 +792,10 @@ func (c *Client) DoREST(method, path string, body any, result any) error {
 		}
 
 		if resp.StatusCode >= 400 {
-			return fmt.Errorf("REST %s %s HTTP %d: %s", method, path, resp.StatusCode, string(respBody))
+			if c.retryTransient(attempt, maxRetries, write, nil, resp.StatusCode, fmt.Sprintf("REST %s %s HTTP %d", method, path, resp.StatusCode)) {
+				continue
+			}
+			return withCategory(fmt.Errorf("REST %s %s HTTP %d: %s%s", method, path, resp.StatusCode, string(respBody), c.acceptedPermissions(resp.StatusCode, resp.Header.Get("X-Accepted-GitHub-Permissions"))), statusCategory(resp.StatusCode))
 		}
 
 		if result != nil && len(respBody) > 0 {
@@ -334,6 +803,9 @@ func (c *Client) DoREST(method, path string, body any, result any) error {
 				return fmt.Errorf("unmarshal REST response: %w", err)
 			}
 		}
+		if header != nil {
+			*header = resp.Header
+		}
 
 		return nil
 	}
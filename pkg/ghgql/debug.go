@@ -0,0 +1,112 @@
+package ghgql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxDebugBody caps how much of a response body the debug log keeps.
+const maxDebugBody = 4096
+
+// debugLog is where every client logs its requests, if anywhere.
+var debugLog struct {
+	sync.Mutex
+	w   io.Writer
+	seq int // numbers requests, to pair concurrent ones with their responses
+}
+
+// SetDebugLog makes every client write each GraphQL and REST request it
+// sends to w: the query or path, the variables or body, the points a query
+// should cost (see EstimateCost), the response status and body, and the
+// rate-limit points it cost. Tokens never appear: they
+// travel in a header, which isn't logged, and are masked should one turn
+// up in a body. A nil w turns the log off.
+func SetDebugLog(w io.Writer) {
+	debugLog.Lock()
+	defer debugLog.Unlock()
+	debugLog.w = w
+}
+
+// debugRequest logs a request about to be sent and returns its number for
+// debugResponse: what names the endpoint, e.g. "graphql query" or "REST GET
+// /orgs/x", and body is its JSON body.
+func (c *Client) debugRequest(what string, attempt int, body []byte) int {
+	debugLog.Lock()
+	defer debugLog.Unlock()
+	if debugLog.w == nil {
+		return 0
+	}
+	debugLog.seq++
+	fmt.Fprintf(debugLog.w, "=== #%d %s %s", debugLog.seq, time.Now().Format(time.RFC3339), what)
+	if attempt > 0 {
+		fmt.Fprintf(debugLog.w, " (attempt %d)", attempt+1)
+	}
+	fmt.Fprintln(debugLog.w)
+	var req Request
+	if strings.HasPrefix(what, "graphql") && json.Unmarshal(body, &req) == nil {
+		fmt.Fprintln(debugLog.w, c.redact(strings.TrimSpace(req.Query)))
+		if len(req.Variables) > 0 {
+			vars, _ := json.MarshalIndent(req.Variables, "", "  ")
+			fmt.Fprintf(debugLog.w, "variables: %s\n", c.redact(string(vars)))
+		}
+		fmt.Fprintf(debugLog.w, "estimated cost: %d\n", EstimateCost(req))
+	} else if len(body) > 0 {
+		fmt.Fprintf(debugLog.w, "body: %s\n", c.redact(string(body)))
+	}
+	return debugLog.seq
+}
+
+// debugResponse logs the response to request number seq, with its cost
+// as Spent counts it.
+func (c *Client) debugResponse(seq int, r Response) {
+	debugLog.Lock()
+	defer debugLog.Unlock()
+	if debugLog.w == nil {
+		return
+	}
+	line := fmt.Sprintf("--- #%d HTTP %d in %s", seq, r.StatusCode, r.Duration.Round(time.Millisecond))
+	if r.Cost > 0 {
+		line += fmt.Sprintf(", cost %d", r.Cost)
+	}
+	if remaining := r.Header.Get("x-ratelimit-remaining"); remaining != "" {
+		line += fmt.Sprintf(", %s remaining", remaining)
+	}
+	fmt.Fprintln(debugLog.w, line)
+	body := r.Body
+	if len(body) > maxDebugBody {
+		body = append(body[:maxDebugBody:maxDebugBody], fmt.Sprintf("... (%d bytes)", len(body))...)
+	}
+	var indented bytes.Buffer
+	if json.Indent(&indented, body, "", "  ") == nil {
+		body = indented.Bytes()
+	}
+	fmt.Fprintf(debugLog.w, "%s\n\n", c.redact(string(body)))
+}
+
+// debugFailure logs that request number seq got no response.
+func debugFailure(seq int, err error) {
+	debugLog.Lock()
+	defer debugLog.Unlock()
+	if debugLog.w != nil {
+		fmt.Fprintf(debugLog.w, "--- #%d failed: %v\n\n", seq, err)
+	}
+}
+
+// redact masks the client's tokens in s.
+func (c *Client) redact(s string) string {
+	tokens := []string{c.Token}
+	if c.tokens != nil {
+		tokens = c.tokens.tokens
+	}
+	for _, t := range tokens {
+		if t != "" {
+			s = strings.ReplaceAll(s, t, "[REDACTED]")
+		}
+	}
+	return s
+}
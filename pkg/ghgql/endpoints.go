@@ -0,0 +1,132 @@
+package ghgql
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Endpoints returns the GraphQL and REST API URLs to use: Endpoint and
+// RESTEndpoint, unless GITHUB_GRAPHQL_URL or GITHUB_API_URL point at a
+// GitHub Enterprise Server instance. GitHub Actions sets both on GHES
+// runners; when only one is set, the other is derived from it
+// (https://HOST/api/v3 and https://HOST/api/graphql).
+func Endpoints() (graphql, rest string) {
+	graphql = strings.TrimSuffix(os.Getenv("GITHUB_GRAPHQL_URL"), "/")
+	rest = strings.TrimSuffix(os.Getenv("GITHUB_API_URL"), "/")
+	switch {
+	case graphql == "" && rest == "":
+		return Endpoint, RESTEndpoint
+	case graphql == "":
+		graphql = strings.TrimSuffix(rest, "/v3") + "/graphql"
+		if rest == RESTEndpoint {
+			graphql = Endpoint
+		}
+	case rest == "":
+		rest = strings.TrimSuffix(graphql, "/graphql") + "/v3"
+		if graphql == Endpoint {
+			rest = RESTEndpoint
+		}
+	}
+	return graphql, rest
+}
+
+// Host returns the web host of the GitHub instance the API URLs point at:
+// github.com, or the GitHub Enterprise Server host.
+func Host() string {
+	_, rest := Endpoints()
+	u, err := url.Parse(rest)
+	if err != nil || u.Host == "" || u.Host == "api.github.com" {
+		return "github.com"
+	}
+	return u.Host
+}
+
+// Enterprise reports whether the client talks to GitHub Enterprise Server
+// rather than github.com.
+func (c *Client) Enterprise() bool {
+	return c.graphQLURL() != Endpoint
+}
+
+func (c *Client) graphQLURL() string {
+	if c.GraphQLURL == "" {
+		return Endpoint
+	}
+	return c.GraphQLURL
+}
+
+func (c *Client) restURL() string {
+	if c.RESTURL == "" {
+		return RESTEndpoint
+	}
+	return c.RESTURL
+}
+
+// Features lists which parts of the Projects API the server supports.
+// github.com has them all; GitHub Enterprise Server gained them release by
+// release, so on older instances some commands can't work.
+type Features struct {
+	ProjectsV2     bool // ProjectV2 boards at all
+	CopyProject    bool // copyProjectV2, for creating a board from a template
+	LinkRepository bool // linkProjectV2ToRepository
+	StatusUpdates  bool // createProjectV2StatusUpdate
+	Views          bool // ProjectV2View, for reading and recreating views
+}
+
+// Missing names the features the server lacks.
+func (f *Features) Missing() []string {
+	var out []string
+	for _, m := range []struct {
+		ok   bool
+		name string
+	}{
+		{f.ProjectsV2, "Projects (ProjectV2)"},
+		{f.CopyProject, "copying projects (copyProjectV2)"},
+		{f.LinkRepository, "linking repositories (linkProjectV2ToRepository)"},
+		{f.StatusUpdates, "project status updates (createProjectV2StatusUpdate)"},
+		{f.Views, "project views (ProjectV2View)"},
+	} {
+		if !m.ok {
+			out = append(out, m.name)
+		}
+	}
+	return out
+}
+
+// Features asks the server, by schema introspection, which parts of the
+// Projects API it supports.
+func (c *Client) Features() (*Features, error) {
+	query := `query {
+		project: __type(name: "ProjectV2") { name }
+		view: __type(name: "ProjectV2View") { name }
+		mutation: __type(name: "Mutation") { fields { name } }
+	}`
+
+	type namedType struct {
+		Name string `json:"name"`
+	}
+	var result struct {
+		Project  *namedType `json:"project"`
+		View     *namedType `json:"view"`
+		Mutation *struct {
+			Fields []namedType `json:"fields"`
+		} `json:"mutation"`
+	}
+	if err := c.Do(Request{Query: query}, &result); err != nil {
+		return nil, fmt.Errorf("schema introspection: %w", err)
+	}
+	mutations := make(map[string]bool)
+	if result.Mutation != nil {
+		for _, f := range result.Mutation.Fields {
+			mutations[f.Name] = true
+		}
+	}
+	return &Features{
+		ProjectsV2:     result.Project != nil,
+		CopyProject:    mutations["copyProjectV2"],
+		LinkRepository: mutations["linkProjectV2ToRepository"],
+		StatusUpdates:  mutations["createProjectV2StatusUpdate"],
+		Views:          result.View != nil,
+	}, nil
+}
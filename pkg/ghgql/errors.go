@@ -0,0 +1,126 @@
+package ghgql
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Categories of API error, for errors.Is. Do and REST wrap their errors with
+// one of these when GitHub's response says which it is; the message is
+// unchanged.
+var (
+	ErrAuth        = errors.New("authentication or permission error")
+	ErrNotFound    = errors.New("not found")
+	ErrRateLimited = errors.New("rate limit exhausted")
+)
+
+// Is makes a *RateLimitError match ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// GraphQLError is the error Do returns when GitHub answers a GraphQL
+// request with errors (DoBatch returns one per failed mutation). Check
+// Errors' types rather than matching the message, which GitHub may reword:
+//
+//	var gqlErr *ghgql.GraphQLError
+//	if errors.As(err, &gqlErr) && gqlErr.HasType("NOT_FOUND") { ... }
+//
+// It also matches ErrAuth, ErrNotFound, and ErrRateLimited with errors.Is,
+// by the types of its errors.
+type GraphQLError struct {
+	Errors []ResponseError
+	msg    string
+}
+
+// ResponseError is one entry of a GraphQL response's errors.
+type ResponseError struct {
+	Type    string `json:"type"` // e.g. NOT_FOUND, FORBIDDEN, RATE_LIMITED; "" if GitHub gave none
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"` // the field that failed, e.g. ["m3", "item"]
+}
+
+// graphQLError builds the error for a response's errors.
+func (c *Client) graphQLError(errs []ResponseError) *GraphQLError {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Message
+	}
+	return &GraphQLError{Errors: errs, msg: "graphql errors: " + c.withFineGrainedHint(strings.Join(msgs, "; "))}
+}
+
+func (e *GraphQLError) Error() string { return e.msg }
+
+// Is makes a *GraphQLError match the category of any of its errors.
+func (e *GraphQLError) Is(target error) bool {
+	for _, r := range e.Errors {
+		if category := graphQLCategory(r.Type); category != nil && category == target {
+			return true
+		}
+	}
+	return false
+}
+
+// HasType reports whether any of the errors has one of types.
+func (e *GraphQLError) HasType(types ...string) bool {
+	return slices.ContainsFunc(e.Errors, func(r ResponseError) bool {
+		return slices.Contains(types, r.Type)
+	})
+}
+
+// categorized is an API error tagged with its category.
+type categorized struct {
+	msg      string
+	category error
+}
+
+func (e *categorized) Error() string { return e.msg }
+func (e *categorized) Unwrap() error { return e.category }
+
+// withCategory tags err with category; a nil category leaves err alone.
+func withCategory(err, category error) error {
+	if category == nil {
+		return err
+	}
+	return &categorized{msg: err.Error(), category: category}
+}
+
+// statusCategory maps an HTTP error status to its category, if any.
+func statusCategory(status int) error {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrAuth
+	case http.StatusNotFound:
+		return ErrNotFound
+	}
+	return nil
+}
+
+// graphQLCategory maps the type of a GraphQL error to its category, if any.
+func graphQLCategory(typ string) error {
+	switch typ {
+	case "NOT_FOUND":
+		return ErrNotFound
+	case "FORBIDDEN", "INSUFFICIENT_SCOPES", "UNAUTHORIZED":
+		return ErrAuth
+	case "RATE_LIMITED":
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// Category names the kind of err: "auth", "not-found", "rate-limit", or ""
+// for anything else.
+func Category(err error) string {
+	switch {
+	case errors.Is(err, ErrAuth):
+		return "auth"
+	case errors.Is(err, ErrNotFound):
+		return "not-found"
+	case errors.Is(err, ErrRateLimited):
+		return "rate-limit"
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package ghgql
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestGraphQLErrorCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int // HTTP status; 200 if zero
+		body     string
+		want     string   // Category
+		wantIs   []error  // categories errors.Is matches
+		wantType []string // types HasType finds; nil if not a *GraphQLError
+	}{
+		{
+			name:     "not found",
+			body:     `{"data":{"node":null},"errors":[{"type":"NOT_FOUND","path":["node"],"message":"Could not resolve to a node with the global id of 'PVT_gone'."}]}`,
+			want:     "not-found",
+			wantIs:   []error{ErrNotFound},
+			wantType: []string{"NOT_FOUND"},
+		},
+		{
+			name:     "forbidden",
+			body:     `{"data":{"organization":{"projectV2":null}},"errors":[{"type":"FORBIDDEN","path":["organization","projectV2"],"message":"Resource not accessible by integration"}]}`,
+			want:     "auth",
+			wantIs:   []error{ErrAuth},
+			wantType: []string{"FORBIDDEN"},
+		},
+		{
+			name:     "insufficient scopes",
+			body:     `{"errors":[{"type":"INSUFFICIENT_SCOPES","message":"Your token has not been granted the required scopes to execute this query. The 'id' field requires one of the following scopes: ['read:project']."}]}`,
+			want:     "auth",
+			wantIs:   []error{ErrAuth},
+			wantType: []string{"INSUFFICIENT_SCOPES"},
+		},
+		{
+			name:   "rate limited",
+			body:   `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded for user ID 1."}]}`,
+			want:   "rate-limit",
+			wantIs: []error{ErrRateLimited},
+		},
+		{
+			name:   "secondary rate limit, untyped",
+			body:   `{"errors":[{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}]}`,
+			want:   "rate-limit",
+			wantIs: []error{ErrRateLimited},
+		},
+		{
+			name:     "not found and forbidden",
+			body:     `{"data":{"m0":null,"m1":null},"errors":[{"type":"NOT_FOUND","path":["m0"],"message":"Could not resolve to ProjectV2Item."},{"type":"FORBIDDEN","path":["m1"],"message":"Resource not accessible by integration"}]}`,
+			want:     "auth",
+			wantIs:   []error{ErrAuth, ErrNotFound},
+			wantType: []string{"NOT_FOUND", "FORBIDDEN"},
+		},
+		{
+			name:     "other type",
+			body:     `{"errors":[{"type":"UNPROCESSABLE","message":"Content already exists in this project"}]}`,
+			wantType: []string{"UNPROCESSABLE"},
+		},
+		{
+			name:     "no type",
+			body:     `{"errors":[{"message":"Something went wrong while executing your query."}]}`,
+			wantType: []string{""},
+		},
+		{
+			name:   "HTTP 401",
+			status: http.StatusUnauthorized,
+			body:   `{"message":"Bad credentials","documentation_url":"https://docs.github.com/graphql"}`,
+			want:   "auth",
+			wantIs: []error{ErrAuth},
+		},
+	}
+	categories := []error{ErrAuth, ErrNotFound, ErrRateLimited}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				io.WriteString(w, tt.body)
+			})
+			err := c.Do(Request{Query: `query { viewer { login } }`}, nil)
+			if err == nil {
+				t.Fatal("Do returned no error")
+			}
+			if got := Category(err); got != tt.want {
+				t.Errorf("Category(%v) = %q, want %q", err, got, tt.want)
+			}
+			for _, category := range categories {
+				want := slices.Contains(tt.wantIs, category)
+				if got := errors.Is(err, category); got != want {
+					t.Errorf("errors.Is(err, %v) = %v, want %v", category, got, want)
+				}
+			}
+			var gqlErr *GraphQLError
+			if !errors.As(err, &gqlErr) {
+				if tt.wantType != nil {
+					t.Fatalf("error %v (%T) is not a *GraphQLError", err, err)
+				}
+				return
+			}
+			if tt.wantType == nil {
+				t.Fatalf("error %v is a *GraphQLError, want another kind", err)
+			}
+			for _, typ := range tt.wantType {
+				if !gqlErr.HasType(typ) {
+					t.Errorf("HasType(%q) = false, want true", typ)
+				}
+			}
+			if gqlErr.HasType("INTERNAL") {
+				t.Error(`HasType("INTERNAL") = true, want false`)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package ghgql
+
+import (
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultPageSize is what EstimateCost assumes for a first or last it
+// can't resolve: GitHub's largest page.
+const defaultPageSize = 100
+
+// variableDefault matches a variable definition with an integer default,
+// e.g. "$first: Int = 50".
+var variableDefault = regexp.MustCompile(`\$(\w+)\s*:\s*[\w!\[\]]+\s*=\s*(\d+)`)
+
+// fragmentDefinition matches the start of a named fragment's definition up
+// to its selection set, e.g. "fragment issueFields on Issue {".
+var fragmentDefinition = regexp.MustCompile(`\bfragment\s+(\w+)\s+on\s+\w+[^{]*\{`)
+
+// EstimateCost estimates the GraphQL points req will cost before it is
+// sent, the way GitHub works them out: every connection (a field with a
+// first or last argument) costs one request per node its enclosing
+// connections can return, and the query costs the total over 100, rounded,
+// and at least 1. Page sizes come from literals, req's variables, or their
+// defaults in the query; one that can't be resolved counts as 100. A named
+// fragment counts wherever it is spread. A mutation costs 1.
+//
+// The result is an upper bound for the page sizes asked for: GitHub charges
+// by what a query could return, not what it does, so it is usually exact.
+func EstimateCost(req Request) int {
+	if opType, _ := operationName(req.Query); opType == "mutation" {
+		return 1
+	}
+	q, fragments := cutFragments(req.Query)
+	start := strings.IndexByte(q, '{')
+	if start < 0 {
+		return 1
+	}
+	// Variable definitions precede the selection set; take their defaults
+	// for any variable req doesn't set.
+	vars := make(map[string]int)
+	if i := strings.IndexByte(q, '('); i >= 0 && i < start {
+		start = i + matching(q[i:], '(', ')')
+		for _, m := range variableDefault.FindAllStringSubmatch(q[i:start], -1) {
+			vars[m[1]], _ = strconv.Atoi(m[2])
+		}
+		if j := strings.IndexByte(q[start:], '{'); j >= 0 {
+			start += j
+		}
+	}
+	for name, v := range req.Variables {
+		if n, ok := pageSize(v); ok {
+			vars[name] = n
+		}
+	}
+
+	return max((selectionRequests(q[start:], vars, fragments, nil)+50)/100, 1)
+}
+
+// selectionRequests returns the requests the selection set q asks for,
+// counting each connection once per node its enclosing connections can
+// return. spreading holds the fragments being expanded, so a fragment that
+// spreads itself isn't followed forever.
+func selectionRequests(q string, vars map[string]int, fragments map[string]string, spreading map[string]bool) int {
+	requests := 0
+	nodes := []int{1} // how many nodes each open selection set can repeat for
+	pending := 0      // page size of the field whose selection set opens next
+	for i := 0; i < len(q); i++ {
+		switch c := q[i]; {
+		case c == '.' && strings.HasPrefix(q[i:], "..."):
+			name := q[i+3:]
+			name = name[len(name)-len(strings.TrimLeft(name, " \t\r\n")):]
+			end := strings.IndexFunc(name, func(r rune) bool { return r >= 128 || !isNameByte(byte(r)) })
+			if end < 0 {
+				end = len(name)
+			}
+			name = name[:end]
+			if body, ok := fragments[name]; ok && !spreading[name] {
+				inner := maps.Clone(spreading)
+				if inner == nil {
+					inner = make(map[string]bool)
+				}
+				inner[name] = true
+				requests += nodes[len(nodes)-1] * selectionRequests(body, vars, fragments, inner)
+			}
+			// "... on Type" is an inline fragment: its selection set
+			// opens next, and counts like any other.
+			i += 2
+			pending = 0
+		case c == '"' || c == '#':
+			i = skipStringOrComment(q, i)
+		case c == '(':
+			end := i + matching(q[i:], '(', ')')
+			pending = connectionSize(q[i:end], vars)
+			i = end - 1
+		case c == '{':
+			n := nodes[len(nodes)-1]
+			if pending > 0 {
+				requests += n
+				n *= pending
+			}
+			nodes = append(nodes, n)
+			pending = 0
+		case c == '}':
+			if len(nodes) > 1 {
+				nodes = nodes[:len(nodes)-1]
+			}
+			pending = 0
+		case isNameByte(c):
+			// A field without arguments: any page size seen belongs
+			// to an earlier field that had no selection set.
+			for i+1 < len(q) && isNameByte(q[i+1]) {
+				i++
+			}
+			pending = 0
+		}
+	}
+	return requests
+}
+
+// cutFragments returns q without its named fragment definitions, and each
+// fragment's selection set by name.
+func cutFragments(q string) (string, map[string]string) {
+	var fragments map[string]string
+	var rest strings.Builder
+	for {
+		m := fragmentDefinition.FindStringSubmatchIndex(q)
+		if m == nil {
+			break
+		}
+		open := m[1] - 1
+		end := open + matching(q[open:], '{', '}')
+		if fragments == nil {
+			fragments = make(map[string]string)
+		}
+		fragments[q[m[2]:m[3]]] = q[open:end]
+		rest.WriteString(q[:m[0]])
+		q = q[end:]
+	}
+	if fragments == nil {
+		return q, nil
+	}
+	rest.WriteString(q)
+	return rest.String(), fragments
+}
+
+// connectionSize returns the first or last argument in args (a field's
+// parenthesised argument list), or 0 if it has neither.
+func connectionSize(args string, vars map[string]int) int {
+	for _, name := range []string{"first", "last"} {
+		for rest := args; ; {
+			i := strings.Index(rest, name)
+			if i < 0 {
+				break
+			}
+			before := byte(' ')
+			if i > 0 {
+				before = rest[i-1]
+			}
+			rest = rest[i+len(name):]
+			value, ok := strings.CutPrefix(strings.TrimLeft(rest, " \t\r\n"), ":")
+			if isNameByte(before) || before == '$' || !ok {
+				continue
+			}
+			value = strings.TrimLeft(value, " \t\r\n")
+			end := strings.IndexFunc(value, func(r rune) bool { return !(r == '$' || r < 128 && isNameByte(byte(r))) })
+			if end < 0 {
+				end = len(value)
+			}
+			if v, isVar := strings.CutPrefix(value[:end], "$"); isVar {
+				if n, ok := vars[v]; ok {
+					return n
+				}
+				return defaultPageSize
+			}
+			if n, err := strconv.Atoi(value[:end]); err == nil {
+				return n
+			}
+			return defaultPageSize
+		}
+	}
+	return 0
+}
+
+// pageSize returns v as a page size, if it is a number.
+func pageSize(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+// matching returns the index just past the close that balances the open at
+// s[0], or len(s) if there is none.
+func matching(s string, open, close byte) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '#':
+			i = skipStringOrComment(s, i)
+		case open:
+			depth++
+		case close:
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(s)
+}
+
+// skipStringOrComment returns the index of the last byte of the string
+// literal or comment starting at s[i].
+func skipStringOrComment(s string, i int) int {
+	if s[i] == '#' {
+		if j := strings.IndexByte(s[i:], '\n'); j >= 0 {
+			return i + j
+		}
+		return len(s) - 1
+	}
+	if strings.HasPrefix(s[i:], `"""`) {
+		if j := strings.Index(s[i+3:], `"""`); j >= 0 {
+			return i + 3 + j + 2
+		}
+		return len(s) - 1
+	}
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '"':
+			return j
+		}
+	}
+	return len(s) - 1
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
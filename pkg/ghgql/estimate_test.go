@@ -0,0 +1,184 @@
+package ghgql
+
+import "testing"
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		want      int
+	}{
+		{
+			// GitHub's first documented example: 1 + 100 + 100×50 = 5,101
+			// requests, 51 points.
+			name: "documented: repositories, issues, labels",
+			query: `query {
+  viewer {
+    login
+    repositories(first: 100) {
+      edges {
+        node {
+          id
+          issues(first: 50) {
+            edges {
+              node {
+                id
+                labels(first: 60) {
+                  edges {
+                    node {
+                      id
+                      name
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`,
+			want: 51,
+		},
+		{
+			// GitHub's second documented example, with aliases:
+			// 1 + 50 + 50×20 + 50 + 50×20 + 1 = 2,102 requests, 21 points.
+			name: "documented: pull requests, issues, and followers",
+			query: `query {
+  viewer {
+    repositories(first: 50) {
+      edges {
+        repository:node {
+          name
+          pullRequests(first: 20) {
+            edges {
+              pullRequest:node {
+                title
+                comments(first: 10) {
+                  edges {
+                    comment:node {
+                      bodyHTML
+                    }
+                  }
+                }
+              }
+            }
+          }
+          issues(first: 20) {
+            totalCount
+            edges {
+              issue:node {
+                title
+                bodyHTML
+                comments(first: 10) {
+                  edges {
+                    comment:node {
+                      bodyHTML
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+    followers(first: 10) {
+      edges {
+        follower:node {
+          login
+        }
+      }
+    }
+  }
+}`,
+			want: 21,
+		},
+		{
+			name:  "nested first and last",
+			query: `{ repository(owner: "o", name: "r") { issues(last: 100) { nodes { comments(first: 100) { nodes { reactions(last: 10) { totalCount } } } } } } }`,
+			want:  101, // 1 + 100 + 100×100
+		},
+		{
+			name:      "page sizes from variables",
+			query:     `query($n: Int!, $m: Int!) { viewer { repositories(first: $n) { nodes { issues(first: $m) { nodes { labels(first: 10) { nodes { name } } } } } } } }`,
+			variables: map[string]any{"n": 20, "m": float64(50)},
+			want:      10, // 1 + 20 + 20×50
+		},
+		{
+			name:  "page size from a variable's default",
+			query: `query($n: Int = 20, $m: Int = 50) { viewer { repositories(first: $n) { nodes { issues(first: $m) { nodes { labels(first: 10) { nodes { name } } } } } } } }`,
+			want:  10,
+		},
+		{
+			name:      "a variable overrides its default",
+			query:     `query($n: Int = 20, $m: Int = 50) { viewer { repositories(first: $n) { nodes { issues(first: $m) { nodes { labels(first: 10) { nodes { name } } } } } } } }`,
+			variables: map[string]any{"m": 10},
+			want:      2, // 1 + 20 + 20×10
+		},
+		{
+			name:  "missing page size counts as 100",
+			query: `query($n: Int!) { viewer { repositories(first: $n) { nodes { issues(first: 50) { nodes { labels(first: 10) { nodes { name } } } } } } } }`,
+			want:  51, // 1 + 100 + 100×50
+		},
+		{
+			name:  "field arguments without a page size",
+			query: `{ repository(owner: "o", name: "r") { issue(number: 1) { comments(first: 100) { nodes { id } } } } }`,
+			want:  1,
+		},
+		{
+			name:  "inline fragment",
+			query: `{ node(id: "x") { ... on Repository { issues(first: 100) { nodes { comments(first: 100) { nodes { reactions(first: 10) { totalCount } } } } } } } }`,
+			want:  101, // 1 + 100 + 100×100
+		},
+		{
+			name: "named fragment spread twice",
+			query: `query { repository(owner: "o", name: "r") {
+  issues(first: 100) { nodes { ...threadFields } }
+  pullRequests(first: 50) { nodes { ...threadFields } }
+} }
+fragment threadFields on Comment { comments(first: 10) { nodes { id } } labels(first: 10) { nodes { name } } }`,
+			want: 3, // 1 + 1 + 100×2 + 50×2 = 302
+		},
+		{
+			name: "named fragment defined first",
+			query: `fragment threadFields on Comment { comments(first: 10) { nodes { id } } labels(first: 10) { nodes { name } } }
+query { repository(owner: "o", name: "r") {
+  issues(first: 100) { nodes { ...threadFields } }
+  pullRequests(first: 50) { nodes { ...threadFields } }
+} }`,
+			want: 3,
+		},
+		{
+			name: "fragment spreading a fragment",
+			query: `query { viewer { repositories(first: 100) { nodes { ...repo } } } }
+fragment repo on Repository { issues(first: 100) { nodes { ...issue } } }
+fragment issue on Issue { labels(first: 10) { nodes { name } } }`,
+			want: 101, // 1 + 100 + 100×100
+		},
+		{
+			name:  "strings and comments are skipped",
+			query: "{\n  # labels(first: 100) {\n  search(query: \"is:open first: 100 {\", type: ISSUE, first: 10) { nodes { id } }\n}",
+			want:  1,
+		},
+		{
+			name:  "no connections",
+			query: `{ viewer { login } }`,
+			want:  1,
+		},
+		{
+			name:  "mutation",
+			query: `mutation { addComment(input: {subjectId: "x", body: "hi"}) { commentEdge { node { id } } } }`,
+			want:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateCost(Request{Query: tt.query, Variables: tt.variables}); got != tt.want {
+				t.Errorf("EstimateCost = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package ghgql
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// roundTripFunc lets a plain function serve a client's requests.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// fakeTransport answers requests with handler, as a server would.
+func fakeTransport(handler http.HandlerFunc) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	})
+}
+
+// newFakeClient returns a client whose requests are answered by handler,
+// without pacing or retries.
+func newFakeClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	c := NewClientWithTransport("test-token", fakeTransport(handler))
+	c.MinDelay = 0
+	c.MaxRetries = 0
+	c.RetryBackoff = 0
+	return c
+}
@@ -0,0 +1,94 @@
+package ghgql
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// IsFineGrained reports whether token is a fine-grained personal access
+// token. Unlike classic tokens, their permissions are granted per resource
+// owner and can't be listed, so access problems only show up as errors.
+func IsFineGrained(token string) bool {
+	return strings.HasPrefix(strings.TrimSpace(token), "github_pat_")
+}
+
+// fineGrainedHint is appended to errors a fine-grained token gets for
+// resources it wasn't granted.
+const fineGrainedHint = "fine-grained token: its resource owner must be the organization owning the board, with the Projects permission (Read and write to sync) and read access to the items' repositories (Issues, Pull requests, Metadata); boards owned by a user need a classic token"
+
+// withFineGrainedHint explains msgs, GraphQL error messages, when the
+// token is fine-grained and GitHub refused it a resource.
+func (c *Client) withFineGrainedHint(msg string) string {
+	if IsFineGrained(c.Token) && strings.Contains(strings.ToLower(msg), "not accessible by personal access token") {
+		return msg + " (" + fineGrainedHint + ")"
+	}
+	return msg
+}
+
+// acceptedPermissions describes the fine-grained permissions a refused REST
+// request needed, from GitHub's X-Accepted-GitHub-Permissions header, e.g.
+// "organization_projects=write".
+func (c *Client) acceptedPermissions(status int, header string) string {
+	if header == "" || !IsFineGrained(c.Token) || (status != http.StatusForbidden && status != http.StatusNotFound) {
+		return ""
+	}
+	return fmt.Sprintf(" (fine-grained token lacks permission; GitHub accepts: %s)", strings.ReplaceAll(header, ";", " or"))
+}
+
+// ProjectAccess is what the client's token may do with an owner's boards.
+type ProjectAccess struct {
+	IsOrg bool // the owner is an organization
+	Read  bool // the owner's boards can be listed
+	Write bool // the board asked about can be updated (false if none asked)
+}
+
+// CheckProjectAccess reports whether the client's token can read owner's
+// boards and, if projectID is set, update that board. It is meant for
+// fine-grained tokens, whose grants can only be discovered by trying; an
+// error means the check itself failed.
+func (c *Client) CheckProjectAccess(owner, projectID string) (*ProjectAccess, error) {
+	var result struct {
+		Owner *struct {
+			Typename string `json:"__typename"`
+		} `json:"repositoryOwner"`
+	}
+	err := c.Do(Request{
+		Query:     `query($login: String!) { repositoryOwner(login: $login) { __typename } }`,
+		Variables: map[string]any{"login": owner},
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Owner == nil {
+		return nil, withCategory(fmt.Errorf("owner %s not found", owner), ErrNotFound)
+	}
+	access := &ProjectAccess{IsOrg: result.Owner.Typename == "Organization"}
+
+	field := "user"
+	if access.IsOrg {
+		field = "organization"
+	}
+	err = c.Do(Request{
+		Query:     `query($login: String!) { ` + field + `(login: $login) { projectsV2(first: 1) { totalCount } } }`,
+		Variables: map[string]any{"login": owner},
+	}, nil)
+	access.Read = err == nil
+	if !access.Read || projectID == "" {
+		return access, nil
+	}
+
+	var project struct {
+		Node *struct {
+			ViewerCanUpdate bool `json:"viewerCanUpdate"`
+		} `json:"node"`
+	}
+	err = c.Do(Request{
+		Query:     `query($id: ID!) { node(id: $id) { ... on ProjectV2 { viewerCanUpdate } } }`,
+		Variables: map[string]any{"id": projectID},
+	}, &project)
+	if err == nil && project.Node != nil {
+		access.Write = project.Node.ViewerCanUpdate
+	}
+	return access, nil
+}
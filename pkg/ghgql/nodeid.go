@@ -0,0 +1,98 @@
+package ghgql
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// NextIDHeader asks the API to return new-format global node IDs, even for
+// objects looked up by a legacy ID.
+const NextIDHeader = "X-Github-Next-Global-ID"
+
+// legacyIDPayload matches the decoded form of a legacy node ID, e.g.
+// "05:Issue123456" or "011:PullRequest123456".
+var legacyIDPayload = regexp.MustCompile(`^0\d+:[A-Za-z]+`)
+
+// IsLegacyID reports whether id is a legacy-format global node ID (base64 of
+// "NN:TypeName<id>", such as "MDU6SXNzdWUxMjM0NTY="). New-format IDs carry a
+// type prefix instead, such as "I_kwDO..." or "PVTI_lADO...".
+func IsLegacyID(id string) bool {
+	if id == "" || strings.Contains(id, "_") {
+		return false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(id)
+	if err != nil {
+		if decoded, err = base64.RawStdEncoding.DecodeString(id); err != nil {
+			return false
+		}
+	}
+	return legacyIDPayload.Match(decoded)
+}
+
+// NextIDs returns the new-format ID of every legacy ID in ids, looked up 100
+// at a time. IDs that are already new-format, and legacy IDs for objects that
+// no longer exist, are absent from the result. A batch the API rejects
+// (typically because one of its objects was deleted) is retried one ID at a
+// time.
+func NextIDs(c *Client, ids []string) (map[string]string, error) {
+	var legacy []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if IsLegacyID(id) && !seen[id] {
+			seen[id] = true
+			legacy = append(legacy, id)
+		}
+	}
+
+	query := `query($ids: [ID!]!) { nodes(ids: $ids) { id } }`
+	next := make(map[string]string, len(legacy))
+	for start := 0; start < len(legacy); start += 100 {
+		batch := legacy[start:min(start+100, len(legacy))]
+		resolved, err := nextIDs(c, query, batch)
+		if err != nil && len(batch) == 1 {
+			continue
+		}
+		if err != nil {
+			for _, id := range batch {
+				if one, err := nextIDs(c, query, []string{id}); err == nil && one[0] != "" {
+					next[id] = one[0]
+				}
+			}
+			continue
+		}
+		for i, id := range resolved {
+			if id != "" {
+				next[batch[i]] = id
+			}
+		}
+	}
+	return next, nil
+}
+
+// nextIDs looks up one batch of legacy IDs, returning the new IDs in order
+// ("" where the API returned no object).
+func nextIDs(c *Client, query string, batch []string) ([]string, error) {
+	var result struct {
+		Nodes []*struct {
+			ID string `json:"id"`
+		} `json:"nodes"`
+	}
+	err := c.Do(Request{
+		Query:     query,
+		Variables: map[string]any{"ids": batch},
+		Header:    http.Header{NextIDHeader: {"1"}},
+	}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("resolving legacy node IDs: %w", err)
+	}
+	ids := make([]string, len(batch))
+	for i, n := range result.Nodes {
+		if i < len(batch) && n != nil {
+			ids[i] = n.ID
+		}
+	}
+	return ids, nil
+}
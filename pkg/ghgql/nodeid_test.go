@@ -0,0 +1,128 @@
+package ghgql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsLegacyID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{id: "MDU6SXNzdWUxMjM0NTY=", want: true}, // 05:Issue123456
+		{id: base64.StdEncoding.EncodeToString([]byte("011:PullRequest42")), want: true},
+		{id: base64.RawStdEncoding.EncodeToString([]byte("05:Issue7")), want: true},
+		{id: "I_kwDOABCDEF", want: false},
+		{id: "PVTI_lADOAbc", want: false},
+		{id: "", want: false},
+		{id: "not base64!", want: false},
+		{id: base64.StdEncoding.EncodeToString([]byte("hello world")), want: false},
+		{id: base64.StdEncoding.EncodeToString([]byte("5:Issue7")), want: false},
+	}
+	for _, tt := range tests {
+		if got := IsLegacyID(tt.id); got != tt.want {
+			t.Errorf("IsLegacyID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+// legacyID returns the legacy node ID of issue n.
+func legacyID(n int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("05:Issue%d", n)))
+}
+
+func TestNextIDs(t *testing.T) {
+	deleted := legacyID(3)
+	var batches [][]string
+	c := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header[NextIDHeader]; len(got) != 1 || got[0] != "1" {
+			t.Errorf("request without %s", NextIDHeader)
+		}
+		var req struct {
+			Variables struct {
+				IDs []string `json:"ids"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		ids := req.Variables.IDs
+		batches = append(batches, ids)
+		// Like GitHub, reject a whole batch that names a deleted object.
+		if len(ids) > 1 && strings.Contains(strings.Join(ids, ","), deleted) {
+			fmt.Fprint(w, `{"errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a node"}]}`)
+			return
+		}
+		nodes := make([]any, len(ids))
+		for i, id := range ids {
+			if id != deleted {
+				nodes[i] = map[string]string{"id": "I_new_" + id}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"nodes": nodes}})
+	})
+
+	one, two := legacyID(1), legacyID(2)
+	got, err := NextIDs(c, []string{one, "I_kwDOalready", two, one})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{one: "I_new_" + one, two: "I_new_" + two}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NextIDs = %v, want %v", got, want)
+	}
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Errorf("batches = %v, want one batch of the two distinct legacy IDs", batches)
+	}
+
+	// A rejected batch is retried one ID at a time, dropping the deleted one.
+	batches = nil
+	got, err = NextIDs(c, []string{one, deleted, two})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NextIDs with a deleted object = %v, want %v", got, want)
+	}
+	if len(batches) != 4 {
+		t.Errorf("sent %d requests, want the batch and then 3 single lookups", len(batches))
+	}
+}
+
+func TestNextIDsBatchesOf100(t *testing.T) {
+	var sizes []int
+	c := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				IDs []string `json:"ids"`
+			} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		sizes = append(sizes, len(req.Variables.IDs))
+		nodes := make([]any, len(req.Variables.IDs))
+		for i, id := range req.Variables.IDs {
+			nodes[i] = map[string]string{"id": "new-" + id}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"nodes": nodes}})
+	})
+	var ids []string
+	for i := range 250 {
+		ids = append(ids, legacyID(i))
+	}
+	got, err := NextIDs(c, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 250 {
+		t.Errorf("resolved %d IDs, want 250", len(got))
+	}
+	if !reflect.DeepEqual(sizes, []int{100, 100, 50}) {
+		t.Errorf("batch sizes = %v, want [100 100 50]", sizes)
+	}
+}
@@ -0,0 +1,37 @@
+package ghgql
+
+import "maps"
+
+// PageInfo is a connection's pageInfo, selected as
+// `pageInfo { hasNextPage endCursor }`.
+type PageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// Paginate sends req once per page of a connection, passing each page's
+// data, decoded into a T, to page, which returns the connection's
+// PageInfo. req's query takes the cursor as `$cursor: String` (`after:
+// $cursor`). Paginate stops after the last page or at the first error;
+// page can stop it early, once it has found what it wants, by returning a
+// PageInfo without a next page.
+func Paginate[T any](c *Client, req Request, page func(result *T) (PageInfo, error)) error {
+	vars := maps.Clone(req.Variables)
+	if vars == nil {
+		vars = make(map[string]any)
+	}
+	for {
+		var result T
+		if err := c.Do(Request{Query: req.Query, Variables: vars, Header: req.Header}, &result); err != nil {
+			return err
+		}
+		info, err := page(&result)
+		if err != nil {
+			return err
+		}
+		if !info.HasNextPage || info.EndCursor == "" || info.EndCursor == vars["cursor"] {
+			return nil
+		}
+		vars["cursor"] = info.EndCursor
+	}
+}
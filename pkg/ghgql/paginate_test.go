@@ -0,0 +1,123 @@
+package ghgql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	// pages are served by cursor; the last has no next page.
+	pages := map[string]string{
+		"":   `{"data": {"items": {"nodes": ["a", "b"], "pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}`,
+		"c1": `{"data": {"items": {"nodes": ["c", "d"], "pageInfo": {"hasNextPage": true, "endCursor": "c2"}}}}`,
+		"c2": `{"data": {"items": {"nodes": ["e"], "pageInfo": {"hasNextPage": false, "endCursor": "c3"}}}}`,
+	}
+	type page struct {
+		Items struct {
+			Nodes    []string `json:"nodes"`
+			PageInfo PageInfo `json:"pageInfo"`
+		} `json:"items"`
+	}
+	errStop := errors.New("stop")
+	tests := []struct {
+		name        string
+		fail        string // cursor whose page answers with an error
+		repeat      bool   // every page claims the same endCursor
+		stopAfter   int    // pages after which the callback returns an empty PageInfo
+		errAfter    int    // pages after which the callback returns errStop
+		wantItems   []string
+		wantCursors []any
+		wantErr     string
+	}{
+		{
+			name:        "all pages",
+			wantItems:   []string{"a", "b", "c", "d", "e"},
+			wantCursors: []any{nil, "c1", "c2"},
+		},
+		{
+			name:        "callback stops early",
+			stopAfter:   2,
+			wantItems:   []string{"a", "b", "c", "d"},
+			wantCursors: []any{nil, "c1"},
+		},
+		{
+			name:        "error partway through",
+			fail:        "c2",
+			wantItems:   []string{"a", "b", "c", "d"},
+			wantCursors: []any{nil, "c1", "c2"},
+			wantErr:     "something went wrong",
+		},
+		{
+			name:        "callback error",
+			errAfter:    1,
+			wantItems:   []string{"a", "b"},
+			wantCursors: []any{nil},
+			wantErr:     "stop",
+		},
+		{
+			name:        "repeated cursor stops",
+			repeat:      true,
+			wantItems:   []string{"a", "b", "a", "b"},
+			wantCursors: []any{nil, "c1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cursors []any
+			c := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var req Request
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatal(err)
+				}
+				if req.Variables["owner"] != "o" {
+					t.Errorf("variables = %v, want the caller's kept", req.Variables)
+				}
+				cursors = append(cursors, req.Variables["cursor"])
+				cursor, _ := req.Variables["cursor"].(string)
+				switch {
+				case tt.fail != "" && cursor == tt.fail:
+					fmt.Fprint(w, `{"errors": [{"message": "something went wrong"}]}`)
+				case tt.repeat:
+					fmt.Fprint(w, pages[""])
+				default:
+					fmt.Fprint(w, pages[cursor])
+				}
+			})
+
+			vars := map[string]any{"owner": "o"}
+			var items []string
+			n := 0
+			err := Paginate(c, Request{Query: "query($owner: String!, $cursor: String) { items(after: $cursor) { nodes pageInfo { hasNextPage endCursor } } }", Variables: vars}, func(result *page) (PageInfo, error) {
+				n++
+				items = append(items, result.Items.Nodes...)
+				if n == tt.errAfter {
+					return PageInfo{}, errStop
+				}
+				if n == tt.stopAfter {
+					return PageInfo{}, nil
+				}
+				return result.Items.PageInfo, nil
+			})
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Paginate: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Paginate error = %v, want %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(items, tt.wantItems) {
+				t.Errorf("items = %q, want %q", items, tt.wantItems)
+			}
+			if !reflect.DeepEqual(cursors, tt.wantCursors) {
+				t.Errorf("cursors sent = %v, want %v", cursors, tt.wantCursors)
+			}
+			if _, ok := vars["cursor"]; ok {
+				t.Errorf("caller's variables gained a cursor: %v", vars)
+			}
+		})
+	}
+}
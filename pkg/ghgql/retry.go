@@ -0,0 +1,61 @@
+package ghgql
+
+import (
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Transient-failure retry settings.
+const (
+	DefaultRetryBackoff = time.Second      // first wait before retrying a transient failure
+	maxRetryBackoff     = 30 * time.Second // longest wait between transient retries
+)
+
+// transient reports whether a failed attempt may succeed if sent again: a
+// network error or a 5xx response. A write is only retried when the
+// connection was never made, since otherwise GitHub may have applied it
+// and a retry could apply it twice (a second comment, a second board).
+func transient(write bool, err error, status int) bool {
+	if err != nil {
+		if errors.Is(err, ErrNotRecorded) {
+			return false
+		}
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return !write
+	}
+	return !write && status >= http.StatusInternalServerError
+}
+
+// backoff returns the wait before retry number attempt (0-based): the
+// client's RetryBackoff doubled each time, capped at maxRetryBackoff, with
+// jitter so many clients failing together don't retry together.
+func (c *Client) backoff(attempt int) time.Duration {
+	base := c.RetryBackoff
+	if base <= 0 {
+		base = DefaultRetryBackoff
+	}
+	d := base << uint(min(attempt, 10))
+	if d > maxRetryBackoff || d <= 0 {
+		d = maxRetryBackoff
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryTransient waits before retrying a transient failure described by
+// what, reporting whether the caller should retry: the failure is
+// transient, attempts remain, and the client's context isn't done.
+func (c *Client) retryTransient(attempt, maxRetries int, write bool, err error, status int, what string) bool {
+	if attempt >= maxRetries || !transient(write, err, status) || c.Context().Err() != nil {
+		return false
+	}
+	wait := c.backoff(attempt)
+	slog.Warn("Request failed, retrying", "reason", what, "attempt", attempt+1, "attempts", maxRetries+1, "wait", wait.Round(100*time.Millisecond))
+	return sleep(c.Context(), wait) == nil
+}
@@ -0,0 +1,183 @@
+package ghgql
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTransient(t *testing.T) {
+	dial := fmt.Errorf("graphql request: %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+	read := fmt.Errorf("read response: %w", &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")})
+	tests := []struct {
+		name   string
+		write  bool
+		err    error
+		status int
+		want   bool
+	}{
+		{name: "read: dial error", err: dial, want: true},
+		{name: "write: dial error", write: true, err: dial, want: true},
+		{name: "read: connection reset", err: read, want: true},
+		{name: "write: connection reset", write: true, err: read, want: false},
+		{name: "read: other network error", err: errors.New("unexpected EOF"), want: true},
+		{name: "write: other network error", write: true, err: errors.New("unexpected EOF"), want: false},
+		{name: "read: not in the cassette", err: fmt.Errorf("replay: %w", ErrNotRecorded), want: false},
+		{name: "read: 500", status: http.StatusInternalServerError, want: true},
+		{name: "read: 502", status: http.StatusBadGateway, want: true},
+		{name: "write: 502", write: true, status: http.StatusBadGateway, want: false},
+		{name: "read: 404", status: http.StatusNotFound, want: false},
+		{name: "read: 403", status: http.StatusForbidden, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transient(tt.write, tt.err, tt.status); got != tt.want {
+				t.Errorf("transient(%v, %v, %d) = %v, want %v", tt.write, tt.err, tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		attempt  int
+		min, max time.Duration
+	}{
+		{name: "first retry", base: time.Second, attempt: 0, min: 500 * time.Millisecond, max: time.Second},
+		{name: "doubles", base: time.Second, attempt: 2, min: 2 * time.Second, max: 4 * time.Second},
+		{name: "capped", base: time.Second, attempt: 8, min: maxRetryBackoff / 2, max: maxRetryBackoff},
+		{name: "no overflow", base: time.Second, attempt: 100, min: maxRetryBackoff / 2, max: maxRetryBackoff},
+		{name: "default base", base: 0, attempt: 1, min: DefaultRetryBackoff, max: 2 * DefaultRetryBackoff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{RetryBackoff: tt.base}
+			for range 50 {
+				if d := c.backoff(tt.attempt); d < tt.min || d > tt.max {
+					t.Fatalf("backoff(%d) = %v, want within [%v, %v]", tt.attempt, d, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestDoRetriesTransientFailures(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+	tests := []struct {
+		name       string
+		query      string
+		maxRetries int
+		fail       func(attempt int) (status int, err error) // status 0 and nil err answer normally
+		wantSent   int
+		wantErr    bool
+	}{
+		{
+			name:       "query retried after 502",
+			query:      "{ viewer { login } }",
+			maxRetries: 3,
+			fail: func(attempt int) (int, error) {
+				if attempt == 0 {
+					return http.StatusBadGateway, nil
+				}
+				return 0, nil
+			},
+			wantSent: 2,
+		},
+		{
+			name:       "query gives up after MaxRetries",
+			query:      "{ viewer { login } }",
+			maxRetries: 2,
+			fail:       func(int) (int, error) { return http.StatusServiceUnavailable, nil },
+			wantSent:   3,
+			wantErr:    true,
+		},
+		{
+			name:       "MaxRetries 0 never retries",
+			query:      "{ viewer { login } }",
+			maxRetries: 0,
+			fail:       func(int) (int, error) { return http.StatusBadGateway, nil },
+			wantSent:   1,
+			wantErr:    true,
+		},
+		{
+			name:       "negative MaxRetries still sends once",
+			query:      "{ viewer { login } }",
+			maxRetries: -1,
+			fail:       func(int) (int, error) { return 0, nil },
+			wantSent:   1,
+		},
+		{
+			name:       "query not retried after 404",
+			query:      "{ viewer { login } }",
+			maxRetries: 3,
+			fail:       func(int) (int, error) { return http.StatusNotFound, nil },
+			wantSent:   1,
+			wantErr:    true,
+		},
+		{
+			name:       "mutation not retried after 502",
+			query:      "mutation { addComment(input: {}) { clientMutationId } }",
+			maxRetries: 3,
+			fail:       func(int) (int, error) { return http.StatusBadGateway, nil },
+			wantSent:   1,
+			wantErr:    true,
+		},
+		{
+			name:       "mutation not retried once sent",
+			query:      "mutation { addComment(input: {}) { clientMutationId } }",
+			maxRetries: 3,
+			fail:       func(int) (int, error) { return 0, readErr },
+			wantSent:   1,
+			wantErr:    true,
+		},
+		{
+			name:       "mutation retried when never sent",
+			query:      "mutation { addComment(input: {}) { clientMutationId } }",
+			maxRetries: 3,
+			fail: func(attempt int) (int, error) {
+				if attempt == 0 {
+					return 0, dialErr
+				}
+				return 0, nil
+			},
+			wantSent: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := 0
+			ok := fakeTransport(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"data": {"viewer": {"login": "a"}}}`)
+			})
+			c := NewClientWithTransport("test-token", roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				attempt := sent
+				sent++
+				status, err := tt.fail(attempt)
+				if err != nil {
+					return nil, err
+				}
+				if status != 0 {
+					return &http.Response{StatusCode: status, Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+				}
+				return ok.RoundTrip(req)
+			}))
+			c.MinDelay = 0
+			c.MaxRetries = tt.maxRetries
+			c.RetryBackoff = time.Nanosecond
+
+			err := c.Do(Request{Query: tt.query}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Do error = %v, want error: %v", err, tt.wantErr)
+			}
+			if sent != tt.wantSent {
+				t.Errorf("sent %d requests, want %d", sent, tt.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package ghgql
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateTime is a DateTime scalar: an instant, sent as RFC 3339 in UTC.
+// It, Date, and GitTimestamp pass GitHub's time scalars as variables and
+// decode them from responses, e.g.
+//
+//	Variables: map[string]any{"since": ghgql.DateTime{Time: since}}
+//
+// for a `$since: DateTime` variable. A zero time is sent as null, as is a
+// nil pointer to one of them, so an optional filter needs no special case;
+// decoding null leaves the time zero.
+type DateTime struct{ time.Time }
+
+// Date is a Date scalar: a calendar day, sent as YYYY-MM-DD in the time's
+// own location, as a board's date fields hold.
+type Date struct{ time.Time }
+
+// GitTimestamp is a GitTimestamp scalar: a commit time, sent as RFC 3339
+// keeping its UTC offset, since a commit's offset is part of its record.
+type GitTimestamp struct{ time.Time }
+
+// dateLayout is how a Date is written.
+const dateLayout = "2006-01-02"
+
+func (t DateTime) MarshalJSON() ([]byte, error) {
+	return marshalTime(t.Time, time.RFC3339, true)
+}
+
+func (t *DateTime) UnmarshalJSON(data []byte) error {
+	return unmarshalTime(data, time.RFC3339, "DateTime", &t.Time)
+}
+
+func (t Date) MarshalJSON() ([]byte, error) {
+	return marshalTime(t.Time, dateLayout, false)
+}
+
+func (t *Date) UnmarshalJSON(data []byte) error {
+	return unmarshalTime(data, dateLayout, "Date", &t.Time)
+}
+
+func (t GitTimestamp) MarshalJSON() ([]byte, error) {
+	return marshalTime(t.Time, time.RFC3339, false)
+}
+
+func (t *GitTimestamp) UnmarshalJSON(data []byte) error {
+	return unmarshalTime(data, time.RFC3339, "GitTimestamp", &t.Time)
+}
+
+// marshalTime writes t in layout, converted to UTC first if utc, or null
+// if t is zero.
+func marshalTime(t time.Time, layout string, utc bool) ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	if utc {
+		t = t.UTC()
+	}
+	return json.Marshal(t.Format(layout))
+}
+
+// unmarshalTime parses a JSON string in layout into t; null leaves t zero.
+func unmarshalTime(data []byte, layout, scalar string, t *time.Time) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%s: %w", scalar, err)
+	}
+	if s == nil {
+		*t = time.Time{}
+		return nil
+	}
+	parsed, err := time.Parse(layout, *s)
+	if err != nil {
+		return fmt.Errorf("%s: %w", scalar, err)
+	}
+	*t = parsed
+	return nil
+}
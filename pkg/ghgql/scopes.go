@@ -0,0 +1,54 @@
+package ghgql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// TokenScopes returns the login the client's token belongs to and the OAuth
+// scopes GitHub reports for it. Fine-grained tokens and GitHub App tokens
+// have no scopes (their permissions are per repository and can't be listed
+// this way), so classic is false for them and scopes is nil.
+func (c *Client) TokenScopes() (login string, scopes []string, classic bool, err error) {
+	if err := c.pace(); err != nil {
+		return "", nil, false, err
+	}
+	ctx, cancel := c.requestContext(false)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.restURL()+"/user", nil)
+	if err != nil {
+		return "", nil, false, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", RESTAPIVersion)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("REST request: %w", c.timeoutError(err))
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("read REST response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, false, withCategory(fmt.Errorf("REST GET /user HTTP %d: %s", resp.StatusCode, body), statusCategory(resp.StatusCode))
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return "", nil, false, fmt.Errorf("unmarshal REST response: %w", err)
+	}
+	header, classic := resp.Header["X-Oauth-Scopes"]
+	if classic && len(header) > 0 {
+		for _, s := range strings.Split(header[0], ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+	return user.Login, scopes, classic, nil
+}
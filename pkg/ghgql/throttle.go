@@ -0,0 +1,73 @@
+package ghgql
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// GitHub's secondary rate limit on writes.
+const (
+	SecondaryLimitPoints = 2000 // secondary-limit points GitHub allows per minute
+	MutationPoints       = 5    // secondary-limit points each mutation (or REST write) costs
+)
+
+// newWriteBucket returns the bucket a client throttles its writes with.
+// The secondary limit applies per user, so the clients WithContext makes
+// share their parent's bucket rather than each getting the full limit.
+func newWriteBucket() *tokenBucket {
+	return &tokenBucket{capacity: SecondaryLimitPoints, perSecond: SecondaryLimitPoints / 60.0}
+}
+
+// tokenBucket is a token bucket that lets callers reserve points ahead:
+// a reservation it can't cover yet drives the balance negative, and the
+// caller waits until the refill has paid it back, so waiting callers are
+// served in the order they asked.
+type tokenBucket struct {
+	mu        sync.Mutex
+	capacity  float64
+	perSecond float64 // refill rate
+	balance   float64
+	last      time.Time // when balance was last brought up to date
+
+	now func() time.Time // the clock; nil means time.Now
+}
+
+// reserve takes n points at now and returns how long the caller must wait
+// before spending them.
+func (b *tokenBucket) reserve(n float64, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.last.IsZero() {
+		b.balance = b.capacity
+	} else {
+		b.balance = min(b.capacity, b.balance+now.Sub(b.last).Seconds()*b.perSecond)
+	}
+	b.last = now
+	b.balance -= min(n, b.capacity)
+	if b.balance >= 0 {
+		return 0
+	}
+	return time.Duration(-b.balance / b.perSecond * float64(time.Second))
+}
+
+// throttleWrites waits until mutations more writes fit under the
+// secondary limit, or until the client's context is done. A client not
+// made by NewClient has no bucket and doesn't throttle.
+func (c *Client) throttleWrites(mutations int) error {
+	if c.writes == nil {
+		return nil
+	}
+	now := time.Now
+	if c.writes.now != nil {
+		now = c.writes.now
+	}
+	wait := c.writes.reserve(float64(mutations*MutationPoints), now())
+	if wait <= 0 {
+		return nil
+	}
+	if wait >= time.Second {
+		slog.Debug("Throttling writes to stay under GitHub's secondary rate limit", "wait", wait.Round(time.Second))
+	}
+	return sleep(c.Context(), wait)
+}
@@ -0,0 +1,106 @@
+package ghgql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	type step struct {
+		after time.Duration // since start
+		n     float64
+		want  time.Duration
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name:  "starts full",
+			steps: []step{{after: 0, n: 10, want: 0}},
+		},
+		{
+			name: "blocks once spent",
+			steps: []step{
+				{after: 0, n: 100, want: 0},
+				{after: 0, n: 10, want: time.Second},
+			},
+		},
+		{
+			name: "waiting callers queue in order",
+			steps: []step{
+				{after: 0, n: 100, want: 0},
+				{after: 0, n: 10, want: time.Second},
+				{after: 0, n: 10, want: 2 * time.Second},
+			},
+		},
+		{
+			name: "refills over time",
+			steps: []step{
+				{after: 0, n: 100, want: 0},
+				{after: 2 * time.Second, n: 20, want: 0},
+				{after: 2 * time.Second, n: 10, want: time.Second},
+			},
+		},
+		{
+			name: "refill stops at capacity",
+			steps: []step{
+				{after: 0, n: 100, want: 0},
+				{after: time.Hour, n: 100, want: 0},
+				{after: time.Hour, n: 10, want: time.Second},
+			},
+		},
+		{
+			name: "a reservation over capacity waits for a full bucket",
+			steps: []step{
+				{after: 0, n: 50, want: 0},
+				{after: 0, n: 500, want: 5 * time.Second},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &tokenBucket{capacity: 100, perSecond: 10}
+			for i, s := range tt.steps {
+				if got := b.reserve(s.n, start.Add(s.after)); got != s.want {
+					t.Errorf("step %d: reserve(%v) = %v, want %v", i, s.n, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestThrottleWrites(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // any wait ends at once, with ctx's error
+	c := (&Client{writes: &tokenBucket{capacity: 2 * MutationPoints, perSecond: 1, now: func() time.Time { return now }}}).WithContext(ctx)
+
+	if err := c.throttleWrites(2); err != nil {
+		t.Fatalf("first writes: %v, want no wait", err)
+	}
+	if err := c.throttleWrites(1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("write over the limit: %v, want a wait", err)
+	}
+	now = now.Add(3 * MutationPoints * time.Second) // pays back the debt, then one more write
+	if err := c.throttleWrites(1); err != nil {
+		t.Errorf("write after refill: %v, want no wait", err)
+	}
+
+	if err := (&Client{}).throttleWrites(1000); err != nil {
+		t.Errorf("client without a bucket: %v, want no throttling", err)
+	}
+}
+
+func TestWithContextSharesWriteBucket(t *testing.T) {
+	c := NewClient("test-token")
+	if c.writes == nil || c.WithContext(context.Background()).writes != c.writes {
+		t.Error("WithContext client doesn't share its parent's write bucket")
+	}
+	if NewClient("test-token").writes == c.writes {
+		t.Error("separate clients share a write bucket")
+	}
+}
@@ -0,0 +1,134 @@
+package ghgql
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// SplitTokens splits a comma-separated list of tokens, as GITHUB_TOKEN may
+// hold several.
+func SplitTokens(tokens string) []string {
+	var out []string
+	for _, t := range strings.Split(tokens, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// tokenPool rotates between several tokens, so a run that spends one
+// token's GraphQL budget carries on with the next instead of sleeping until
+// the reset. It is the oauth2.TokenSource of the client's transport, which
+// asks it for a token on every request.
+type tokenPool struct {
+	mu        sync.Mutex
+	tokens    []string
+	remaining []int       // last x-ratelimit-remaining seen, -1 if unknown
+	resetAt   []time.Time // when each token's budget resets
+	current   int
+}
+
+func newTokenPool(tokens []string) *tokenPool {
+	p := &tokenPool{
+		tokens:    tokens,
+		remaining: make([]int, len(tokens)),
+		resetAt:   make([]time.Time, len(tokens)),
+	}
+	for i := range p.remaining {
+		p.remaining[i] = -1
+	}
+	return p
+}
+
+// Token implements oauth2.TokenSource.
+func (p *tokenPool) Token() (*oauth2.Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return &oauth2.Token{AccessToken: p.tokens[p.current]}, nil
+}
+
+// sentWith returns the index of the token resp's request was sent with,
+// or the current token's if the request doesn't say.
+func (p *tokenPool) sentWith(resp *http.Response) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if resp.Request != nil {
+		auth := resp.Request.Header.Get("Authorization")
+		for i, t := range p.tokens {
+			if auth == "Bearer "+t {
+				return i
+			}
+		}
+	}
+	return p.current
+}
+
+// observe records the budget GitHub reported in resp for the token it was
+// sent with, and moves on to the next token once that one is spent.
+func (p *tokenPool) observe(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("x-ratelimit-remaining"))
+	if err != nil {
+		return
+	}
+	i := p.sentWith(resp)
+	p.mu.Lock()
+	p.remaining[i] = remaining
+	if epoch, err := strconv.ParseInt(resp.Header.Get("x-ratelimit-reset"), 10, 64); err == nil {
+		p.resetAt[i] = time.Unix(epoch, 0)
+	}
+	p.mu.Unlock()
+	if remaining == 0 {
+		p.rotate(i)
+	}
+}
+
+// rotate marks token spent as out of budget and, if it is still the
+// current token, switches to the next token with budget left, reporting
+// whether the pool has moved on to another token. A token whose budget has
+// reset counts as unspent.
+func (p *tokenPool) rotate(spent int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.remaining[spent] = 0
+	if p.current != spent {
+		return true // observe, or another request, already switched
+	}
+	now := time.Now()
+	for step := 1; step < len(p.tokens); step++ {
+		i := (p.current + step) % len(p.tokens)
+		if p.remaining[i] != 0 || now.After(p.resetAt[i]) {
+			slog.Info("Token is out of GraphQL budget, switching tokens",
+				"token", p.current+1, "tokens", len(p.tokens), "reset", p.resetAt[p.current].Local().Format("15:04:05 MST"), "next", i+1)
+			p.current = i
+			return true
+		}
+	}
+	return false
+}
+
+// rotateForRateLimit switches tokens after a rate-limit response that
+// says the token's primary budget is spent, if the client has another token
+// with budget left, so the request can be retried at once instead of after
+// a back-off. Secondary (abuse) limits count requests by the account behind
+// every token alike, so switching would only hit them again: those are
+// backed off instead.
+func (c *Client) rotateForRateLimit(resp *http.Response) bool {
+	return c.tokens != nil && primaryLimitSpent(resp) && c.tokens.rotate(c.tokens.sentWith(resp))
+}
+
+// primaryLimitSpent reports whether resp says the primary rate limit is
+// spent: x-ratelimit-remaining is 0, or it is a 429 naming when the budget
+// resets.
+func primaryLimitSpent(resp *http.Response) bool {
+	if resp.Header.Get("x-ratelimit-remaining") == "0" {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("x-ratelimit-reset") != ""
+}
@@ -0,0 +1,251 @@
+package ghgql
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSplitTokens(t *testing.T) {
+	got := SplitTokens(" a, ,b ,c,")
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitTokens = %q, want %q", got, want)
+	}
+}
+
+// budgetResponse is a response to a request sent with token, reporting
+// remaining points until reset.
+func budgetResponse(status int, token string, remaining int, reset time.Time) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Request:    &http.Request{Header: http.Header{"Authorization": {"Bearer " + token}}},
+	}
+	if remaining >= 0 {
+		resp.Header.Set("x-ratelimit-remaining", strconv.Itoa(remaining))
+	}
+	if !reset.IsZero() {
+		resp.Header.Set("x-ratelimit-reset", strconv.FormatInt(reset.Unix(), 10))
+	}
+	return resp
+}
+
+func currentToken(t *testing.T, p *tokenPool) string {
+	t.Helper()
+	tok, err := p.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok.AccessToken
+}
+
+func TestTokenPoolRotate(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	earlier := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		setup     func(p *tokenPool)
+		spent     int
+		wantOK    bool
+		wantToken string
+	}{
+		{
+			name:      "moves to the next token",
+			spent:     0,
+			wantOK:    true,
+			wantToken: "b",
+		},
+		{
+			name: "skips spent tokens",
+			setup: func(p *tokenPool) {
+				p.remaining[1], p.resetAt[1] = 0, later
+			},
+			spent:     0,
+			wantOK:    true,
+			wantToken: "c",
+		},
+		{
+			name: "wraps around",
+			setup: func(p *tokenPool) {
+				p.current = 2
+			},
+			spent:     2,
+			wantOK:    true,
+			wantToken: "a",
+		},
+		{
+			name: "a reset token counts as unspent",
+			setup: func(p *tokenPool) {
+				p.remaining[1], p.resetAt[1] = 0, later
+				p.remaining[2], p.resetAt[2] = 0, earlier
+			},
+			spent:     0,
+			wantOK:    true,
+			wantToken: "c",
+		},
+		{
+			name: "all spent",
+			setup: func(p *tokenPool) {
+				p.remaining[1], p.resetAt[1] = 0, later
+				p.remaining[2], p.resetAt[2] = 0, later
+			},
+			spent:     0,
+			wantOK:    false,
+			wantToken: "a",
+		},
+		{
+			name: "already switched away from the spent token",
+			setup: func(p *tokenPool) {
+				p.current = 1
+			},
+			spent:     0,
+			wantOK:    true,
+			wantToken: "b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTokenPool([]string{"a", "b", "c"})
+			if tt.setup != nil {
+				tt.setup(p)
+			}
+			if ok := p.rotate(tt.spent); ok != tt.wantOK {
+				t.Errorf("rotate(%d) = %v, want %v", tt.spent, ok, tt.wantOK)
+			}
+			if got := currentToken(t, p); got != tt.wantToken {
+				t.Errorf("current token = %q, want %q", got, tt.wantToken)
+			}
+			if p.remaining[tt.spent] != 0 {
+				t.Errorf("token %d not marked spent", tt.spent)
+			}
+		})
+	}
+}
+
+func TestTokenPoolObserve(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	p := newTokenPool([]string{"a", "b"})
+
+	p.observe(budgetResponse(http.StatusOK, "a", 10, reset))
+	if got := currentToken(t, p); got != "a" || p.remaining[0] != 10 || !p.resetAt[0].Equal(reset) {
+		t.Fatalf("after a response with budget left: token %q, remaining %d, reset %v", got, p.remaining[0], p.resetAt[0])
+	}
+
+	p.observe(budgetResponse(http.StatusOK, "a", 0, reset))
+	if got := currentToken(t, p); got != "b" {
+		t.Fatalf("after the budget ran out: token %q, want b", got)
+	}
+
+	// A late response to a request sent with a, say from another goroutine,
+	// is recorded against a and doesn't move the pool again.
+	p.observe(budgetResponse(http.StatusOK, "a", 0, reset))
+	if got := currentToken(t, p); got != "b" {
+		t.Errorf("after a late response for a: token %q, want b", got)
+	}
+	if p.remaining[1] != -1 {
+		t.Errorf("b's budget = %d, want it still unknown", p.remaining[1])
+	}
+
+	// Responses without budget headers change nothing.
+	p.observe(budgetResponse(http.StatusOK, "b", -1, time.Time{}))
+	if got := currentToken(t, p); got != "b" || p.remaining[1] != -1 {
+		t.Errorf("after a response without headers: token %q, remaining %d", got, p.remaining[1])
+	}
+}
+
+func TestRotateForRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	tests := []struct {
+		name string
+		resp *http.Response
+		want bool
+	}{
+		{name: "primary budget spent", resp: budgetResponse(http.StatusOK, "a", 0, reset), want: true},
+		{name: "429 with a reset", resp: budgetResponse(http.StatusTooManyRequests, "a", -1, reset), want: true},
+		{name: "429 without a reset", resp: budgetResponse(http.StatusTooManyRequests, "a", -1, time.Time{}), want: false},
+		{name: "403 secondary limit", resp: budgetResponse(http.StatusForbidden, "a", 4000, reset), want: false},
+		{name: "403 secondary limit without headers", resp: budgetResponse(http.StatusForbidden, "a", -1, time.Time{}), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{tokens: newTokenPool([]string{"a", "b"})}
+			if got := c.rotateForRateLimit(tt.resp); got != tt.want {
+				t.Errorf("rotateForRateLimit = %v, want %v", got, tt.want)
+			}
+			want := "a"
+			if tt.want {
+				want = "b"
+			}
+			if got := currentToken(t, c.tokens); got != want {
+				t.Errorf("current token = %q, want %q", got, want)
+			}
+		})
+	}
+
+	single := &Client{}
+	if single.rotateForRateLimit(budgetResponse(http.StatusOK, "a", 0, reset)) {
+		t.Error("a client with one token rotated")
+	}
+}
+
+func TestClientSwitchesTokens(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	tests := []struct {
+		name       string
+		respond    func(w http.ResponseWriter)
+		maxRetries int
+		wantSentTo []string
+		wantErr    bool
+	}{
+		{
+			name: "primary budget spent: retried at once with the next token",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("x-ratelimit-remaining", "0")
+				w.Header().Set("x-ratelimit-reset", strconv.FormatInt(reset.Unix(), 10))
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			maxRetries: 1,
+			wantSentTo: []string{"Bearer a", "Bearer b"},
+		},
+		{
+			// With no retries left, a back-off fails at once rather than
+			// sleeping through the test.
+			name: "secondary limit: backed off with the same token",
+			respond: func(w http.ResponseWriter) {
+				w.Header().Set("x-ratelimit-remaining", "4000")
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"message": "You have exceeded a secondary rate limit"}`)
+			},
+			wantSentTo: []string{"Bearer a"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentTo []string
+			c := NewClientWithTransport("a,b", fakeTransport(func(w http.ResponseWriter, r *http.Request) {
+				sentTo = append(sentTo, r.Header.Get("Authorization"))
+				if r.Header.Get("Authorization") == "Bearer a" {
+					tt.respond(w)
+					return
+				}
+				w.Header().Set("x-ratelimit-remaining", "4999")
+				fmt.Fprint(w, `{"data": {"viewer": {"login": "b"}}}`)
+			}))
+			c.MinDelay, c.MaxRetries = 0, tt.maxRetries
+
+			err := c.Do(Request{Query: "{ viewer { login } }"}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Do error = %v, want error: %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(sentTo, tt.wantSentTo) {
+				t.Errorf("requests sent with %q, want %q", sentTo, tt.wantSentTo)
+			}
+		})
+	}
+}
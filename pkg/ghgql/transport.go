@@ -0,0 +1,83 @@
+package ghgql
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default connection settings of DefaultTransport.
+const (
+	DefaultKeepAlive       = 30 * time.Second  // TCP keep-alive probe interval
+	DefaultIdleConnTimeout = 90 * time.Second  // how long an idle connection is kept for reuse
+	DefaultMaxIdleConns    = DefaultConcurrent // idle connections kept per host
+)
+
+// DefaultTransport returns the transport clients use when not given one
+// (see WithTransport): http.DefaultTransport's settings, proxy included,
+// with the connection settings from the environment:
+//
+//   - GITHUB_KEEP_ALIVE, the TCP keep-alive interval (default
+//     DefaultKeepAlive; 0 turns keep-alives off)
+//   - GITHUB_IDLE_CONN_TIMEOUT, how long an idle connection is kept for
+//     reuse (default DefaultIdleConnTimeout; 0 for no limit)
+//   - GITHUB_MAX_IDLE_CONNS, how many idle connections are kept per host
+//     (default DefaultMaxIdleConns, enough for MaxConcurrent requests to
+//     reuse theirs rather than each opening a new one)
+//
+// It is made once, on first use.
+func DefaultTransport() http.RoundTripper {
+	return defaultTransport()
+}
+
+var defaultTransport = sync.OnceValue(func() http.RoundTripper {
+	keepAlive := envDuration("GITHUB_KEEP_ALIVE", DefaultKeepAlive)
+	if keepAlive == 0 {
+		keepAlive = -1 // net.Dialer's "off"; its 0 means a default
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: keepAlive}).DialContext
+	t.IdleConnTimeout = envDuration("GITHUB_IDLE_CONN_TIMEOUT", DefaultIdleConnTimeout)
+	t.MaxIdleConnsPerHost = DefaultMaxIdleConns
+	if n, err := strconv.Atoi(os.Getenv("GITHUB_MAX_IDLE_CONNS")); err == nil && n > 0 {
+		t.MaxIdleConnsPerHost = n
+	}
+	t.MaxIdleConns = max(t.MaxIdleConns, t.MaxIdleConnsPerHost)
+	return t
+})
+
+// BaseTransport returns the transport requests from a client made with
+// opts go through before it adds its token: the one WithTransport names,
+// else DefaultTransport. Code making its own HTTP client for GitHub, such
+// as a go-github one, should use it with the options its clients get.
+func BaseTransport(opts ...Option) http.RoundTripper {
+	var o clientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.base != nil {
+		return o.base
+	}
+	return DefaultTransport()
+}
+
+// RequestTimeout returns the limit on each HTTP request: GITHUB_REQUEST_TIMEOUT
+// if set, else DefaultTimeout. 0 means no limit.
+func RequestTimeout() time.Duration {
+	if d := envDuration("GITHUB_REQUEST_TIMEOUT", DefaultTimeout); d >= 0 {
+		return d
+	}
+	return DefaultTimeout
+}
+
+// envDuration returns the environment variable name as a duration, or def
+// if it is unset or invalid.
+func envDuration(name string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(name)); err == nil {
+		return d
+	}
+	return def
+}
@@ -0,0 +1,170 @@
+package ghgql
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Response describes a response a client received, for OnResponse.
+type Response struct {
+	*http.Response        // its body already read and closed; see Body
+	Body           []byte // the response body
+	Duration       time.Duration
+	Cost           int // GraphQL points it cost (see Spent); 0 for REST
+
+	// RateLimit is the rateLimit the query selected (see
+	// Client.SelectRateLimit), or nil if it selected none.
+	RateLimit *RateLimit
+}
+
+// RateLimit is the GraphQL budget as a query's rateLimit selection reports
+// it, after that query's cost.
+type RateLimit struct {
+	Cost      int       `json:"cost"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"resetAt"`
+}
+
+// rateLimitSelection is what Client.SelectRateLimit adds to each query.
+const rateLimitSelection = "rateLimit { cost remaining resetAt }"
+
+// selectRateLimit adds rateLimitSelection to the end of query's top-level
+// selection set. Mutations, queries that already select rateLimit, and
+// documents it can't parse are returned unchanged.
+func selectRateLimit(query string) string {
+	if opType, _ := operationName(query); opType != "query" || strings.Contains(query, "rateLimit") {
+		return query
+	}
+	depth, open := 0, -1
+	for i := 0; i < len(query); i++ {
+		switch query[i] {
+		case '(':
+			if open < 0 {
+				depth++
+			}
+		case ')':
+			if open < 0 {
+				depth--
+			}
+		case '{':
+			if open < 0 && depth == 0 {
+				open = i
+			}
+			if open >= 0 {
+				depth++
+			}
+		case '}':
+			if open < 0 {
+				continue
+			}
+			if depth--; depth == 0 {
+				return query[:i] + "  " + rateLimitSelection + "\n" + query[i:]
+			}
+		}
+	}
+	return query
+}
+
+// usage totals the GraphQL points every client in the process has spent.
+var usage struct {
+	sync.Mutex
+	points, requests int
+	used             map[string]int // x-ratelimit-used last seen, by token window
+
+	selected *RateLimit // the latest rateLimit a query selected
+	since    int        // points spent since selected
+	drift    int        // points spent by others; see Drift
+}
+
+// Spent returns the GraphQL points spent by every client in the process so
+// far, and the number of GraphQL requests that spent them. A request's cost
+// is the rateLimit.cost its query selected, if it did; otherwise the rise in
+// x-ratelimit-used since the previous response in the same budget window,
+// which also counts anything else spending the same token meanwhile. The
+// first request in a window, whose rise can't be known, counts as 1.
+func Spent() (points, requests int) {
+	usage.Lock()
+	defer usage.Unlock()
+	return usage.points, usage.requests
+}
+
+// LastRateLimit returns the GraphQL budget as of the latest response whose
+// query selected rateLimit, by any client in the process; ok is false if
+// none has.
+func LastRateLimit() (rl RateLimit, ok bool) {
+	usage.Lock()
+	defer usage.Unlock()
+	if usage.selected == nil {
+		return RateLimit{}, false
+	}
+	return *usage.selected, true
+}
+
+// Drift returns how many GraphQL points something other than this process
+// has spent from the budget so far, as far as rateLimit selections show:
+// between two selections in the same budget window, the fall in remaining
+// beyond what this process's requests cost. It only grows while queries
+// select rateLimit (see Client.SelectRateLimit).
+func Drift() int {
+	usage.Lock()
+	defer usage.Unlock()
+	return usage.drift
+}
+
+// graphQLCost works out what a GraphQL response cost (see Spent), adds it
+// to the total, and returns it with the rateLimit the query selected, if
+// any.
+func graphQLCost(resp *http.Response, body []byte) (int, *RateLimit) {
+	usage.Lock()
+	defer usage.Unlock()
+	cost := 1
+	var selected struct {
+		Data struct {
+			RateLimit *RateLimit `json:"rateLimit"`
+		} `json:"data"`
+	}
+	if used, err := strconv.Atoi(resp.Header.Get("x-ratelimit-used")); err == nil {
+		if usage.used == nil {
+			usage.used = make(map[string]int)
+		}
+		window := resp.Header.Get("x-ratelimit-resource") + "@" + resp.Header.Get("x-ratelimit-reset")
+		if last, ok := usage.used[window]; ok && used >= last {
+			cost = used - last
+		}
+		usage.used[window] = used
+	}
+	var rl *RateLimit
+	if json.Unmarshal(body, &selected) == nil && selected.Data.RateLimit != nil {
+		rl = selected.Data.RateLimit
+		cost = rl.Cost
+	}
+	usage.points += cost
+	usage.requests++
+	usage.since += cost
+	if rl != nil {
+		if last := usage.selected; last != nil && last.ResetAt.Equal(rl.ResetAt) {
+			usage.drift += max(last.Remaining-rl.Remaining-usage.since, 0)
+		}
+		usage.selected, usage.since = rl, 0
+	}
+	return cost, rl
+}
+
+// received reports a response to the debug log and OnResponse and returns
+// its cost; graphQL says whether it answered a GraphQL request, whose cost is
+// counted (a REST response costs 0).
+func (c *Client) received(seq int, resp *http.Response, body []byte, elapsed time.Duration, graphQL bool) int {
+	r := Response{Response: resp, Body: body, Duration: elapsed}
+	if graphQL {
+		r.Cost, r.RateLimit = graphQLCost(resp, body)
+	}
+	c.debugResponse(seq, r)
+	if c.OnResponse != nil {
+		c.OnResponse(r)
+	}
+	return r.Cost
+}
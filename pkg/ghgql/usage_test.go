@@ -0,0 +1,93 @@
+package ghgql
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSelectRateLimit(t *testing.T) {
+	tests := []struct {
+		name, query, want string
+	}{
+		{
+			name:  "anonymous query",
+			query: "{ viewer { login } }",
+			want:  "{ viewer { login }   " + rateLimitSelection + "\n}",
+		},
+		{
+			name:  "variables with defaults and nested selections",
+			query: "query($n: Int = 10, $after: String) {\n  search(first: $n, after: $after) { nodes { id } }\n}",
+			want:  "query($n: Int = 10, $after: String) {\n  search(first: $n, after: $after) { nodes { id } }\n  " + rateLimitSelection + "\n}",
+		},
+		{
+			name:  "named query",
+			query: "query Items { node(id: \"x\") { id } }",
+			want:  "query Items { node(id: \"x\") { id }   " + rateLimitSelection + "\n}",
+		},
+		{
+			name:  "mutation",
+			query: "mutation($i: AddInput!) { add(input: $i) { id } }",
+			want:  "mutation($i: AddInput!) { add(input: $i) { id } }",
+		},
+		{
+			name:  "already selected",
+			query: "{ rateLimit { remaining } viewer { login } }",
+			want:  "{ rateLimit { remaining } viewer { login } }",
+		},
+		{
+			name:  "unbalanced",
+			query: "query { viewer { login }",
+			want:  "query { viewer { login }",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectRateLimit(tt.query); got != tt.want {
+				t.Errorf("selectRateLimit(%q) =\n%q\nwant\n%q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoRecordsCost(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	saved := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(saved) })
+
+	calls := 0
+	c := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway) // retried, and charged 1 point
+			return
+		}
+		io.WriteString(w, `{"data": {"viewer": {"login": "octocat"}, "rateLimit": {"cost": 7, "remaining": 4990, "resetAt": "2026-01-01T00:00:00Z"}}}`)
+	})
+	c.MaxRetries = 1
+	c.SelectRateLimit = true
+	if err := c.Do(Request{Query: "query { viewer { login } }"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended %d spans, want 1", len(spans))
+	}
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs["github.cost"].AsInt64(); got != 8 {
+		t.Errorf("github.cost = %d, want 8 (1 for the failed attempt + 7)", got)
+	}
+	if _, ok := attrs["github.cost.estimate"]; !ok {
+		t.Error("span has no github.cost.estimate")
+	}
+}
@@ -0,0 +1,50 @@
+package items
+
+import (
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+// DaysSince returns the number of whole days between t and now. A zero t
+// (e.g. a field the API did not return) yields -1 so callers can tell
+// "unknown" apart from "today".
+func DaysSince(t, now time.Time) int {
+	if t.IsZero() {
+		return -1
+	}
+	return int(now.Sub(t).Hours() / 24)
+}
+
+// AgeDays returns the number of days since the item was created.
+func AgeDays(item board.ProjectItemWithFields, now time.Time) int {
+	return DaysSince(item.CreatedAt, now)
+}
+
+// IdleDays returns the number of days since the item was last updated.
+func IdleDays(item board.ProjectItemWithFields, now time.Time) int {
+	return DaysSince(item.UpdatedAt, now)
+}
+
+// FilterAge keeps items whose age in days (since createdAt) is within
+// [minDays, maxDays]. A bound <= 0 is ignored. Items with an unknown
+// creation date are kept.
+func FilterAge(list []board.ProjectItemWithFields, minDays, maxDays int, now time.Time) []board.ProjectItemWithFields {
+	if minDays <= 0 && maxDays <= 0 {
+		return list
+	}
+	var kept []board.ProjectItemWithFields
+	for _, item := range list {
+		age := AgeDays(item, now)
+		if age >= 0 {
+			if minDays > 0 && age < minDays {
+				continue
+			}
+			if maxDays > 0 && age > maxDays {
+				continue
+			}
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
@@ -0,0 +1,103 @@
+package items
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/expr"
+)
+
+// ExprFields documents the variables available to --filter expressions,
+// for use in flag help text.
+const ExprFields = "item.{number,title,type,url,repo,private,state,author,labels,assignees,milestone," +
+	"ageDays,idleDays,comments,reactions,additions,deletions,changedFiles,size," +
+	"priorityWeight,priorityScore,fields}"
+
+// ExprEnv returns the expression environment for item: a single "item"
+// variable exposing its content metadata, computed columns, and board field
+// values (item.fields["Status"]). priorityWeight and priorityScore use the
+// built-in priority heuristic (DefaultPriorityConfig).
+func ExprEnv(item board.ProjectItemWithFields, now time.Time) map[string]any {
+	priority := DefaultPriorityConfig()
+	size := ""
+	if item.Type == "PullRequest" {
+		size = SizeBucket(item.Additions, item.Deletions)
+	}
+	return map[string]any{
+		"item": map[string]any{
+			"number":         item.Number,
+			"title":          item.Title,
+			"type":           item.Type,
+			"url":            item.URL,
+			"repo":           item.Repo,
+			"private":        item.Private,
+			"state":          item.State,
+			"author":         item.Author,
+			"labels":         item.Labels,
+			"assignees":      item.Assignees,
+			"milestone":      item.Milestone,
+			"createdAt":      item.CreatedAt,
+			"updatedAt":      item.UpdatedAt,
+			"ageDays":        AgeDays(item, now),
+			"idleDays":       IdleDays(item, now),
+			"comments":       item.Comments,
+			"reactions":      item.Reactions,
+			"additions":      item.Additions,
+			"deletions":      item.Deletions,
+			"changedFiles":   item.ChangedFiles,
+			"size":           size,
+			"priorityWeight": priority.LabelWeight(item),
+			"priorityScore":  priority.Score(item, now),
+			"fields":         item.Fields,
+		},
+	}
+}
+
+// FilterExpr keeps the items for which prog evaluates to true. An
+// evaluation error (e.g. comparing a string with a number) aborts the
+// filter, naming the first item it failed on.
+func FilterExpr(list []board.ProjectItemWithFields, prog *expr.Program, now time.Time) ([]board.ProjectItemWithFields, error) {
+	var out []board.ProjectItemWithFields
+	for _, item := range list {
+		keep, err := prog.EvalBool(ExprEnv(item, now))
+		if err != nil {
+			return nil, fmt.Errorf("item #%d %q: %w", item.Number, item.Title, err)
+		}
+		if keep {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// SortExpr orders list in place by the numeric value of prog for each item,
+// lowest first, or highest first when desc is set. Ties keep their current
+// order.
+func SortExpr(list []board.ProjectItemWithFields, prog *expr.Program, desc bool, now time.Time) error {
+	// Scores travel with their items rather than being looked up by
+	// ItemID, which search results don't have.
+	type scored struct {
+		score float64
+		item  board.ProjectItemWithFields
+	}
+	pairs := make([]scored, len(list))
+	for i, item := range list {
+		v, err := prog.EvalNumber(ExprEnv(item, now))
+		if err != nil {
+			return fmt.Errorf("item #%d %q: %w", item.Number, item.Title, err)
+		}
+		pairs[i] = scored{v, item}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if desc {
+			return pairs[i].score > pairs[j].score
+		}
+		return pairs[i].score < pairs[j].score
+	})
+	for i, p := range pairs {
+		list[i] = p.item
+	}
+	return nil
+}
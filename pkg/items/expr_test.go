@@ -0,0 +1,64 @@
+package items
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/expr"
+)
+
+func TestSortExpr(t *testing.T) {
+	now := time.Now()
+	// Search results have no ItemID, so every item here shares the empty one.
+	list := func() []board.ProjectItemWithFields {
+		return []board.ProjectItemWithFields{
+			{Number: 1, Title: "b", Comments: 5},
+			{Number: 2, Title: "a", Comments: 1},
+			{Number: 3, Title: "c", Comments: 9},
+			{Number: 4, Title: "d", Comments: 1},
+		}
+	}
+	numbers := func(list []board.ProjectItemWithFields) []int {
+		var out []int
+		for _, it := range list {
+			out = append(out, it.Number)
+		}
+		return out
+	}
+
+	prog, err := expr.Compile("item.comments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		desc bool
+		want []int
+	}{
+		{desc: false, want: []int{2, 4, 1, 3}}, // ties keep their order
+		{desc: true, want: []int{3, 1, 2, 4}},
+	}
+	for _, tt := range tests {
+		got := list()
+		if err := SortExpr(got, prog, tt.desc, now); err != nil {
+			t.Fatalf("SortExpr(desc=%v): %v", tt.desc, err)
+		}
+		if !reflect.DeepEqual(numbers(got), tt.want) {
+			t.Errorf("SortExpr(desc=%v) = %v, want %v", tt.desc, numbers(got), tt.want)
+		}
+	}
+
+	prog, err = expr.Compile("item.title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := list()
+	if err := SortExpr(got, prog, false, now); err == nil || !strings.Contains(err.Error(), `item #1 "b"`) {
+		t.Errorf("SortExpr with a string expression: error = %v, want one naming item #1", err)
+	}
+	if !reflect.DeepEqual(numbers(got), []int{1, 2, 3, 4}) {
+		t.Errorf("SortExpr reordered the list on error: %v", numbers(got))
+	}
+}
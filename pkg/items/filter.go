@@ -0,0 +1,161 @@
+package items
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/expr"
+)
+
+// Filter narrows a list of board items. Filters may also reorder the list.
+type Filter interface {
+	Name() string
+	Apply(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error)
+}
+
+type filterFunc struct {
+	name string
+	fn   func([]board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error)
+}
+
+func (f filterFunc) Name() string { return f.name }
+
+func (f filterFunc) Apply(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+	return f.fn(list)
+}
+
+// NewFilter returns a Filter that runs fn.
+func NewFilter(name string, fn func([]board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error)) Filter {
+	return filterFunc{name: name, fn: fn}
+}
+
+// FilterFactory builds a Filter from the argument in a filter spec ("" when
+// the spec has none).
+type FilterFactory func(arg string) (Filter, error)
+
+// filterRegistry maps filter names to factories. Built-ins are registered
+// below; RegisterFilter adds more.
+var filterRegistry = map[string]FilterFactory{
+	"max-size": func(arg string) (Filter, error) {
+		size, err := ParseSize(arg)
+		if err != nil {
+			return nil, err
+		}
+		return NewFilter("max-size", func(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+			return FilterMaxSize(list, size), nil
+		}), nil
+	},
+	"min-age": func(arg string) (Filter, error) {
+		days, err := filterDays(arg)
+		if err != nil {
+			return nil, err
+		}
+		return NewFilter("min-age", func(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+			return FilterAge(list, days, 0, time.Now()), nil
+		}), nil
+	},
+	"max-age": func(arg string) (Filter, error) {
+		days, err := filterDays(arg)
+		if err != nil {
+			return nil, err
+		}
+		return NewFilter("max-age", func(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+			return FilterAge(list, 0, days, time.Now()), nil
+		}), nil
+	},
+	"label": func(arg string) (Filter, error) {
+		if arg == "" {
+			return nil, fmt.Errorf("needs a label")
+		}
+		return NewFilter("label", func(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+			var kept []board.ProjectItemWithFields
+			for _, item := range list {
+				if HasLabel(item, arg) {
+					kept = append(kept, item)
+				}
+			}
+			return kept, nil
+		}), nil
+	},
+	"expr": func(arg string) (Filter, error) {
+		prog, err := expr.Compile(arg)
+		if err != nil {
+			return nil, err
+		}
+		return NewFilter("expr", func(list []board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+			return FilterExpr(list, prog, time.Now())
+		}), nil
+	},
+}
+
+func filterDays(arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("needs a positive number of days, got %q", arg)
+	}
+	return n, nil
+}
+
+// RegisterFilter makes a filter available to ParseFilters under name. It is
+// meant to be called from an init function and panics if name is taken.
+func RegisterFilter(name string, factory FilterFactory) {
+	if _, ok := filterRegistry[name]; ok {
+		panic(fmt.Sprintf("items: filter %q registered twice", name))
+	}
+	filterRegistry[name] = factory
+}
+
+// FilterNames lists the registered filters, comma-separated, for usage text.
+func FilterNames() string {
+	names := make([]string, 0, len(filterRegistry))
+	for name := range filterRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// ParseFilters builds a filter chain from a spec of semicolon-separated
+// "name" or "name=arg" entries, e.g. `label=sig/auth; max-size=M`.
+func ParseFilters(spec string) ([]Filter, error) {
+	var chain []Filter
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(entry, "=")
+		name, arg = strings.TrimSpace(name), strings.TrimSpace(arg)
+		factory, ok := filterRegistry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown filter %q (want one of %s)", name, FilterNames())
+		}
+		f, err := factory(arg)
+		if err != nil {
+			return nil, fmt.Errorf("filter %s: %w", name, err)
+		}
+		chain = append(chain, f)
+	}
+	return chain, nil
+}
+
+// FilterMaxSize drops pull requests larger than the given size bucket.
+// Issues and drafts have no size and are always kept.
+func FilterMaxSize(list []board.ProjectItemWithFields, maxSize string) []board.ProjectItemWithFields {
+	limit := SizeRank(maxSize)
+	if limit < 0 {
+		return list
+	}
+	var kept []board.ProjectItemWithFields
+	for _, item := range list {
+		if item.Type == "PullRequest" && SizeRank(SizeBucket(item.Additions, item.Deletions)) > limit {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
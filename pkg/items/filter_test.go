@@ -0,0 +1,102 @@
+package items
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+func TestParseFilters(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []string // filter names, in order
+		wantErr string
+	}{
+		{spec: "", want: nil},
+		{spec: " ; ", want: nil},
+		{spec: "label=sig/auth; max-size=M", want: []string{"label", "max-size"}},
+		{spec: "min-age=30;max-age = 90;", want: []string{"min-age", "max-age"}},
+		{spec: `expr=item.state == "OPEN"`, want: []string{"expr"}},
+		{spec: "nope", wantErr: `unknown filter "nope" (want one of expr, label, max-age, max-size, min-age)`},
+		{spec: "label", wantErr: "filter label: needs a label"},
+		{spec: "max-size=XXL", wantErr: `filter max-size: invalid size "XXL"`},
+		{spec: "min-age=0", wantErr: `filter min-age: needs a positive number of days, got "0"`},
+		{spec: "max-age=soon", wantErr: "filter max-age: needs a positive number of days"},
+		{spec: "expr=item.state ==", wantErr: "filter expr: expr:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			chain, err := ParseFilters(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseFilters(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFilters(%q): %v", tt.spec, err)
+			}
+			var got []string
+			for _, f := range chain {
+				got = append(got, f.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFilters(%q) = %q, want %q", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFiltersApply(t *testing.T) {
+	now := time.Now()
+	list := []board.ProjectItemWithFields{
+		{Number: 1, Type: "Issue", State: "OPEN", Labels: []string{"sig/auth"}, CreatedAt: now.AddDate(0, 0, -40)},
+		{Number: 2, Type: "PullRequest", State: "OPEN", Labels: []string{"SIG/Auth"}, Additions: 900, CreatedAt: now.AddDate(0, 0, -40)},
+		{Number: 3, Type: "Issue", State: "CLOSED", Labels: []string{"sig/auth"}, CreatedAt: now.AddDate(0, 0, -40)},
+		{Number: 4, Type: "Issue", State: "OPEN", Labels: []string{"sig/node"}, CreatedAt: now.AddDate(0, 0, -40)},
+		{Number: 5, Type: "Issue", State: "OPEN", Labels: []string{"sig/auth"}, CreatedAt: now.AddDate(0, 0, -2)},
+	}
+	chain, err := ParseFilters(`label=sig/auth; max-size=M; min-age=30; expr=item.state == "OPEN"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range chain {
+		if list, err = f.Apply(list); err != nil {
+			t.Fatalf("%s: %v", f.Name(), err)
+		}
+	}
+	var got []int
+	for _, item := range list {
+		got = append(got, item.Number)
+	}
+	if !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("filtered items = %v, want [1]", got)
+	}
+}
+
+func TestRegisterFilter(t *testing.T) {
+	RegisterFilter("test-none", func(string) (Filter, error) {
+		return NewFilter("test-none", func([]board.ProjectItemWithFields) ([]board.ProjectItemWithFields, error) {
+			return nil, nil
+		}), nil
+	})
+	t.Cleanup(func() { delete(filterRegistry, "test-none") })
+
+	chain, err := ParseFilters("test-none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 1 || chain[0].Name() != "test-none" {
+		t.Errorf("ParseFilters(test-none) = %v, want the registered filter", chain)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a filter twice did not panic")
+		}
+	}()
+	RegisterFilter("label", nil)
+}
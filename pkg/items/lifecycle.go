@@ -0,0 +1,70 @@
+package items
+
+import (
+	"sort"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+// Kubernetes lifecycle bot thresholds (fejta-bot / k8s-triage-robot): an
+// item is marked stale after 90 days of inactivity, rotten 30 days after
+// that, and closed 30 days after that. Each label change is itself activity,
+// so each stage's clock restarts from the item's last update.
+const (
+	StaleAfterDays  = 90
+	RottenAfterDays = 30
+	CloseAfterDays  = 30
+)
+
+// Lifecycle labels applied by the bot.
+const (
+	LabelStale  = "lifecycle/stale"
+	LabelRotten = "lifecycle/rotten"
+	LabelFrozen = "lifecycle/frozen"
+)
+
+// LifecycleRisk is an open item that will reach its next lifecycle stage soon.
+type LifecycleRisk struct {
+	Item     board.ProjectItemWithFields
+	Next     string // "stale", "rotten", or "closed"
+	DaysLeft int    // days until the bot acts (0 = due now)
+	Action   string // what a human should do to rescue it
+}
+
+// PredictLifecycle returns open items that will be marked stale, rotten, or
+// closed by the lifecycle bot within withinDays, soonest first. Items
+// labeled lifecycle/frozen are exempt.
+func PredictLifecycle(list []board.ProjectItemWithFields, now time.Time, withinDays int) []LifecycleRisk {
+	var risks []LifecycleRisk
+	for _, item := range list {
+		if item.Type == "DraftIssue" || item.State == "CLOSED" || item.State == "MERGED" || HasLabel(item, LabelFrozen) {
+			continue
+		}
+		idle := IdleDays(item, now)
+		if idle < 0 {
+			continue
+		}
+
+		var r LifecycleRisk
+		switch {
+		case HasLabel(item, LabelRotten):
+			r = LifecycleRisk{Next: "closed", DaysLeft: CloseAfterDays - idle, Action: "/remove-lifecycle rotten"}
+		case HasLabel(item, LabelStale):
+			r = LifecycleRisk{Next: "rotten", DaysLeft: RottenAfterDays - idle, Action: "/remove-lifecycle stale"}
+		default:
+			r = LifecycleRisk{Next: "stale", DaysLeft: StaleAfterDays - idle, Action: "comment (any activity resets the clock) or /lifecycle frozen"}
+		}
+		if r.DaysLeft < 0 {
+			r.DaysLeft = 0
+		}
+		if r.DaysLeft > withinDays {
+			continue
+		}
+		r.Item = item
+		risks = append(risks, r)
+	}
+
+	sort.SliceStable(risks, func(i, j int) bool { return risks[i].DaysLeft < risks[j].DaysLeft })
+	return risks
+}
@@ -0,0 +1,69 @@
+package items
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+func TestPredictLifecycle(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	idle := func(number, days int, labels ...string) board.ProjectItemWithFields {
+		return board.ProjectItemWithFields{Number: number, Type: "Issue", State: "OPEN", Labels: labels, UpdatedAt: now.AddDate(0, 0, -days)}
+	}
+	type risk struct {
+		Number   int
+		Next     string
+		DaysLeft int
+	}
+	tests := []struct {
+		name   string
+		item   board.ProjectItemWithFields
+		within int
+		want   []risk
+	}{
+		{name: "active", item: idle(1, 10), within: 14},
+		{name: "going stale", item: idle(1, 80), within: 14, want: []risk{{1, "stale", 10}}},
+		{name: "going stale, outside the window", item: idle(1, 70), within: 14},
+		{name: "at the edge of the window", item: idle(1, 76), within: 14, want: []risk{{1, "stale", 14}}},
+		{name: "stale going rotten", item: idle(1, 25, "lifecycle/stale"), within: 14, want: []risk{{1, "rotten", 5}}},
+		{name: "rotten going closed", item: idle(1, 29, "lifecycle/rotten"), within: 14, want: []risk{{1, "closed", 1}}},
+		{name: "overdue is due now", item: idle(1, 200), within: 14, want: []risk{{1, "stale", 0}}},
+		{name: "label in another case", item: idle(1, 25, "Lifecycle/Stale"), within: 14, want: []risk{{1, "rotten", 5}}},
+		{name: "frozen", item: idle(1, 200, "lifecycle/frozen"), within: 14},
+		{name: "closed", item: func() board.ProjectItemWithFields { it := idle(1, 200); it.State = "CLOSED"; return it }(), within: 14},
+		{name: "merged", item: func() board.ProjectItemWithFields { it := idle(1, 200); it.State = "MERGED"; return it }(), within: 14},
+		{name: "draft", item: func() board.ProjectItemWithFields { it := idle(1, 200); it.Type = "DraftIssue"; return it }(), within: 14},
+		{name: "unknown update time", item: board.ProjectItemWithFields{Number: 1, Type: "Issue", State: "OPEN"}, within: 14},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []risk
+			for _, r := range PredictLifecycle([]board.ProjectItemWithFields{tt.item}, now, tt.within) {
+				got = append(got, risk{r.Item.Number, r.Next, r.DaysLeft})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PredictLifecycle = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPredictLifecycleOrder(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	list := []board.ProjectItemWithFields{
+		{Number: 1, UpdatedAt: now.AddDate(0, 0, -80)},                                       // stale in 10
+		{Number: 2, UpdatedAt: now.AddDate(0, 0, -29), Labels: []string{"lifecycle/rotten"}}, // closed in 1
+		{Number: 3, UpdatedAt: now.AddDate(0, 0, -85)},                                       // stale in 5
+		{Number: 4, UpdatedAt: now.AddDate(0, 0, -85)},                                       // stale in 5, after 3
+	}
+	var got []int
+	for _, r := range PredictLifecycle(list, now, 30) {
+		got = append(got, r.Item.Number)
+	}
+	if want := []int{2, 3, 4, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package items
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+// PrintItems prints items in the multi-line CLI format, one block per item:
+//
+//	[Issue] #4193  KEP-4193: Bound service account token improvements
+//	         Author:    enj
+//	         URL:       https://github.com/kubernetes/enhancements/issues/4193
+//	         Status:    Tracked
+//
+// Board field values are printed after the content metadata, sorted by name,
+// followed by any lines contributed by annotators.
+func PrintItems(heading string, list []board.ProjectItemWithFields, annotators ...Annotator) {
+	fmt.Printf("\n=== %s ===\n", heading)
+	fmt.Printf("Found %d item(s)\n\n", len(list))
+
+	now := time.Now()
+
+	for _, item := range list {
+		typ := item.Type
+		if typ == "" {
+			typ = "Item"
+		}
+		private := ""
+		if item.Private {
+			private = "  [PRIVATE]"
+		}
+		if item.Number > 0 {
+			fmt.Printf("[%s] #%-5d %s%s\n", typ, item.Number, item.Title, private)
+		} else {
+			fmt.Printf("[%s] %s%s\n", typ, item.Title, private)
+		}
+
+		printLine("Author", item.Author)
+		printLine("URL", item.URL)
+		printLine("Repo", item.Repo)
+		printLine("Visibility", Visibility(item))
+		printLine("State", item.State)
+		printLine("Labels", strings.Join(item.Labels, ", "))
+		printLine("Assignees", strings.Join(item.Assignees, ", "))
+		if age := AgeDays(item, now); age >= 0 {
+			printLine("Age", fmt.Sprintf("%dd old, %dd since last update", age, IdleDays(item, now)))
+		}
+		if !item.UpdatedAt.IsZero() {
+			printLine("Activity", fmt.Sprintf("updated %s (%s)", Ago(item.UpdatedAt, now), item.UpdatedAt.Local().Format("2006-01-02")))
+		}
+		if item.Type != "DraftIssue" {
+			printLine("Discussion", fmt.Sprintf("%d comment(s), %d reaction(s)", item.Comments, item.Reactions))
+		}
+		if item.Type == "PullRequest" {
+			printLine("Size", fmt.Sprintf("%s (+%d/-%d, %d file(s))",
+				SizeBucket(item.Additions, item.Deletions), item.Additions, item.Deletions, item.ChangedFiles))
+		}
+
+		names := make([]string, 0, len(item.Fields))
+		for name := range item.Fields {
+			if name == "Title" {
+				continue // duplicated in the heading line
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			printLine(name, item.Fields[name])
+		}
+		for _, annotate := range annotators {
+			for _, a := range annotate(item) {
+				printLine(a.Label, a.Value)
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// Annotation is an extra "Label: value" line printed for an item.
+type Annotation struct {
+	Label string
+	Value string
+}
+
+// Annotator computes extra output lines for an item, e.g. time-in-status or
+// SLA breaches that are not stored on the item itself.
+type Annotator func(item board.ProjectItemWithFields) []Annotation
+
+func printLine(label, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Printf("         %-10s %s\n", label+":", value)
+}
+
+// Truncate shortens s to at most n bytes, ending in "..." when cut.
+func Truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
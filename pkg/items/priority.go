@@ -0,0 +1,171 @@
+package items
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+// PriorityConfig is the heuristic used to score items for a default board
+// ordering. An item's score is the sum of:
+//
+//   - the points of every label it carries that appears in Labels, matched
+//     case-insensitively, as GitHub matches label names
+//   - ReactionPoints per reaction and CommentPoints per comment
+//   - AgePointsPerDay per day since creation, capped at MaxAgePoints
+//   - MilestonePoints when its milestone is due within MilestoneDays (or overdue)
+//
+// The score is then mapped to the first bucket whose MinScore it reaches.
+type PriorityConfig struct {
+	Labels          map[string]float64 `yaml:"labels"`
+	ReactionPoints  float64            `yaml:"reactionPoints"`
+	CommentPoints   float64            `yaml:"commentPoints"`
+	AgePointsPerDay float64            `yaml:"agePointsPerDay"`
+	MaxAgePoints    float64            `yaml:"maxAgePoints"`
+	MilestoneDays   int                `yaml:"milestoneDays"`
+	MilestonePoints float64            `yaml:"milestonePoints"`
+	Buckets         []PriorityBucket   `yaml:"buckets"`
+}
+
+// PriorityBucket is a named score range, e.g. P0 for scores of 80 and above.
+type PriorityBucket struct {
+	Name     string  `yaml:"name"`
+	MinScore float64 `yaml:"minScore"`
+}
+
+// DefaultPriorityConfig weights the Kubernetes priority/* labels most
+// heavily, then community interest, release pressure, and age.
+func DefaultPriorityConfig() *PriorityConfig {
+	return &PriorityConfig{
+		Labels: map[string]float64{
+			"priority/critical-urgent":        100,
+			"priority/important-soon":         50,
+			"priority/important-longterm":     20,
+			"priority/backlog":                5,
+			"priority/awaiting-more-evidence": 0,
+		},
+		ReactionPoints:  2,
+		CommentPoints:   0.5,
+		AgePointsPerDay: 0.1,
+		MaxAgePoints:    20,
+		MilestoneDays:   30,
+		MilestonePoints: 30,
+		Buckets: []PriorityBucket{
+			{Name: "P0", MinScore: 80},
+			{Name: "P1", MinScore: 40},
+			{Name: "P2", MinScore: 15},
+			{Name: "P3", MinScore: 0},
+		},
+	}
+}
+
+// LoadPriorityConfig reads a priority heuristic from a YAML file. Buckets
+// are required; omitted weights are zero. Label names are lowercased, and
+// two that differ only in case are an error.
+func LoadPriorityConfig(path string) (*PriorityConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read priority config: %w", err)
+	}
+	var cfg PriorityConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse priority config: %w", err)
+	}
+	if len(cfg.Buckets) == 0 {
+		return nil, fmt.Errorf("priority config %s defines no buckets", path)
+	}
+	labels := make(map[string]float64, len(cfg.Labels))
+	for name, points := range cfg.Labels {
+		key := strings.ToLower(name)
+		if _, dup := labels[key]; dup {
+			return nil, fmt.Errorf("priority config %s lists label %q more than once (label names ignore case)", path, key)
+		}
+		labels[key] = points
+	}
+	cfg.Labels = labels
+	for i, b := range cfg.Buckets {
+		if b.Name == "" {
+			return nil, fmt.Errorf("priority bucket %d has no name", i+1)
+		}
+	}
+	return &cfg, nil
+}
+
+// Score computes item's priority score under cfg.
+func (cfg *PriorityConfig) Score(item board.ProjectItemWithFields, now time.Time) float64 {
+	score := cfg.LabelWeight(item)
+	score += cfg.ReactionPoints * float64(item.Reactions)
+	score += cfg.CommentPoints * float64(item.Comments)
+
+	if age := AgeDays(item, now); age > 0 {
+		agePoints := cfg.AgePointsPerDay * float64(age)
+		if cfg.MaxAgePoints > 0 && agePoints > cfg.MaxAgePoints {
+			agePoints = cfg.MaxAgePoints
+		}
+		score += agePoints
+	}
+
+	if !item.MilestoneDueOn.IsZero() && cfg.MilestoneDays > 0 {
+		if item.MilestoneDueOn.Sub(now) <= time.Duration(cfg.MilestoneDays)*24*time.Hour {
+			score += cfg.MilestonePoints
+		}
+	}
+	return score
+}
+
+// LabelWeight is the sum of the points of item's labels under cfg — the
+// label-only part of its score. cfg.Labels' keys are lowercase (see
+// LoadPriorityConfig), and each label is lowercased to look it up.
+func (cfg *PriorityConfig) LabelWeight(item board.ProjectItemWithFields) float64 {
+	w := 0.0
+	for _, l := range item.Labels {
+		w += cfg.Labels[strings.ToLower(l)]
+	}
+	return w
+}
+
+// Bucket returns the name of the highest bucket score reaches, or the
+// lowest bucket when score is below every threshold.
+func (cfg *PriorityConfig) Bucket(score float64) string {
+	buckets := cfg.sortedBuckets()
+	for _, b := range buckets {
+		if score >= b.MinScore {
+			return b.Name
+		}
+	}
+	return buckets[len(buckets)-1].Name
+}
+
+// BucketNames returns bucket names from highest to lowest priority, for use
+// as single-select options.
+func (cfg *PriorityConfig) BucketNames() []string {
+	buckets := cfg.sortedBuckets()
+	names := make([]string, len(buckets))
+	for i, b := range buckets {
+		names[i] = b.Name
+	}
+	return names
+}
+
+func (cfg *PriorityConfig) sortedBuckets() []PriorityBucket {
+	buckets := append([]PriorityBucket(nil), cfg.Buckets...)
+	sort.SliceStable(buckets, func(i, j int) bool { return buckets[i].MinScore > buckets[j].MinScore })
+	return buckets
+}
+
+// PriorityAnnotator returns an Annotator printing each item's score and bucket.
+func PriorityAnnotator(cfg *PriorityConfig, now time.Time) Annotator {
+	return func(item board.ProjectItemWithFields) []Annotation {
+		if item.Type == "DraftIssue" {
+			return nil
+		}
+		score := cfg.Score(item, now)
+		return []Annotation{{Label: "Priority", Value: fmt.Sprintf("%s (score %.1f)", cfg.Bucket(score), score)}}
+	}
+}
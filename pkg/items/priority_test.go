@@ -0,0 +1,170 @@
+package items
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+func TestLoadPriorityConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		yaml       string
+		wantLabels map[string]float64
+		wantErr    string
+	}{
+		{
+			name:       "label names lowercased",
+			yaml:       "labels:\n  Priority/Critical-Urgent: 100\n  kind/bug: 5\nbuckets:\n  - {name: P0, minScore: 50}\n",
+			wantLabels: map[string]float64{"priority/critical-urgent": 100, "kind/bug": 5},
+		},
+		{
+			name:    "label listed twice in different case",
+			yaml:    "labels:\n  kind/bug: 5\n  Kind/Bug: 10\nbuckets:\n  - {name: P0, minScore: 50}\n",
+			wantErr: `label "kind/bug" more than once`,
+		},
+		{name: "no buckets", yaml: "labels:\n  kind/bug: 5\n", wantErr: "defines no buckets"},
+		{name: "unnamed bucket", yaml: "buckets:\n  - {minScore: 50}\n", wantErr: "priority bucket 1 has no name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "priority.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadPriorityConfig(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LoadPriorityConfig error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(cfg.Labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", cfg.Labels, tt.wantLabels)
+			}
+		})
+	}
+}
+
+func TestPriorityScore(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	cfg := DefaultPriorityConfig()
+	tests := []struct {
+		name       string
+		item       board.ProjectItemWithFields
+		want       float64
+		wantBucket string
+	}{
+		{name: "nothing", item: board.ProjectItemWithFields{}, want: 0, wantBucket: "P3"},
+		{
+			name:       "label",
+			item:       board.ProjectItemWithFields{Labels: []string{"priority/critical-urgent"}},
+			want:       100,
+			wantBucket: "P0",
+		},
+		{
+			name:       "label in another case",
+			item:       board.ProjectItemWithFields{Labels: []string{"Priority/Important-Soon", "kind/bug"}},
+			want:       50,
+			wantBucket: "P1",
+		},
+		{
+			name:       "reactions and comments",
+			item:       board.ProjectItemWithFields{Reactions: 5, Comments: 10},
+			want:       15, // 5×2 + 10×0.5
+			wantBucket: "P2",
+		},
+		{
+			name:       "age",
+			item:       board.ProjectItemWithFields{CreatedAt: now.AddDate(0, 0, -50)},
+			want:       5, // 50 days × 0.1
+			wantBucket: "P3",
+		},
+		{
+			name:       "age capped",
+			item:       board.ProjectItemWithFields{CreatedAt: now.AddDate(-3, 0, 0)},
+			want:       20,
+			wantBucket: "P2",
+		},
+		{
+			name:       "milestone due soon",
+			item:       board.ProjectItemWithFields{MilestoneDueOn: now.AddDate(0, 0, 10)},
+			want:       30,
+			wantBucket: "P2",
+		},
+		{
+			name:       "milestone overdue",
+			item:       board.ProjectItemWithFields{MilestoneDueOn: now.AddDate(0, 0, -10)},
+			want:       30,
+			wantBucket: "P2",
+		},
+		{
+			name:       "milestone far off",
+			item:       board.ProjectItemWithFields{MilestoneDueOn: now.AddDate(0, 0, 60)},
+			want:       0,
+			wantBucket: "P3",
+		},
+		{
+			name: "everything",
+			item: board.ProjectItemWithFields{
+				Labels:         []string{"priority/important-longterm"},
+				Reactions:      3,
+				Comments:       4,
+				CreatedAt:      now.AddDate(0, 0, -100),
+				MilestoneDueOn: now.AddDate(0, 0, 30),
+			},
+			want:       20 + 6 + 2 + 10 + 30,
+			wantBucket: "P1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cfg.Score(tt.item, now)
+			if got != tt.want {
+				t.Errorf("Score = %v, want %v", got, tt.want)
+			}
+			if b := cfg.Bucket(got); b != tt.wantBucket {
+				t.Errorf("Bucket(%v) = %s, want %s", got, b, tt.wantBucket)
+			}
+		})
+	}
+}
+
+func TestPriorityBucket(t *testing.T) {
+	// Buckets in any order; a score below every threshold gets the lowest.
+	cfg := &PriorityConfig{Buckets: []PriorityBucket{{Name: "Low", MinScore: 10}, {Name: "High", MinScore: 50}, {Name: "Mid", MinScore: 25}}}
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{score: 100, want: "High"},
+		{score: 50, want: "High"},
+		{score: 49.9, want: "Mid"},
+		{score: 25, want: "Mid"},
+		{score: 10, want: "Low"},
+		{score: 0, want: "Low"},
+		{score: -5, want: "Low"},
+	}
+	for _, tt := range tests {
+		if got := cfg.Bucket(tt.score); got != tt.want {
+			t.Errorf("Bucket(%v) = %s, want %s", tt.score, got, tt.want)
+		}
+	}
+	if got, want := cfg.BucketNames(), []string{"High", "Mid", "Low"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BucketNames = %v, want %v", got, want)
+	}
+}
+
+func TestExamplePriorityConfigLoads(t *testing.T) {
+	if _, err := LoadPriorityConfig(filepath.Join("..", "..", "cmd", "kube-board", "priority.yaml")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,61 @@
+// Package items provides computed columns, filters, and CLI printing for
+// project board items fetched via pkg/board.
+package items
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pull request size buckets, ordered smallest to largest.
+const (
+	SizeXS = "XS"
+	SizeS  = "S"
+	SizeM  = "M"
+	SizeL  = "L"
+	SizeXL = "XL"
+)
+
+// SizeBuckets lists every size bucket in ascending order. It doubles as the
+// option list for a "Size" single-select field on the board.
+var SizeBuckets = []string{SizeXS, SizeS, SizeM, SizeL, SizeXL}
+
+// SizeBucket returns the size bucket for a pull request based on the total
+// number of changed lines (additions + deletions). Thresholds follow the
+// Kubernetes size/* labels, with everything from 500 lines up folded into XL.
+func SizeBucket(additions, deletions int) string {
+	lines := additions + deletions
+	switch {
+	case lines < 10:
+		return SizeXS
+	case lines < 30:
+		return SizeS
+	case lines < 100:
+		return SizeM
+	case lines < 500:
+		return SizeL
+	default:
+		return SizeXL
+	}
+}
+
+// SizeRank returns the position of bucket in SizeBuckets, or -1 if bucket
+// is not a known size. Comparison is case-insensitive.
+func SizeRank(bucket string) int {
+	for i, b := range SizeBuckets {
+		if strings.EqualFold(b, bucket) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseSize validates a user-supplied size bucket (e.g. "m") and returns
+// its canonical form ("M").
+func ParseSize(s string) (string, error) {
+	rank := SizeRank(strings.TrimSpace(s))
+	if rank < 0 {
+		return "", fmt.Errorf("invalid size %q (expected one of %s)", s, strings.Join(SizeBuckets, ", "))
+	}
+	return SizeBuckets[rank], nil
+}
@@ -0,0 +1,148 @@
+package items
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+// SLARule is one service-level rule evaluated against open items. An item
+// matches when it carries Label (if set) and its Status contains Status (if
+// set); a matching item breaches when it is older than MaxAgeDays (since
+// creation) or has been untouched for longer than MaxIdleDays (since last
+// update).
+type SLARule struct {
+	Name        string `yaml:"name"`
+	Label       string `yaml:"label"`
+	Status      string `yaml:"status"`
+	MaxAgeDays  int    `yaml:"maxAgeDays"`
+	MaxIdleDays int    `yaml:"maxIdleDays"`
+}
+
+// SLAConfig is the YAML structure of an SLA rules file.
+type SLAConfig struct {
+	// StatusField is the board field matched by rule.Status (default "Status").
+	StatusField string    `yaml:"statusField"`
+	Rules       []SLARule `yaml:"rules"`
+}
+
+// LoadSLAConfig reads and validates an SLA rules file.
+func LoadSLAConfig(path string) (*SLAConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read SLA rules: %w", err)
+	}
+	var cfg SLAConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse SLA rules: %w", err)
+	}
+	if cfg.StatusField == "" {
+		cfg.StatusField = "Status"
+	}
+	for i, r := range cfg.Rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("SLA rule %d has no name", i+1)
+		}
+		if r.MaxAgeDays <= 0 && r.MaxIdleDays <= 0 {
+			return nil, fmt.Errorf("SLA rule %q needs maxAgeDays or maxIdleDays", r.Name)
+		}
+	}
+	return &cfg, nil
+}
+
+// Breach is a single item violating a single SLA rule.
+type Breach struct {
+	Rule   SLARule
+	Item   board.ProjectItemWithFields
+	Reason string // e.g. "20d since created (limit 14d)"
+}
+
+// matches reports whether the rule applies to item.
+func (r SLARule) matches(item board.ProjectItemWithFields, statusField string) bool {
+	if r.Label != "" && !HasLabel(item, r.Label) {
+		return false
+	}
+	if r.Status != "" && !StatusMatches(item.Fields[statusField], r.Status) {
+		return false
+	}
+	return true
+}
+
+// EvaluateSLA returns every breach of cfg's rules among the open items in
+// list. Closed and merged items never breach.
+func EvaluateSLA(cfg *SLAConfig, list []board.ProjectItemWithFields, now time.Time) []Breach {
+	var breaches []Breach
+	for _, item := range list {
+		if item.State == "CLOSED" || item.State == "MERGED" {
+			continue
+		}
+		for _, r := range cfg.Rules {
+			if !r.matches(item, cfg.StatusField) {
+				continue
+			}
+			if age := AgeDays(item, now); r.MaxAgeDays > 0 && age > r.MaxAgeDays {
+				breaches = append(breaches, Breach{Rule: r, Item: item,
+					Reason: fmt.Sprintf("%dd since created (limit %dd)", age, r.MaxAgeDays)})
+				continue
+			}
+			if idle := IdleDays(item, now); r.MaxIdleDays > 0 && idle > r.MaxIdleDays {
+				breaches = append(breaches, Breach{Rule: r, Item: item,
+					Reason: fmt.Sprintf("%dd without activity (limit %dd)", idle, r.MaxIdleDays)})
+			}
+		}
+	}
+	return breaches
+}
+
+// HasLabel reports whether item carries label, ignoring case.
+func HasLabel(item board.ProjectItemWithFields, label string) bool {
+	for _, l := range item.Labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// BreachAnnotator returns an Annotator that highlights the given breaches.
+func BreachAnnotator(breaches []Breach) Annotator {
+	byItem := make(map[string][]Breach)
+	for _, b := range breaches {
+		byItem[b.Item.ItemID] = append(byItem[b.Item.ItemID], b)
+	}
+	return func(item board.ProjectItemWithFields) []Annotation {
+		var out []Annotation
+		for _, b := range byItem[item.ItemID] {
+			out = append(out, Annotation{Label: "SLA BREACH", Value: b.Rule.Name + ": " + b.Reason})
+		}
+		return out
+	}
+}
+
+// FormatBreaches renders a plain-text summary of breaches grouped by rule,
+// in rule order. It is used for both the CLI summary and Slack messages.
+func FormatBreaches(cfg *SLAConfig, breaches []Breach) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "SLA breaches: %d\n", len(breaches))
+	for _, r := range cfg.Rules {
+		var matched []Breach
+		for _, br := range breaches {
+			if br.Rule.Name == r.Name {
+				matched = append(matched, br)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s (%d)\n", r.Name, len(matched))
+		for _, br := range matched {
+			fmt.Fprintf(&b, "  #%d %s — %s\n    %s\n", br.Item.Number, br.Item.Title, br.Reason, br.Item.URL)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package items
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+func TestEvaluateSLA(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+	cfg := &SLAConfig{StatusField: "Status", Rules: []SLARule{
+		{Name: "Untriaged", Label: "needs-triage", MaxAgeDays: 14},
+		{Name: "Urgent idle", Label: "priority/critical-urgent", MaxIdleDays: 3},
+		{Name: "Stuck in review", Status: "In Review", MaxAgeDays: 30, MaxIdleDays: 7},
+	}}
+	tests := []struct {
+		name string
+		item board.ProjectItemWithFields
+		want []string // "rule: reason" of each breach
+	}{
+		{
+			name: "no matching rule",
+			item: board.ProjectItemWithFields{Labels: []string{"kind/bug"}, CreatedAt: daysAgo(400), UpdatedAt: daysAgo(400)},
+		},
+		{
+			name: "at the age limit",
+			item: board.ProjectItemWithFields{Labels: []string{"needs-triage"}, CreatedAt: daysAgo(14), UpdatedAt: now},
+		},
+		{
+			name: "past the age limit",
+			item: board.ProjectItemWithFields{Labels: []string{"needs-triage"}, CreatedAt: daysAgo(15), UpdatedAt: now},
+			want: []string{"Untriaged: 15d since created (limit 14d)"},
+		},
+		{
+			name: "label matches in any case",
+			item: board.ProjectItemWithFields{Labels: []string{"Needs-Triage"}, CreatedAt: daysAgo(20), UpdatedAt: now},
+			want: []string{"Untriaged: 20d since created (limit 14d)"},
+		},
+		{
+			name: "at the idle limit",
+			item: board.ProjectItemWithFields{Labels: []string{"priority/critical-urgent"}, CreatedAt: daysAgo(100), UpdatedAt: daysAgo(3)},
+		},
+		{
+			name: "past the idle limit",
+			item: board.ProjectItemWithFields{Labels: []string{"priority/critical-urgent"}, CreatedAt: daysAgo(100), UpdatedAt: daysAgo(4)},
+			want: []string{"Urgent idle: 4d without activity (limit 3d)"},
+		},
+		{
+			name: "age breach reported instead of idle",
+			item: board.ProjectItemWithFields{Fields: map[string]string{"Status": "In Review"}, CreatedAt: daysAgo(40), UpdatedAt: daysAgo(10)},
+			want: []string{"Stuck in review: 40d since created (limit 30d)"},
+		},
+		{
+			name: "status rule, idle only",
+			item: board.ProjectItemWithFields{Fields: map[string]string{"Status": "In Review"}, CreatedAt: daysAgo(20), UpdatedAt: daysAgo(8)},
+			want: []string{"Stuck in review: 8d without activity (limit 7d)"},
+		},
+		{
+			name: "status doesn't match",
+			item: board.ProjectItemWithFields{Fields: map[string]string{"Status": "Todo"}, CreatedAt: daysAgo(40), UpdatedAt: daysAgo(10)},
+		},
+		{
+			name: "several rules",
+			item: board.ProjectItemWithFields{Labels: []string{"needs-triage", "priority/critical-urgent"}, CreatedAt: daysAgo(30), UpdatedAt: daysAgo(5)},
+			want: []string{"Untriaged: 30d since created (limit 14d)", "Urgent idle: 5d without activity (limit 3d)"},
+		},
+		{
+			name: "closed items never breach",
+			item: board.ProjectItemWithFields{State: "CLOSED", Labels: []string{"needs-triage"}, CreatedAt: daysAgo(30)},
+		},
+		{
+			name: "merged items never breach",
+			item: board.ProjectItemWithFields{State: "MERGED", Labels: []string{"priority/critical-urgent"}, UpdatedAt: daysAgo(30)},
+		},
+		{
+			name: "unknown dates never breach",
+			item: board.ProjectItemWithFields{Labels: []string{"needs-triage", "priority/critical-urgent"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, b := range EvaluateSLA(cfg, []board.ProjectItemWithFields{tt.item}, now) {
+				got = append(got, b.Rule.Name+": "+b.Reason)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("breaches = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSLAConfig(t *testing.T) {
+	tests := []struct {
+		name, yaml string
+		wantField  string
+		wantErr    string
+	}{
+		{name: "default status field", yaml: "rules:\n  - {name: Old, maxAgeDays: 30}\n", wantField: "Status"},
+		{name: "status field", yaml: "statusField: Stage\nrules:\n  - {name: Old, maxAgeDays: 30}\n", wantField: "Stage"},
+		{name: "unnamed rule", yaml: "rules:\n  - {maxAgeDays: 30}\n", wantErr: "SLA rule 1 has no name"},
+		{name: "no limit", yaml: "rules:\n  - {name: Old, label: x}\n", wantErr: `"Old" needs maxAgeDays or maxIdleDays`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sla.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadSLAConfig(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LoadSLAConfig error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.StatusField != tt.wantField {
+				t.Errorf("StatusField = %q, want %q", cfg.StatusField, tt.wantField)
+			}
+		})
+	}
+	if _, err := LoadSLAConfig(filepath.Join("..", "..", "cmd", "kube-board", "sla.yaml")); err != nil {
+		t.Errorf("example rules: %v", err)
+	}
+}
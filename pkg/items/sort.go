@@ -0,0 +1,93 @@
+package items
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+// sortKey orders two items in the key's natural direction — the order
+// a triager most likely wants to read them in.
+type sortKey struct {
+	less        func(a, b board.ProjectItemWithFields) bool
+	description string
+}
+
+var sortKeys = map[string]sortKey{
+	"updated": {
+		less:        func(a, b board.ProjectItemWithFields) bool { return a.UpdatedAt.After(b.UpdatedAt) },
+		description: "most recently updated first",
+	},
+	"created": {
+		less:        func(a, b board.ProjectItemWithFields) bool { return a.CreatedAt.Before(b.CreatedAt) },
+		description: "oldest first",
+	},
+	"comments": {
+		less:        func(a, b board.ProjectItemWithFields) bool { return a.Comments > b.Comments },
+		description: "most comments first",
+	},
+	"reactions": {
+		less:        func(a, b board.ProjectItemWithFields) bool { return a.Reactions > b.Reactions },
+		description: "most reactions first",
+	},
+	"number": {
+		less:        func(a, b board.ProjectItemWithFields) bool { return a.Number < b.Number },
+		description: "lowest issue/PR number first",
+	},
+}
+
+// SortKeys returns the recognized sort key names with their natural order,
+// for use in flag help text.
+func SortKeys() string {
+	names := make([]string, 0, len(sortKeys))
+	for name := range sortKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%s)", name, sortKeys[name].description)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Sort orders list in place by the named key. reverse flips the key's
+// natural order (e.g. "updated" reversed puts the least active items first).
+func Sort(list []board.ProjectItemWithFields, key string, reverse bool) error {
+	k, ok := sortKeys[strings.ToLower(key)]
+	if !ok {
+		return fmt.Errorf("unknown sort key %q (expected one of: %s)", key, SortKeys())
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if reverse {
+			return k.less(list[j], list[i])
+		}
+		return k.less(list[i], list[j])
+	})
+	return nil
+}
+
+// Ago renders the time since t in a compact human form such as "3d ago".
+func Ago(t, now time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 60*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d.Hours()/24/30))
+	default:
+		return fmt.Sprintf("%dy ago", int(d.Hours()/24/365))
+	}
+}
@@ -0,0 +1,125 @@
+package items
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/cache"
+)
+
+// StatusEntry records one item's status at the time a snapshot was taken.
+// Snapshots are lists of these, written with cache.Write on every run.
+type StatusEntry struct {
+	ItemID string `json:"item_id"`
+	Status string `json:"status"`
+}
+
+// StatusEntries extracts the value of statusField for every item.
+func StatusEntries(list []board.ProjectItemWithFields, statusField string) []StatusEntry {
+	entries := make([]StatusEntry, 0, len(list))
+	for _, item := range list {
+		entries = append(entries, StatusEntry{ItemID: item.ItemID, Status: item.Fields[statusField]})
+	}
+	return entries
+}
+
+// StatusSince returns, per project item ID, the earliest time the item is
+// known to have held its current status without interruption. history must
+// be ordered oldest first (as returned by cache.ReadSnapshots). Items with
+// no matching history are considered to have entered their status at now.
+//
+// The result is a lower bound on time-in-status: if the oldest snapshot
+// already shows the current status, the item may have been there longer.
+func StatusSince(list []board.ProjectItemWithFields, statusField string, history []cache.Snapshot[StatusEntry], now time.Time) map[string]time.Time {
+	byItem := make([]map[string]string, len(history))
+	for i, snap := range history {
+		m := make(map[string]string, len(snap.Items))
+		for _, e := range snap.Items {
+			m[e.ItemID] = e.Status
+		}
+		byItem[i] = m
+	}
+
+	since := make(map[string]time.Time, len(list))
+	for _, item := range list {
+		current := item.Fields[statusField]
+		t := now
+		for i := len(history) - 1; i >= 0; i-- {
+			status, ok := byItem[i][item.ItemID]
+			if !ok || status != current {
+				break
+			}
+			t = history[i].At
+		}
+		since[item.ItemID] = t
+	}
+	return since
+}
+
+// StatusMatches reports whether status contains want, ignoring case. This
+// tolerates decorated option names such as "👀 In Review".
+func StatusMatches(status, want string) bool {
+	return want != "" && strings.Contains(strings.ToLower(status), strings.ToLower(want))
+}
+
+// StatusMap translates status option names from source boards to the
+// equivalent option on a destination board. Keys match case-insensitively;
+// the key "*" catches any status not otherwise listed. Statuses with no
+// entry and no "*" pass through unchanged.
+type StatusMap map[string]string
+
+// ParseStatusMap reads a status mapping either inline, as comma-separated
+// From=To pairs ("Todo=Backlog,In review=In Progress"), or from a YAML file
+// of from: to entries when s names a .yaml or .yml file.
+func ParseStatusMap(s string) (StatusMap, error) {
+	s = strings.TrimSpace(s)
+	m := make(StatusMap)
+	if s == "" {
+		return m, nil
+	}
+	if strings.HasSuffix(s, ".yaml") || strings.HasSuffix(s, ".yml") {
+		data, err := os.ReadFile(s)
+		if err != nil {
+			return nil, fmt.Errorf("read status map: %w", err)
+		}
+		var raw map[string]string
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parse status map: %w", err)
+		}
+		for from, to := range raw {
+			m[strings.ToLower(strings.TrimSpace(from))] = strings.TrimSpace(to)
+		}
+		return m, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(from) == "" {
+			return nil, fmt.Errorf("invalid status mapping %q (expected From=To)", pair)
+		}
+		m[strings.ToLower(strings.TrimSpace(from))] = strings.TrimSpace(to)
+	}
+	return m, nil
+}
+
+// Map returns the destination status for a source status. An empty source
+// status maps to "" so unset items stay unset.
+func (m StatusMap) Map(status string) string {
+	if status == "" {
+		return ""
+	}
+	if to, ok := m[strings.ToLower(status)]; ok {
+		return to
+	}
+	if to, ok := m["*"]; ok {
+		return to
+	}
+	return status
+}
@@ -0,0 +1,92 @@
+package items
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStatusMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    StatusMap
+		wantErr string
+	}{
+		{name: "empty", spec: "  ", want: StatusMap{}},
+		{
+			name: "pairs",
+			spec: "Todo=Backlog, In review = In Progress",
+			want: StatusMap{"todo": "Backlog", "in review": "In Progress"},
+		},
+		{name: "catch-all", spec: "*=Backlog", want: StatusMap{"*": "Backlog"}},
+		{name: "empty pairs skipped", spec: "Todo=Backlog,,", want: StatusMap{"todo": "Backlog"}},
+		{name: "unset target", spec: "Todo=", want: StatusMap{"todo": ""}},
+		{name: "missing =", spec: "Todo", wantErr: `invalid status mapping "Todo"`},
+		{name: "missing source", spec: "=Backlog", wantErr: "invalid status mapping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStatusMap(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseStatusMap(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatusMap(%q): %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStatusMap(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStatusMapFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "status.yaml")
+	if err := os.WriteFile(path, []byte("Todo: Backlog\n\" In review \": \" In Progress \"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseStatusMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := StatusMap{"todo": "Backlog", "in review": "In Progress"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStatusMap(file) = %v, want %v", got, want)
+	}
+
+	if _, err := ParseStatusMap(filepath.Join(dir, "missing.yml")); err == nil || !strings.Contains(err.Error(), "read status map") {
+		t.Errorf("missing file error = %v, want a read error", err)
+	}
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("- not\n- a map\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseStatusMap(bad); err == nil || !strings.Contains(err.Error(), "parse status map") {
+		t.Errorf("bad file error = %v, want a parse error", err)
+	}
+}
+
+func TestStatusMapMap(t *testing.T) {
+	m := StatusMap{"todo": "Backlog", "*": "Triage"}
+	tests := []struct{ in, want string }{
+		{in: "", want: ""},
+		{in: "Todo", want: "Backlog"},
+		{in: "TODO", want: "Backlog"},
+		{in: "Done", want: "Triage"},
+	}
+	for _, tt := range tests {
+		if got := m.Map(tt.in); got != tt.want {
+			t.Errorf("Map(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if got := (StatusMap{}).Map("Done"); got != "Done" {
+		t.Errorf("empty map Map(Done) = %q, want it unchanged", got)
+	}
+}
@@ -0,0 +1,33 @@
+package items
+
+import "github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+
+// Visibility values written to a board's Visibility field.
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+// Visibility reports whether item comes from a public or private
+// repository, or "" for drafts, which have no repository.
+func Visibility(item board.ProjectItemWithFields) string {
+	switch {
+	case item.Repo == "":
+		return ""
+	case item.Private:
+		return VisibilityPrivate
+	default:
+		return VisibilityPublic
+	}
+}
+
+// CountPrivate returns how many items in list come from private repositories.
+func CountPrivate(list []board.ProjectItemWithFields) int {
+	n := 0
+	for _, item := range list {
+		if item.Private {
+			n++
+		}
+	}
+	return n
+}
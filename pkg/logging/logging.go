@@ -0,0 +1,156 @@
+// Package logging configures log/slog output: the classic plain lines, slog
+// key=value text, or JSON, filtered by level. Anything still written through
+// the standard log package is logged at info.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Formats accepted by Setup.
+const (
+	FormatPlain = "plain" // the classic "2006/01/02 15:04:05 message key=value" lines
+	FormatText  = "text"  // slog key=value lines
+	FormatJSON  = "json"  // one JSON object per line
+)
+
+// ParseLevel parses "debug", "info", "warn", or "error".
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q (want debug, info, warn, or error)", s)
+	}
+	return l, nil
+}
+
+// Setup sends every slog call, and every log.Printf, to w in format,
+// dropping records below level.
+func Setup(w io.Writer, format string, level slog.Level) error {
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch format {
+	case FormatPlain:
+		h = newPlainHandler(w, opts)
+	case FormatText:
+		h = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format %q (want %s, %s, or %s)", format, FormatPlain, FormatText, FormatJSON)
+	}
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// plainHandler writes records as "2006/01/02 15:04:05 message key=value",
+// naming the level after the time unless it is info.
+type plainHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	level  slog.Leveler
+	attrs  string // preformatted " key=value" pairs from WithAttrs
+	prefix string // group names from WithGroup, each followed by "."
+}
+
+// newPlainHandler returns a plainHandler writing to w. opts may be nil.
+func newPlainHandler(w io.Writer, opts *slog.HandlerOptions) *plainHandler {
+	h := &plainHandler{mu: &sync.Mutex{}, w: w, level: slog.LevelInfo}
+	if opts != nil && opts.Level != nil {
+		h.level = opts.Level
+	}
+	return h
+}
+
+// Enabled reports whether level is at or above the handler's minimum.
+func (h *plainHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle writes one line for r.
+func (h *plainHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	b.WriteString(t.Format("2006/01/02 15:04:05 "))
+	if r.Level != slog.LevelInfo {
+		b.WriteString(r.Level.String())
+		b.WriteByte(' ')
+	}
+	b.WriteString(r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&b, h.prefix, a)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// WithAttrs returns a handler that adds attrs to every line.
+func (h *plainHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		appendAttr(&b, h.prefix, a)
+	}
+	h2 := *h
+	h2.attrs = b.String()
+	return &h2
+}
+
+// WithGroup returns a handler that qualifies later keys with name.
+func (h *plainHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix += name + "."
+	return &h2
+}
+
+// appendAttr writes " key=value" for a, flattening groups into dotted keys.
+func appendAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			appendAttr(b, prefix, ga)
+		}
+		return
+	}
+	b.WriteByte(' ')
+	b.WriteString(prefix)
+	b.WriteString(a.Key)
+	b.WriteByte('=')
+	b.WriteString(quote(a.Value.String()))
+}
+
+// quote quotes s if it is empty or holds spaces, quotes, '=', or
+// unprintable characters, so each line still splits into key=value pairs.
+func quote(s string) string {
+	if s == "" || strings.IndexFunc(s, func(r rune) bool {
+		return r == '"' || r == '=' || unicode.IsSpace(r) || !unicode.IsPrint(r)
+	}) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// stamp matches the plain format's leading "2006/01/02 15:04:05 ".
+var stamp = regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} `)
+
+func TestPlainHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *slog.Logger)
+		want string
+	}{
+		{
+			name: "info has no level",
+			log:  func(l *slog.Logger) { l.Info("Fetched items", "count", 3) },
+			want: "Fetched items count=3\n",
+		},
+		{
+			name: "other levels are named",
+			log:  func(l *slog.Logger) { l.Warn("Could not list views", "err", errors.New("boom")) },
+			want: "WARN Could not list views err=boom\n",
+		},
+		{
+			name: "below the level is dropped",
+			log:  func(l *slog.Logger) { l.Debug("Added item", "item", "kubernetes/kubernetes#1") },
+		},
+		{
+			name: "values with spaces, quotes, or = are quoted",
+			log: func(l *slog.Logger) {
+				l.Error("Could not add item", "item", "#1 Fix it", "q", `a"b`, "eq", "a=b", "empty", "")
+			},
+			want: `ERROR Could not add item item="#1 Fix it" q="a\"b" eq="a=b" empty=""` + "\n",
+		},
+		{
+			name: "WithAttrs and WithGroup",
+			log: func(l *slog.Logger) {
+				l.With("board", "sig-auth").WithGroup("sync").Info("Synced", "count", 2, slog.Group("items", "added", 1))
+			},
+			want: "Synced board=sig-auth sync.count=2 sync.items.added=1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(slog.New(newPlainHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+			got := buf.String()
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("logged %q, want nothing", got)
+				}
+				return
+			}
+			if !stamp.MatchString(got) {
+				t.Fatalf("logged %q, want a leading timestamp", got)
+			}
+			if got = stamp.ReplaceAllString(got, ""); got != tt.want {
+				t.Errorf("logged %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	defaultLogger, flags, output := slog.Default(), log.Flags(), log.Writer()
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+		log.SetFlags(flags)
+		log.SetOutput(output)
+	})
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: FormatPlain, want: "left over\n"},
+		{format: FormatText, want: "level=INFO msg=\"left over\""},
+		{format: FormatJSON, want: `"level":"INFO","msg":"left over"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Setup(&buf, tt.format, slog.LevelInfo); err != nil {
+				t.Fatal(err)
+			}
+			log.Printf("left over")
+			slog.Debug("dropped")
+			if got := buf.String(); !strings.Contains(got, tt.want) || strings.Contains(got, "dropped") {
+				t.Errorf("logged %q, want %q and no debug line", got, tt.want)
+			}
+		})
+	}
+
+	if err := Setup(os.Stderr, "xml", slog.LevelInfo); err == nil || !strings.Contains(err.Error(), "invalid log format") {
+		t.Errorf("Setup(xml) error = %v, want invalid log format", err)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, want := range map[string]slog.Level{"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "error": slog.LevelError} {
+		if got, err := ParseLevel(s); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("ParseLevel(loud) succeeded, want an error")
+	}
+}
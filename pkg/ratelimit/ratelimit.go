@@ -3,9 +3,10 @@ package ratelimit
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
@@ -39,11 +40,70 @@ type GraphQLInfo struct {
 }
 
 // FetchREST calls GET /rate_limit (free — does not count against quota).
-func FetchREST(token string) (*Status, error) {
+// For a comma-separated list of tokens, it returns their combined budget
+// (see Sum), since a client given the list rotates through them. opts
+// are the options the caller's ghgql clients get; the request goes through
+// their transport.
+func FetchREST(token string, opts ...ghgql.Option) (*Status, error) {
+	if tokens := ghgql.SplitTokens(token); len(tokens) > 1 {
+		each, err := FetchEach(tokens, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return Sum(each), nil
+	}
+	return fetchREST(strings.TrimSpace(token), opts)
+}
+
+// FetchEach returns the rate-limit status of each token, in order.
+func FetchEach(tokens []string, opts ...ghgql.Option) ([]*Status, error) {
+	out := make([]*Status, len(tokens))
+	for i, t := range tokens {
+		s, err := fetchREST(t, opts)
+		if err != nil {
+			return nil, fmt.Errorf("token %d of %d: %w", i+1, len(tokens), err)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+// Sum adds up several tokens' budgets: limits, used, and remaining are
+// summed, and each category resets when the first token's does.
+func Sum(statuses []*Status) *Status {
+	total := &Status{}
+	for _, s := range statuses {
+		total.Core = total.Core.add(s.Core)
+		total.Search = total.Search.add(s.Search)
+		total.GraphQL = total.GraphQL.add(s.GraphQL)
+	}
+	return total
+}
+
+func (c Category) add(o Category) Category {
+	if c.ResetAt.IsZero() || (!o.ResetAt.IsZero() && o.ResetAt.Before(c.ResetAt)) {
+		c.ResetAt = o.ResetAt
+	}
+	c.Limit += o.Limit
+	c.Used += o.Used
+	c.Remaining += o.Remaining
+	return c
+}
+
+func fetchREST(token string, opts []ghgql.Option) (*Status, error) {
 	ctx := context.Background()
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
+	tc := &http.Client{
+		Transport: &oauth2.Transport{Source: ts, Base: ghgql.BaseTransport(opts...)},
+		Timeout:   ghgql.RequestTimeout(),
+	}
 	client := github.NewClient(tc)
+	if _, rest := ghgql.Endpoints(); rest != ghgql.RESTEndpoint {
+		var err error
+		if client, err = client.WithEnterpriseURLs(rest, rest); err != nil {
+			return nil, fmt.Errorf("GitHub Enterprise URL %s: %w", rest, err)
+		}
+	}
 
 	limits, _, err := client.RateLimit.Get(ctx)
 	if err != nil {
@@ -97,11 +157,11 @@ func FetchGraphQL(gql *ghgql.Client) (*GraphQLInfo, error) {
 			Login string `json:"login"`
 		} `json:"viewer"`
 		RateLimit struct {
-			Limit     int    `json:"limit"`
-			Remaining int    `json:"remaining"`
-			Used      int    `json:"used"`
-			ResetAt   string `json:"resetAt"`
-			Cost      int    `json:"cost"`
+			Limit     int            `json:"limit"`
+			Remaining int            `json:"remaining"`
+			Used      int            `json:"used"`
+			ResetAt   ghgql.DateTime `json:"resetAt"`
+			Cost      int            `json:"cost"`
 		} `json:"rateLimit"`
 	}
 
@@ -110,14 +170,12 @@ func FetchGraphQL(gql *ghgql.Client) (*GraphQLInfo, error) {
 		return nil, fmt.Errorf("querying GraphQL rate limit: %w", err)
 	}
 
-	resetAt, _ := time.Parse(time.RFC3339, result.RateLimit.ResetAt)
-
 	return &GraphQLInfo{
 		Login:     result.Viewer.Login,
 		Limit:     result.RateLimit.Limit,
 		Remaining: result.RateLimit.Remaining,
 		Used:      result.RateLimit.Used,
-		ResetAt:   resetAt,
+		ResetAt:   result.RateLimit.ResetAt.Time,
 		QueryCost: result.RateLimit.Cost,
 	}, nil
 }
@@ -161,12 +219,20 @@ func printCategory(indent string, cat Category) {
 // CheckAndWarn performs a pre-flight rate-limit check and prints warnings.
 // It checks both REST and GraphQL limits. The GET /rate_limit call is free;
 // the GraphQL probe costs 1 point.
-func CheckAndWarn(token string) {
-	log.Println("Checking rate limit status...")
+func CheckAndWarn(token string, opts ...ghgql.Option) {
+	slog.Info("Checking rate limit status")
 
-	rest, err := FetchREST(token)
+	rest, err := FetchREST(token, opts...)
 	if err != nil {
-		log.Printf("Warning: could not fetch REST rate limits: %v", err)
+		slog.Warn("Could not fetch REST rate limits", "err", err)
+	}
+	if tokens := ghgql.SplitTokens(token); len(tokens) > 1 {
+		if each, err := FetchEach(tokens, opts...); err == nil {
+			for i, s := range each {
+				slog.Info("GraphQL budget", "token", i+1, "tokens", len(tokens),
+					"remaining", s.GraphQL.Remaining, "limit", s.GraphQL.Limit, "reset", s.GraphQL.ResetAt.Local().Format("15:04:05 MST"))
+			}
+		}
 	}
 
 	// If the free REST call already shows GraphQL budget is exhausted, skip
@@ -174,36 +240,30 @@ func CheckAndWarn(token string) {
 	// rate-limit window to reset.
 	var gqlInfo *GraphQLInfo
 	if rest != nil && rest.GraphQL.Remaining == 0 {
-		log.Println("BUDGET EXCEEDED: GraphQL budget is 0 (per REST). Skipping live GraphQL probe.")
+		slog.Warn("BUDGET EXCEEDED: GraphQL budget is 0 (per REST); skipping live GraphQL probe")
 		fmt.Printf("\n*** BUDGET EXCEEDED — GraphQL points remaining: 0 / %d ***\n", rest.GraphQL.Limit)
 		fmt.Printf("    Resets at: %s\n\n", rest.GraphQL.ResetAt.Local().Format("2006-01-02 15:04:05 MST"))
 	} else {
-		gql := ghgql.NewClient(token)
+		gql := ghgql.NewClient(token, opts...)
 		gqlInfo, err = FetchGraphQL(gql)
 		if err != nil {
-			log.Printf("Warning: could not fetch GraphQL rate limits: %v", err)
+			slog.Warn("Could not fetch GraphQL rate limits", "err", err)
 		}
 	}
 
 	PrintStatus(rest, gqlInfo)
 
 	if rest != nil && rest.Core.Remaining < 10 {
-		log.Printf("WARNING: REST API core budget is very low (%d remaining). Resets at %s",
-			rest.Core.Remaining, rest.Core.ResetAt.Local().Format("15:04:05 MST"))
+		slog.Warn("REST API core budget is very low",
+			"remaining", rest.Core.Remaining, "reset", rest.Core.ResetAt.Local().Format("15:04:05 MST"))
 	}
 
 	if gqlInfo != nil && gqlInfo.Remaining < 10 {
-		log.Printf("WARNING: GraphQL API budget is very low (%d points remaining). Resets at %s",
-			gqlInfo.Remaining, gqlInfo.ResetAt.Local().Format("15:04:05 MST"))
+		slog.Warn("GraphQL API budget is very low",
+			"remaining", gqlInfo.Remaining, "reset", gqlInfo.ResetAt.Local().Format("15:04:05 MST"))
 	}
 
 	if rest != nil {
-		data := map[string]any{
-			"rest":    rest,
-			"graphql": gqlInfo,
-			"checked": time.Now().Format(time.RFC3339),
-		}
-		jsonData, _ := json.MarshalIndent(data, "", "  ")
-		log.Printf("Rate limit snapshot:\n%s", string(jsonData))
+		slog.Debug("Rate limit snapshot", "rest", rest, "graphql", gqlInfo)
 	}
 }
@@ -0,0 +1,139 @@
+// Package releasenotes drafts Markdown release notes from completed board
+// items, using the ```release-note blocks Kubernetes contributors put in PR
+// and issue descriptions.
+package releasenotes
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/items"
+)
+
+// Note is one release-note entry.
+type Note struct {
+	Item board.ProjectItemWithFields
+	Text string
+	Kind string // section heading, from the item's kind/* label
+}
+
+// sections maps kind/* labels to headings, in output order.
+var sections = []struct{ label, heading string }{
+	{"kind/api-change", "API Change"},
+	{"kind/deprecation", "Deprecation"},
+	{"kind/feature", "Feature"},
+	{"kind/bug", "Bug or Regression"},
+	{"kind/cleanup", "Other (Cleanup or Flake)"},
+}
+
+// otherHeading is the section for notes with no recognized kind label.
+const otherHeading = "Other"
+
+var noteBlock = regexp.MustCompile("(?s)```release-note[^\\n]*\\n(.*?)```")
+
+// Extract returns the text of the first release-note block in body. Blocks
+// that are empty or say NONE (the Kubernetes "no note needed" marker) yield
+// ok == false.
+func Extract(body string) (text string, ok bool) {
+	m := noteBlock.FindStringSubmatch(strings.ReplaceAll(body, "\r\n", "\n"))
+	if m == nil {
+		return "", false
+	}
+	text = strings.TrimSpace(m[1])
+	if text == "" || strings.EqualFold(text, "none") {
+		return "", false
+	}
+	return text, true
+}
+
+// Kind returns the section heading for item from its kind/* labels.
+func Kind(item board.ProjectItemWithFields) string {
+	for _, s := range sections {
+		if items.HasLabel(item, s.label) {
+			return s.heading
+		}
+	}
+	return otherHeading
+}
+
+// Completed reports whether item is done: a merged pull request or a closed
+// issue.
+func Completed(item board.ProjectItemWithFields) bool {
+	return (item.Type == "PullRequest" && item.State == "MERGED") ||
+		(item.Type == "Issue" && item.State == "CLOSED")
+}
+
+// FetchBodies returns the description of each issue or pull request in ids,
+// keyed by content node ID, fetching up to 100 per request.
+func FetchBodies(gql *ghgql.Client, ids []string) (map[string]string, error) {
+	query := `query($ids: [ID!]!) {
+		nodes(ids: $ids) {
+			... on Issue { id body }
+			... on PullRequest { id body }
+		}
+	}`
+
+	bodies := make(map[string]string, len(ids))
+	for start := 0; start < len(ids); start += 100 {
+		end := min(start+100, len(ids))
+		var result struct {
+			Nodes []*struct {
+				ID   string `json:"id"`
+				Body string `json:"body"`
+			} `json:"nodes"`
+		}
+		if err := gql.Do(ghgql.Request{Query: query, Variables: map[string]any{"ids": ids[start:end]}}, &result); err != nil {
+			return nil, fmt.Errorf("fetching descriptions: %w", err)
+		}
+		for _, n := range result.Nodes {
+			if n != nil {
+				bodies[n.ID] = n.Body
+			}
+		}
+	}
+	return bodies, nil
+}
+
+// Render writes notes as a Markdown draft grouped by kind, each entry linking
+// back to its issue or PR.
+func Render(w io.Writer, title string, notes []Note) {
+	fmt.Fprintf(w, "# %s\n\n", title)
+	if len(notes) == 0 {
+		fmt.Fprintln(w, "_No release notes found._")
+		return
+	}
+
+	byKind := make(map[string][]Note)
+	for _, n := range notes {
+		byKind[n.Kind] = append(byKind[n.Kind], n)
+	}
+	headings := make([]string, 0, len(sections)+1)
+	for _, s := range sections {
+		headings = append(headings, s.heading)
+	}
+	headings = append(headings, otherHeading)
+
+	for _, h := range headings {
+		group := byKind[h]
+		if len(group) == 0 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].Item.Repo != group[j].Item.Repo {
+				return group[i].Item.Repo < group[j].Item.Repo
+			}
+			return group[i].Item.Number < group[j].Item.Number
+		})
+		fmt.Fprintf(w, "## %s\n\n", h)
+		for _, n := range group {
+			text := strings.ReplaceAll(n.Text, "\n", "\n  ")
+			fmt.Fprintf(w, "- %s ([%s#%d](%s), @%s)\n", text, n.Item.Repo, n.Item.Number, n.Item.URL, n.Item.Author)
+		}
+		fmt.Fprintln(w)
+	}
+}
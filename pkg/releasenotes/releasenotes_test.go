@@ -0,0 +1,112 @@
+package releasenotes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   string
+		wantOK bool
+	}{
+		{name: "no block", body: "Fixes #12.", wantOK: false},
+		{
+			name:   "plain block",
+			body:   "What this PR does:\n\n```release-note\nAdded the --foo flag.\n```\n",
+			want:   "Added the --foo flag.",
+			wantOK: true,
+		},
+		{
+			name:   "CRLF line endings",
+			body:   "```release-note\r\nFixed a crash.\r\n```\r\n",
+			want:   "Fixed a crash.",
+			wantOK: true,
+		},
+		{
+			name:   "info string after the fence",
+			body:   "```release-note  \nMultiple\nlines\n```",
+			want:   "Multiple\nlines",
+			wantOK: true,
+		},
+		{
+			name:   "first block wins",
+			body:   "```release-note\nfirst\n```\n```release-note\nsecond\n```",
+			want:   "first",
+			wantOK: true,
+		},
+		{name: "NONE", body: "```release-note\nNONE\n```", wantOK: false},
+		{name: "none, any case", body: "```release-note\n  None \n```", wantOK: false},
+		{name: "empty", body: "```release-note\n\n```", wantOK: false},
+		{name: "other fence", body: "```go\nfmt.Println()\n```", wantOK: false},
+		{name: "unterminated", body: "```release-note\ndangling", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Extract(tt.body)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Extract(%q) = %q, %v; want %q, %v", tt.body, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestKindAndCompleted(t *testing.T) {
+	if got := Kind(board.ProjectItemWithFields{Labels: []string{"kind/bug", "kind/feature"}}); got != "Feature" {
+		t.Errorf("Kind = %q, want Feature (earlier section wins)", got)
+	}
+	if got := Kind(board.ProjectItemWithFields{Labels: []string{"kind/documentation"}}); got != otherHeading {
+		t.Errorf("Kind = %q, want %q", got, otherHeading)
+	}
+
+	tests := []struct {
+		typ, state string
+		want       bool
+	}{
+		{"PullRequest", "MERGED", true},
+		{"PullRequest", "CLOSED", false},
+		{"Issue", "CLOSED", true},
+		{"Issue", "OPEN", false},
+		{"DraftIssue", "", false},
+	}
+	for _, tt := range tests {
+		if got := Completed(board.ProjectItemWithFields{Type: tt.typ, State: tt.state}); got != tt.want {
+			t.Errorf("Completed(%s %s) = %v, want %v", tt.typ, tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	note := func(repo string, number int, kind, text string) Note {
+		return Note{
+			Item: board.ProjectItemWithFields{Repo: repo, Number: number, URL: "https://example.com/" + repo, Author: "dev"},
+			Kind: kind,
+			Text: text,
+		}
+	}
+	var b strings.Builder
+	Render(&b, "v1.0", []Note{
+		note("k/b", 2, otherHeading, "Other change"),
+		note("k/b", 9, "Feature", "Second\nline two"),
+		note("k/a", 5, "Feature", "First"),
+	})
+	want := "# v1.0\n\n" +
+		"## Feature\n\n" +
+		"- First ([k/a#5](https://example.com/k/a), @dev)\n" +
+		"- Second\n  line two ([k/b#9](https://example.com/k/b), @dev)\n\n" +
+		"## Other\n\n" +
+		"- Other change ([k/b#2](https://example.com/k/b), @dev)\n\n"
+	if got := b.String(); got != want {
+		t.Errorf("Render =\n%s\nwant\n%s", got, want)
+	}
+
+	b.Reset()
+	Render(&b, "empty", nil)
+	if got := b.String(); !strings.Contains(got, "_No release notes found._") {
+		t.Errorf("Render(nil) = %q, want the no-notes line", got)
+	}
+}
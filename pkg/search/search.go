@@ -0,0 +1,267 @@
+// Package search runs GitHub issue/PR searches through the GraphQL search
+// API and returns the results in the same shape as board items, so they can
+// be compared with, filtered like, and synced onto project boards.
+package search
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/board"
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// MaxResults is the most results GitHub returns for a single search query,
+// regardless of how many match.
+const MaxResults = 1000
+
+// sliceStart is the earliest creation date searched when a query is split
+// into date ranges; nothing on GitHub predates it.
+var sliceStart = time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Result is the outcome of one paged search.
+type Result struct {
+	Items      []board.ProjectItemWithFields
+	IssueCount int // total matches GitHub reported
+}
+
+// Truncated reports whether GitHub matched more items than were returned.
+func (r *Result) Truncated() bool {
+	return r.IssueCount > len(r.Items)
+}
+
+// Issues runs an issue/PR search (GitHub search syntax, e.g.
+// `org:kubernetes label:sig/auth is:open`) and pages through every result.
+// Queries matching more than MaxResults items are split into created: date
+// ranges small enough to return in full. If the items collected still fall
+// short of the count GitHub reported (the query already has a created:
+// qualifier, or results were inaccessible), a warning is logged and
+// Truncated reports true. Items carry no ItemID or Fields since they are not
+// on a board.
+func Issues(gql *ghgql.Client, query string) (*Result, error) {
+	sliceable := !strings.Contains(query, "created:")
+	res, err := pages(gql, query, sliceable)
+	if err != nil {
+		return nil, err
+	}
+	if sliceable && res.IssueCount > MaxResults {
+		slog.Info("Search matched more items than GitHub returns; splitting it by creation date", "query", query, "count", res.IssueCount, "max", MaxResults)
+		total := res.IssueCount
+		seen := make(map[string]bool)
+		res = &Result{IssueCount: total}
+		if err := slice(gql, query, sliceStart, time.Now().UTC(), res, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	if res.Truncated() {
+		slog.Warn("Search results are incomplete", "query", query, "count", res.IssueCount, "collected", len(res.Items))
+	}
+	return res, nil
+}
+
+// slice collects every match created between from and to, halving the range
+// until each piece fits under MaxResults. Ranges are inclusive at both ends,
+// so items on a boundary are de-duplicated through seen.
+func slice(gql *ghgql.Client, query string, from, to time.Time, res *Result, seen map[string]bool) error {
+	q := fmt.Sprintf("%s created:%s..%s", query, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	part, err := pages(gql, q, true)
+	if err != nil {
+		return err
+	}
+	if part.IssueCount > MaxResults && to.Sub(from) > time.Second {
+		mid := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+		if err := slice(gql, query, from, mid, res, seen); err != nil {
+			return err
+		}
+		return slice(gql, query, mid, to, res, seen)
+	}
+	if part.Truncated() {
+		slog.Warn("Search returned fewer items than it matched", "query", q, "count", part.IssueCount, "returned", len(part.Items))
+	}
+	for _, it := range part.Items {
+		if !seen[it.ContentID] {
+			seen[it.ContentID] = true
+			res.Items = append(res.Items, it)
+		}
+	}
+	return nil
+}
+
+// pages pages through one search query, up to MaxResults. With stopOver it
+// returns after the first page when GitHub reports more than MaxResults
+// matches, since the caller is going to split the query anyway.
+func pages(gql *ghgql.Client, query string, stopOver bool) (*Result, error) {
+	q := `query($q: String!, $cursor: String) {
+		search(query: $q, type: ISSUE, first: 100, after: $cursor) {
+			issueCount
+			nodes {
+				__typename
+				... on Issue {
+					id number title url state createdAt updatedAt
+					repository { nameWithOwner isPrivate }
+					author { login }
+					labels(first: 20) { nodes { name } }
+					comments { totalCount }
+					reactions { totalCount }
+				}
+				... on PullRequest {
+					id number title url state createdAt updatedAt
+					repository { nameWithOwner isPrivate }
+					author { login }
+					labels(first: 20) { nodes { name } }
+					comments { totalCount }
+					reactions { totalCount }
+					additions deletions changedFiles
+				}
+			}
+			pageInfo { hasNextPage endCursor }
+		}
+	}`
+
+	res := &Result{}
+	seen := make(map[string]bool)
+	type page struct {
+		Search struct {
+			IssueCount int            `json:"issueCount"`
+			Nodes      []searchNode   `json:"nodes"`
+			PageInfo   ghgql.PageInfo `json:"pageInfo"`
+		} `json:"search"`
+	}
+	err := ghgql.Paginate(gql, ghgql.Request{Query: q, Variables: map[string]any{"q": query}}, func(result *page) (ghgql.PageInfo, error) {
+		res.IssueCount = result.Search.IssueCount
+		if stopOver && res.IssueCount > MaxResults {
+			return ghgql.PageInfo{}, nil
+		}
+		for _, n := range result.Search.Nodes {
+			if n.ID == "" || seen[n.ID] {
+				continue // inaccessible, unexpected type, or shifted onto a later page
+			}
+			seen[n.ID] = true
+			res.Items = append(res.Items, n.item())
+		}
+		if len(res.Items) >= MaxResults {
+			return ghgql.PageInfo{}, nil
+		}
+		return result.Search.PageInfo, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search %q: %w", query, err)
+	}
+	return res, nil
+}
+
+// searchNode is an Issue or PullRequest search result.
+type searchNode struct {
+	Typename   string    `json:"__typename"`
+	ID         string    `json:"id"`
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	URL        string    `json:"url"`
+	State      string    `json:"state"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+		IsPrivate     bool   `json:"isPrivate"`
+	} `json:"repository"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Comments struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"comments"`
+	Reactions struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"reactions"`
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changedFiles"`
+}
+
+func (n searchNode) item() board.ProjectItemWithFields {
+	var labels []string
+	for _, l := range n.Labels.Nodes {
+		labels = append(labels, l.Name)
+	}
+	return board.ProjectItemWithFields{
+		ContentID:    n.ID,
+		Number:       n.Number,
+		Title:        n.Title,
+		Type:         n.Typename,
+		URL:          n.URL,
+		Repo:         n.Repository.NameWithOwner,
+		Private:      n.Repository.IsPrivate,
+		State:        n.State,
+		Author:       n.Author.Login,
+		Labels:       labels,
+		CreatedAt:    n.CreatedAt,
+		UpdatedAt:    n.UpdatedAt,
+		Comments:     n.Comments.TotalCount,
+		Reactions:    n.Reactions.TotalCount,
+		Additions:    n.Additions,
+		Deletions:    n.Deletions,
+		ChangedFiles: n.ChangedFiles,
+	}
+}
+
+// Count returns how many items query matches, for one page's worth of
+// points and without fetching any.
+func Count(gql *ghgql.Client, query string) (int, error) {
+	q := `query($q: String!) { search(query: $q, type: ISSUE, first: 1) { issueCount } }`
+	var result struct {
+		Search struct {
+			IssueCount int `json:"issueCount"`
+		} `json:"search"`
+	}
+	if err := gql.Do(ghgql.Request{Query: q, Variables: map[string]any{"q": query}}, &result); err != nil {
+		return 0, fmt.Errorf("search %q: %w", query, err)
+	}
+	return result.Search.IssueCount, nil
+}
+
+// Qualifiers returns the values of every key: qualifier in query, negated
+// (-key:) or not, with quotes removed: Qualifiers(`repo:a/b label:"help
+// wanted" -label:x`, "label") is ["help wanted", "x"].
+func Qualifiers(query, key string) []string {
+	var values []string
+	for _, term := range terms(query) {
+		k, v, ok := strings.Cut(strings.TrimPrefix(term, "-"), ":")
+		if ok && strings.EqualFold(k, key) && v != "" {
+			values = append(values, strings.Trim(v, `"`))
+		}
+	}
+	return values
+}
+
+// terms splits query on spaces outside double quotes.
+func terms(query string) []string {
+	var out []string
+	var b strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			b.WriteRune(r)
+		case r == ' ' && !quoted:
+			if b.Len() > 0 {
+				out = append(out, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		out = append(out, b.String())
+	}
+	return out
+}
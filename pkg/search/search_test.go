@@ -0,0 +1,196 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// roundTripFunc lets a plain function serve a client's requests.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// newFakeClient returns a client whose requests are answered by handler,
+// without pacing or retries.
+func newFakeClient(t *testing.T, handler http.HandlerFunc) *ghgql.Client {
+	t.Helper()
+	c := ghgql.NewClientWithTransport("test-token", roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	}))
+	c.MinDelay = 0
+	c.MaxRetries = 0
+	c.RetryBackoff = 0
+	return c
+}
+
+// fakeSearch answers searches over created, one issue per time, the way
+// GitHub does: created:from..to is inclusive at both ends, pages hold 100,
+// and no more than MaxResults are returned. It records each query.
+type fakeSearch struct {
+	created []time.Time
+	queries []string
+}
+
+func (f *fakeSearch) handle(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				Q      string `json:"q"`
+				Cursor string `json:"cursor"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		q := req.Variables.Q
+		f.queries = append(f.queries, q)
+
+		var from, to time.Time
+		if _, rng, ok := strings.Cut(q, " created:"); ok {
+			a, b, _ := strings.Cut(rng, "..")
+			var errA, errB error
+			from, errA = time.Parse(time.RFC3339, a)
+			to, errB = time.Parse(time.RFC3339, b)
+			if errA != nil || errB != nil {
+				from, to = time.Time{}, time.Time{} // the caller's own qualifier: match all
+			}
+		}
+		var matches []int
+		for i, c := range f.created {
+			if from.IsZero() || !c.Before(from) && !c.After(to) {
+				matches = append(matches, i)
+			}
+		}
+
+		offset, _ := strconv.Atoi(req.Variables.Cursor)
+		end := min(offset+100, len(matches), MaxResults)
+		var nodes []string
+		for _, i := range matches[min(offset, end):end] {
+			nodes = append(nodes, fmt.Sprintf(`{"__typename": "Issue", "id": "I_%d", "number": %d, "createdAt": %q, "repository": {"nameWithOwner": "o/r"}}`, i, i, f.created[i].Format(time.RFC3339)))
+		}
+		fmt.Fprintf(w, `{"data": {"search": {"issueCount": %d, "nodes": [%s], "pageInfo": {"hasNextPage": %t, "endCursor": "%d"}}}}`,
+			len(matches), strings.Join(nodes, ","), end < min(len(matches), MaxResults), end)
+	}
+}
+
+// spread returns n times evenly spaced from from, every step apart.
+func spread(n int, from time.Time, step time.Duration) []time.Time {
+	out := make([]time.Time, n)
+	for i := range out {
+		out[i] = from.Add(time.Duration(i) * step)
+	}
+	return out
+}
+
+func TestSliceSplitsTwice(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(2400 * time.Hour)
+	f := &fakeSearch{created: spread(2400, from, time.Hour)}
+	gql := newFakeClient(t, f.handle(t))
+
+	res := &Result{}
+	if err := slice(gql, "org:o", from, to, res, make(map[string]bool)); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 2400 {
+		t.Errorf("collected %d items, want 2400", len(res.Items))
+	}
+	ranges := make(map[string]bool)
+	for _, q := range f.queries {
+		if !strings.HasPrefix(q, "org:o created:") || strings.Count(q, "created:") != 1 {
+			t.Errorf("query %q, want org:o and one created: range", q)
+		}
+		ranges[q] = true
+	}
+	// The whole range and both halves match over 1000; the quarters fit.
+	if len(ranges) != 7 {
+		t.Errorf("searched %d ranges, want 7 (the range, 2 halves, 4 quarters): %q", len(ranges), f.queries)
+	}
+}
+
+func TestSliceDeduplicatesBoundaries(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(4000 * time.Hour)
+	// An item every 2h, so one sits on every boundary the split makes.
+	f := &fakeSearch{created: spread(2001, from, 2*time.Hour)}
+	gql := newFakeClient(t, f.handle(t))
+
+	res := &Result{}
+	if err := slice(gql, "org:o", from, to, res, make(map[string]bool)); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for _, it := range res.Items {
+		if seen[it.ContentID] {
+			t.Errorf("%s collected twice", it.ContentID)
+		}
+		seen[it.ContentID] = true
+	}
+	if len(res.Items) != 2001 {
+		t.Errorf("collected %d items, want 2001", len(res.Items))
+	}
+	mid := from.Add(2000 * time.Hour).Format(time.RFC3339)
+	var onBoundary int
+	for _, q := range f.queries {
+		if strings.HasSuffix(q, ".."+mid) || strings.Contains(q, "created:"+mid+"..") {
+			onBoundary++
+		}
+	}
+	if onBoundary < 2 {
+		t.Errorf("no two ranges share the boundary %s: %q", mid, f.queries)
+	}
+}
+
+func TestIssues(t *testing.T) {
+	recent := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		query         string
+		count         int
+		wantItems     int
+		wantTruncated bool
+		wantSliced    bool
+	}{
+		{name: "fits", query: "org:o", count: 250, wantItems: 250},
+		{name: "sliced", query: "org:o", count: 1500, wantItems: 1500, wantSliced: true},
+		{name: "already has created:", query: "org:o created:>2023-01-01", count: 1500, wantItems: MaxResults, wantTruncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeSearch{created: spread(tt.count, recent, time.Minute)}
+			gql := newFakeClient(t, f.handle(t))
+
+			res, err := Issues(gql, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Items) != tt.wantItems || res.IssueCount != tt.count {
+				t.Errorf("collected %d of %d, want %d of %d", len(res.Items), res.IssueCount, tt.wantItems, tt.count)
+			}
+			if res.Truncated() != tt.wantTruncated {
+				t.Errorf("Truncated() = %v, want %v", res.Truncated(), tt.wantTruncated)
+			}
+			sliced := false
+			for _, q := range f.queries {
+				if strings.Count(q, "created:") > strings.Count(tt.query, "created:") {
+					sliced = true
+				}
+			}
+			if sliced != tt.wantSliced {
+				t.Errorf("split by creation date: %v, want %v (queries %q)", sliced, tt.wantSliced, f.queries)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package security fetches open Dependabot alerts and in-progress repository
+// security advisories through the GitHub REST API, so they can be triaged on
+// a project board next to issues and PRs.
+package security
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// Alert kinds.
+const (
+	KindDependabot = "dependabot"
+	KindAdvisory   = "advisory"
+)
+
+// Severities, most severe first, as returned by the API.
+var Severities = []string{"critical", "high", "medium", "low"}
+
+// Alert is one open Dependabot alert or repository security advisory.
+type Alert struct {
+	Kind      string // KindDependabot or KindAdvisory
+	Repo      string // "owner/name"
+	ID        string // alert number or GHSA ID
+	Severity  string // critical, high, medium, low
+	Package   string // "ecosystem/name"; advisories may list several, comma-separated
+	Summary   string
+	URL       string
+	CreatedAt time.Time
+}
+
+// Key identifies the alert across runs; it is embedded in draft titles so a
+// re-run doesn't add the same alert twice.
+func (a Alert) Key() string {
+	if a.Kind == KindDependabot {
+		return fmt.Sprintf("%s dependabot#%s", a.Repo, a.ID)
+	}
+	return fmt.Sprintf("%s %s", a.Repo, a.ID)
+}
+
+// Title is the draft item title for the alert.
+func (a Alert) Title() string {
+	return fmt.Sprintf("[%s] %s: %s", a.Key(), a.Package, a.Summary)
+}
+
+// Body is the draft item body for the alert.
+func (a Alert) Body() string {
+	return fmt.Sprintf("**%s** %s alert in `%s`, opened %s.\n\n%s\n\n%s",
+		strings.ToUpper(a.Severity), a.Kind, a.Repo, a.CreatedAt.Format("2006-01-02"), a.Summary, a.URL)
+}
+
+// SeverityAtLeast reports whether severity is at or above min in Severities
+// order. An empty min matches everything.
+func SeverityAtLeast(severity, min string) bool {
+	if min == "" {
+		return true
+	}
+	rank := func(s string) int {
+		for i, v := range Severities {
+			if strings.EqualFold(s, v) {
+				return i
+			}
+		}
+		return len(Severities)
+	}
+	return rank(severity) <= rank(min)
+}
+
+// perPage is the REST page size.
+const perPage = 100
+
+// dependabotAlert is one alert as the REST API returns it.
+type dependabotAlert struct {
+	Number     int       `json:"number"`
+	HTMLURL    string    `json:"html_url"`
+	CreatedAt  time.Time `json:"created_at"`
+	Dependency struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+	} `json:"dependency"`
+	SecurityAdvisory struct {
+		Summary  string `json:"summary"`
+		Severity string `json:"severity"`
+	} `json:"security_advisory"`
+}
+
+// repositoryAdvisory is one advisory as the REST API returns it.
+type repositoryAdvisory struct {
+	GHSAID          string    `json:"ghsa_id"`
+	Summary         string    `json:"summary"`
+	Severity        string    `json:"severity"`
+	HTMLURL         string    `json:"html_url"`
+	CreatedAt       time.Time `json:"created_at"`
+	Vulnerabilities []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+	} `json:"vulnerabilities"`
+}
+
+// getAll GETs path and every page after it, following the Link header's
+// cursors.
+func getAll[T any](gql *ghgql.Client, path string) ([]T, error) {
+	var all []T
+	for path != "" {
+		var page []T
+		next, err := gql.GetRESTPage(path, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if next == path {
+			break // a server that links a page to itself would loop forever
+		}
+		path = next
+	}
+	return all, nil
+}
+
+// DependabotAlerts returns the open Dependabot alerts for repo ("owner/name").
+func DependabotAlerts(gql *ghgql.Client, repo string) ([]Alert, error) {
+	result, err := getAll[dependabotAlert](gql, fmt.Sprintf("/repos/%s/dependabot/alerts?state=open&per_page=%d", repo, perPage))
+	if err != nil {
+		return nil, fmt.Errorf("dependabot alerts for %s: %w", repo, err)
+	}
+	var out []Alert
+	for _, r := range result {
+		out = append(out, Alert{
+			Kind:      KindDependabot,
+			Repo:      repo,
+			ID:        fmt.Sprint(r.Number),
+			Severity:  strings.ToLower(r.SecurityAdvisory.Severity),
+			Package:   r.Dependency.Package.Ecosystem + "/" + r.Dependency.Package.Name,
+			Summary:   r.SecurityAdvisory.Summary,
+			URL:       r.HTMLURL,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// advisoryStates are the states of advisories still being worked on;
+// published and closed advisories are left out.
+var advisoryStates = []string{"triage", "draft"}
+
+// RepositoryAdvisories returns the repository security advisories for repo
+// that are still being worked on (triage or draft).
+func RepositoryAdvisories(gql *ghgql.Client, repo string) ([]Alert, error) {
+	var out []Alert
+	for _, state := range advisoryStates {
+		result, err := getAll[repositoryAdvisory](gql, fmt.Sprintf("/repos/%s/security-advisories?state=%s&per_page=%d", repo, state, perPage))
+		if err != nil {
+			return nil, fmt.Errorf("security advisories for %s: %w", repo, err)
+		}
+		for _, r := range result {
+			var pkgs []string
+			for _, v := range r.Vulnerabilities {
+				if v.Package.Name != "" {
+					pkgs = append(pkgs, v.Package.Ecosystem+"/"+v.Package.Name)
+				}
+			}
+			out = append(out, Alert{
+				Kind:      KindAdvisory,
+				Repo:      repo,
+				ID:        r.GHSAID,
+				Severity:  strings.ToLower(r.Severity),
+				Package:   strings.Join(pkgs, ", "),
+				Summary:   r.Summary,
+				URL:       r.HTMLURL,
+				CreatedAt: r.CreatedAt,
+			})
+		}
+	}
+	return out, nil
+}
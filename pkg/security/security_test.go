@@ -0,0 +1,109 @@
+package security
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// roundTripFunc lets a plain function serve a client's requests.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// newFakeClient returns a client whose requests are answered by handler,
+// without pacing or retries.
+func newFakeClient(t *testing.T, handler http.HandlerFunc) *ghgql.Client {
+	t.Helper()
+	c := ghgql.NewClientWithTransport("test-token", roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	}))
+	c.MinDelay = 0
+	c.MaxRetries = 0
+	c.RetryBackoff = 0
+	return c
+}
+
+func TestDependabotAlertsFollowsCursor(t *testing.T) {
+	var queries []string
+	gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Has("page") {
+			t.Errorf("request %s uses page numbers", r.URL)
+		}
+		switch r.URL.Query().Get("after") {
+		case "":
+			w.Header().Set("Link", `<https://api.github.com/repos/my-org/api/dependabot/alerts?state=open&per_page=100&after=c1>; rel="next"`)
+			fmt.Fprint(w, `[{"number": 1, "security_advisory": {"severity": "HIGH"}, "dependency": {"package": {"ecosystem": "go", "name": "x/net"}}}]`)
+		case "c1":
+			w.Header().Set("Link", `<https://api.github.com/repos/my-org/api/dependabot/alerts?state=open&per_page=100&before=c0>; rel="prev"`)
+			fmt.Fprint(w, `[{"number": 2, "security_advisory": {"severity": "low"}}]`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	alerts, err := DependabotAlerts(gql, "my-org/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, a := range alerts {
+		ids = append(ids, a.ID+"/"+a.Severity)
+	}
+	if want := []string{"1/high", "2/low"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("alerts = %v, want %v", ids, want)
+	}
+	if alerts[0].Package != "go/x/net" || alerts[0].Key() != "my-org/api dependabot#1" {
+		t.Errorf("first alert = %+v", alerts[0])
+	}
+	if want := []string{"state=open&per_page=100", "state=open&per_page=100&after=c1"}; !reflect.DeepEqual(queries, want) {
+		t.Errorf("queries = %q, want %q", queries, want)
+	}
+}
+
+func TestRepositoryAdvisoriesFiltersByState(t *testing.T) {
+	var states []string
+	gql := newFakeClient(t, func(w http.ResponseWriter, r *http.Request) {
+		state := r.URL.Query().Get("state")
+		states = append(states, state)
+		fmt.Fprintf(w, `[{"ghsa_id": "GHSA-%s", "severity": "critical", "vulnerabilities": [{"package": {"ecosystem": "go", "name": "a"}}, {"package": {"ecosystem": "go", "name": "b"}}]}]`, state)
+	})
+
+	alerts, err := RepositoryAdvisories(gql, "my-org/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"triage", "draft"}; !reflect.DeepEqual(states, want) {
+		t.Errorf("requested states %v, want %v", states, want)
+	}
+	if len(alerts) != 2 || alerts[0].ID != "GHSA-triage" || alerts[1].ID != "GHSA-draft" || alerts[0].Package != "go/a, go/b" {
+		t.Errorf("alerts = %+v", alerts)
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	tests := []struct {
+		severity, min string
+		want          bool
+	}{
+		{"low", "", true},
+		{"critical", "high", true},
+		{"HIGH", "high", true},
+		{"medium", "high", false},
+		{"unknown", "low", false},
+	}
+	for _, tt := range tests {
+		if got := SeverityAtLeast(tt.severity, tt.min); got != tt.want {
+			t.Errorf("SeverityAtLeast(%q, %q) = %v, want %v", tt.severity, tt.min, got, tt.want)
+		}
+	}
+}
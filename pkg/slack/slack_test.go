@@ -0,0 +1,90 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The example request from Slack's "Verifying requests from Slack" guide.
+const (
+	exampleSecret    = "8f742231b10e8888abcd99yyyzzz85a5"
+	exampleTimestamp = "1531420618"
+	exampleBody      = "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+	exampleSignature = "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503"
+)
+
+func sign(secret, ts, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyRequest(t *testing.T) {
+	sent, _ := strconv.ParseInt(exampleTimestamp, 10, 64)
+	at := time.Unix(sent, 0)
+	header := func(ts, sig string) http.Header {
+		h := http.Header{}
+		if ts != "" {
+			h.Set("X-Slack-Request-Timestamp", ts)
+		}
+		if sig != "" {
+			h.Set("X-Slack-Signature", sig)
+		}
+		return h
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		header  http.Header
+		body    string
+		now     time.Time
+		wantErr string
+	}{
+		{name: "slack's example", secret: exampleSecret, header: header(exampleTimestamp, exampleSignature), body: exampleBody, now: at},
+		{name: "within the window", secret: exampleSecret, header: header(exampleTimestamp, exampleSignature), body: exampleBody, now: at.Add(MaxRequestAge)},
+		{name: "clock skew the other way", secret: exampleSecret, header: header(exampleTimestamp, exampleSignature), body: exampleBody, now: at.Add(-MaxRequestAge)},
+		{name: "too old", secret: exampleSecret, header: header(exampleTimestamp, exampleSignature), body: exampleBody, now: at.Add(MaxRequestAge + time.Second), wantErr: "request timestamp is 5m1s off"},
+		{name: "from the future", secret: exampleSecret, header: header(exampleTimestamp, exampleSignature), body: exampleBody, now: at.Add(-time.Hour), wantErr: "request timestamp is -1h0m0s off"},
+		{name: "no timestamp", secret: exampleSecret, header: header("", exampleSignature), body: exampleBody, now: at, wantErr: "missing or invalid request timestamp"},
+		{name: "bad timestamp", secret: exampleSecret, header: header("yesterday", exampleSignature), body: exampleBody, now: at, wantErr: "missing or invalid request timestamp"},
+		{name: "no signature", secret: exampleSecret, header: header(exampleTimestamp, ""), body: exampleBody, now: at, wantErr: "signature mismatch"},
+		{name: "wrong secret", secret: "other", header: header(exampleTimestamp, exampleSignature), body: exampleBody, now: at, wantErr: "signature mismatch"},
+		{name: "tampered body", secret: exampleSecret, header: header(exampleTimestamp, exampleSignature), body: exampleBody + "&x=1", now: at, wantErr: "signature mismatch"},
+		{
+			name:    "replayed with a new timestamp",
+			secret:  exampleSecret,
+			header:  header(strconv.FormatInt(sent+60, 10), exampleSignature),
+			body:    exampleBody,
+			now:     at,
+			wantErr: "signature mismatch",
+		},
+		{
+			name:   "freshly signed",
+			secret: "s3cret",
+			header: header(exampleTimestamp, sign("s3cret", exampleTimestamp, "text=agenda")),
+			body:   "text=agenda",
+			now:    at,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyRequest(tt.secret, tt.header, []byte(tt.body), tt.now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("VerifyRequest: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifyRequest error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
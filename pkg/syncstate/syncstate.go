@@ -8,7 +8,7 @@ package syncstate
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
@@ -242,7 +242,7 @@ func (s *State) Save() error {
 // Intended for use inside the sync loop where a save failure is non-fatal.
 func (s *State) Flush() {
 	if err := s.Save(); err != nil {
-		log.Printf("Warning: could not save sync state: %v", err)
+		slog.Warn("Could not save sync state", "err", err)
 	}
 }
 
@@ -0,0 +1,99 @@
+// Package tracing wires OpenTelemetry tracing for the CLI. Tracing is off
+// unless an OTLP endpoint is configured through the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT
+// variables; when off, every span is a no-op and costs nothing.
+//
+// Each invocation produces one trace: Setup starts a root span for the run,
+// and spans started with Start (API calls in pkg/ghgql, phases in pkg/board)
+// become its children.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies spans created by this module.
+const InstrumentationName = "github.com/benjaminapetersen/github-project-boards-stuff"
+
+// shutdownTimeout bounds flushing spans at exit, so an unreachable collector
+// can't hold up a failing run.
+const shutdownTimeout = 5 * time.Second
+
+var (
+	mu      sync.Mutex
+	rootCtx = context.Background()
+)
+
+// Enabled reports whether an OTLP endpoint is configured.
+func Enabled() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup installs an OTLP/HTTP exporter when Enabled and starts the root span
+// for this run, named after the subcommand. The returned function ends the
+// root span and flushes pending spans; call it before exiting. When tracing
+// is disabled Setup does nothing and the returned function is a no-op.
+func Setup(service, version, run string) (shutdown func(), err error) {
+	if !Enabled() {
+		return func() {}, nil
+	}
+
+	ctx := context.Background()
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return func() {}, fmt.Errorf("create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(service),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return func() {}, fmt.Errorf("build trace resource: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+
+	runCtx, span := tp.Tracer(InstrumentationName).Start(ctx, run)
+	mu.Lock()
+	rootCtx = runCtx
+	mu.Unlock()
+
+	return func() {
+		span.End()
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		tp.Shutdown(ctx)
+	}, nil
+}
+
+// Start begins a span as a child of the run's root span. Call End on the
+// returned span when the operation finishes.
+func Start(name string, attrs ...attribute.KeyValue) trace.Span {
+	mu.Lock()
+	parent := rootCtx
+	mu.Unlock()
+	_, span := otel.Tracer(InstrumentationName).Start(parent, name, trace.WithAttributes(attrs...))
+	return span
+}
+
+// EndWithError records err (if any) on span and ends it.
+func EndWithError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,52 @@
+package upstream
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// Label is a repository label.
+type Label struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"` // hex, without "#"
+	Description string `json:"description"`
+}
+
+// Labels returns every label defined in repo ("owner/name").
+func Labels(gql *ghgql.Client, repo string) ([]Label, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repo %q (expected owner/name)", repo)
+	}
+	query := `query($owner: String!, $name: String!, $cursor: String) {
+		repository(owner: $owner, name: $name) {
+			labels(first: 100, after: $cursor) {
+				nodes { name color description }
+				pageInfo { hasNextPage endCursor }
+			}
+		}
+	}`
+
+	var labels []Label
+	type page struct {
+		Repository *struct {
+			Labels struct {
+				Nodes    []Label        `json:"nodes"`
+				PageInfo ghgql.PageInfo `json:"pageInfo"`
+			} `json:"labels"`
+		} `json:"repository"`
+	}
+	err := ghgql.Paginate(gql, ghgql.Request{Query: query, Variables: map[string]any{"owner": owner, "name": name}}, func(result *page) (ghgql.PageInfo, error) {
+		if result.Repository == nil {
+			return ghgql.PageInfo{}, fmt.Errorf("repository %s not found", repo)
+		}
+		labels = append(labels, result.Repository.Labels.Nodes...)
+		return result.Repository.Labels.PageInfo, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
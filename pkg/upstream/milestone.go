@@ -0,0 +1,108 @@
+package upstream
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// MilestoneState returns the state (OPEN or CLOSED) of the milestone titled
+// title in repo ("owner/name"), or "" if the repository has no such
+// milestone.
+func MilestoneState(gql *ghgql.Client, repo, title string) (string, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return "", fmt.Errorf("invalid repo %q (expected owner/name)", repo)
+	}
+	query := `query($owner: String!, $name: String!, $title: String!) {
+		repository(owner: $owner, name: $name) {
+			milestones(first: 20, query: $title, states: [OPEN, CLOSED]) {
+				nodes { title state }
+			}
+		}
+	}`
+
+	var result struct {
+		Repository *struct {
+			Milestones struct {
+				Nodes []struct {
+					Title string `json:"title"`
+					State string `json:"state"`
+				} `json:"nodes"`
+			} `json:"milestones"`
+		} `json:"repository"`
+	}
+	err := gql.Do(ghgql.Request{
+		Query:     query,
+		Variables: map[string]any{"owner": owner, "name": name, "title": title},
+	}, &result)
+	if err != nil {
+		return "", err
+	}
+	if result.Repository == nil {
+		return "", fmt.Errorf("repository %s not found", repo)
+	}
+	for _, m := range result.Repository.Milestones.Nodes {
+		if m.Title == title { // the query also matches longer titles
+			return m.State, nil
+		}
+	}
+	return "", nil
+}
+
+// releaseMilestone matches release milestone titles such as v1.36.
+var releaseMilestone = regexp.MustCompile(`^v(\d+)\.(\d+)$`)
+
+// CurrentMilestone returns the title of the earliest open release milestone
+// (vMAJOR.MINOR) in repo, or "" if none is open. The earliest is the
+// release in progress: the next release's milestone is usually opened
+// before the current one closes.
+func CurrentMilestone(gql *ghgql.Client, repo string) (string, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return "", fmt.Errorf("invalid repo %q (expected owner/name)", repo)
+	}
+	query := `query($owner: String!, $name: String!) {
+		repository(owner: $owner, name: $name) {
+			milestones(first: 100, states: [OPEN]) {
+				nodes { title }
+			}
+		}
+	}`
+
+	var result struct {
+		Repository *struct {
+			Milestones struct {
+				Nodes []struct {
+					Title string `json:"title"`
+				} `json:"nodes"`
+			} `json:"milestones"`
+		} `json:"repository"`
+	}
+	err := gql.Do(ghgql.Request{
+		Query:     query,
+		Variables: map[string]any{"owner": owner, "name": name},
+	}, &result)
+	if err != nil {
+		return "", err
+	}
+	if result.Repository == nil {
+		return "", fmt.Errorf("repository %s not found", repo)
+	}
+	current, currentMajor, currentMinor := "", 0, 0
+	for _, m := range result.Repository.Milestones.Nodes {
+		parts := releaseMilestone.FindStringSubmatch(m.Title)
+		if parts == nil {
+			continue
+		}
+		major, _ := strconv.Atoi(parts[1])
+		minor, _ := strconv.Atoi(parts[2])
+		if current == "" || major < currentMajor || major == currentMajor && minor < currentMinor {
+			current, currentMajor, currentMinor = m.Title, major, minor
+		}
+	}
+	return current, nil
+}
@@ -0,0 +1,134 @@
+// Package upstream acts on the issues and pull requests behind board items:
+// closing them, labeling them, and commenting on them, and reads the state of
+// their milestones. Unlike pkg/board, every change here is visible in the
+// (usually public) repositories.
+package upstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/benjaminapetersen/github-project-boards-stuff/pkg/ghgql"
+)
+
+// CloseIssue closes an issue as completed.
+func CloseIssue(gql *ghgql.Client, issueID string) error {
+	mutation := `mutation($id: ID!) {
+		closeIssue(input: {issueId: $id, stateReason: COMPLETED}) {
+			issue { id }
+		}
+	}`
+
+	var result json.RawMessage
+	return gql.Do(ghgql.Request{Query: mutation, Variables: map[string]any{"id": issueID}}, &result)
+}
+
+// AddComment posts a comment on an issue or pull request.
+func AddComment(gql *ghgql.Client, subjectID, body string) error {
+	mutation := `mutation($id: ID!, $body: String!) {
+		addComment(input: {subjectId: $id, body: $body}) {
+			commentEdge { node { id } }
+		}
+	}`
+
+	var result json.RawMessage
+	return gql.Do(ghgql.Request{Query: mutation, Variables: map[string]any{"id": subjectID, "body": body}}, &result)
+}
+
+// HasComment reports whether any of the last 100 comments on an issue or
+// pull request contains marker, so repeat runs don't post the same comment.
+func HasComment(gql *ghgql.Client, subjectID, marker string) (bool, error) {
+	query := `query($id: ID!) {
+		node(id: $id) {
+			... on Issue { comments(last: 100) { nodes { body } } }
+			... on PullRequest { comments(last: 100) { nodes { body } } }
+		}
+	}`
+
+	var result struct {
+		Node struct {
+			Comments struct {
+				Nodes []struct {
+					Body string `json:"body"`
+				} `json:"nodes"`
+			} `json:"comments"`
+		} `json:"node"`
+	}
+	if err := gql.Do(ghgql.Request{Query: query, Variables: map[string]any{"id": subjectID}}, &result); err != nil {
+		return false, err
+	}
+	for _, c := range result.Node.Comments.Nodes {
+		if strings.Contains(c.Body, marker) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Labeler adds existing repository labels to issues and pull requests,
+// caching label IDs per repository.
+type Labeler struct {
+	gql *ghgql.Client
+	ids map[string]string // "owner/repo\x00label" → label node ID ("" if missing)
+}
+
+// NewLabeler returns a Labeler using gql.
+func NewLabeler(gql *ghgql.Client) *Labeler {
+	return &Labeler{gql: gql, ids: make(map[string]string)}
+}
+
+// AddLabel adds label to the issue or pull request labelableID in repo
+// ("owner/name"). The label must already exist in the repository.
+func (l *Labeler) AddLabel(repo, labelableID, label string) error {
+	id, err := l.labelID(repo, label)
+	if err != nil {
+		return err
+	}
+
+	mutation := `mutation($id: ID!, $labels: [ID!]!) {
+		addLabelsToLabelable(input: {labelableId: $id, labelIds: $labels}) {
+			clientMutationId
+		}
+	}`
+
+	var result json.RawMessage
+	return l.gql.Do(ghgql.Request{Query: mutation, Variables: map[string]any{"id": labelableID, "labels": []string{id}}}, &result)
+}
+
+func (l *Labeler) labelID(repo, label string) (string, error) {
+	key := repo + "\x00" + label
+	id, ok := l.ids[key]
+	if !ok {
+		owner, name, found := strings.Cut(repo, "/")
+		if !found {
+			return "", fmt.Errorf("invalid repository %q", repo)
+		}
+		query := `query($owner: String!, $name: String!, $label: String!) {
+			repository(owner: $owner, name: $name) { label(name: $label) { id } }
+		}`
+
+		var result struct {
+			Repository *struct {
+				Label *struct {
+					ID string `json:"id"`
+				} `json:"label"`
+			} `json:"repository"`
+		}
+		vars := map[string]any{"owner": owner, "name": name, "label": label}
+		if err := l.gql.Do(ghgql.Request{Query: query, Variables: vars}, &result); err != nil {
+			return "", err
+		}
+		if result.Repository == nil {
+			return "", fmt.Errorf("repository %s not found", repo)
+		}
+		if result.Repository.Label != nil {
+			id = result.Repository.Label.ID
+		}
+		l.ids[key] = id
+	}
+	if id == "" {
+		return "", fmt.Errorf("label %q does not exist in %s", label, repo)
+	}
+	return id, nil
+}
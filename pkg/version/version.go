@@ -0,0 +1,81 @@
+// Package version reports what build of the tools is running. Version,
+// Commit, and Date are set at build time via -ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/benjaminapetersen/github-project-boards-stuff/pkg/version.Version=v0.3.0" ./cmd/kube-board
+//
+// Unset values fall back to the VCS information Go embeds in the binary.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Set via -ldflags "-X ...". See the Makefile.
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// Info is the build information of the running binary.
+type Info struct {
+	Version   string
+	Commit    string
+	Date      string
+	Modified  bool // built from a dirty working tree
+	GoVersion string
+	Platform  string
+}
+
+// Get returns the build information, filling Commit and Date from the
+// embedded VCS stamp when they were not set via -ldflags.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+		if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.Date == "" {
+		info.Date = "unknown"
+	}
+	return info
+}
+
+// String renders the info on one line, e.g.
+// "v0.3.0 (commit 1a2b3c4, built 2025-06-01T12:00:00Z, go1.24.2 linux/amd64)".
+func (i Info) String() string {
+	commit := i.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	if i.Modified {
+		commit += "-dirty"
+	}
+	return fmt.Sprintf("%s (commit %s, built %s, %s %s)", i.Version, commit, i.Date, i.GoVersion, i.Platform)
+}
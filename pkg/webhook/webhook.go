@@ -0,0 +1,63 @@
+// Package webhook verifies and decodes GitHub webhook deliveries.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// VerifySignature checks the X-Hub-Signature-256 header of a delivery
+// against the webhook secret.
+func VerifySignature(secret string, header http.Header, body []byte) error {
+	sig := header.Get("X-Hub-Signature-256")
+	if sig == "" {
+		return errors.New("missing X-Hub-Signature-256 header")
+	}
+	want, ok := strings.CutPrefix(sig, "sha256=")
+	if !ok {
+		return errors.New("X-Hub-Signature-256 is not a sha256 signature")
+	}
+	got, err := hex.DecodeString(want)
+	if err != nil {
+		return errors.New("X-Hub-Signature-256 is not hex")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// Event returns the delivery's event name (X-GitHub-Event), e.g.
+// "projects_v2_item".
+func Event(header http.Header) string {
+	return header.Get("X-GitHub-Event")
+}
+
+// ProjectsV2ItemEvent is the payload of a projects_v2_item delivery. These
+// are only sent for organization-owned projects, to organization webhooks
+// and GitHub Apps.
+type ProjectsV2ItemEvent struct {
+	Action string `json:"action"` // created, edited, archived, restored, converted, reordered, deleted
+	Item   struct {
+		NodeID        string `json:"node_id"`
+		ProjectNodeID string `json:"project_node_id"`
+		ContentNodeID string `json:"content_node_id"`
+		ContentType   string `json:"content_type"` // Issue, PullRequest, DraftIssue
+	} `json:"projects_v2_item"`
+	Changes struct {
+		FieldValue *struct {
+			FieldNodeID string `json:"field_node_id"`
+			FieldType   string `json:"field_type"`
+		} `json:"field_value"`
+	} `json:"changes"`
+	Sender struct {
+		Login string `json:"login"`
+		Type  string `json:"type"` // User, Bot
+	} `json:"sender"`
+}
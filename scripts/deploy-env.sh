@@ -78,7 +78,7 @@ declare -A CONFIG_KEYS=(
   [GITHUB_EXCLUDE_STATUSES]="${GITHUB_EXCLUDE_STATUSES:-}"
   [GITHUB_DEST_BOARD_OWNER]="${GITHUB_DEST_BOARD_OWNER:-}"
   [GITHUB_DEST_BOARD_NAME]="${GITHUB_DEST_BOARD_NAME:-}"
-  [GITHUB_DEST_BOARD_PRIVACY]="${GITHUB_DEST_BOARD_PRIVACY:-private}"
+  [GITHUB_DEST_BOARD_VISIBILITY]="${GITHUB_DEST_BOARD_VISIBILITY:-private}"
   [GITHUB_DEST_BOARD_AUTHOR_FIELD_NAME]="${GITHUB_DEST_BOARD_AUTHOR_FIELD_NAME:-Item Author}"
   [GITHUB_LINK_REPOS]="${GITHUB_LINK_REPOS:-}"
   [GITHUB_DEST_BOARD_ADDITIONAL_VIEWS]="${GITHUB_DEST_BOARD_ADDITIONAL_VIEWS:-}"